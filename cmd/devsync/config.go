@@ -34,7 +34,7 @@ const (
 var errConfigInitCanceled = errors.New("config init canceled")
 
 var availableSystemManagers = []string{
-	"apt", "brew", "go", "npm", "pnpm", "nvm", "snap", "flatpak", "fwupdmgr", "pipx", "cargo", "uv", "rustup", "gem", "winget", "scoop",
+	"apt", "dnf", "pacman", "zypper", "apk", "brew", "nix", "go", "npm", "pnpm", "nvm", "mise", "asdf", "pyenv", "rbenv", "goenv", "sdkman", "snap", "flatpak", "docker", "podman", "vscode", "vscodium", "cursor", "gh", "krew", "helm", "fwupdmgr", "conda", "mamba", "pip", "pipx", "cargo", "uv", "rustup", "gem", "winget", "scoop",
 }
 
 // テストで対話入力や外部依存を差し替えるためのフック
@@ -207,13 +207,23 @@ func runConfigValidate(cmd *cobra.Command, args []string) error {
 		fmt.Println("⚪ 設定ファイルは未作成です（デフォルト値で検証します）")
 	}
 
+	registerExternalUpdaters(&cfg.Sys)
+
 	knownManagers := make(map[string]struct{})
+	versionPolicyManagers := make(map[string]struct{})
+
 	for _, u := range updater.All() {
 		knownManagers[u.Name()] = struct{}{}
+
+		if p, ok := u.(updater.VersionPolicyUpdater); ok && p.SupportsVersionPolicy() {
+			versionPolicyManagers[u.Name()] = struct{}{}
+		}
 	}
 
 	result := config.Validate(cfg, config.ValidateOptions{
-		KnownSysManagers: knownManagers,
+		KnownSysManagers:      knownManagers,
+		KnownRunPhases:        knownRunPhases(),
+		VersionPolicyManagers: versionPolicyManagers,
 	})
 
 	if len(result.Warnings) > 0 {
@@ -351,8 +361,9 @@ func buildConfigFromInitAnswers(answers configInitAnswers) *config.Config {
 			Managers: make(map[string]config.ManagerConfig),
 		},
 		Secrets: config.SecretsConfig{
-			Enabled:  true, // 常に有効（env:プレフィックスで自動検索）
-			Provider: "bitwarden",
+			Enabled:           true, // 常に有効（env:プレフィックスで自動検索）
+			Provider:          "bitwarden",
+			ExpiryWarningDays: config.DefaultExpiryWarningDays,
 		},
 	}
 
@@ -7,6 +7,7 @@ import (
 
 	"github.com/fatih/color"
 	"github.com/scottlz0310/devsync/internal/config"
+	"github.com/scottlz0310/devsync/internal/secret"
 	"github.com/spf13/cobra"
 )
 
@@ -17,15 +18,21 @@ var doctorCmd = &cobra.Command{
 	Long: `開発に必要なツール (git, git-lfs, bw など) がインストールされているか確認し、
 設定ファイルの状態を診断します。`,
 	Run: func(cmd *cobra.Command, args []string) {
-		runDoctor()
+		if !runDoctor() {
+			os.Exit(1)
+		}
 	},
 }
 
+// doctorSecretAuditFunc はテストで差し替え可能なシークレット監査処理です。
+var doctorSecretAuditFunc = secret.Audit
+
 func init() {
 	rootCmd.AddCommand(doctorCmd)
 }
 
-func runDoctor() {
+// runDoctor は診断を実行し、すべての項目をパスしたかを返します。
+func runDoctor() bool {
 	fmt.Println("🏥 DevSync Doctor: 環境診断を開始します...")
 	fmt.Println()
 
@@ -55,7 +62,8 @@ func runDoctor() {
 
 	if cfg == nil {
 		fmt.Println("\n❌ 重大なエラー: 設定がロードできないため、以降のチェックを中断します")
-		os.Exit(1)
+
+		return false
 	}
 
 	fmt.Println("\n🛠️  基本ツール:")
@@ -95,6 +103,10 @@ func runDoctor() {
 			allPassed = false
 		} else {
 			printResult(true, "環境変数 BW_SESSION が設定されています")
+
+			if !reportSecretAudit(secretAuditPolicy(cfg)) {
+				allPassed = false
+			}
 		}
 	} else {
 		fmt.Println("   ⚪ スキップ (設定で無効化されています)")
@@ -106,8 +118,42 @@ func runDoctor() {
 		color.Green("✅ すべての診断項目をパスしました！準備完了です。")
 	} else {
 		color.Red("❌ 一部の項目で問題が見つかりました。ログを確認してください。")
-		os.Exit(1)
 	}
+
+	return allPassed
+}
+
+// reportSecretAudit はシークレットの期限・ローテーション状態を表示します。
+// 期限切れの項目がある場合のみ false を返し、期限間近・ローテーション推奨は警告に留めます。
+func reportSecretAudit(policy secret.AuditPolicy) bool {
+	entries, err := doctorSecretAuditFunc(policy)
+	if err != nil {
+		printWarning(fmt.Sprintf("シークレットの期限を確認できませんでした: %v", err))
+		return true
+	}
+
+	warnings := secret.FilterAuditWarnings(entries)
+	if len(warnings) == 0 {
+		printResult(true, fmt.Sprintf("シークレットの期限・ローテーション: %d 件すべて問題ありません", len(entries)))
+		return true
+	}
+
+	ok := true
+
+	for _, entry := range warnings {
+		message := fmt.Sprintf("%s: %s（%s）", entry.Name, secret.AuditStatusLabel(entry.Status), entry.Message)
+		if entry.Status == secret.AuditStatusExpired {
+			printResult(false, message)
+
+			ok = false
+
+			continue
+		}
+
+		printWarning(message)
+	}
+
+	return ok
 }
 
 func checkCommand(name string) error {
@@ -123,6 +169,10 @@ func printResult(ok bool, message string) {
 	}
 }
 
+func printWarning(message string) {
+	color.Yellow("  ⚠️  %s", message)
+}
+
 func buildDoctorConfigStatusMessage(configExists bool, configPath string) string {
 	if configExists {
 		return fmt.Sprintf("設定ファイルを読み込みました: %s", configPath)
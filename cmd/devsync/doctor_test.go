@@ -1,8 +1,11 @@
 package main
 
 import (
+	"errors"
 	"strings"
 	"testing"
+
+	"github.com/scottlz0310/devsync/internal/secret"
 )
 
 func TestBuildDoctorConfigStatusMessage(t *testing.T) {
@@ -44,3 +47,35 @@ func TestBuildDoctorConfigStatusMessage(t *testing.T) {
 		})
 	}
 }
+
+func TestReportSecretAudit(t *testing.T) {
+	testCases := []struct {
+		name    string
+		entries []secret.AuditEntry
+		err     error
+		want    bool
+	}{
+		{name: "問題なし", entries: []secret.AuditEntry{{Name: "env:A", Status: secret.AuditStatusOK}}, want: true},
+		{name: "期限間近は警告のみ", entries: []secret.AuditEntry{{Name: "env:A", Status: secret.AuditStatusExpiring}}, want: true},
+		{name: "ローテーション推奨は警告のみ", entries: []secret.AuditEntry{{Name: "env:A", Status: secret.AuditStatusRotationDue}}, want: true},
+		{name: "期限切れは失敗", entries: []secret.AuditEntry{{Name: "env:A", Status: secret.AuditStatusExpired}}, want: false},
+		{name: "取得失敗は警告のみ", err: errors.New("locked"), want: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			original := doctorSecretAuditFunc
+			doctorSecretAuditFunc = func(secret.AuditPolicy) ([]secret.AuditEntry, error) {
+				return tc.entries, tc.err
+			}
+
+			t.Cleanup(func() {
+				doctorSecretAuditFunc = original
+			})
+
+			if got := reportSecretAudit(secret.AuditPolicy{}); got != tc.want {
+				t.Fatalf("reportSecretAudit() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
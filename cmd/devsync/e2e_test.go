@@ -52,6 +52,7 @@ func resetGlobalFlags(t *testing.T) {
 	sysTimeout = "10m"
 	sysTUI = false
 	sysNoTUI = false
+	sysApplyPrune = false
 
 	// repo update のグローバル変数
 	repoRootOverride = ""
@@ -61,6 +62,16 @@ func resetGlobalFlags(t *testing.T) {
 	repoUpdateNoSubmodule = false
 	repoUpdateTUI = false
 	repoUpdateNoTUI = false
+
+	// --wait / --no-wait のグローバル変数
+	lockWait = false
+	lockNoWait = false
+
+	// env export のグローバル変数
+	envExportFormat = ""
+	envAuditSync = false
+	envAuditWarnDays = 0
+	envAuditRotationDays = 0
 }
 
 // executeRootCommand は rootCmd にコマンドライン引数を設定して実行する。
@@ -159,6 +170,19 @@ func TestSysUpdate_DryRun(t *testing.T) {
 	}
 }
 
+func TestSysCheck_NoManagers_ExitZero(t *testing.T) {
+	setupEmptyConfig(t)
+
+	stdout, _, err := executeRootCommand(t, "sys", "check")
+	if err != nil {
+		t.Fatalf("sys check with no managers should exit 0, got error: %v", err)
+	}
+
+	if !strings.Contains(stdout, "有効化されたマネージャがありません") {
+		t.Fatalf("stdout should contain no-manager help, got: %q", stdout)
+	}
+}
+
 // --- repo update E2E テスト ---
 
 func TestRepoUpdate_NoRepos_ExitZero(t *testing.T) {
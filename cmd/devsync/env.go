@@ -3,9 +3,14 @@ package main
 import (
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"strconv"
+	"strings"
+	"text/tabwriter"
 
+	"github.com/scottlz0310/devsync/internal/config"
 	"github.com/scottlz0310/devsync/internal/secret"
 	"github.com/spf13/cobra"
 )
@@ -23,15 +28,50 @@ var envExportCmd = &cobra.Command{
 
 使用方法:
   bash/zsh:    eval "$(devsync env export)"
+  fish:        devsync env export --format fish | source
+  nushell:     devsync env export --format nushell | save -f ~/.devsync-env.nu
   PowerShell:  & devsync env export | Invoke-Expression
+  cmd.exe:     devsync env export --format cmd > env.cmd && call env.cmd
+
+ファイル/ツール向けの形式:
+  dotenv:      devsync env export --format dotenv > .env
+  docker:      devsync env export --format docker > app.env（docker run --env-file app.env）
+  direnv:      devsync env export --format direnv > .envrc
+  json:        devsync env export --format json
+  github-env:  devsync env export --format github-env >> "$GITHUB_ENV"
+
+--format を省略した場合は現在のシェルを自動判定します。
 
 注意:
 - 環境変数名は大文字とアンダースコアのみ（例: MY_VAR, API_KEY）
-- 改行を含む値はサポートされません
+- 改行を含む値は json / github-env 形式以外ではサポートされません
 - eval前提の出力のため、安全なクオート/エスケープを保証します`,
 	RunE: runEnvExport,
 }
 
+var envExportFormat string
+
+var envAuditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "シークレットの有効期限とローテーション状態を一覧表示",
+	Long: `Bitwarden の env: 項目について、項目名・最終更新からの経過日数・状態を一覧表示します。
+シークレットの値は表示しません。
+
+判定基準:
+  - カスタムフィールド "expires"（例: 2026-12-31）がある項目は、期限切れ・期限間近を判定します
+  - secrets.rotation_days を設定すると、最終更新からその日数を超えた項目をローテーション推奨とします
+
+基準日数は config.yaml の secrets.expiry_warning_days / secrets.rotation_days、
+またはフラグで指定できます。`,
+	RunE: runEnvAudit,
+}
+
+var (
+	envAuditSync         bool
+	envAuditWarnDays     int
+	envAuditRotationDays int
+)
+
 var envRunCmd = &cobra.Command{
 	Use:   "run [command...]",
 	Short: "環境変数を注入してコマンドを実行",
@@ -39,9 +79,21 @@ var envRunCmd = &cobra.Command{
 
 これは eval を使わずに環境変数を利用する安全な方法です。
 
+引数に含まれるシークレット参照（'${secret:bitwarden/項目名/フィールド}' または
+'bw://項目名/フィールド'）は実行直前に Bitwarden の値へ置換されます。
+シェルに展開されないよう、参照は単一引用符で囲んでください。
+
+オプション（コマンドより前に指定）:
+  --mask      子プロセスの stdout/stderr に含まれるシークレット値
+              （base64 / URL エンコード表現を含む）を *** に置換します
+  --no-mask   設定 secrets.mask_output: true を一時的に無効化します
+  --          以降をすべて実行するコマンドとして扱います
+
 使用例:
   devsync env run npm run build
-  devsync env run go test ./...`,
+  devsync env run go test ./...
+  devsync env run --mask -- ./scripts/deploy.sh
+  devsync env run curl -H 'Authorization: Bearer ${secret:bitwarden/GitHub/token}' https://api.github.com/user`,
 	RunE:               runEnvRun,
 	DisableFlagParsing: true, // コマンド引数をそのまま渡す
 }
@@ -50,17 +102,35 @@ func init() {
 	rootCmd.AddCommand(envCmd)
 	envCmd.AddCommand(envExportCmd)
 	envCmd.AddCommand(envRunCmd)
+	envCmd.AddCommand(envAuditCmd)
+
+	envExportCmd.Flags().StringVar(&envExportFormat, "format", "", "出力形式（"+strings.Join(secret.SupportedExportFormats(), ", ")+"）。省略時はシェルを自動判定")
+
+	envAuditCmd.Flags().BoolVar(&envAuditSync, "sync", false, "一覧表示の前に Bitwarden サーバーと同期する")
+	envAuditCmd.Flags().IntVar(&envAuditWarnDays, "warn-days", 0, "期限間近とみなす残り日数（0 で無効、省略時は secrets.expiry_warning_days）")
+	envAuditCmd.Flags().IntVar(&envAuditRotationDays, "rotation-days", 0, "ローテーション推奨とみなす経過日数（0 で無効、省略時は secrets.rotation_days）")
 }
 
 func runEnvExport(cmd *cobra.Command, args []string) error {
-	// Bitwardenから環境変数を取得
-	envVars, err := secret.GetEnvVars()
+	shellType := secret.DetectShell()
+
+	if envExportFormat != "" {
+		parsed, err := secret.ParseExportFormat(envExportFormat)
+		if err != nil {
+			return err
+		}
+
+		shellType = parsed
+	}
+
+	// Bitwardenから環境変数を取得（期限・ローテーションの監査結果も同時に取得）
+	envVars, auditEntries, err := secret.GetEnvVarsWithAudit(secretAuditPolicy(config.Get()))
 	if err != nil {
 		return fmt.Errorf("環境変数の取得に失敗しました: %w", err)
 	}
 
 	// シェル用の形式でフォーマット
-	output, err := secret.ExportFormat(envVars)
+	output, err := secret.FormatForShell(envVars, shellType)
 	if err != nil {
 		return fmt.Errorf("エクスポート形式の生成に失敗しました: %w", err)
 	}
@@ -71,10 +141,126 @@ func runEnvExport(cmd *cobra.Command, args []string) error {
 	// 統計情報を stderr に出力（stdout は eval/Invoke-Expression 用なので汚さない）
 	fmt.Fprintf(os.Stderr, "✅ %d 個の環境変数を読み込みました。\n", len(envVars))
 
+	printSecretAuditWarnings(os.Stderr, secret.FilterAuditWarnings(auditEntries))
+
+	return nil
+}
+
+func runEnvAudit(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		cfg = config.Default()
+	}
+
+	policy := secretAuditPolicy(cfg)
+
+	// 0 を明示した場合は判定を無効にする
+	if cmd.Flags().Changed("warn-days") {
+		if envAuditWarnDays < 0 {
+			return fmt.Errorf("--warn-days は 0 以上を指定してください: %d", envAuditWarnDays)
+		}
+
+		policy.ExpiryWarningDays = envAuditWarnDays
+	}
+
+	if cmd.Flags().Changed("rotation-days") {
+		if envAuditRotationDays < 0 {
+			return fmt.Errorf("--rotation-days は 0 以上を指定してください: %d", envAuditRotationDays)
+		}
+
+		policy.RotationDays = envAuditRotationDays
+	}
+
+	if envAuditSync {
+		if err := secret.Sync(); err != nil {
+			return err
+		}
+	}
+
+	entries, err := secret.Audit(policy)
+	if err != nil {
+		return fmt.Errorf("シークレットの監査に失敗しました: %w", err)
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("env: 項目が見つかりません")
+		return nil
+	}
+
+	if err := writeSecretAuditTable(os.Stdout, entries); err != nil {
+		return err
+	}
+
+	warnings := secret.FilterAuditWarnings(entries)
+
+	fmt.Println()
+
+	if len(warnings) == 0 {
+		fmt.Printf("✅ %d 件すべて問題ありません\n", len(entries))
+		return nil
+	}
+
+	fmt.Printf("⚠️  %d 件中 %d 件に対応が必要です\n", len(entries), len(warnings))
+
 	return nil
 }
 
+// secretAuditPolicy は設定から期限・ローテーションの判定基準を作成します。
+func secretAuditPolicy(cfg *config.Config) secret.AuditPolicy {
+	if cfg == nil {
+		return secret.AuditPolicy{ExpiryWarningDays: config.DefaultExpiryWarningDays}
+	}
+
+	return secret.AuditPolicy{
+		ExpiryWarningDays: cfg.Secrets.ExpiryWarningDays,
+		RotationDays:      cfg.Secrets.RotationDays,
+	}
+}
+
+func writeSecretAuditTable(output io.Writer, entries []secret.AuditEntry) error {
+	writer := tabwriter.NewWriter(output, 0, 8, 2, ' ', 0)
+
+	if _, err := fmt.Fprintln(writer, "項目\t経過日数\t期限\t状態\t詳細"); err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintln(writer, "----\t--------\t----\t----\t----"); err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		age := "-"
+		if entry.AgeDays >= 0 {
+			age = strconv.Itoa(entry.AgeDays)
+		}
+
+		expires := "-"
+		if !entry.Expires.IsZero() {
+			expires = entry.Expires.Format("2006-01-02")
+		}
+
+		if _, err := fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n",
+			entry.Name, age, expires, secret.AuditStatusLabel(entry.Status), entry.Message); err != nil {
+			return err
+		}
+	}
+
+	return writer.Flush()
+}
+
+// printSecretAuditWarnings は警告対象のシークレットを1行ずつ表示します（値は表示しません）。
+func printSecretAuditWarnings(output io.Writer, warnings []secret.AuditEntry) {
+	for _, entry := range warnings {
+		fmt.Fprintf(output, "⚠️  %s: %s（%s）\n", entry.Name, secret.AuditStatusLabel(entry.Status), entry.Message)
+	}
+
+	if len(warnings) > 0 {
+		fmt.Fprintln(output, "💡 詳細は 'devsync env audit' で確認できます。")
+	}
+}
+
 func runEnvRun(cmd *cobra.Command, args []string) error {
+	args, maskOverride := parseEnvRunArgs(args)
 	if len(args) == 0 {
 		return fmt.Errorf("実行するコマンドを指定してください")
 	}
@@ -85,8 +271,19 @@ func runEnvRun(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("環境変数の取得に失敗しました: %w", err)
 	}
 
+	// 引数内のシークレット参照を解決
+	expandedArgs, err := secret.ExpandArgs(commandContext(cmd), args)
+	if err != nil {
+		return fmt.Errorf("シークレット参照の解決に失敗しました: %w", err)
+	}
+
+	opts := secret.RunOptions{
+		MaskOutput: resolveEnvRunMask(maskOverride),
+		MaskValues: secret.ResolvedValues(),
+	}
+
 	// 環境変数を注入してコマンドを実行
-	if err := secret.RunWithEnv(args, envVars); err != nil {
+	if err := secret.RunWithEnvOptions(expandedArgs, envVars, opts); err != nil {
 		// コマンドの終了コードを取得して終了
 		var exitErr *exec.ExitError
 		if errors.As(err, &exitErr) {
@@ -98,3 +295,42 @@ func runEnvRun(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// parseEnvRunArgs はコマンドより前に置かれた env run 自身のオプションを取り除きます。
+// DisableFlagParsing のため cobra ではなくここで解釈し、マスク指定がなければ nil を返します。
+func parseEnvRunArgs(args []string) ([]string, *bool) {
+	var mask *bool
+
+	for len(args) > 0 {
+		switch args[0] {
+		case "--mask":
+			enabled := true
+			mask = &enabled
+		case "--no-mask":
+			disabled := false
+			mask = &disabled
+		case "--":
+			return args[1:], mask
+		default:
+			return args, mask
+		}
+
+		args = args[1:]
+	}
+
+	return args, mask
+}
+
+// resolveEnvRunMask はフラグ指定を優先し、未指定なら設定 secrets.mask_output を参照します。
+func resolveEnvRunMask(override *bool) bool {
+	if override != nil {
+		return *override
+	}
+
+	cfg := config.Get()
+	if cfg == nil {
+		return false
+	}
+
+	return cfg.Secrets.MaskOutput
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/scottlz0310/devsync/internal/config"
+	"github.com/scottlz0310/devsync/internal/secret"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEnvRunArgs(t *testing.T) {
+	enabled, disabled := true, false
+
+	tests := []struct {
+		name     string
+		args     []string
+		wantArgs []string
+		wantMask *bool
+	}{
+		{name: "オプションなし", args: []string{"npm", "test"}, wantArgs: []string{"npm", "test"}},
+		{name: "--mask", args: []string{"--mask", "npm", "test"}, wantArgs: []string{"npm", "test"}, wantMask: &enabled},
+		{name: "--no-mask", args: []string{"--no-mask", "npm"}, wantArgs: []string{"npm"}, wantMask: &disabled},
+		{name: "-- 以降はコマンド", args: []string{"--mask", "--", "--mask"}, wantArgs: []string{"--mask"}, wantMask: &enabled},
+		{name: "コマンド後の --mask は子プロセスの引数", args: []string{"echo", "--mask"}, wantArgs: []string{"echo", "--mask"}},
+		{name: "オプションのみ", args: []string{"--mask"}, wantArgs: []string{}, wantMask: &enabled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotArgs, gotMask := parseEnvRunArgs(tt.args)
+			assert.Equal(t, tt.wantArgs, gotArgs)
+			assert.Equal(t, tt.wantMask, gotMask)
+		})
+	}
+}
+
+func TestResolveEnvRunMask(t *testing.T) {
+	setupEmptyConfig(t)
+
+	enabled, disabled := true, false
+
+	t.Run("設定で有効化", func(t *testing.T) {
+		t.Setenv("DEVSYNC_SECRETS_MASK_OUTPUT", "true")
+		_, err := config.Load()
+		require.NoError(t, err)
+
+		assert.True(t, resolveEnvRunMask(nil))
+		assert.False(t, resolveEnvRunMask(&disabled), "--no-mask が設定より優先される")
+	})
+
+	t.Run("既定は無効", func(t *testing.T) {
+		_, err := config.Load()
+		require.NoError(t, err)
+
+		assert.False(t, resolveEnvRunMask(nil))
+		assert.True(t, resolveEnvRunMask(&enabled))
+	})
+}
+
+func TestWriteSecretAuditTable(t *testing.T) {
+	entries := []secret.AuditEntry{
+		{
+			Name:    "env:GITHUB_TOKEN",
+			AgeDays: 12,
+			Expires: time.Date(2026, 11, 1, 23, 59, 59, 0, time.UTC),
+			Status:  secret.AuditStatusExpiring,
+			Message: "あと 13 日で期限切れです（2026-11-01）",
+		},
+		{Name: "env:NO_REVISION", AgeDays: -1, Status: secret.AuditStatusOK},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, writeSecretAuditTable(&buf, entries))
+
+	output := buf.String()
+	assert.Contains(t, output, "項目")
+	assert.Contains(t, output, "env:GITHUB_TOKEN")
+	assert.Contains(t, output, "2026-11-01")
+	assert.Contains(t, output, "期限間近")
+	assert.Regexp(t, `env:NO_REVISION\s+-\s+-\s+OK`, output)
+}
+
+func TestPrintSecretAuditWarnings(t *testing.T) {
+	var buf bytes.Buffer
+
+	printSecretAuditWarnings(&buf, nil)
+	assert.Empty(t, buf.String())
+
+	printSecretAuditWarnings(&buf, []secret.AuditEntry{
+		{Name: "env:OLD", Status: secret.AuditStatusRotationDue, Message: "最終更新から 200 日経過しています（ローテーション基準: 90 日）"},
+	})
+	assert.Contains(t, buf.String(), "env:OLD: ローテーション推奨")
+	assert.Contains(t, buf.String(), "devsync env audit")
+}
+
+func TestSecretAuditPolicy(t *testing.T) {
+	assert.Equal(t, secret.AuditPolicy{ExpiryWarningDays: config.DefaultExpiryWarningDays}, secretAuditPolicy(nil))
+
+	cfg := config.Default()
+	cfg.Secrets.RotationDays = 90
+
+	assert.Equal(t, secret.AuditPolicy{ExpiryWarningDays: 14, RotationDays: 90}, secretAuditPolicy(cfg))
+
+	cfg.Secrets.ExpiryWarningDays = 0
+	assert.Equal(t, secret.AuditPolicy{RotationDays: 90}, secretAuditPolicy(cfg), "0 は期限間近の警告を無効にする")
+}
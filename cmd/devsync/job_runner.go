@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/scottlz0310/devsync/internal/runner"
 	progressui "github.com/scottlz0310/devsync/internal/tui"
@@ -50,6 +51,8 @@ func runJobsWithOptionalTUI(ctx context.Context, title string, jobs int, execJob
 		logger.WriteSummary(summary)
 	}
 
+	recordRunJobs(strings.TrimSuffix(title, " 進捗"), summary)
+
 	return summary
 }
 
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/scottlz0310/devsync/internal/config"
+	"github.com/scottlz0310/devsync/internal/lock"
+	"github.com/spf13/cobra"
+)
+
+// sys update / sys apply / repo update / repo cleanup / run で共通の --wait / --no-wait フラグ
+var (
+	lockWait   bool
+	lockNoWait bool
+)
+
+// addLockFlags は多重起動時の動作を指定するフラグを追加します。
+func addLockFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&lockWait, "wait", false, "別の devsync が実行中の場合、終了を待ってから実行（対話端末での既定）")
+	cmd.Flags().BoolVar(&lockNoWait, "no-wait", false, "別の devsync が実行中の場合、待たずにエラー終了（非対話時の既定）")
+}
+
+// resolveLockWait はロックの解放を待つかを返します。
+// 未指定の場合、対話端末では待機し、スケジュール実行などの非対話時は待たずにエラーとします。
+func resolveLockWait(cmd *cobra.Command) (bool, error) {
+	wait := cmd.Flags().Changed("wait") && lockWait
+	noWait := cmd.Flags().Changed("no-wait") && lockNoWait
+
+	switch {
+	case wait && noWait:
+		return false, errors.New("--wait と --no-wait は同時指定できません")
+	case wait:
+		return true, nil
+	case noWait:
+		return false, nil
+	default:
+		return isTerminal(os.Stdin), nil
+	}
+}
+
+// lockDir はロックファイルの配置先です（状態ディレクトリの locks）。
+func lockDir() (string, error) {
+	stateDir, err := config.StateDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(stateDir, "locks"), nil
+}
+
+// repoLockPath はリポジトリ単位のロックファイルのパスを返します。
+// 同名のリポジトリを区別するため、ファイル名には絶対パスのハッシュを含めます。
+func repoLockPath(dir, repoPath string) string {
+	if abs, err := filepath.Abs(repoPath); err == nil {
+		repoPath = abs
+	}
+
+	sum := sha256.Sum256([]byte(filepath.Clean(repoPath)))
+	name := strings.Trim(filepath.Base(repoPath), ".")
+
+	return filepath.Join(dir, "repos", fmt.Sprintf("%s-%s.lock", name, hex.EncodeToString(sum[:6])))
+}
+
+// acquireCommandLock は devsync 全体のロックを取得し、解放する関数を返します。
+// run から呼ばれる sys update などは、同じプロセスが保持しているロックをそのまま使います。
+func acquireCommandLock(cmd *cobra.Command) (release func(), err error) {
+	wait, err := resolveLockWait(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := lockDir()
+	if err != nil {
+		return nil, fmt.Errorf("ロックの取得に失敗: %w", err)
+	}
+
+	l, err := lock.Acquire(commandContext(cmd), filepath.Join(dir, "devsync.lock"), lock.Options{
+		Command: cmd.CommandPath(),
+		Wait:    wait,
+		OnWait: func(holder lock.Info) {
+			fmt.Fprintf(os.Stderr, "⏳ 別の devsync（PID %d: %s）が実行中のため、終了を待っています...\n", holder.PID, holder.Command)
+		},
+	})
+	if err != nil {
+		return nil, wrapLockError(err)
+	}
+
+	return func() { releaseLock(l) }, nil
+}
+
+// acquireRepoLock はリポジトリ単位のロックを取得します。
+// 同じリポジトリを操作している別のプロセスがあれば、終了を待ってから処理します。
+func acquireRepoLock(ctx context.Context, repoPath string, onWait func(holder lock.Info)) (release func(), err error) {
+	dir, err := lockDir()
+	if err != nil {
+		return nil, fmt.Errorf("ロックの取得に失敗: %w", err)
+	}
+
+	l, err := lock.Acquire(ctx, repoLockPath(dir, repoPath), lock.Options{
+		Command: strings.Join(os.Args, " "),
+		Wait:    true,
+		OnWait:  onWait,
+	})
+	if err != nil {
+		return nil, wrapLockError(err)
+	}
+
+	return func() { releaseLock(l) }, nil
+}
+
+func wrapLockError(err error) error {
+	var lockedErr *lock.LockedError
+	if !errors.As(err, &lockedErr) {
+		return fmt.Errorf("ロックの取得に失敗: %w", err)
+	}
+
+	holder := lockedErr.Holder
+
+	return fmt.Errorf("別の devsync が実行中です（PID %d: %s、%s から実行中）。終了を待つには --wait を指定してください（プロセスが残っていない場合は %s を削除してください）",
+		holder.PID, holder.Command, holder.StartedAt.Local().Format("2006-01-02 15:04:05"), lockedErr.Path)
+}
+
+func releaseLock(l *lock.Lock) {
+	if err := l.Release(); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
+	}
+}
+
+// runWithRepoLock はリポジトリ単位のロックを保持したまま fn を実行します。
+func runWithRepoLock(ctx context.Context, repoPath string, onWait func(holder lock.Info), fn func() error) error {
+	release, err := acquireRepoLock(ctx, repoPath, onWait)
+	if err != nil {
+		return err
+	}
+
+	defer release()
+
+	return fn()
+}
+
+// repoLockWaitNotice はリポジトリのロック待ちを通知する関数を返します（TUI 使用時は表示しない）。
+func repoLockWaitNotice(repoName string, useTUI bool) func(holder lock.Info) {
+	if useTUI {
+		return nil
+	}
+
+	return func(holder lock.Info) {
+		fmt.Printf("⏳ %s: 別のプロセス（PID %d: %s）が操作中のため、終了を待っています...\n", repoName, holder.PID, holder.Command)
+	}
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scottlz0310/devsync/internal/lock"
+	"github.com/spf13/cobra"
+)
+
+// writeHeldLock は別のプロセス（テストを実行している親プロセス）が保持しているロックファイルを作成します。
+func writeHeldLock(t *testing.T, path, command string) {
+	t.Helper()
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(lock.Info{PID: os.Getppid(), Command: command, StartedAt: time.Now(), Hostname: hostname})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestResolveLockWait(t *testing.T) {
+	testCases := []struct {
+		name    string
+		args    []string
+		want    bool
+		wantErr bool
+	}{
+		{name: "未指定は対話端末でのみ待機（テストは非対話）", args: nil, want: false},
+		{name: "--wait", args: []string{"--wait"}, want: true},
+		{name: "--no-wait", args: []string{"--no-wait"}, want: false},
+		{name: "--wait=false は未指定と同じ", args: []string{"--wait=false"}, want: false},
+		{name: "同時指定はエラー", args: []string{"--wait", "--no-wait"}, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			origWait, origNoWait := lockWait, lockNoWait
+
+			t.Cleanup(func() {
+				lockWait, lockNoWait = origWait, origNoWait
+			})
+
+			cmd := &cobra.Command{Use: "update"}
+			addLockFlags(cmd)
+
+			if err := cmd.ParseFlags(tc.args); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := resolveLockWait(cmd)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("resolveLockWait() expected error")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("resolveLockWait() unexpected error: %v", err)
+			}
+
+			if got != tc.want {
+				t.Fatalf("resolveLockWait() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRepoLockPath(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join("state", "locks")
+	a := repoLockPath(dir, filepath.Join("work", "a", "devsync"))
+	b := repoLockPath(dir, filepath.Join("work", "b", "devsync"))
+
+	if a == b {
+		t.Fatalf("同名の別リポジトリのロックが同じパスです: %s", a)
+	}
+
+	if filepath.Dir(a) != filepath.Join(dir, "repos") || !strings.HasPrefix(filepath.Base(a), "devsync-") || !strings.HasSuffix(a, ".lock") {
+		t.Fatalf("repoLockPath() = %s, want %s/devsync-<hash>.lock", a, filepath.Join(dir, "repos"))
+	}
+
+	if again := repoLockPath(dir, filepath.Join("work", "a", ".", "devsync")); again != a {
+		t.Fatalf("repoLockPath() = %s, want %s（同じリポジトリは同じパス）", again, a)
+	}
+}
+
+func TestSysUpdate_LockHeldByAnotherProcess(t *testing.T) {
+	home := setupEmptyConfig(t)
+	lockPath := filepath.Join(home, ".local", "state", "devsync", "locks", "devsync.lock")
+	writeHeldLock(t, lockPath, "devsync run")
+
+	_, _, err := executeRootCommand(t, "sys", "update", "--no-wait")
+	if err == nil || !strings.Contains(err.Error(), "別の devsync が実行中です") || !strings.Contains(err.Error(), "devsync run") {
+		t.Fatalf("sys update error = %v, want lock error", err)
+	}
+
+	// 保持しているプロセスのロックは残る
+	if _, statErr := os.Stat(lockPath); statErr != nil {
+		t.Fatalf("lock file should remain: %v", statErr)
+	}
+}
+
+func TestSysApply_LockHeldByAnotherProcess(t *testing.T) {
+	home := setupEmptyConfig(t)
+	lockPath := filepath.Join(home, ".local", "state", "devsync", "locks", "devsync.lock")
+	writeHeldLock(t, lockPath, "devsync run")
+
+	_, _, err := executeRootCommand(t, "sys", "apply", "--no-wait")
+	if err == nil || !strings.Contains(err.Error(), "別の devsync が実行中です") {
+		t.Fatalf("sys apply error = %v, want lock error", err)
+	}
+}
+
+func TestRunDaily_TakesLockForAllPhases(t *testing.T) {
+	setupRunPhasesConfig(t, "")
+
+	calls := stubRunPhaseSteps(t, nil)
+
+	var sawLock bool
+
+	runSysUpdateStep = func(cmd *cobra.Command, _ []string) error {
+		*calls = append(*calls, "sys_update")
+
+		dir, err := lockDir()
+		if err != nil {
+			return err
+		}
+
+		info, err := lock.ReadInfo(filepath.Join(dir, "devsync.lock"))
+		if err != nil {
+			return err
+		}
+
+		sawLock = info.PID == os.Getpid()
+
+		// run から呼ばれたフェーズは run のロックをそのまま使う
+		release, err := acquireCommandLock(cmd)
+		if err != nil {
+			return err
+		}
+
+		release()
+
+		return nil
+	}
+
+	if err := runDaily(&cobra.Command{Use: "run"}, nil); err != nil {
+		t.Fatalf("runDaily() unexpected error: %v", err)
+	}
+
+	if !sawLock {
+		t.Fatal("sys フェーズの実行中に run のロックが取得されていません")
+	}
+
+	dir, err := lockDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "devsync.lock")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("run の終了後もロックが残っています: %v", err)
+	}
+}
+
+func TestRunWithRepoLock_WaitsForOtherProcess(t *testing.T) {
+	home := t.TempDir()
+	setupRunPhasesConfig(t, "")
+	t.Setenv("XDG_STATE_HOME", home)
+
+	repoPath := filepath.Join(t.TempDir(), "repo")
+	lockPath := repoLockPath(filepath.Join(home, "devsync", "locks"), repoPath)
+	writeHeldLock(t, lockPath, "devsync repo update")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	var waited, ran bool
+
+	err := runWithRepoLock(ctx, repoPath, func(lock.Info) { waited = true }, func() error {
+		ran = true
+		return nil
+	})
+	if err == nil || !waited || ran {
+		t.Fatalf("runWithRepoLock() = %v (waited=%v, ran=%v), want wait until canceled", err, waited, ran)
+	}
+
+	if err := os.Remove(lockPath); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := runWithRepoLock(context.Background(), repoPath, nil, func() error {
+		ran = true
+		return nil
+	}); err != nil || !ran {
+		t.Fatalf("runWithRepoLock() = %v (ran=%v), want success", err, ran)
+	}
+}
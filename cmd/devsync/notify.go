@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/scottlz0310/devsync/internal/config"
+	"github.com/scottlz0310/devsync/internal/notify"
+	"github.com/scottlz0310/devsync/internal/runner"
+)
+
+var sendNotificationsStep = notify.Send
+
+// run の実行中に各フェーズの結果を記録する Report（run 以外から呼ばれた場合は nil）
+var (
+	runReportMu     sync.Mutex
+	activeRunReport *notify.Report
+)
+
+// beginRunReport は run の結果の記録を開始し、記録を終了する関数とともに Report を返します。
+func beginRunReport() (report *notify.Report, end func()) {
+	report = notify.NewReport("devsync run", time.Now())
+
+	runReportMu.Lock()
+	activeRunReport = report
+	runReportMu.Unlock()
+
+	return report, func() {
+		runReportMu.Lock()
+		activeRunReport = nil
+		runReportMu.Unlock()
+	}
+}
+
+// recordRunJobs は run の実行中であれば、ジョブ実行の集計を結果に追加します。
+func recordRunJobs(name string, summary runner.Summary) {
+	runReportMu.Lock()
+	defer runReportMu.Unlock()
+
+	if activeRunReport != nil && summary.Total > 0 {
+		activeRunReport.AddJobs(name, summary)
+	}
+}
+
+// recordRunUpdates は run の実行中であれば、更新したパッケージの数を結果に加算します。
+func recordRunUpdates(updated int) {
+	runReportMu.Lock()
+	defer runReportMu.Unlock()
+
+	if activeRunReport != nil {
+		activeRunReport.Updated += updated
+	}
+}
+
+// appendJobResult はジョブの結果を集計に追加します（runner を使わずに順に実行する場合に使用）。
+func appendJobResult(summary *runner.Summary, result runner.Result) {
+	summary.Total++
+
+	switch result.Status {
+	case runner.StatusSuccess:
+		summary.Success++
+	case runner.StatusFailed:
+		summary.Failed++
+	case runner.StatusSkipped:
+		summary.Skipped++
+	}
+
+	summary.Results = append(summary.Results, result)
+}
+
+// finishRunReport は各フェーズのエラーを結果に反映します。
+func finishRunReport(report *notify.Report, phaseErrors []phaseError) {
+	report.FinishedAt = time.Now()
+	report.Success = len(phaseErrors) == 0
+
+	for _, pe := range phaseErrors {
+		report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", pe.Name, pe.Err))
+	}
+}
+
+// notifyRunResult は notify の設定に従って run の結果を通知します。
+// 通知の失敗は警告にとどめ、run の終了コードには影響させません。
+func notifyRunResult(ctx context.Context, cfg *config.Config, report *notify.Report, dryRun bool) {
+	if !notify.Enabled(cfg.Notify) {
+		return
+	}
+
+	if dryRun {
+		fmt.Println("ℹ️  DryRun のため通知は送信しません")
+		return
+	}
+
+	sent, err := sendNotificationsStep(ctx, cfg.Notify, report)
+	if sent > 0 {
+		fmt.Printf("📣 通知を送信しました（%d 件）\n", sent)
+	}
+
+	if err != nil {
+		for _, e := range splitJoinedErrors(err) {
+			fmt.Fprintf(os.Stderr, "⚠️  通知の送信に失敗: %v\n", e)
+		}
+	}
+}
+
+// splitJoinedErrors は errors.Join でまとめたエラーを個々のエラーに分けます。
+func splitJoinedErrors(err error) []error {
+	var joined interface{ Unwrap() []error }
+	if errors.As(err, &joined) {
+		return joined.Unwrap()
+	}
+
+	return []error{err}
+}
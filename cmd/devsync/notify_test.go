@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scottlz0310/devsync/internal/config"
+	"github.com/scottlz0310/devsync/internal/notify"
+	"github.com/scottlz0310/devsync/internal/runner"
+	"github.com/scottlz0310/devsync/internal/updater"
+	"github.com/spf13/cobra"
+)
+
+const testNotifySection = `notify:
+  on: always
+  webhooks:
+    - url: https://hooks.example.com/devsync
+`
+
+// stubSendNotifications は通知の送信を差し替え、送信しようとした Report を記録します。
+func stubSendNotifications(t *testing.T, sendErr error) *[]*notify.Report {
+	t.Helper()
+
+	original := sendNotificationsStep
+	t.Cleanup(func() { sendNotificationsStep = original })
+
+	reports := make([]*notify.Report, 0, 1)
+	sendNotificationsStep = func(_ context.Context, cfg config.NotifyConfig, report *notify.Report) (int, error) {
+		if len(cfg.Webhooks) != 1 {
+			t.Errorf("notify.webhooks = %#v, want 1 entry", cfg.Webhooks)
+		}
+
+		reports = append(reports, report)
+
+		return 1, sendErr
+	}
+
+	return &reports
+}
+
+func TestRunDaily_NotifiesRunResult(t *testing.T) {
+	setupRunPhasesConfig(t, testNotifySection)
+	stubRunPhaseSteps(t, map[string]error{"repo_update": errors.New("1 件のリポジトリ更新に失敗しました")})
+
+	reports := stubSendNotifications(t, errors.New("webhooks[0]: 送信に失敗: HTTP 500"))
+
+	runSysUpdateStep = func(*cobra.Command, []string) error {
+		recordRunJobs("sys update", runner.Summary{Total: 1, Success: 1, Results: []runner.Result{{Name: "apt", Status: runner.StatusSuccess}}})
+		recordRunUpdates(3)
+
+		return nil
+	}
+
+	var err error
+
+	stderr := captureStderr(t, func() {
+		err = runDaily(&cobra.Command{Use: "run"}, nil)
+	})
+
+	// 通知の失敗は警告のみで、終了コードはフェーズの結果で決まる
+	if err == nil || !strings.Contains(err.Error(), "1 件のフェーズでエラーが発生しました") {
+		t.Fatalf("runDaily() error = %v, want phase error", err)
+	}
+
+	if !strings.Contains(stderr, "⚠️  通知の送信に失敗: webhooks[0]: 送信に失敗: HTTP 500") {
+		t.Fatalf("stderr does not contain notify warning:\n%s", stderr)
+	}
+
+	if len(*reports) != 1 {
+		t.Fatalf("notifications = %d, want 1", len(*reports))
+	}
+
+	report := (*reports)[0]
+	if report.Success || report.Updated != 3 || len(report.Jobs) != 1 || report.Jobs[0].Name != "sys update" {
+		t.Fatalf("report = %+v, want failed run with 3 updates and sys update jobs", report)
+	}
+
+	if len(report.Errors) != 1 || !strings.HasPrefix(report.Errors[0], "リポジトリ同期: ") {
+		t.Fatalf("report.Errors = %#v, want repo-update error", report.Errors)
+	}
+
+	if report.FinishedAt.Before(report.StartedAt) {
+		t.Fatalf("report.FinishedAt = %v, want after %v", report.FinishedAt, report.StartedAt)
+	}
+
+	// run の終了後は記録しない
+	recordRunUpdates(1)
+
+	if report.Updated != 3 {
+		t.Fatalf("report.Updated = %d after run, want 3", report.Updated)
+	}
+}
+
+func TestRunDaily_NoNotificationOnDryRunOrWithoutSinks(t *testing.T) {
+	testCases := []struct {
+		name    string
+		section string
+		dryRun  bool
+		wantOut string
+	}{
+		{name: "DryRun では送信しない", section: testNotifySection, dryRun: true, wantOut: "DryRun のため通知は送信しません"},
+		{name: "通知先が未設定", section: "", wantOut: ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			setupRunPhasesConfig(t, tc.section)
+			stubRunPhaseSteps(t, nil)
+
+			reports := stubSendNotifications(t, nil)
+
+			cmd := &cobra.Command{Use: "run"}
+			cmd.Flags().BoolVarP(&runDryRun, "dry-run", "n", false, "")
+
+			if tc.dryRun {
+				if err := cmd.Flags().Set("dry-run", "true"); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			output := captureStdout(t, func() {
+				if err := runDaily(cmd, nil); err != nil {
+					t.Errorf("runDaily() unexpected error: %v", err)
+				}
+			})
+
+			if len(*reports) != 0 {
+				t.Fatalf("notifications = %d, want 0", len(*reports))
+			}
+
+			if tc.wantOut != "" && !strings.Contains(output, tc.wantOut) {
+				t.Fatalf("output does not contain %q:\n%s", tc.wantOut, output)
+			}
+		})
+	}
+}
+
+func TestUpdaterJobResult(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name       string
+		result     *updater.UpdateResult
+		err        error
+		wantStatus runner.ResultStatus
+		wantErr    string
+	}{
+		{name: "成功", result: &updater.UpdateResult{UpdatedCount: 2}, wantStatus: runner.StatusSuccess},
+		{name: "実行エラー", err: errors.New("exit status 100"), wantStatus: runner.StatusFailed, wantErr: "exit status 100"},
+		{
+			name:       "一部のパッケージの更新に失敗",
+			result:     &updater.UpdateResult{FailedCount: 1, Errors: []error{errors.New("foo: 失敗")}},
+			wantStatus: runner.StatusFailed,
+			wantErr:    "foo: 失敗",
+		},
+		{name: "エラー詳細なしの失敗", result: &updater.UpdateResult{FailedCount: 2}, wantStatus: runner.StatusFailed, wantErr: "2 件の更新に失敗しました"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := updaterJobResult("apt", tc.result, tc.err, time.Second)
+			if got.Name != "apt" || got.Status != tc.wantStatus || got.Duration != time.Second {
+				t.Fatalf("updaterJobResult() = %+v, want status %s", got, tc.wantStatus)
+			}
+
+			if (tc.wantErr == "" && got.Err != nil) || (tc.wantErr != "" && (got.Err == nil || got.Err.Error() != tc.wantErr)) {
+				t.Fatalf("updaterJobResult().Err = %v, want %q", got.Err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -98,6 +98,7 @@ func init() {
 	repoUpdateCmd.Flags().BoolVar(&repoUpdateTUI, "tui", false, "Bubble Tea の進捗UIを表示（既定値は config.yaml の ui.tui）")
 	repoUpdateCmd.Flags().BoolVar(&repoUpdateNoTUI, "no-tui", false, "TUI 進捗表示を無効化（設定より優先）")
 	repoUpdateCmd.Flags().StringVar(&repoUpdateLogFile, "log-file", "", "ジョブ実行ログをファイルに保存")
+	addLockFlags(repoUpdateCmd)
 }
 
 func runRepoList(cmd *cobra.Command, args []string) error {
@@ -161,6 +162,13 @@ func runRepoUpdate(cmd *cobra.Command, args []string) error {
 	ctx, cancel := context.WithTimeout(baseCtx, timeout)
 	defer cancel()
 
+	release, err := acquireCommandLock(cmd)
+	if err != nil {
+		return err
+	}
+
+	defer release()
+
 	repoPaths, err := repomgr.Discover(root)
 	if err != nil {
 		return wrapRepoRootError(err, root, cmd.Flags().Changed("root"), configExists, configPath)
@@ -314,7 +322,14 @@ func buildRepoUpdateJobs(root string, repoPaths []string, opts repomgr.UpdateOpt
 		execJobs = append(execJobs, runner.Job{
 			Name: repoName,
 			Run: func(jobCtx context.Context) error {
-				updateResult, updateErr := repomgr.Update(jobCtx, repoPath, opts)
+				var updateResult *repomgr.UpdateResult
+
+				updateErr := runWithRepoLock(jobCtx, repoPath, repoLockWaitNotice(repoName, useTUI), func() error {
+					var err error
+					updateResult, err = repomgr.Update(jobCtx, repoPath, opts)
+
+					return err
+				})
 				if !useTUI {
 					outputMu.Lock()
 					printRepoUpdateResult(repoName, updateResult, updateErr)
@@ -47,6 +47,7 @@ func init() {
 	repoCleanupCmd.Flags().BoolVar(&repoCleanupTUI, "tui", false, "Bubble Tea の進捗UIを表示（既定値は config.yaml の ui.tui）")
 	repoCleanupCmd.Flags().BoolVar(&repoCleanupNoTUI, "no-tui", false, "TUI 進捗表示を無効化（設定より優先）")
 	repoCleanupCmd.Flags().StringVar(&repoCleanupLogFile, "log-file", "", "ジョブ実行ログをファイルに保存")
+	addLockFlags(repoCleanupCmd)
 }
 
 func runRepoCleanup(cmd *cobra.Command, args []string) error {
@@ -75,6 +76,13 @@ func runRepoCleanup(cmd *cobra.Command, args []string) error {
 	ctx, cancel := context.WithTimeout(baseCtx, timeout)
 	defer cancel()
 
+	release, err := acquireCommandLock(cmd)
+	if err != nil {
+		return err
+	}
+
+	defer release()
+
 	repoPaths, err := repomgr.Discover(root)
 	if err != nil {
 		return wrapRepoRootError(err, root, cmd.Flags().Changed("root"), configExists, configPath)
@@ -163,7 +171,14 @@ func buildRepoCleanupJobs(root string, repoPaths []string, opts repomgr.CleanupO
 		execJobs = append(execJobs, runner.Job{
 			Name: repoName,
 			Run: func(jobCtx context.Context) error {
-				cleanupResult, cleanupErr := runRepoCleanupJob(jobCtx, repoPath, opts)
+				var cleanupResult *repomgr.CleanupResult
+
+				cleanupErr := runWithRepoLock(jobCtx, repoPath, repoLockWaitNotice(repoName, useTUI), func() error {
+					var err error
+					cleanupResult, err = runRepoCleanupJob(jobCtx, repoPath, opts)
+
+					return err
+				})
 
 				if !useTUI {
 					outputMu.Lock()
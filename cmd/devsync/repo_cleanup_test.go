@@ -13,6 +13,7 @@ import (
 
 	repomgr "github.com/scottlz0310/devsync/internal/repo"
 	"github.com/scottlz0310/devsync/internal/runner"
+	"github.com/scottlz0310/devsync/internal/testutil"
 )
 
 func TestWantsCleanupTarget(t *testing.T) {
@@ -363,6 +364,9 @@ func TestBuildRepoCleanupJobs_NameCollisionAndPrint(t *testing.T) {
 		}, nil
 	}
 
+	// リポジトリ単位のロックをテスト用の HOME 配下に作成する
+	testutil.SetTestHome(t, t.TempDir())
+
 	root := t.TempDir()
 	repoA := filepath.Join(t.TempDir(), "repo")
 	repoB := filepath.Join(t.TempDir(), "repo")
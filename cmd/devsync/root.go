@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
@@ -23,10 +24,13 @@ var rootCmd = &cobra.Command{
 
 日次運用:
   devsync run           Bitwarden解錠→環境変数読込→システム更新を実行
+  devsync schedule      devsync run の定期実行（systemd / cron / launchd）を管理
 
 システム更新:
   devsync sys update    パッケージマネージャで一括更新
   devsync sys list      利用可能なマネージャを一覧表示
+  devsync sys check     更新可能なパッケージを確認（更新があれば終了コード 1）
+  devsync sys apply     宣言されたパッケージ一覧に環境を合わせる
 
 リポジトリ管理:
   devsync repo update   管理下のリポジトリを更新
@@ -53,10 +57,30 @@ var rootCmd = &cobra.Command{
   devsync repo cleanup -n         # 削除対象ブランチの計画を表示（DryRun）`,
 }
 
+// exitCodeError は終了コードを指定するエラーです（監視用途のコマンドで使用）。
+type exitCodeError struct {
+	code int
+	err  error
+}
+
+func (e *exitCodeError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitCodeError) Unwrap() error {
+	return e.err
+}
+
 // Execute はコマンド実行のエントリーポイントです
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
+
+		var exitErr *exitCodeError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.code)
+		}
+
 		os.Exit(1)
 	}
 }
@@ -22,6 +22,10 @@ var (
 	runTUI     bool
 	runNoTUI   bool
 	runLogFile string
+	runOnly    []string
+	runSkip    []string
+
+	runNonInteractive bool
 )
 
 // runCmd は日次処理を実行するコマンドの定義です
@@ -31,14 +35,18 @@ var runCmd = &cobra.Command{
 	Long: `設定ファイルに基づいて、システムの更新、リポジトリの同期、
 環境変数の設定などを一括で行います。毎日の作業開始時に実行することを想定しています。
 
-処理順序:
-  1. Bitwarden のアンロック（secrets.enabled=true かつ未アンロック時のみ）
-  2. Bitwarden データの同期（bw sync でキャッシュを最新化）
-  3. 環境変数の読み込み（secrets.enabled=true かつ未読み込み時のみ）
-  4. システム更新
-  5. リポジトリ同期
+処理順序（run.phases 未設定時）:
+  1. secrets: Bitwarden のアンロック・データ同期・環境変数の読み込み（secrets.enabled=true の場合のみ）
+  2. sys: システム更新
+  3. repo-update: リポジトリ同期
+
+run.phases で組み込みフェーズ（secrets / sys / repo-update / repo-cleanup / doctor）と
+独自のシェルコマンドの順序、実行条件（ホスト / WSL / 曜日）、前後のフックを設定できます。
 
-フラグ（--dry-run, --tui/--no-tui, --jobs）は sys update / repo update に伝播されます。`,
+フラグ（--dry-run, --tui/--no-tui, --jobs, --log-file）は sys update / repo update / repo cleanup に伝播されます。`,
+	Example: `  devsync run
+  devsync run --only sys           # システム更新のみ
+  devsync run --skip repo-cleanup  # ブランチ整理を除いて実行`,
 	RunE: runDaily,
 }
 
@@ -50,33 +58,46 @@ func init() {
 	runCmd.Flags().BoolVar(&runTUI, "tui", false, "Bubble Tea の進捗UIを表示（sys/repo に伝播）")
 	runCmd.Flags().BoolVar(&runNoTUI, "no-tui", false, "TUI 進捗表示を無効化（sys/repo に伝播）")
 	runCmd.Flags().StringVar(&runLogFile, "log-file", "", "ジョブ実行ログをファイルに保存（sys/repo に伝播）")
+	runCmd.Flags().BoolVar(&runNonInteractive, "non-interactive", false, "パスワード入力を求めない（未アンロックの Bitwarden と、認証情報がキャッシュされていない sudo が必要なマネージャをスキップ）")
+	runCmd.Flags().StringSliceVar(&runOnly, "only", nil, "指定したフェーズのみ実行（カンマ区切りで複数指定可）")
+	runCmd.Flags().StringSliceVar(&runSkip, "skip", nil, "指定したフェーズを除いて実行（カンマ区切りで複数指定可）")
+	addLockFlags(runCmd)
 }
 
-// propagateRunFlags は run コマンドのフラグを sys/repo のグローバルフラグ変数に伝播します。
+// propagateRunFlags は run コマンドのフラグを sys/repo（update / cleanup）のグローバルフラグ変数に伝播します。
 func propagateRunFlags(cmd *cobra.Command) {
 	if cmd.Flags().Changed("dry-run") {
 		sysDryRun = runDryRun
 		repoUpdateDryRun = runDryRun
+		repoCleanupDryRun = runDryRun
 	}
 
 	if cmd.Flags().Changed("jobs") {
 		sysJobs = runJobs
 		repoUpdateJobs = runJobs
+		repoCleanupJobs = runJobs
 	}
 
 	if cmd.Flags().Changed("tui") {
 		sysTUI = runTUI
 		repoUpdateTUI = runTUI
+		repoCleanupTUI = runTUI
 	}
 
 	if cmd.Flags().Changed("no-tui") {
 		sysNoTUI = runNoTUI
 		repoUpdateNoTUI = runNoTUI
+		repoCleanupNoTUI = runNoTUI
+	}
+
+	if cmd.Flags().Changed("non-interactive") {
+		sysNonInteractive = runNonInteractive
 	}
 
 	if cmd.Flags().Changed("log-file") {
 		sysLogFile = runLogFile
 		repoUpdateLogFile = runLogFile
+		repoCleanupLogFile = runLogFile
 	}
 }
 
@@ -99,29 +120,33 @@ func runDaily(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("--tui と --no-tui は同時指定できません")
 	}
 
-	// 1 & 2. Bitwarden のアンロック + 環境変数読み込み
-	runSecretsPhase(cfg)
-
-	var phaseErrors []phaseError
+	phases, err := buildRunPhases(cfg)
+	if err != nil {
+		return err
+	}
 
-	// 3. システム更新
-	fmt.Println("🛠  システムを更新中...")
+	phases, err = filterRunPhases(phases, runOnly, runSkip)
+	if err != nil {
+		return err
+	}
 
-	if err := runSysUpdateStep(cmd, nil); err != nil {
-		phaseErrors = append(phaseErrors, phaseError{Name: "システム更新", Err: err})
-		fmt.Fprintf(os.Stderr, "⚠️  システム更新でエラーが発生しましたが、続行します: %v\n", err)
+	// 各フェーズ（sys update / repo update など）は run が取得したロックをそのまま使う
+	release, err := acquireCommandLock(cmd)
+	if err != nil {
+		return err
 	}
 
-	fmt.Println()
+	defer release()
 
-	// 4. リポジトリ同期
-	fmt.Println("📦 リポジトリを同期中...")
+	dryRun := runDryRunEnabled(cmd, cfg)
 
-	if err := runRepoUpdateStep(cmd, nil); err != nil {
-		phaseErrors = append(phaseErrors, phaseError{Name: "リポジトリ同期", Err: err})
-	}
+	report, endReport := beginRunReport()
+	defer endReport()
 
-	fmt.Println()
+	phaseErrors := executeRunPhases(cmd, cfg, phases, dryRun)
+
+	finishRunReport(report, phaseErrors)
+	notifyRunResult(commandContext(cmd), cfg, report, dryRun)
 
 	// 統合サマリー
 	if len(phaseErrors) > 0 {
@@ -170,6 +195,14 @@ func runSecretsPhase(cfg *config.Config) {
 		return
 	}
 
+	// 非対話モード（スケジュール実行など）ではマスターパスワードを入力できないため、未アンロックならスキップする
+	if runNonInteractive && os.Getenv("BW_SESSION") == "" {
+		fmt.Println("ℹ️  非対話モードのため、Bitwarden のアンロックをスキップします（BW_SESSION 未設定）")
+		fmt.Println()
+
+		return
+	}
+
 	// シェル関数側（devsync-unlock）で BW_SESSION が既に設定済みの場合、
 	// Unlock 内部で「既にアンロック済み」と判定して bw unlock をスキップする。
 	fmt.Println("🔐 シークレットをアンロック中...")
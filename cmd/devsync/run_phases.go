@@ -0,0 +1,361 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/scottlz0310/devsync/internal/config"
+	"github.com/scottlz0310/devsync/internal/env"
+	"github.com/spf13/cobra"
+)
+
+// run の組み込みフェーズ名（run.phases の name、--only / --skip で指定）
+const (
+	runPhaseSecrets     = "secrets"
+	runPhaseSys         = "sys"
+	runPhaseRepoUpdate  = "repo-update"
+	runPhaseRepoCleanup = "repo-cleanup"
+	runPhaseDoctor      = "doctor"
+)
+
+// defaultRunPhases は run.phases 未設定時に実行するフェーズです。
+var defaultRunPhases = []string{runPhaseSecrets, runPhaseSys, runPhaseRepoUpdate}
+
+var (
+	runRepoCleanupStep = runRepoCleanup
+	runDoctorStep      = runDoctor
+	runShellStep       = runShellCommand
+	currentRunPhaseEnv = detectRunPhaseEnv
+)
+
+// runPhase は実行順に解決した run の 1 フェーズです。
+type runPhase struct {
+	Name  string
+	Label string // 表示名（エラー一覧にも使用）
+	Start string // 開始時のメッセージ（空の場合は表示しない）
+	// Builtin は組み込みフェーズの処理です。nil の場合は Command をシェルで実行します。
+	Builtin         func(cmd *cobra.Command, cfg *config.Config) error
+	Command         string
+	ContinueOnError bool
+	When            config.RunConditionConfig
+	Pre             []string
+	Post            []string
+}
+
+// runPhaseEnv はフェーズの実行条件（when）の判定に使う実行環境です。
+type runPhaseEnv struct {
+	Hostname string
+	WSL      bool
+	Now      time.Time
+}
+
+func detectRunPhaseEnv() runPhaseEnv {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = ""
+	}
+
+	return runPhaseEnv{Hostname: hostname, WSL: env.IsWSL(), Now: time.Now()}
+}
+
+// knownRunPhases は組み込みフェーズ名の集合です（config validate でも使用）。
+func knownRunPhases() map[string]struct{} {
+	return map[string]struct{}{
+		runPhaseSecrets:     {},
+		runPhaseSys:         {},
+		runPhaseRepoUpdate:  {},
+		runPhaseRepoCleanup: {},
+		runPhaseDoctor:      {},
+	}
+}
+
+// newBuiltinRunPhase は組み込みフェーズの表示名と処理を設定した runPhase を返します。
+// テストで差し替えられるよう、各処理は呼び出し時点の *Step 変数を参照します。
+func newBuiltinRunPhase(name string) runPhase {
+	switch name {
+	case runPhaseSecrets:
+		return runPhase{Name: name, Label: "シークレット", Builtin: func(_ *cobra.Command, cfg *config.Config) error {
+			runSecretsPhase(cfg)
+			return nil
+		}}
+	case runPhaseSys:
+		return runPhase{Name: name, Label: "システム更新", Start: "🛠  システムを更新中...", Builtin: func(cmd *cobra.Command, _ *config.Config) error {
+			return runSysUpdateStep(cmd, nil)
+		}}
+	case runPhaseRepoUpdate:
+		return runPhase{Name: name, Label: "リポジトリ同期", Start: "📦 リポジトリを同期中...", Builtin: func(cmd *cobra.Command, _ *config.Config) error {
+			return runRepoUpdateStep(cmd, nil)
+		}}
+	case runPhaseRepoCleanup:
+		return runPhase{Name: name, Label: "ブランチ整理", Start: "🧹 マージ済みブランチを整理中...", Builtin: func(cmd *cobra.Command, _ *config.Config) error {
+			return runRepoCleanupStep(cmd, nil)
+		}}
+	default:
+		return runPhase{Name: name, Label: "環境診断", Builtin: func(_ *cobra.Command, _ *config.Config) error {
+			if !runDoctorStep() {
+				return errors.New("診断で問題が見つかりました")
+			}
+
+			return nil
+		}}
+	}
+}
+
+// buildRunPhases は run.phases（未設定の場合は既定のフェーズ）から実行するフェーズを組み立てます。
+func buildRunPhases(cfg *config.Config) ([]runPhase, error) {
+	if len(cfg.Run.Phases) == 0 {
+		phases := make([]runPhase, 0, len(defaultRunPhases))
+		for _, name := range defaultRunPhases {
+			phase := newBuiltinRunPhase(name)
+			phase.ContinueOnError = true
+			phases = append(phases, phase)
+		}
+
+		return phases, nil
+	}
+
+	builtin := knownRunPhases()
+	phases := make([]runPhase, 0, len(cfg.Run.Phases))
+	seen := make(map[string]struct{}, len(cfg.Run.Phases))
+
+	for i, pc := range cfg.Run.Phases {
+		if err := config.ValidateRunPhase(pc, builtin); err != nil {
+			return nil, fmt.Errorf("run.phases[%d]: %w", i, err)
+		}
+
+		name := strings.TrimSpace(pc.Name)
+		if _, ok := seen[name]; ok {
+			return nil, fmt.Errorf("run.phases[%d]: フェーズ名が重複しています: %q", i, name)
+		}
+
+		seen[name] = struct{}{}
+
+		phase := runPhase{Name: name, Label: name, Start: fmt.Sprintf("▶️  %s を実行中...", name), Command: strings.TrimSpace(pc.Run)}
+		if _, ok := builtin[name]; ok {
+			phase = newBuiltinRunPhase(name)
+		}
+
+		phase.ContinueOnError = pc.ContinueOnError == nil || *pc.ContinueOnError
+		phase.When = pc.When
+		phase.Pre = pc.Pre
+		phase.Post = pc.Post
+		phases = append(phases, phase)
+	}
+
+	return phases, nil
+}
+
+// filterRunPhases は --only / --skip で実行するフェーズを絞り込みます。
+// 設定にないフェーズ名を指定した場合はエラーを返します。
+func filterRunPhases(phases []runPhase, only, skip []string) ([]runPhase, error) {
+	onlySet, err := runPhaseNameSet(phases, "--only", only)
+	if err != nil {
+		return nil, err
+	}
+
+	skipSet, err := runPhaseNameSet(phases, "--skip", skip)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make([]runPhase, 0, len(phases))
+
+	for _, phase := range phases {
+		if _, ok := onlySet[phase.Name]; len(onlySet) > 0 && !ok {
+			continue
+		}
+
+		if _, ok := skipSet[phase.Name]; ok {
+			continue
+		}
+
+		filtered = append(filtered, phase)
+	}
+
+	return filtered, nil
+}
+
+func runPhaseNameSet(phases []runPhase, flag string, names []string) (map[string]struct{}, error) {
+	available := make(map[string]struct{}, len(phases))
+	for _, phase := range phases {
+		available[phase.Name] = struct{}{}
+	}
+
+	set := make(map[string]struct{}, len(names))
+
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if _, ok := available[name]; !ok {
+			return nil, fmt.Errorf("%s: 設定にないフェーズです: %q（実行対象: %s）", flag, name, strings.Join(runPhaseNames(phases), ", "))
+		}
+
+		set[name] = struct{}{}
+	}
+
+	return set, nil
+}
+
+func runPhaseNames(phases []runPhase) []string {
+	names := make([]string, 0, len(phases))
+	for _, phase := range phases {
+		names = append(names, phase.Name)
+	}
+
+	return names
+}
+
+// runPhaseSkipReason は実行条件（when）を満たさない場合にその理由を返します（満たす場合は空文字）。
+func runPhaseSkipReason(when config.RunConditionConfig, penv runPhaseEnv) string {
+	if len(when.Host) > 0 && !matchRunPhaseHost(when.Host, penv.Hostname) {
+		return fmt.Sprintf("ホスト %s は対象外", penv.Hostname)
+	}
+
+	if when.WSL != nil && *when.WSL != penv.WSL {
+		if penv.WSL {
+			return "WSL 環境は対象外"
+		}
+
+		return "WSL 環境ではありません"
+	}
+
+	if len(when.Weekday) > 0 && !matchRunPhaseWeekday(when.Weekday, penv.Now.Weekday()) {
+		return fmt.Sprintf("%s は実行日ではありません", penv.Now.Weekday())
+	}
+
+	return ""
+}
+
+// matchRunPhaseHost はホスト名（またはドメインを除いた短い名前）がいずれかに一致するかを返します。
+func matchRunPhaseHost(hosts []string, hostname string) bool {
+	short, _, _ := strings.Cut(hostname, ".")
+
+	for _, host := range hosts {
+		host = strings.TrimSpace(host)
+		if strings.EqualFold(host, hostname) || strings.EqualFold(host, short) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchRunPhaseWeekday(days []string, today time.Weekday) bool {
+	for _, day := range days {
+		if weekday, ok := config.ParseWeekday(day); ok && weekday == today {
+			return true
+		}
+	}
+
+	return false
+}
+
+// executeRunPhases はフェーズを順に実行し、失敗したフェーズのエラーを返します。
+// continue_on_error: false のフェーズが失敗した場合は、以降のフェーズを実行しません。
+func executeRunPhases(cmd *cobra.Command, cfg *config.Config, phases []runPhase, dryRun bool) []phaseError {
+	penv := currentRunPhaseEnv()
+
+	var phaseErrors []phaseError
+
+	for i, phase := range phases {
+		if reason := runPhaseSkipReason(phase.When, penv); reason != "" {
+			fmt.Printf("⏭️  %s をスキップします（%s）\n", phase.Label, reason)
+			fmt.Println()
+
+			continue
+		}
+
+		err := runPhaseWithHooks(cmd, cfg, phase, dryRun)
+		if err == nil {
+			fmt.Println()
+			continue
+		}
+
+		phaseErrors = append(phaseErrors, phaseError{Name: phase.Label, Err: err})
+
+		if !phase.ContinueOnError {
+			fmt.Fprintf(os.Stderr, "⛔ %s でエラーが発生したため、残りの %d フェーズを中止します: %v\n", phase.Label, len(phases)-i-1, err)
+			fmt.Println()
+
+			break
+		}
+
+		fmt.Fprintf(os.Stderr, "⚠️  %s でエラーが発生しましたが、続行します: %v\n", phase.Label, err)
+		fmt.Println()
+	}
+
+	return phaseErrors
+}
+
+// runPhaseWithHooks は pre フック、フェーズ本体、post フック（本体が成功した場合のみ）の順に実行します。
+func runPhaseWithHooks(cmd *cobra.Command, cfg *config.Config, phase runPhase, dryRun bool) error {
+	for _, hook := range phase.Pre {
+		if err := runShellPhaseCommand(cmd, hook, dryRun); err != nil {
+			return fmt.Errorf("pre フック %q に失敗: %w", hook, err)
+		}
+	}
+
+	if phase.Start != "" {
+		fmt.Println(phase.Start)
+	}
+
+	var err error
+	if phase.Builtin != nil {
+		err = phase.Builtin(cmd, cfg)
+	} else {
+		err = runShellPhaseCommand(cmd, phase.Command, dryRun)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	for _, hook := range phase.Post {
+		if err := runShellPhaseCommand(cmd, hook, dryRun); err != nil {
+			return fmt.Errorf("post フック %q に失敗: %w", hook, err)
+		}
+	}
+
+	return nil
+}
+
+// runShellPhaseCommand は独自フェーズ・フックのコマンドを実行します（DryRun では表示のみ）。
+func runShellPhaseCommand(cmd *cobra.Command, command string, dryRun bool) error {
+	if dryRun {
+		fmt.Printf("🔍 [DryRun] 実行予定: %s\n", command)
+		return nil
+	}
+
+	fmt.Printf("$ %s\n", command)
+
+	return runShellStep(commandContext(cmd), command)
+}
+
+// runShellCommand はコマンド文字列をシェル経由で実行し、出力をそのまま端末に流します。
+func runShellCommand(ctx context.Context, command string) error {
+	name, args := "sh", []string{"-c", command}
+	if runtime.GOOS == "windows" {
+		name, args = "cmd", []string{"/C", command}
+	}
+
+	c := exec.CommandContext(ctx, name, args...)
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+
+	return c.Run()
+}
+
+// runDryRunEnabled は run の DryRun 指定（--dry-run、未指定時は control.dry_run）を返します。
+func runDryRunEnabled(cmd *cobra.Command, cfg *config.Config) bool {
+	if cmd.Flags().Changed("dry-run") {
+		return runDryRun
+	}
+
+	return cfg.Control.DryRun
+}
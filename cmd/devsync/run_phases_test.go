@@ -0,0 +1,341 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scottlz0310/devsync/internal/config"
+	"github.com/scottlz0310/devsync/internal/testutil"
+	"github.com/spf13/cobra"
+)
+
+// setupRunPhasesConfig は run.phases を含む設定をテスト用の HOME に作成します。
+func setupRunPhasesConfig(t *testing.T, runSection string) {
+	t.Helper()
+
+	tmpHome := t.TempDir()
+	configDir := filepath.Join(tmpHome, ".config", "devsync")
+
+	if err := os.MkdirAll(configDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	configContent := "version: 1\nsecrets:\n  enabled: false\n" + runSection
+	if err := os.WriteFile(filepath.Join(configDir, "config.yaml"), []byte(configContent), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	testutil.SetTestHome(t, tmpHome)
+}
+
+// stubRunPhaseSteps は各フェーズの処理を呼び出し記録用のスタブに差し替えます。
+func stubRunPhaseSteps(t *testing.T, failures map[string]error) *[]string {
+	t.Helper()
+
+	originalSysUpdate := runSysUpdateStep
+	originalRepoUpdate := runRepoUpdateStep
+	originalRepoCleanup := runRepoCleanupStep
+	originalDoctor := runDoctorStep
+	originalShell := runShellStep
+	originalEnv := currentRunPhaseEnv
+	originalOnly := runOnly
+	originalSkip := runSkip
+
+	t.Cleanup(func() {
+		runSysUpdateStep = originalSysUpdate
+		runRepoUpdateStep = originalRepoUpdate
+		runRepoCleanupStep = originalRepoCleanup
+		runDoctorStep = originalDoctor
+		runShellStep = originalShell
+		currentRunPhaseEnv = originalEnv
+		runOnly = originalOnly
+		runSkip = originalSkip
+	})
+
+	calls := make([]string, 0, 8)
+
+	runSysUpdateStep = func(*cobra.Command, []string) error {
+		calls = append(calls, "sys_update")
+		return failures["sys_update"]
+	}
+
+	runRepoUpdateStep = func(*cobra.Command, []string) error {
+		calls = append(calls, "repo_update")
+		return failures["repo_update"]
+	}
+
+	runRepoCleanupStep = func(*cobra.Command, []string) error {
+		calls = append(calls, "repo_cleanup")
+		return failures["repo_cleanup"]
+	}
+
+	runDoctorStep = func() bool {
+		calls = append(calls, "doctor")
+		return failures["doctor"] == nil
+	}
+
+	runShellStep = func(_ context.Context, command string) error {
+		calls = append(calls, "sh:"+command)
+		return failures["sh:"+command]
+	}
+
+	// 2024-06-01 は土曜日
+	currentRunPhaseEnv = func() runPhaseEnv {
+		return runPhaseEnv{Hostname: "work-laptop.local", WSL: true, Now: time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC)}
+	}
+
+	runOnly = nil
+	runSkip = nil
+
+	return &calls
+}
+
+const testRunPhasesSection = `run:
+  phases:
+    - name: secrets
+    - name: sys
+      pre: ["echo before-sys"]
+      post: ["brew bundle dump --force"]
+    - name: repo-update
+    - name: repo-cleanup
+      when:
+        weekday: [mon]
+    - name: restart-docker
+      run: "systemctl --user restart docker"
+      when:
+        host: [work-laptop]
+        wsl: true
+    - name: doctor
+`
+
+func TestRunDaily_ConfiguredPhases(t *testing.T) {
+	testCases := []struct {
+		name          string
+		section       string
+		only          []string
+		skip          []string
+		failures      map[string]error
+		wantCalls     []string
+		wantErrSubstr string
+	}{
+		{
+			name:    "設定した順序で実行し、条件を満たさないフェーズはスキップ",
+			section: testRunPhasesSection,
+			wantCalls: []string{
+				"sh:echo before-sys", "sys_update", "sh:brew bundle dump --force",
+				"repo_update", "sh:systemctl --user restart docker", "doctor",
+			},
+		},
+		{
+			name:      "--only で指定したフェーズのみ実行",
+			section:   testRunPhasesSection,
+			only:      []string{"repo-update", "doctor"},
+			wantCalls: []string{"repo_update", "doctor"},
+		},
+		{
+			name:      "--skip で指定したフェーズを除外",
+			section:   testRunPhasesSection,
+			skip:      []string{"sys", "restart-docker"},
+			wantCalls: []string{"repo_update", "doctor"},
+		},
+		{
+			name:          "pre フックの失敗でフェーズ本体と post フックを実行しない",
+			section:       testRunPhasesSection,
+			failures:      map[string]error{"sh:echo before-sys": errors.New("exit status 1")},
+			wantCalls:     []string{"sh:echo before-sys", "repo_update", "sh:systemctl --user restart docker", "doctor"},
+			wantErrSubstr: "1 件のフェーズでエラーが発生しました",
+		},
+		{
+			name: "continue_on_error: false のフェーズが失敗すると以降を中止",
+			section: `run:
+  phases:
+    - name: sys
+      continue_on_error: false
+    - name: repo-update
+`,
+			failures:      map[string]error{"sys_update": errors.New("sys failed")},
+			wantCalls:     []string{"sys_update"},
+			wantErrSubstr: "1 件のフェーズでエラーが発生しました",
+		},
+		{
+			name:          "診断の失敗はフェーズのエラー",
+			section:       "run:\n  phases:\n    - name: doctor\n",
+			failures:      map[string]error{"doctor": errors.New("failed")},
+			wantCalls:     []string{"doctor"},
+			wantErrSubstr: "1 件のフェーズでエラーが発生しました",
+		},
+		{
+			name:          "--only に設定にないフェーズを指定するとエラー",
+			section:       "",
+			only:          []string{"repo-cleanup"},
+			wantCalls:     []string{},
+			wantErrSubstr: "--only: 設定にないフェーズです",
+		},
+		{
+			name:          "未知のフェーズはエラー",
+			section:       "run:\n  phases:\n    - name: repo-sync\n",
+			wantCalls:     []string{},
+			wantErrSubstr: "未知のフェーズです",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			setupRunPhasesConfig(t, tc.section)
+
+			calls := stubRunPhaseSteps(t, tc.failures)
+			runOnly = tc.only
+			runSkip = tc.skip
+
+			err := runDaily(&cobra.Command{Use: "run"}, nil)
+			if tc.wantErrSubstr == "" && err != nil {
+				t.Fatalf("runDaily() unexpected error: %v", err)
+			}
+
+			if tc.wantErrSubstr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErrSubstr)) {
+				t.Fatalf("runDaily() error = %v, want substring %q", err, tc.wantErrSubstr)
+			}
+
+			if !reflect.DeepEqual(*calls, tc.wantCalls) {
+				t.Fatalf("runDaily() calls = %#v, want %#v", *calls, tc.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRunDaily_DryRunDoesNotRunShellCommands(t *testing.T) {
+	setupRunPhasesConfig(t, testRunPhasesSection)
+
+	calls := stubRunPhaseSteps(t, nil)
+
+	cmd := &cobra.Command{Use: "run"}
+	cmd.Flags().BoolVarP(&runDryRun, "dry-run", "n", false, "")
+
+	if err := cmd.Flags().Set("dry-run", "true"); err != nil {
+		t.Fatal(err)
+	}
+
+	output := captureStdout(t, func() {
+		if err := runDaily(cmd, nil); err != nil {
+			t.Errorf("runDaily() unexpected error: %v", err)
+		}
+	})
+
+	want := []string{"sys_update", "repo_update", "doctor"}
+	if !reflect.DeepEqual(*calls, want) {
+		t.Fatalf("runDaily() calls = %#v, want %#v", *calls, want)
+	}
+
+	for _, substr := range []string{
+		"🔍 [DryRun] 実行予定: brew bundle dump --force",
+		"🔍 [DryRun] 実行予定: systemctl --user restart docker",
+		"⏭️  ブランチ整理 をスキップします（Saturday は実行日ではありません）",
+	} {
+		if !strings.Contains(output, substr) {
+			t.Fatalf("output does not contain %q:\n%s", substr, output)
+		}
+	}
+}
+
+func TestBuildRunPhases_Default(t *testing.T) {
+	t.Parallel()
+
+	phases, err := buildRunPhases(config.Default())
+	if err != nil {
+		t.Fatalf("buildRunPhases() unexpected error: %v", err)
+	}
+
+	if got := strings.Join(runPhaseNames(phases), ","); got != "secrets,sys,repo-update" {
+		t.Fatalf("phases = %s, want secrets,sys,repo-update", got)
+	}
+
+	for _, phase := range phases {
+		if !phase.ContinueOnError {
+			t.Fatalf("%s: ContinueOnError = false, want true", phase.Name)
+		}
+	}
+}
+
+func TestBuildRunPhases_DuplicateName(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.Default()
+	cfg.Run.Phases = []config.RunPhaseConfig{{Name: "sys"}, {Name: "backup", Run: "true"}, {Name: "backup", Run: "false"}}
+
+	_, err := buildRunPhases(cfg)
+	if err == nil || !strings.Contains(err.Error(), "run.phases[2]: フェーズ名が重複しています") {
+		t.Fatalf("buildRunPhases() error = %v, want duplicate error", err)
+	}
+}
+
+func TestRunPhaseSkipReason(t *testing.T) {
+	t.Parallel()
+
+	enabled := true
+	disabled := false
+	penv := runPhaseEnv{Hostname: "Desktop.example.com", WSL: false, Now: time.Date(2024, 6, 3, 9, 0, 0, 0, time.UTC)} // 月曜日
+
+	testCases := []struct {
+		name string
+		when config.RunConditionConfig
+		want string
+	}{
+		{name: "条件なし", when: config.RunConditionConfig{}, want: ""},
+		{name: "短いホスト名に一致（大文字小文字を区別しない）", when: config.RunConditionConfig{Host: []string{"desktop"}}, want: ""},
+		{name: "FQDN に一致", when: config.RunConditionConfig{Host: []string{"desktop.example.com"}}, want: ""},
+		{name: "ホスト名が不一致", when: config.RunConditionConfig{Host: []string{"laptop"}}, want: "ホスト Desktop.example.com は対象外"},
+		{name: "WSL のみ", when: config.RunConditionConfig{WSL: &enabled}, want: "WSL 環境ではありません"},
+		{name: "WSL 以外のみ", when: config.RunConditionConfig{WSL: &disabled}, want: ""},
+		{name: "曜日に一致", when: config.RunConditionConfig{Weekday: []string{"Mon", "fri"}}, want: ""},
+		{name: "曜日が不一致", when: config.RunConditionConfig{Weekday: []string{"sat", "sun"}}, want: "Monday は実行日ではありません"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := runPhaseSkipReason(tc.when, penv); got != tc.want {
+				t.Fatalf("runPhaseSkipReason() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPropagateRunFlags_RepoCleanup(t *testing.T) {
+	origDryRun := repoCleanupDryRun
+	origJobs := repoCleanupJobs
+	origLogFile := repoCleanupLogFile
+
+	t.Cleanup(func() {
+		repoCleanupDryRun = origDryRun
+		repoCleanupJobs = origJobs
+		repoCleanupLogFile = origLogFile
+	})
+
+	repoCleanupDryRun = false
+	repoCleanupJobs = 0
+	repoCleanupLogFile = ""
+
+	cmd := &cobra.Command{Use: "run"}
+	cmd.Flags().BoolVarP(&runDryRun, "dry-run", "n", false, "")
+	cmd.Flags().IntVarP(&runJobs, "jobs", "j", 0, "")
+	cmd.Flags().StringVar(&runLogFile, "log-file", "", "")
+
+	for flag, value := range map[string]string{"dry-run": "true", "jobs": "3", "log-file": "/tmp/run.log"} {
+		if err := cmd.Flags().Set(flag, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	propagateRunFlags(cmd)
+
+	if !repoCleanupDryRun || repoCleanupJobs != 3 || repoCleanupLogFile != "/tmp/run.log" {
+		t.Fatalf("repo cleanup flags = (%v, %d, %q), want (true, 3, /tmp/run.log)", repoCleanupDryRun, repoCleanupJobs, repoCleanupLogFile)
+	}
+}
@@ -0,0 +1,765 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/scottlz0310/devsync/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// スケジュールの登録先
+const (
+	scheduleBackendAuto    = "auto"
+	scheduleBackendSystemd = "systemd"
+	scheduleBackendCron    = "cron"
+	scheduleBackendLaunchd = "launchd"
+)
+
+const (
+	scheduleUnitName        = "devsync"
+	scheduleLaunchdLabel    = "com.github.scottlz0310.devsync"
+	scheduleCronBeginMarker = "# BEGIN devsync schedule"
+	scheduleCronEndMarker   = "# END devsync schedule"
+	scheduleGeneratedNote   = "devsync schedule install で生成（devsync schedule remove で削除）"
+	defaultScheduleTime     = "07:30"
+	defaultScheduleKeepLogs = 14
+)
+
+var (
+	scheduleBackend  string
+	scheduleTime     string
+	scheduleDryRun   bool
+	scheduleLogDir   string
+	scheduleKeepLogs int
+)
+
+var (
+	scheduleCommandStep    = runScheduleCommand
+	scheduleLookPathStep   = exec.LookPath
+	scheduleExecutableStep = os.Executable
+	scheduleRunStep        = runDaily
+	scheduleNow            = time.Now
+)
+
+var scheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "devsync run の定期実行（systemd ユーザータイマー / cron / launchd）を管理します",
+	Long: `devsync run を毎日決まった時刻に無人で実行するよう登録します。
+
+登録先（--backend auto の場合は自動選択）:
+  - systemd  ユーザータイマー（~/.config/systemd/user/devsync.{service,timer}）
+  - cron     crontab（systemd のユーザーセッションが使えない環境）
+  - launchd  LaunchAgent の plist（macOS。~/Library/LaunchAgents に生成）
+
+定期実行は devsync schedule run を呼び出し、TUI 無効・非対話モードで devsync run を実行します。
+ログは状態ディレクトリ（~/.local/state/devsync/logs）に実行ごとに保存し、古いものから削除します。`,
+}
+
+var scheduleInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "定期実行を登録します",
+	Example: `  devsync schedule install                 # 毎日 07:30 に実行
+  devsync schedule install --time 12:15    # 実行時刻を指定
+  devsync schedule install -n              # 生成する内容を表示のみ
+  devsync schedule install --backend cron  # cron に登録`,
+	Args: cobra.NoArgs,
+	RunE: runScheduleInstall,
+}
+
+var scheduleRemoveCmd = &cobra.Command{
+	Use:   "remove",
+	Short: "登録した定期実行を削除します",
+	Args:  cobra.NoArgs,
+	RunE:  runScheduleRemove,
+}
+
+var scheduleStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "定期実行の登録状況と最新のログを表示します",
+	Args:  cobra.NoArgs,
+	RunE:  runScheduleStatus,
+}
+
+var scheduleRunCmd = &cobra.Command{
+	Use:   "run",
+	Short: "定期実行と同じ条件（TUI 無効・非対話・ログ保存）で devsync run を実行します",
+	Args:  cobra.NoArgs,
+	RunE:  runScheduleRun,
+}
+
+func init() {
+	rootCmd.AddCommand(scheduleCmd)
+	scheduleCmd.AddCommand(scheduleInstallCmd, scheduleRemoveCmd, scheduleStatusCmd, scheduleRunCmd)
+
+	scheduleCmd.PersistentFlags().StringVar(&scheduleLogDir, "log-dir", "", "ログの保存先（既定: ~/.local/state/devsync/logs）")
+
+	scheduleInstallCmd.Flags().StringVar(&scheduleBackend, "backend", scheduleBackendAuto, "登録先（auto / systemd / cron / launchd）")
+	scheduleInstallCmd.Flags().StringVar(&scheduleTime, "time", defaultScheduleTime, "毎日の実行時刻（HH:MM）")
+	scheduleInstallCmd.Flags().IntVar(&scheduleKeepLogs, "keep-logs", defaultScheduleKeepLogs, "保持するログの件数")
+	scheduleInstallCmd.Flags().BoolVarP(&scheduleDryRun, "dry-run", "n", false, "登録せず、生成する内容を表示のみ")
+
+	scheduleRemoveCmd.Flags().StringVar(&scheduleBackend, "backend", scheduleBackendAuto, "削除する登録先（auto はすべて）")
+
+	scheduleRunCmd.Flags().IntVar(&scheduleKeepLogs, "keep-logs", defaultScheduleKeepLogs, "保持するログの件数")
+}
+
+// scheduleSpec は定期実行の登録内容です。
+type scheduleSpec struct {
+	Executable string
+	Hour       int
+	Minute     int
+	LogDir     string
+	KeepLogs   int
+	// Path は実行時の PATH です（systemd / launchd は既定の PATH が最小限のため、登録時の値を引き継ぎます）。
+	Path string
+}
+
+// command は定期実行で呼び出すコマンドラインです。
+func (s scheduleSpec) command() []string {
+	return []string{s.Executable, "schedule", "run", "--log-dir", s.LogDir, "--keep-logs", strconv.Itoa(s.KeepLogs)}
+}
+
+// scheduleFile は登録先に書き出すファイルです。
+type scheduleFile struct {
+	Path    string
+	Content string
+}
+
+func runScheduleInstall(cmd *cobra.Command, _ []string) error {
+	ctx := commandContext(cmd)
+
+	spec, err := buildScheduleSpec()
+	if err != nil {
+		return err
+	}
+
+	backend, err := resolveScheduleBackend(ctx, scheduleBackend)
+	if err != nil {
+		return err
+	}
+
+	if scheduleDryRun {
+		return printSchedulePlan(backend, spec)
+	}
+
+	switch backend {
+	case scheduleBackendSystemd:
+		err = installSystemdSchedule(ctx, spec)
+	case scheduleBackendCron:
+		err = installCronSchedule(ctx, spec)
+	default:
+		err = installLaunchdSchedule(spec)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("📝 ログ: %s（最新 %d 件を保持）\n", spec.LogDir, spec.KeepLogs)
+
+	return nil
+}
+
+func buildScheduleSpec() (scheduleSpec, error) {
+	hour, minute, err := parseScheduleTime(scheduleTime)
+	if err != nil {
+		return scheduleSpec{}, err
+	}
+
+	if scheduleKeepLogs <= 0 {
+		return scheduleSpec{}, fmt.Errorf("--keep-logs は 1 以上を指定してください: %d", scheduleKeepLogs)
+	}
+
+	executable, err := resolveScheduleExecutable()
+	if err != nil {
+		return scheduleSpec{}, err
+	}
+
+	logDir, err := resolveScheduleLogDir()
+	if err != nil {
+		return scheduleSpec{}, err
+	}
+
+	return scheduleSpec{
+		Executable: executable,
+		Hour:       hour,
+		Minute:     minute,
+		LogDir:     logDir,
+		KeepLogs:   scheduleKeepLogs,
+		Path:       os.Getenv("PATH"),
+	}, nil
+}
+
+// parseScheduleTime は HH:MM 形式の時刻を解釈します。
+func parseScheduleTime(value string) (hour, minute int, err error) {
+	parsed, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, 0, fmt.Errorf("--time は HH:MM 形式で指定してください: %q", value)
+	}
+
+	return parsed.Hour(), parsed.Minute(), nil
+}
+
+// resolveScheduleExecutable は定期実行で呼び出す devsync のパスを返します。
+// PATH 上の devsync が実行中のバイナリと同じ場合は、シンボリックリンク（Homebrew など）が
+// 更新後も有効なよう PATH 上のパスを優先します。
+func resolveScheduleExecutable() (string, error) {
+	executable, err := scheduleExecutableStep()
+	if err != nil {
+		return "", fmt.Errorf("実行ファイルのパスを取得できません: %w", err)
+	}
+
+	onPath, err := scheduleLookPathStep("devsync")
+	if err != nil {
+		return executable, nil
+	}
+
+	onPath, err = filepath.Abs(onPath)
+	if err != nil {
+		return executable, nil
+	}
+
+	pathInfo, pathErr := os.Stat(onPath)
+	exeInfo, exeErr := os.Stat(executable)
+
+	if pathErr == nil && exeErr == nil && os.SameFile(pathInfo, exeInfo) {
+		return onPath, nil
+	}
+
+	return executable, nil
+}
+
+func resolveScheduleLogDir() (string, error) {
+	if scheduleLogDir != "" {
+		return filepath.Abs(scheduleLogDir)
+	}
+
+	stateDir, err := config.StateDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(stateDir, "logs"), nil
+}
+
+// resolveScheduleBackend は --backend の値を検証し、auto の場合は環境に合わせて選択します。
+func resolveScheduleBackend(ctx context.Context, name string) (string, error) {
+	switch name {
+	case scheduleBackendSystemd, scheduleBackendCron, scheduleBackendLaunchd:
+		return name, nil
+	case scheduleBackendAuto, "":
+		return detectScheduleBackend(ctx)
+	default:
+		return "", fmt.Errorf("--backend が不正です: %q（auto / systemd / cron / launchd）", name)
+	}
+}
+
+func detectScheduleBackend(ctx context.Context) (string, error) {
+	switch {
+	case runtime.GOOS == "darwin":
+		return scheduleBackendLaunchd, nil
+	case runtime.GOOS == "windows":
+		return "", errors.New("Windows は未対応です（タスク スケジューラに `devsync schedule run` を登録してください）")
+	case systemdUserAvailable(ctx):
+		return scheduleBackendSystemd, nil
+	}
+
+	if _, err := scheduleLookPathStep("crontab"); err == nil {
+		return scheduleBackendCron, nil
+	}
+
+	return "", errors.New("systemd のユーザーセッションも crontab も利用できません（--backend で登録先を指定してください）")
+}
+
+// systemdUserAvailable は systemd のユーザーセッション（systemctl --user）が利用できるかを返します。
+func systemdUserAvailable(ctx context.Context) bool {
+	if _, err := scheduleLookPathStep("systemctl"); err != nil {
+		return false
+	}
+
+	_, err := scheduleCommandStep(ctx, "", "systemctl", "--user", "show-environment")
+
+	return err == nil
+}
+
+// scheduleFiles は systemd / launchd の登録先に書き出すファイルを返します（cron は crontab に登録するため対象外）。
+func scheduleFiles(backend string, spec scheduleSpec) ([]scheduleFile, error) {
+	switch backend {
+	case scheduleBackendSystemd:
+		dir, err := systemdUserUnitDir()
+		if err != nil {
+			return nil, err
+		}
+
+		return []scheduleFile{
+			{Path: filepath.Join(dir, scheduleUnitName+".service"), Content: renderSystemdService(spec)},
+			{Path: filepath.Join(dir, scheduleUnitName+".timer"), Content: renderSystemdTimer(spec)},
+		}, nil
+	case scheduleBackendLaunchd:
+		path, err := launchdPlistPath()
+		if err != nil {
+			return nil, err
+		}
+
+		return []scheduleFile{{Path: path, Content: renderLaunchdPlist(spec)}}, nil
+	default:
+		return nil, nil
+	}
+}
+
+func printSchedulePlan(backend string, spec scheduleSpec) error {
+	fmt.Printf("📋 DryRun: %s に登録する内容（毎日 %02d:%02d）\n", backend, spec.Hour, spec.Minute)
+
+	if backend == scheduleBackendCron {
+		fmt.Println()
+		fmt.Print(renderCronBlock(spec))
+
+		return nil
+	}
+
+	files, err := scheduleFiles(backend, spec)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		fmt.Printf("\n# %s\n%s", file.Path, file.Content)
+	}
+
+	return nil
+}
+
+func writeScheduleFiles(files []scheduleFile) error {
+	for _, file := range files {
+		if err := os.MkdirAll(filepath.Dir(file.Path), 0o755); err != nil {
+			return fmt.Errorf("ディレクトリの作成に失敗: %w", err)
+		}
+
+		if err := os.WriteFile(file.Path, []byte(file.Content), 0o644); err != nil {
+			return fmt.Errorf("%s の書き込みに失敗: %w", file.Path, err)
+		}
+
+		fmt.Printf("📄 %s を作成しました\n", file.Path)
+	}
+
+	return nil
+}
+
+func installSystemdSchedule(ctx context.Context, spec scheduleSpec) error {
+	files, err := scheduleFiles(scheduleBackendSystemd, spec)
+	if err != nil {
+		return err
+	}
+
+	if err := writeScheduleFiles(files); err != nil {
+		return err
+	}
+
+	if _, err := scheduleCommandStep(ctx, "", "systemctl", "--user", "daemon-reload"); err != nil {
+		return fmt.Errorf("systemctl --user daemon-reload に失敗: %w", err)
+	}
+
+	if _, err := scheduleCommandStep(ctx, "", "systemctl", "--user", "enable", "--now", scheduleUnitName+".timer"); err != nil {
+		return fmt.Errorf("%s.timer の有効化に失敗: %w", scheduleUnitName, err)
+	}
+
+	fmt.Printf("✅ systemd ユーザータイマーを登録しました（毎日 %02d:%02d、停止中の実行は次回起動時に実行）\n", spec.Hour, spec.Minute)
+	fmt.Println("ℹ️  ログインしていない間も実行するには `loginctl enable-linger $USER` を実行してください")
+
+	return nil
+}
+
+func installCronSchedule(ctx context.Context, spec scheduleSpec) error {
+	current, err := readCrontab(ctx)
+	if err != nil {
+		return err
+	}
+
+	updated := replaceCronBlock(current, renderCronBlock(spec))
+	if _, err := scheduleCommandStep(ctx, updated, "crontab", "-"); err != nil {
+		return fmt.Errorf("crontab の更新に失敗: %w", err)
+	}
+
+	fmt.Printf("✅ crontab に登録しました（毎日 %02d:%02d）\n", spec.Hour, spec.Minute)
+
+	return nil
+}
+
+func installLaunchdSchedule(spec scheduleSpec) error {
+	files, err := scheduleFiles(scheduleBackendLaunchd, spec)
+	if err != nil {
+		return err
+	}
+
+	if err := writeScheduleFiles(files); err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ LaunchAgent を生成しました（毎日 %02d:%02d）。読み込むには次を実行してください:\n", spec.Hour, spec.Minute)
+	fmt.Printf("   launchctl bootstrap gui/$(id -u) %s\n", shellQuote(files[0].Path))
+
+	return nil
+}
+
+// readCrontab は現在の crontab を返します（未作成の場合は空文字）。
+func readCrontab(ctx context.Context) (string, error) {
+	output, err := scheduleCommandStep(ctx, "", "crontab", "-l")
+	if err == nil {
+		return output, nil
+	}
+
+	// 未作成の場合（"no crontab for <user>"）以外のエラーで空として扱うと既存の登録を消してしまうため中断する
+	if strings.Contains(strings.ToLower(output), "no crontab") {
+		return "", nil
+	}
+
+	return "", fmt.Errorf("crontab -l に失敗: %w", err)
+}
+
+func runScheduleRemove(cmd *cobra.Command, _ []string) error {
+	ctx := commandContext(cmd)
+
+	backends := []string{scheduleBackendSystemd, scheduleBackendCron, scheduleBackendLaunchd}
+	if scheduleBackend != scheduleBackendAuto && scheduleBackend != "" {
+		if _, err := resolveScheduleBackend(ctx, scheduleBackend); err != nil {
+			return err
+		}
+
+		backends = []string{scheduleBackend}
+	}
+
+	removed := 0
+
+	for _, backend := range backends {
+		ok, err := removeSchedule(ctx, backend)
+		if err != nil {
+			return err
+		}
+
+		if ok {
+			removed++
+		}
+	}
+
+	if removed == 0 {
+		fmt.Println("ℹ️  登録された定期実行はありません")
+	}
+
+	return nil
+}
+
+// removeSchedule は登録先から定期実行を削除し、削除したかを返します。
+func removeSchedule(ctx context.Context, backend string) (bool, error) {
+	if backend == scheduleBackendCron {
+		return removeCronSchedule(ctx)
+	}
+
+	files, err := scheduleFiles(backend, scheduleSpec{})
+	if err != nil || !fileExists(files[0].Path) {
+		return false, err
+	}
+
+	if backend == scheduleBackendSystemd {
+		// 停止・無効化の失敗（ユーザーセッションがないなど）でもファイルは削除する
+		if _, err := scheduleCommandStep(ctx, "", "systemctl", "--user", "disable", "--now", scheduleUnitName+".timer"); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  %s.timer の無効化に失敗: %v\n", scheduleUnitName, err)
+		}
+	}
+
+	for _, file := range files {
+		if err := os.Remove(file.Path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return false, fmt.Errorf("%s の削除に失敗: %w", file.Path, err)
+		}
+	}
+
+	switch backend {
+	case scheduleBackendSystemd:
+		if _, err := scheduleCommandStep(ctx, "", "systemctl", "--user", "daemon-reload"); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  systemctl --user daemon-reload に失敗: %v\n", err)
+		}
+
+		fmt.Println("🗑️  systemd ユーザータイマーを削除しました")
+	default:
+		fmt.Printf("🗑️  %s を削除しました（読み込み済みの場合は launchctl bootout gui/$(id -u)/%s を実行してください）\n", files[0].Path, scheduleLaunchdLabel)
+	}
+
+	return true, nil
+}
+
+func removeCronSchedule(ctx context.Context) (bool, error) {
+	if _, err := scheduleLookPathStep("crontab"); err != nil {
+		return false, nil
+	}
+
+	current, err := readCrontab(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	updated, found := removeCronBlock(current)
+	if !found {
+		return false, nil
+	}
+
+	if _, err := scheduleCommandStep(ctx, updated, "crontab", "-"); err != nil {
+		return false, fmt.Errorf("crontab の更新に失敗: %w", err)
+	}
+
+	fmt.Println("🗑️  crontab から削除しました")
+
+	return true, nil
+}
+
+func runScheduleStatus(cmd *cobra.Command, _ []string) error {
+	ctx := commandContext(cmd)
+
+	fmt.Println("🕒 定期実行の登録状況")
+
+	installed := printSystemdScheduleStatus(ctx)
+	installed = printCronScheduleStatus(ctx) || installed
+	installed = printLaunchdScheduleStatus() || installed
+
+	if !installed {
+		fmt.Println("  登録されていません（devsync schedule install で登録できます）")
+	}
+
+	logDir, err := resolveScheduleLogDir()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println()
+	printLatestScheduleLog(logDir)
+
+	return nil
+}
+
+func printSystemdScheduleStatus(ctx context.Context) bool {
+	files, err := scheduleFiles(scheduleBackendSystemd, scheduleSpec{})
+	if err != nil || !fileExists(files[1].Path) {
+		return false
+	}
+
+	fmt.Printf("  ✅ systemd: %s\n", files[1].Path)
+
+	output, err := scheduleCommandStep(ctx, "", "systemctl", "--user", "list-timers", scheduleUnitName+".timer", "--no-pager")
+	if err == nil && strings.TrimSpace(output) != "" {
+		for _, line := range strings.Split(strings.TrimRight(output, "\n"), "\n") {
+			fmt.Printf("     %s\n", line)
+		}
+	}
+
+	return true
+}
+
+func printCronScheduleStatus(ctx context.Context) bool {
+	if _, err := scheduleLookPathStep("crontab"); err != nil {
+		return false
+	}
+
+	current, err := readCrontab(ctx)
+	if err != nil {
+		return false
+	}
+
+	entry := cronScheduleEntry(current)
+	if entry == "" {
+		return false
+	}
+
+	fmt.Printf("  ✅ cron: %s\n", entry)
+
+	return true
+}
+
+func printLaunchdScheduleStatus() bool {
+	path, err := launchdPlistPath()
+	if err != nil || !fileExists(path) {
+		return false
+	}
+
+	fmt.Printf("  ✅ launchd: %s\n", path)
+
+	return true
+}
+
+func printLatestScheduleLog(logDir string) {
+	logs, err := scheduleLogFiles(logDir)
+	if err != nil || len(logs) == 0 {
+		fmt.Printf("📝 ログはまだありません（%s）\n", logDir)
+		return
+	}
+
+	latest := logs[len(logs)-1]
+	fmt.Printf("📝 最新のログ: %s\n", latest)
+
+	if last := lastNonEmptyLine(latest); last != "" {
+		fmt.Printf("   %s\n", last)
+	}
+}
+
+// runScheduleRun は定期実行の本体です。ログファイルに出力を切り替え、TUI 無効・非対話モードで run を実行します。
+func runScheduleRun(cmd *cobra.Command, _ []string) error {
+	logDir, err := resolveScheduleLogDir()
+	if err != nil {
+		return err
+	}
+
+	start := scheduleNow()
+
+	logFile, err := openScheduleLog(logDir, scheduleKeepLogs, start)
+	if err != nil {
+		return err
+	}
+	defer logFile.Close()
+
+	restore := redirectScheduleOutput(logFile)
+	defer restore()
+
+	fmt.Printf("🕒 devsync schedule run: %s\n", start.Format(time.RFC3339))
+	fmt.Println()
+
+	runErr := scheduleRunStep(newScheduledRunCommand(cmd.Context()), nil)
+	elapsed := scheduleNow().Sub(start).Round(time.Second)
+
+	if runErr != nil {
+		fmt.Printf("❌ 終了（%s）: %v\n", elapsed, runErr)
+		return runErr
+	}
+
+	fmt.Printf("✅ 終了（%s）\n", elapsed)
+
+	return nil
+}
+
+// newScheduledRunCommand は --no-tui と --non-interactive を指定した run コマンド相当を返します。
+func newScheduledRunCommand(ctx context.Context) *cobra.Command {
+	c := &cobra.Command{Use: "run"}
+	c.Flags().BoolVar(&runNoTUI, "no-tui", false, "")
+	c.Flags().BoolVar(&runNonInteractive, "non-interactive", false, "")
+
+	for _, name := range []string{"no-tui", "non-interactive"} {
+		if err := c.Flags().Set(name, "true"); err != nil {
+			panic(err)
+		}
+	}
+
+	if ctx != nil {
+		c.SetContext(ctx)
+	}
+
+	return c
+}
+
+// redirectScheduleOutput は標準出力・標準エラー出力をログファイルに切り替え、元に戻す関数を返します。
+func redirectScheduleOutput(f *os.File) func() {
+	stdout, stderr := os.Stdout, os.Stderr
+	colorOutput, colorError := color.Output, color.Error
+
+	os.Stdout, os.Stderr = f, f
+	color.Output, color.Error = f, f
+
+	return func() {
+		os.Stdout, os.Stderr = stdout, stderr
+		color.Output, color.Error = colorOutput, colorError
+	}
+}
+
+// openScheduleLog は実行ごとのログファイル（run-YYYYMMDD-HHMMSS.log）を作成し、
+// keep 件を超える古いログを削除します。
+func openScheduleLog(dir string, keep int, now time.Time) (*os.File, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("ログディレクトリの作成に失敗: %w", err)
+	}
+
+	path := filepath.Join(dir, "run-"+now.Format("20060102-150405")+".log")
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("ログファイルの作成に失敗: %w", err)
+	}
+
+	if err := pruneScheduleLogs(dir, keep); err != nil {
+		fmt.Fprintf(f, "⚠️  古いログの削除に失敗: %v\n", err)
+	}
+
+	return f, nil
+}
+
+func pruneScheduleLogs(dir string, keep int) error {
+	if keep <= 0 {
+		return nil
+	}
+
+	logs, err := scheduleLogFiles(dir)
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < len(logs)-keep; i++ {
+		if err := os.Remove(logs[i]); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// scheduleLogFiles はログファイルを古い順に返します（ファイル名の日時順）。
+func scheduleLogFiles(dir string) ([]string, error) {
+	logs, err := filepath.Glob(filepath.Join(dir, "run-*.log"))
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(logs)
+
+	return logs, nil
+}
+
+func lastNonEmptyLine(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func commandContext(cmd *cobra.Command) context.Context {
+	if ctx := cmd.Context(); ctx != nil {
+		return ctx
+	}
+
+	return context.Background()
+}
+
+// runScheduleCommand は外部コマンドを実行し、標準出力と標準エラー出力をまとめて返します。
+func runScheduleCommand(ctx context.Context, stdin, name string, args ...string) (string, error) {
+	c := exec.CommandContext(ctx, name, args...)
+	if stdin != "" {
+		c.Stdin = strings.NewReader(stdin)
+	}
+
+	output, err := c.CombinedOutput()
+
+	return string(output), err
+}
@@ -0,0 +1,405 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scottlz0310/devsync/internal/testutil"
+	"github.com/spf13/cobra"
+)
+
+func testScheduleSpec() scheduleSpec {
+	return scheduleSpec{
+		Executable: "/home/me/My Tools/devsync",
+		Hour:       7,
+		Minute:     5,
+		LogDir:     "/home/me/.local/state/devsync/logs",
+		KeepLogs:   14,
+		Path:       "/home/me/go/bin:/usr/bin",
+	}
+}
+
+func TestRenderSystemdUnits(t *testing.T) {
+	t.Parallel()
+
+	service := renderSystemdService(testScheduleSpec())
+	for _, want := range []string{
+		"[Service]\nType=oneshot\n",
+		"Environment=PATH=/home/me/go/bin:/usr/bin\n",
+		`ExecStart="/home/me/My Tools/devsync" schedule run --log-dir /home/me/.local/state/devsync/logs --keep-logs 14` + "\n",
+	} {
+		if !strings.Contains(service, want) {
+			t.Fatalf("service does not contain %q:\n%s", want, service)
+		}
+	}
+
+	timer := renderSystemdTimer(testScheduleSpec())
+	for _, want := range []string{"OnCalendar=*-*-* 07:05:00\n", "Persistent=true\n", "WantedBy=timers.target\n"} {
+		if !strings.Contains(timer, want) {
+			t.Fatalf("timer does not contain %q:\n%s", want, timer)
+		}
+	}
+}
+
+func TestSystemdQuote(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		input string
+		want  string
+	}{
+		{input: "/usr/bin/devsync", want: "/usr/bin/devsync"},
+		{input: "/opt/my tools/devsync", want: `"/opt/my tools/devsync"`},
+		{input: "/logs/100%", want: "/logs/100%%"},
+		{input: `a"b$c`, want: `"a\"b$$c"`},
+		{input: "", want: `""`},
+	}
+
+	for _, tc := range testCases {
+		if got := systemdQuote(tc.input); got != tc.want {
+			t.Fatalf("systemdQuote(%q) = %s, want %s", tc.input, got, tc.want)
+		}
+	}
+}
+
+func TestRenderCronBlock(t *testing.T) {
+	t.Parallel()
+
+	spec := testScheduleSpec()
+	spec.LogDir = "/logs/100%"
+
+	want := scheduleCronBeginMarker + "\n# " + scheduleGeneratedNote + "\n" +
+		`5 7 * * * PATH=/home/me/go/bin:/usr/bin '/home/me/My Tools/devsync' schedule run --log-dir '/logs/100\%' --keep-logs 14 >/dev/null 2>&1` + "\n" +
+		scheduleCronEndMarker + "\n"
+
+	if got := renderCronBlock(spec); got != want {
+		t.Fatalf("renderCronBlock() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestReplaceAndRemoveCronBlock(t *testing.T) {
+	t.Parallel()
+
+	block := renderCronBlock(testScheduleSpec())
+	existing := "MAILTO=me@example.com\n0 * * * * /usr/bin/backup"
+
+	installed := replaceCronBlock(existing, block)
+	if want := existing + "\n" + block; installed != want {
+		t.Fatalf("replaceCronBlock() =\n%s\nwant\n%s", installed, want)
+	}
+
+	// 再登録しても重複しない
+	reinstalled := replaceCronBlock(installed, block)
+	if reinstalled != installed {
+		t.Fatalf("replaceCronBlock() twice =\n%s\nwant\n%s", reinstalled, installed)
+	}
+
+	if entry := cronScheduleEntry(installed); !strings.HasPrefix(entry, "5 7 * * * ") {
+		t.Fatalf("cronScheduleEntry() = %q", entry)
+	}
+
+	removed, found := removeCronBlock(installed)
+	if !found || removed != existing+"\n" {
+		t.Fatalf("removeCronBlock() = (%q, %v), want (%q, true)", removed, found, existing+"\n")
+	}
+
+	if _, found := removeCronBlock(existing); found {
+		t.Fatal("removeCronBlock() found = true for crontab without block")
+	}
+}
+
+func TestRenderLaunchdPlist(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("POSIX パスを前提とするため Windows ではスキップ")
+	}
+
+	spec := testScheduleSpec()
+	spec.Path = "/opt/homebrew/bin:/usr/bin&more"
+
+	plist := renderLaunchdPlist(spec)
+	for _, want := range []string{
+		"<string>" + scheduleLaunchdLabel + "</string>",
+		"\t\t<string>/home/me/My Tools/devsync</string>\n\t\t<string>schedule</string>\n\t\t<string>run</string>\n",
+		"<string>/opt/homebrew/bin:/usr/bin&amp;more</string>",
+		"<key>Hour</key>\n\t\t<integer>7</integer>\n\t\t<key>Minute</key>\n\t\t<integer>5</integer>",
+		"<string>/home/me/.local/state/devsync/logs/launchd.log</string>",
+	} {
+		if !strings.Contains(plist, want) {
+			t.Fatalf("plist does not contain %q:\n%s", want, plist)
+		}
+	}
+}
+
+func TestParseScheduleTime(t *testing.T) {
+	t.Parallel()
+
+	hour, minute, err := parseScheduleTime("07:30")
+	if err != nil || hour != 7 || minute != 30 {
+		t.Fatalf("parseScheduleTime(07:30) = (%d, %d, %v)", hour, minute, err)
+	}
+
+	for _, input := range []string{"7:30am", "24:00", ""} {
+		if _, _, err := parseScheduleTime(input); err == nil {
+			t.Fatalf("parseScheduleTime(%q) error = nil, want error", input)
+		}
+	}
+}
+
+func TestOpenScheduleLog_Rotates(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	for _, name := range []string{"run-20240101-073000.log", "run-20240102-073000.log", "run-20240103-073000.log", "other.log"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("old\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	f, err := openScheduleLog(dir, 2, time.Date(2024, 1, 4, 7, 30, 0, 0, time.Local))
+	if err != nil {
+		t.Fatalf("openScheduleLog() unexpected error: %v", err)
+	}
+	defer f.Close()
+
+	logs, err := scheduleLogFiles(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{filepath.Join(dir, "run-20240103-073000.log"), filepath.Join(dir, "run-20240104-073000.log")}
+	if !reflect.DeepEqual(logs, want) {
+		t.Fatalf("logs = %#v, want %#v", logs, want)
+	}
+
+	if !fileExists(filepath.Join(dir, "other.log")) {
+		t.Fatal("ローテーション対象外のファイルを削除しました")
+	}
+}
+
+// stubScheduleCommands は外部コマンドの実行を記録し、crontab の内容を保持するスタブに差し替えます。
+func stubScheduleCommands(t *testing.T, crontab *string) *[]string {
+	t.Helper()
+
+	originalCommand := scheduleCommandStep
+	originalLookPath := scheduleLookPathStep
+	originalExecutable := scheduleExecutableStep
+
+	t.Cleanup(func() {
+		scheduleCommandStep = originalCommand
+		scheduleLookPathStep = originalLookPath
+		scheduleExecutableStep = originalExecutable
+	})
+
+	calls := make([]string, 0, 4)
+
+	scheduleCommandStep = func(_ context.Context, stdin, name string, args ...string) (string, error) {
+		call := strings.Join(append([]string{name}, args...), " ")
+		calls = append(calls, call)
+
+		switch {
+		case call == "crontab -l" && *crontab == "":
+			return "no crontab for me\n", errors.New("exit status 1")
+		case call == "crontab -l":
+			return *crontab, nil
+		case call == "crontab -":
+			*crontab = stdin
+		}
+
+		return "", nil
+	}
+
+	scheduleLookPathStep = func(file string) (string, error) {
+		return "/usr/bin/" + file, nil
+	}
+
+	scheduleExecutableStep = func() (string, error) {
+		return "/opt/devsync/bin/devsync", nil
+	}
+
+	return &calls
+}
+
+func setScheduleFlags(t *testing.T, backend string) {
+	t.Helper()
+
+	originalBackend, originalTime, originalKeep := scheduleBackend, scheduleTime, scheduleKeepLogs
+	originalDryRun, originalLogDir := scheduleDryRun, scheduleLogDir
+
+	t.Cleanup(func() {
+		scheduleBackend, scheduleTime, scheduleKeepLogs = originalBackend, originalTime, originalKeep
+		scheduleDryRun, scheduleLogDir = originalDryRun, originalLogDir
+	})
+
+	scheduleBackend = backend
+	scheduleTime = "06:45"
+	scheduleKeepLogs = 7
+	scheduleDryRun = false
+	scheduleLogDir = ""
+}
+
+func TestScheduleInstallRemove_Systemd(t *testing.T) {
+
+	if runtime.GOOS == "windows" {
+		t.Skip("POSIX パスを前提とするため Windows ではスキップ")
+	}
+	home := t.TempDir()
+	testutil.SetTestHome(t, home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("XDG_STATE_HOME", "")
+
+	crontab := ""
+	calls := stubScheduleCommands(t, &crontab)
+	setScheduleFlags(t, scheduleBackendSystemd)
+
+	if err := runScheduleInstall(&cobra.Command{}, nil); err != nil {
+		t.Fatalf("runScheduleInstall() unexpected error: %v", err)
+	}
+
+	unitDir := filepath.Join(home, ".config", "systemd", "user")
+
+	service, err := os.ReadFile(filepath.Join(unitDir, "devsync.service"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantExec := "ExecStart=/opt/devsync/bin/devsync schedule run --log-dir " + filepath.Join(home, ".local", "state", "devsync", "logs") + " --keep-logs 7\n"
+	if !strings.Contains(string(service), wantExec) {
+		t.Fatalf("service does not contain %q:\n%s", wantExec, service)
+	}
+
+	if !fileExists(filepath.Join(unitDir, "devsync.timer")) {
+		t.Fatal("devsync.timer が作成されていません")
+	}
+
+	if err := runScheduleRemove(&cobra.Command{}, nil); err != nil {
+		t.Fatalf("runScheduleRemove() unexpected error: %v", err)
+	}
+
+	if fileExists(filepath.Join(unitDir, "devsync.service")) || fileExists(filepath.Join(unitDir, "devsync.timer")) {
+		t.Fatal("ユニットファイルが削除されていません")
+	}
+
+	want := []string{
+		"systemctl --user daemon-reload",
+		"systemctl --user enable --now devsync.timer",
+		"systemctl --user disable --now devsync.timer",
+		"systemctl --user daemon-reload",
+	}
+	if !reflect.DeepEqual(*calls, want) {
+		t.Fatalf("calls = %#v, want %#v", *calls, want)
+	}
+}
+
+func TestScheduleInstallRemove_CronKeepsOtherEntries(t *testing.T) {
+	testutil.SetTestHome(t, t.TempDir())
+
+	crontab := "0 * * * * /usr/bin/backup\n"
+	stubScheduleCommands(t, &crontab)
+	setScheduleFlags(t, scheduleBackendCron)
+
+	if err := runScheduleInstall(&cobra.Command{}, nil); err != nil {
+		t.Fatalf("runScheduleInstall() unexpected error: %v", err)
+	}
+
+	if !strings.HasPrefix(crontab, "0 * * * * /usr/bin/backup\n"+scheduleCronBeginMarker+"\n") {
+		t.Fatalf("crontab =\n%s", crontab)
+	}
+
+	if entry := cronScheduleEntry(crontab); !strings.HasPrefix(entry, "45 6 * * * ") {
+		t.Fatalf("cron entry = %q, want 06:45", entry)
+	}
+
+	setScheduleFlags(t, scheduleBackendAuto)
+
+	if err := runScheduleRemove(&cobra.Command{}, nil); err != nil {
+		t.Fatalf("runScheduleRemove() unexpected error: %v", err)
+	}
+
+	if crontab != "0 * * * * /usr/bin/backup\n" {
+		t.Fatalf("crontab after remove =\n%s", crontab)
+	}
+}
+
+func TestScheduleInstall_CronListFailureDoesNotOverwrite(t *testing.T) {
+	testutil.SetTestHome(t, t.TempDir())
+
+	crontab := "0 * * * * /usr/bin/backup\n"
+	calls := stubScheduleCommands(t, &crontab)
+	setScheduleFlags(t, scheduleBackendCron)
+
+	scheduleCommandStep = func(_ context.Context, _, name string, args ...string) (string, error) {
+		*calls = append(*calls, strings.Join(append([]string{name}, args...), " "))
+		return "crontab: permission denied\n", errors.New("exit status 1")
+	}
+
+	if err := runScheduleInstall(&cobra.Command{}, nil); err == nil {
+		t.Fatal("runScheduleInstall() error = nil, want error")
+	}
+
+	if want := []string{"crontab -l"}; !reflect.DeepEqual(*calls, want) {
+		t.Fatalf("calls = %#v, want %#v", *calls, want)
+	}
+}
+
+func TestRunScheduleRun(t *testing.T) {
+	logDir := t.TempDir()
+
+	originalRun := scheduleRunStep
+	originalNow := scheduleNow
+	originalNoTUI := runNoTUI
+	originalNonInteractive := runNonInteractive
+
+	t.Cleanup(func() {
+		scheduleRunStep = originalRun
+		scheduleNow = originalNow
+		runNoTUI = originalNoTUI
+		runNonInteractive = originalNonInteractive
+	})
+
+	setScheduleFlags(t, scheduleBackendAuto)
+	scheduleLogDir = logDir
+
+	scheduleNow = func() time.Time {
+		return time.Date(2024, 6, 1, 7, 30, 0, 0, time.Local)
+	}
+
+	scheduleRunStep = func(cmd *cobra.Command, _ []string) error {
+		if !cmd.Flags().Changed("no-tui") || !runNoTUI || !cmd.Flags().Changed("non-interactive") || !runNonInteractive {
+			t.Errorf("no-tui / non-interactive が指定されていません")
+		}
+
+		os.Stdout.WriteString("🛠  システムを更新中...\n")
+
+		return errors.New("1 件のフェーズでエラーが発生しました")
+	}
+
+	err := runScheduleRun(&cobra.Command{}, nil)
+	if err == nil {
+		t.Fatal("runScheduleRun() error = nil, want error")
+	}
+
+	data, readErr := os.ReadFile(filepath.Join(logDir, "run-20240601-073000.log"))
+	if readErr != nil {
+		t.Fatal(readErr)
+	}
+
+	for _, want := range []string{"🛠  システムを更新中...", "❌ 終了（0s）: 1 件のフェーズでエラーが発生しました"} {
+		if !strings.Contains(string(data), want) {
+			t.Fatalf("log does not contain %q:\n%s", want, data)
+		}
+	}
+
+	if last := lastNonEmptyLine(filepath.Join(logDir, "run-20240601-073000.log")); !strings.HasPrefix(last, "❌ 終了") {
+		t.Fatalf("lastNonEmptyLine() = %q", last)
+	}
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// systemdUserUnitDir は systemd のユーザーユニットの配置先です（$XDG_CONFIG_HOME/systemd/user）。
+func systemdUserUnitDir() (string, error) {
+	if configHome := os.Getenv("XDG_CONFIG_HOME"); configHome != "" && filepath.IsAbs(configHome) {
+		return filepath.Join(configHome, "systemd", "user"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("ホームディレクトリの取得に失敗: %w", err)
+	}
+
+	return filepath.Join(home, ".config", "systemd", "user"), nil
+}
+
+// launchdPlistPath は LaunchAgent の plist の配置先です。
+func launchdPlistPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("ホームディレクトリの取得に失敗: %w", err)
+	}
+
+	return filepath.Join(home, "Library", "LaunchAgents", scheduleLaunchdLabel+".plist"), nil
+}
+
+// renderSystemdService は定期実行の service ユニットを生成します。
+func renderSystemdService(spec scheduleSpec) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s\n", scheduleGeneratedNote)
+	b.WriteString("[Unit]\n")
+	b.WriteString("Description=devsync run の定期実行\n")
+	b.WriteString("\n[Service]\n")
+	b.WriteString("Type=oneshot\n")
+
+	if spec.Path != "" {
+		fmt.Fprintf(&b, "Environment=%s\n", systemdQuote("PATH="+spec.Path))
+	}
+
+	quoted := make([]string, 0, len(spec.command()))
+	for _, arg := range spec.command() {
+		quoted = append(quoted, systemdQuote(arg))
+	}
+
+	fmt.Fprintf(&b, "ExecStart=%s\n", strings.Join(quoted, " "))
+	b.WriteString("Nice=10\n")
+
+	return b.String()
+}
+
+// renderSystemdTimer は毎日 spec の時刻に service を起動する timer ユニットを生成します。
+// Persistent=true により、電源オフやスリープで逃した実行は次回起動時に行います。
+func renderSystemdTimer(spec scheduleSpec) string {
+	return fmt.Sprintf(`# %s
+[Unit]
+Description=devsync run の定期実行タイマー
+
+[Timer]
+OnCalendar=*-*-* %02d:%02d:00
+Persistent=true
+
+[Install]
+WantedBy=timers.target
+`, scheduleGeneratedNote, spec.Hour, spec.Minute)
+}
+
+// systemdQuote は systemd のユニットファイルで 1 つの引数として扱われるよう値をクォートします。
+// % は指定子として解釈されるため常に %% にエスケープします。
+func systemdQuote(value string) string {
+	value = strings.ReplaceAll(value, "%", "%%")
+	if value != "" && !strings.ContainsAny(value, " \t\"'\\;$") {
+		return value
+	}
+
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "$", "$$")
+
+	return `"` + replacer.Replace(value) + `"`
+}
+
+// renderCronBlock は crontab に追記する定期実行のブロック（開始・終了マーカー付き）を生成します。
+// 出力はログファイルに保存するため、cron のメール通知は抑止します。
+func renderCronBlock(spec scheduleSpec) string {
+	quoted := make([]string, 0, len(spec.command()))
+	for _, arg := range spec.command() {
+		quoted = append(quoted, shellQuote(arg))
+	}
+
+	command := strings.Join(quoted, " ")
+	if spec.Path != "" {
+		command = "PATH=" + shellQuote(spec.Path) + " " + command
+	}
+
+	// cron は % を改行として扱うためエスケープする
+	command = strings.ReplaceAll(command, "%", `\%`)
+
+	return fmt.Sprintf("%s\n# %s\n%d %d * * * %s >/dev/null 2>&1\n%s\n",
+		scheduleCronBeginMarker, scheduleGeneratedNote, spec.Minute, spec.Hour, command, scheduleCronEndMarker)
+}
+
+// replaceCronBlock は crontab の既存のブロックを取り除き、末尾に block を追加します。
+func replaceCronBlock(crontab, block string) string {
+	rest, _ := removeCronBlock(crontab)
+	if rest != "" && !strings.HasSuffix(rest, "\n") {
+		rest += "\n"
+	}
+
+	return rest + block
+}
+
+// removeCronBlock は crontab から定期実行のブロックを取り除き、ブロックがあったかを返します。
+func removeCronBlock(crontab string) (string, bool) {
+	var (
+		kept    []string
+		inBlock bool
+		found   bool
+	)
+
+	for _, line := range strings.SplitAfter(crontab, "\n") {
+		switch trimmed := strings.TrimSpace(line); {
+		case trimmed == scheduleCronBeginMarker:
+			inBlock, found = true, true
+		case inBlock && trimmed == scheduleCronEndMarker:
+			inBlock = false
+		case !inBlock:
+			kept = append(kept, line)
+		}
+	}
+
+	return strings.Join(kept, ""), found
+}
+
+// cronScheduleEntry は crontab のブロック内の登録行を返します（ブロックがない場合は空文字）。
+func cronScheduleEntry(crontab string) string {
+	inBlock := false
+
+	for _, line := range strings.Split(crontab, "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == scheduleCronBeginMarker:
+			inBlock = true
+		case trimmed == scheduleCronEndMarker:
+			inBlock = false
+		case inBlock && trimmed != "" && !strings.HasPrefix(trimmed, "#"):
+			return trimmed
+		}
+	}
+
+	return ""
+}
+
+// shellQuote は POSIX シェルで 1 つの引数として扱われるよう値をクォートします。
+func shellQuote(value string) string {
+	if value != "" && strings.Trim(value, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_-./:=@+,") == "" {
+		return value
+	}
+
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// renderLaunchdPlist は毎日 spec の時刻に実行する LaunchAgent の plist を生成します。
+func renderLaunchdPlist(spec scheduleSpec) string {
+	var b strings.Builder
+
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+`)
+	fmt.Fprintf(&b, "<!-- %s -->\n", scheduleGeneratedNote)
+	b.WriteString("<plist version=\"1.0\">\n<dict>\n")
+	fmt.Fprintf(&b, "\t<key>Label</key>\n\t<string>%s</string>\n", xmlEscape(scheduleLaunchdLabel))
+	b.WriteString("\t<key>ProgramArguments</key>\n\t<array>\n")
+
+	for _, arg := range spec.command() {
+		fmt.Fprintf(&b, "\t\t<string>%s</string>\n", xmlEscape(arg))
+	}
+
+	b.WriteString("\t</array>\n")
+
+	if spec.Path != "" {
+		fmt.Fprintf(&b, "\t<key>EnvironmentVariables</key>\n\t<dict>\n\t\t<key>PATH</key>\n\t\t<string>%s</string>\n\t</dict>\n", xmlEscape(spec.Path))
+	}
+
+	fmt.Fprintf(&b, "\t<key>StartCalendarInterval</key>\n\t<dict>\n\t\t<key>Hour</key>\n\t\t<integer>%d</integer>\n\t\t<key>Minute</key>\n\t\t<integer>%d</integer>\n\t</dict>\n", spec.Hour, spec.Minute)
+	// devsync schedule run 自体の起動失敗を記録するための出力先（通常の出力は実行ごとのログに保存）
+	fmt.Fprintf(&b, "\t<key>StandardOutPath</key>\n\t<string>%s</string>\n", xmlEscape(filepath.Join(spec.LogDir, "launchd.log")))
+	fmt.Fprintf(&b, "\t<key>StandardErrorPath</key>\n\t<string>%s</string>\n", xmlEscape(filepath.Join(spec.LogDir, "launchd.log")))
+	b.WriteString("</dict>\n</plist>\n")
+
+	return b.String()
+}
+
+func xmlEscape(value string) string {
+	var buf bytes.Buffer
+	if err := xml.EscapeText(&buf, []byte(value)); err != nil {
+		return value
+	}
+
+	return buf.String()
+}
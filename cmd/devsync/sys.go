@@ -7,6 +7,7 @@ import (
 	"os"
 	"os/exec"
 	"os/signal"
+	"strings"
 	"sync"
 	"time"
 
@@ -24,8 +25,17 @@ var (
 	sysTUI     bool
 	sysNoTUI   bool
 	sysLogFile string
+
+	sysSecurityOnly bool
+	sysChangelog    bool
+	sysAllowMajor   bool
+
+	sysNonInteractive bool
 )
 
+// sudoCredentialsCachedStep はテストで差し替え可能な sudo 認証情報のキャッシュ確認です。
+var sudoCredentialsCachedStep = sudoCredentialsCached
+
 // sysCmd はシステム関連コマンドのルートです
 var sysCmd = &cobra.Command{
 	Use:   "sys",
@@ -60,7 +70,10 @@ var sysUpdateCmd = &cobra.Command{
   devsync sys update           # 設定に基づいて更新
   devsync sys update --dry-run # 更新計画のみ表示
   devsync sys update -v        # 詳細ログを表示
-  devsync sys update --jobs 4  # 4並列で更新`,
+  devsync sys update --jobs 4  # 4並列で更新
+  devsync sys update --security-only # セキュリティ更新のみ（apt / dnf）
+  devsync sys update -n --changelog  # 更新計画とリリースノートを表示（npm / pnpm / go / cargo / brew）
+  devsync sys update --allow-major   # allow の設定で保留している更新も適用`,
 	RunE: runSysUpdate,
 }
 
@@ -84,18 +97,35 @@ func init() {
 	sysUpdateCmd.Flags().BoolVar(&sysTUI, "tui", false, "Bubble Tea の進捗UIを表示（既定値は config.yaml の ui.tui）")
 	sysUpdateCmd.Flags().BoolVar(&sysNoTUI, "no-tui", false, "TUI 進捗表示を無効化（設定より優先）")
 	sysUpdateCmd.Flags().StringVar(&sysLogFile, "log-file", "", "ジョブ実行ログをファイルに保存")
+	sysUpdateCmd.Flags().BoolVar(&sysSecurityOnly, "security-only", false, "セキュリティ更新のみを適用（対応マネージャ以外はスキップ）")
+	sysUpdateCmd.Flags().BoolVar(&sysAllowMajor, "allow-major", false, "allow（許可するバージョンの範囲）を超える更新も適用")
+	sysUpdateCmd.Flags().BoolVar(&sysNonInteractive, "non-interactive", false, "sudo のパスワードを求めず、認証情報がキャッシュされていなければ sudo が必要なマネージャをスキップ")
+	sysUpdateCmd.Flags().BoolVar(&sysChangelog, "changelog", false, "DryRun の更新計画に現在から新しいバージョンまでのリリースノートを表示")
+	addLockFlags(sysUpdateCmd)
 }
 
 func runSysUpdate(cmd *cobra.Command, args []string) error {
 	// 設定の読み込み
 	cfg, opts := loadSysUpdateConfig(cmd)
 
+	if err := validateSysChangelog(sysChangelog, opts); err != nil {
+		return err
+	}
+
+	// 別の devsync とパッケージマネージャのロックを奪い合わないよう、多重起動を防ぐ
+	release, err := acquireCommandLock(cmd)
+	if err != nil {
+		return err
+	}
+
+	defer release()
+
 	// コンテキストの作成（タイムアウト + キャンセル対応）
 	ctx, cancel := setupContext()
 	defer cancel()
 
 	// 有効なマネージャを取得
-	enabledUpdaters, err := updater.GetEnabled(&cfg.Sys)
+	enabledUpdaters, err := updater.GetEnabled(ctx, &cfg.Sys)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
 	}
@@ -108,6 +138,13 @@ func runSysUpdate(cmd *cobra.Command, args []string) error {
 	useTUI, warning := resolveTUIEnabled(tuiReq)
 	printTUIWarning(warning)
 
+	if opts.SecurityOnly {
+		var skipped []updater.Updater
+
+		enabledUpdaters, skipped = filterSecurityOnlyUpdaters(enabledUpdaters)
+		printSecurityOnlySkipped(skipped)
+	}
+
 	// 有効なマネージャがない場合は利用可能なものを表示
 	if len(enabledUpdaters) == 0 {
 		printNoTargetTUIMessage(tuiReq, "sys update")
@@ -134,9 +171,17 @@ func runSysUpdate(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	recordRunUpdates(stats.Updated)
+
 	// TUI 使用時は TUI 側で完了サマリーを表示済みのため、テキストサマリーは非 TUI 時のみ出力
 	if !useTUI {
 		printUpdateSummary(stats)
+	} else {
+		printHeldUpdates(stats.Held)
+	}
+
+	if sysChangelog {
+		printReleaseNotes(os.Stdout, collectReleaseNotes(ctx, updater.NewReleaseNotesFetcher(), stats.Planned, jobs))
 	}
 
 	// 失敗ジョブのエラー詳細を表示
@@ -174,18 +219,13 @@ func runSysUpdatePhases(ctx context.Context, cfg *config.Config, opts updater.Up
 }
 
 func runExclusivePhase(ctx context.Context, cfg *config.Config, opts updater.UpdateOptions, updaters []updater.Updater, useTUI bool, stats *updateStats) error {
-	if phaseRequiresSudo(updaters, cfg.Sys.Managers) {
-		if err := ensureSudoAuthentication(ctx, "単独実行フェーズ", useTUI); err != nil {
-			return err
-		}
-
-		if !useTUI {
-			fmt.Println()
-		}
+	updaters, err := prepareSudoPhase(ctx, cfg, opts, updaters, "単独実行フェーズ", useTUI)
+	if err != nil || len(updaters) == 0 {
+		return err
 	}
 
 	if !useTUI {
-		fmt.Println("🔒 依存関係の都合で単独実行するマネージャがあります（apt）。")
+		fmt.Printf("🔒 依存関係の都合で単独実行するマネージャがあります（%s）。\n", strings.Join(updaterNames(updaters), ", "))
 		fmt.Println()
 	}
 
@@ -199,14 +239,9 @@ func runExclusivePhase(ctx context.Context, cfg *config.Config, opts updater.Upd
 }
 
 func runParallelPhase(ctx context.Context, cfg *config.Config, opts updater.UpdateOptions, updaters []updater.Updater, jobs int, useTUI bool, stats *updateStats) error {
-	if phaseRequiresSudo(updaters, cfg.Sys.Managers) {
-		if err := ensureSudoAuthentication(ctx, "並列実行フェーズ", useTUI); err != nil {
-			return err
-		}
-
-		if !useTUI {
-			fmt.Println()
-		}
+	updaters, err := prepareSudoPhase(ctx, cfg, opts, updaters, "並列実行フェーズ", useTUI)
+	if err != nil || len(updaters) == 0 {
+		return err
 	}
 
 	mergeUpdateStats(stats, executeParallelUpdaters(ctx, updaters, opts, jobs, useTUI))
@@ -214,6 +249,37 @@ func runParallelPhase(ctx context.Context, cfg *config.Config, opts updater.Upda
 	return nil
 }
 
+// prepareSudoPhase はフェーズに sudo が必要な場合に認証を確認し、実行するマネージャを返します。
+// DryRun は Check 相当の読み取りのみのため sudo 認証は不要です。
+// --non-interactive ではパスワードを求めず、認証情報がキャッシュされていなければ sudo が必要なマネージャを除外します。
+func prepareSudoPhase(ctx context.Context, cfg *config.Config, opts updater.UpdateOptions, updaters []updater.Updater, phase string, useTUI bool) ([]updater.Updater, error) {
+	if opts.DryRun || !phaseRequiresSudo(updaters, cfg.Sys.Managers) {
+		return updaters, nil
+	}
+
+	if sysNonInteractive {
+		if sudoCredentialsCachedStep(ctx) {
+			return updaters, nil
+		}
+
+		runnable, skipped := splitSudoUpdaters(updaters, cfg.Sys.Managers)
+		fmt.Printf("🔐 sudo の認証情報がキャッシュされていないため、sudo が必要なマネージャをスキップします（%s）\n", strings.Join(updaterNames(skipped), ", "))
+		fmt.Println()
+
+		return runnable, nil
+	}
+
+	if err := ensureSudoAuthentication(ctx, phase, useTUI); err != nil {
+		return nil, err
+	}
+
+	if !useTUI {
+		fmt.Println()
+	}
+
+	return updaters, nil
+}
+
 func printSysUpdateDryRunNotice(dryRun bool) {
 	if !dryRun {
 		return
@@ -228,6 +294,24 @@ type updateStats struct {
 	Updated int
 	Failed  int
 	Errors  []error
+	// Planned はマネージャごとの更新（DryRun では更新予定）のパッケージです。
+	Planned []plannedPackages
+	// Held は allow の範囲を超えるため保留した更新です。
+	Held []updater.PackageInfo
+}
+
+// plannedPackages は 1 つのマネージャの更新対象パッケージです。
+type plannedPackages struct {
+	Manager  string
+	Packages []updater.PackageInfo
+}
+
+func appendPlannedPackages(planned []plannedPackages, manager string, result *updater.UpdateResult) []plannedPackages {
+	if len(result.Packages) == 0 {
+		return planned
+	}
+
+	return append(planned, plannedPackages{Manager: manager, Packages: result.Packages})
 }
 
 // loadSysUpdateConfig は設定とオプションを読み込みます。
@@ -245,13 +329,24 @@ func loadSysUpdateConfig(cmd *cobra.Command) (*config.Config, updater.UpdateOpti
 	}
 
 	opts := updater.UpdateOptions{
-		DryRun:  cfg.Control.DryRun,
-		Verbose: sysVerbose,
+		DryRun:       cfg.Control.DryRun,
+		Verbose:      sysVerbose,
+		SecurityOnly: sysSecurityOnly,
+		AllowMajor:   sysAllowMajor,
 	}
 
+	registerExternalUpdaters(&cfg.Sys)
+
 	return cfg, opts
 }
 
+// registerExternalUpdaters は外部プラグイン（devsync-updater-<name>）をレジストリに登録します。
+func registerExternalUpdaters(sysCfg *config.SysConfig) {
+	if err := updater.RegisterExternal(sysCfg); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
+	}
+}
+
 // setupContext はタイムアウトとシグナルハンドリング付きのコンテキストを作成します。
 func setupContext() (context.Context, context.CancelFunc) {
 	timeout, err := time.ParseDuration(sysTimeout)
@@ -291,7 +386,12 @@ func printNoManagerHelp() {
 
 // executeUpdates は各マネージャで更新を実行し、統計を返します。
 func executeUpdates(ctx context.Context, updaters []updater.Updater, opts updater.UpdateOptions) updateStats {
-	var stats updateStats
+	var (
+		stats   updateStats
+		summary runner.Summary
+	)
+
+	defer func() { recordRunJobs("sys update", summary) }()
 
 	for _, u := range updaters {
 		select {
@@ -304,7 +404,11 @@ func executeUpdates(ctx context.Context, updaters []updater.Updater, opts update
 
 		printUpdaterHeader(u)
 
+		start := time.Now()
+
 		result, err := u.Update(ctx, opts)
+		appendJobResult(&summary, updaterJobResult(u.Name(), result, err, time.Since(start)))
+
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "❌ エラー: %v\n", err)
 			stats.Errors = append(stats.Errors, fmt.Errorf("%s: %w", u.Name(), err))
@@ -317,6 +421,8 @@ func executeUpdates(ctx context.Context, updaters []updater.Updater, opts update
 		stats.Updated += result.UpdatedCount
 		stats.Failed += result.FailedCount
 		stats.Errors = append(stats.Errors, result.Errors...)
+		stats.Planned = appendPlannedPackages(stats.Planned, u.Name(), result)
+		stats.Held = append(stats.Held, result.Held...)
 
 		fmt.Println()
 	}
@@ -324,6 +430,22 @@ func executeUpdates(ctx context.Context, updaters []updater.Updater, opts update
 	return stats
 }
 
+// updaterJobResult は単独で実行したマネージャの結果を runner.Result に変換します（通知用）。
+func updaterJobResult(name string, result *updater.UpdateResult, err error, duration time.Duration) runner.Result {
+	if err == nil && result != nil && result.FailedCount > 0 {
+		err = errors.Join(result.Errors...)
+		if err == nil {
+			err = fmt.Errorf("%d 件の更新に失敗しました", result.FailedCount)
+		}
+	}
+
+	if err != nil {
+		return runner.Result{Name: name, Status: runner.StatusFailed, Err: err, Duration: duration}
+	}
+
+	return runner.Result{Name: name, Status: runner.StatusSuccess, Duration: duration}
+}
+
 func executeParallelUpdaters(ctx context.Context, updaters []updater.Updater, opts updater.UpdateOptions, jobs int, useTUI bool) updateStats {
 	switch {
 	case useTUI:
@@ -386,7 +508,7 @@ func runUpdaterJob(jobCtx context.Context, u updater.Updater, opts updater.Updat
 	}
 
 	printUpdaterResultIfNeeded(result, useTUI, outputMu)
-	mergeUpdaterResult(stats, statsMu, result)
+	mergeUpdaterResult(stats, statsMu, u.Name(), result)
 
 	return nil
 }
@@ -433,12 +555,14 @@ func printUpdaterResultIfNeeded(result *updater.UpdateResult, useTUI bool, outpu
 	outputMu.Unlock()
 }
 
-func mergeUpdaterResult(stats *updateStats, statsMu *sync.Mutex, result *updater.UpdateResult) {
+func mergeUpdaterResult(stats *updateStats, statsMu *sync.Mutex, manager string, result *updater.UpdateResult) {
 	statsMu.Lock()
 
 	stats.Updated += result.UpdatedCount
 	stats.Failed += result.FailedCount
 	stats.Errors = append(stats.Errors, result.Errors...)
+	stats.Planned = appendPlannedPackages(stats.Planned, manager, result)
+	stats.Held = append(stats.Held, result.Held...)
 
 	statsMu.Unlock()
 }
@@ -476,18 +600,33 @@ func isContextCancellation(err error) bool {
 }
 
 func mustRunExclusively(u updater.Updater) bool {
-	return u.Name() == "apt"
+	if exclusive, ok := u.(updater.ExclusiveRunner); ok && exclusive.RunsExclusively() {
+		return true
+	}
+
+	return isSystemPackageManager(u.Name())
+}
+
+func updaterNames(updaters []updater.Updater) []string {
+	names := make([]string, 0, len(updaters))
+	for _, u := range updaters {
+		names = append(names, u.Name())
+	}
+
+	return names
 }
 
 func mergeUpdateStats(dst *updateStats, src updateStats) {
 	dst.Updated += src.Updated
 	dst.Failed += src.Failed
 	dst.Errors = append(dst.Errors, src.Errors...)
+	dst.Planned = append(dst.Planned, src.Planned...)
+	dst.Held = append(dst.Held, src.Held...)
 }
 
 func phaseRequiresSudo(updaters []updater.Updater, managers map[string]config.ManagerConfig) bool {
 	for _, u := range updaters {
-		if updaterRequiresSudo(u.Name(), managers) {
+		if updaterNeedsSudo(u, managers) {
 			return true
 		}
 	}
@@ -495,6 +634,30 @@ func phaseRequiresSudo(updaters []updater.Updater, managers map[string]config.Ma
 	return false
 }
 
+// updaterNeedsSudo はマネージャの実行に sudo が必要かを判定します。
+func updaterNeedsSudo(u updater.Updater, managers map[string]config.ManagerConfig) bool {
+	// sudo の要否を自身で判断するマネージャ（カスタムコマンドなど）はその判定を優先
+	if requirer, ok := u.(updater.SudoRequirer); ok {
+		return requirer.RequiresSudo()
+	}
+
+	return updaterRequiresSudo(u.Name(), managers)
+}
+
+// splitSudoUpdaters は sudo が不要なマネージャと必要なマネージャに分けます。
+func splitSudoUpdaters(updaters []updater.Updater, managers map[string]config.ManagerConfig) (runnable, needsSudo []updater.Updater) {
+	for _, u := range updaters {
+		if updaterNeedsSudo(u, managers) {
+			needsSudo = append(needsSudo, u)
+			continue
+		}
+
+		runnable = append(runnable, u)
+	}
+
+	return runnable, needsSudo
+}
+
 func updaterRequiresSudo(name string, managers map[string]config.ManagerConfig) bool {
 	if !isSudoManagedUpdater(name) {
 		return false
@@ -509,7 +672,18 @@ func updaterRequiresSudo(name string, managers map[string]config.ManagerConfig)
 }
 
 func isSudoManagedUpdater(name string) bool {
-	return name == "apt" || name == "snap"
+	return isSystemPackageManager(name) || name == "snap"
+}
+
+// isSystemPackageManager はパッケージデータベースをロックするディストリビューションのパッケージマネージャかを判定します。
+// これらは既定で sudo を使用し、ロック競合を避けるため単独実行します。
+func isSystemPackageManager(name string) bool {
+	switch name {
+	case "apt", "dnf", "pacman", "zypper", "apk":
+		return true
+	default:
+		return false
+	}
 }
 
 func resolveManagerUseSudo(name string, managers map[string]config.ManagerConfig) (useSudo, configured bool) {
@@ -533,6 +707,11 @@ func resolveManagerUseSudo(name string, managers map[string]config.ManagerConfig
 	return false, false
 }
 
+// sudoCredentialsCached は sudo の認証情報がキャッシュされている（パスワードなしで実行できる）かを返します。
+func sudoCredentialsCached(ctx context.Context) bool {
+	return exec.CommandContext(ctx, "sudo", "-n", "true").Run() == nil
+}
+
 func ensureSudoAuthentication(ctx context.Context, phase string, suppressOutput bool) error {
 	if !suppressOutput {
 		fmt.Printf("🔐 sudo 認証を確認します（%s）...\n", phase)
@@ -554,6 +733,40 @@ func ensureSudoAuthentication(ctx context.Context, phase string, suppressOutput
 	return nil
 }
 
+// filterSecurityOnlyUpdaters はセキュリティ更新のみの適用に対応するマネージャと、それ以外に分けます。
+func filterSecurityOnlyUpdaters(updaters []updater.Updater) (supported, skipped []updater.Updater) {
+	supported = make([]updater.Updater, 0, len(updaters))
+
+	for _, u := range updaters {
+		if s, ok := u.(updater.SecurityOnlyUpdater); ok && s.SupportsSecurityOnly() {
+			supported = append(supported, u)
+			continue
+		}
+
+		skipped = append(skipped, u)
+	}
+
+	return supported, skipped
+}
+
+func printSecurityOnlySkipped(skipped []updater.Updater) {
+	if len(skipped) == 0 {
+		return
+	}
+
+	fmt.Printf("🛡️  --security-only: セキュリティ更新を判別できないマネージャをスキップします（%s）\n", strings.Join(updaterNames(skipped), ", "))
+	fmt.Println()
+}
+
+// securityMark はセキュリティ更新のパッケージに付ける表示上の印を返します。
+func securityMark(pkg updater.PackageInfo) string {
+	if pkg.Security {
+		return " [security]"
+	}
+
+	return ""
+}
+
 // printUpdaterHeader はマネージャのヘッダーを表示します。
 func printUpdaterHeader(u updater.Updater) {
 	fmt.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
@@ -572,13 +785,17 @@ func printUpdaterResult(result *updater.UpdateResult) {
 
 		for _, pkg := range result.Packages {
 			if pkg.CurrentVersion != "" {
-				fmt.Printf("    - %s: %s → %s\n", pkg.Name, pkg.CurrentVersion, pkg.NewVersion)
+				fmt.Printf("    - %s: %s → %s%s\n", pkg.Name, pkg.CurrentVersion, pkg.NewVersion, securityMark(pkg))
 			} else {
-				fmt.Printf("    - %s %s\n", pkg.Name, pkg.NewVersion)
+				fmt.Printf("    - %s %s%s\n", pkg.Name, pkg.NewVersion, securityMark(pkg))
 			}
 		}
 	}
 
+	for _, pkg := range result.Held {
+		fmt.Printf("  ⏸️  %s\n", heldUpdateLine(pkg))
+	}
+
 	if len(result.Errors) > 0 {
 		for _, e := range result.Errors {
 			fmt.Fprintf(os.Stderr, "  ⚠️  %v\n", e)
@@ -586,6 +803,33 @@ func printUpdaterResult(result *updater.UpdateResult) {
 	}
 }
 
+// heldUpdateLine は保留した更新の説明です（例: typescript: 4.9.5 → 5.5.2（メジャー更新があります。--allow-major で適用））。
+func heldUpdateLine(pkg updater.PackageInfo) string {
+	kind := "メジャー"
+
+	switch updater.VersionBump(pkg.CurrentVersion, pkg.NewVersion) {
+	case updater.BumpMinor:
+		kind = "マイナー"
+	case updater.BumpPatch:
+		kind = "パッチ"
+	}
+
+	return fmt.Sprintf("%s: %s → %s（%s更新があります。--allow-major で適用）", pkg.Name, pkg.CurrentVersion, pkg.NewVersion, kind)
+}
+
+// printHeldUpdates は保留した更新を一覧表示します（TUI では各マネージャの結果を表示しないため、完了後にまとめて出力）。
+func printHeldUpdates(held []updater.PackageInfo) {
+	if len(held) == 0 {
+		return
+	}
+
+	fmt.Printf("⏸️  %d 件の更新を保留しました:\n", len(held))
+
+	for _, pkg := range held {
+		fmt.Printf("  - %s\n", heldUpdateLine(pkg))
+	}
+}
+
 // printUpdateSummary は更新サマリーを表示します。
 func printUpdateSummary(stats updateStats) {
 	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
@@ -593,6 +837,10 @@ func printUpdateSummary(stats updateStats) {
 	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 	fmt.Printf("  更新成功: %d 件\n", stats.Updated)
 
+	if len(stats.Held) > 0 {
+		fmt.Printf("  保留: %d 件（--allow-major で適用）\n", len(stats.Held))
+	}
+
 	if stats.Failed > 0 {
 		fmt.Printf("  失敗: %d 件\n", stats.Failed)
 	}
@@ -612,6 +860,8 @@ func runSysList(cmd *cobra.Command, args []string) error {
 		cfg = config.Default()
 	}
 
+	registerExternalUpdaters(&cfg.Sys)
+
 	enabledSet := make(map[string]bool)
 	for _, name := range cfg.Sys.Enable {
 		enabledSet[name] = true
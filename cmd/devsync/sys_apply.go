@@ -0,0 +1,268 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/scottlz0310/devsync/internal/config"
+	"github.com/scottlz0310/devsync/internal/updater"
+	"github.com/spf13/cobra"
+)
+
+var sysApplyPrune bool
+
+// sysApplyCmd は宣言されたパッケージ一覧に環境を合わせます
+var sysApplyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "宣言されたパッケージ一覧（sys.managers.<name>.packages）に環境を合わせます",
+	Long: `config.yaml の sys.managers.<name>.packages に宣言されたパッケージのうち、
+未インストールのものをインストールします。
+--prune（または remove_unlisted: true）を指定すると、一覧にないパッケージを削除します。
+
+対応マネージャ: go, npm, pnpm, pipx, uv, cargo, brew, flatpak`,
+	Example: `  devsync sys apply            # 不足しているパッケージをインストール
+  devsync sys apply --dry-run  # 計画のみ表示
+  devsync sys apply --prune    # 一覧にないパッケージも削除`,
+	RunE: runSysApply,
+}
+
+// packageTarget は宣言的パッケージ一覧の適用対象です。
+type packageTarget struct {
+	installer updater.PackageInstaller
+	desired   []string
+	prune     bool
+}
+
+func init() {
+	sysCmd.AddCommand(sysApplyCmd)
+
+	sysApplyCmd.Flags().BoolVarP(&sysDryRun, "dry-run", "n", false, "実際のインストール・削除は行わず、計画のみ表示")
+	sysApplyCmd.Flags().BoolVar(&sysApplyPrune, "prune", false, "一覧にないパッケージを削除（既定値は各マネージャの remove_unlisted）")
+	sysApplyCmd.Flags().StringVarP(&sysTimeout, "timeout", "t", "10m", "全体のタイムアウト時間")
+	addLockFlags(sysApplyCmd)
+}
+
+func runSysApply(cmd *cobra.Command, args []string) error {
+	cfg, opts := loadSysUpdateConfig(cmd)
+
+	// インストール・削除がスケジュール実行の run などとパッケージマネージャのロックを奪い合わないよう、多重起動を防ぐ
+	release, err := acquireCommandLock(cmd)
+	if err != nil {
+		return err
+	}
+
+	defer release()
+
+	ctx, cancel := setupContext()
+	defer cancel()
+
+	enabledUpdaters, err := updater.GetEnabled(ctx, &cfg.Sys)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
+	}
+
+	var prune *bool
+	if cmd.Flags().Changed("prune") {
+		prune = &sysApplyPrune
+	}
+
+	targets, warnings := collectPackageTargets(enabledUpdaters, &cfg.Sys, prune)
+	for _, warning := range warnings {
+		fmt.Fprintf(os.Stderr, "⚠️  %s\n", warning)
+	}
+
+	if len(targets) == 0 {
+		printNoPackageTargetHelp()
+		return nil
+	}
+
+	fmt.Println("📦 宣言されたパッケージ一覧を適用します...")
+	fmt.Println()
+	printSysUpdateDryRunNotice(opts.DryRun)
+
+	var errs []error
+
+	for _, target := range targets {
+		fmt.Printf("📦 %s\n", target.installer.DisplayName())
+
+		result, err := updater.ApplyPackages(ctx, target.installer, target.desired, updater.ApplyOptions{
+			DryRun: opts.DryRun,
+			Prune:  target.prune,
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ エラー: %v\n\n", err)
+			errs = append(errs, fmt.Errorf("%s: %w", target.installer.Name(), err))
+
+			continue
+		}
+
+		printApplyResult(os.Stdout, result, target.prune, opts.DryRun)
+		fmt.Println()
+
+		for _, applyErr := range result.Errors {
+			errs = append(errs, fmt.Errorf("%s: %w", target.installer.Name(), applyErr))
+		}
+	}
+
+	printFailedErrors(errs)
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%d 件のエラーが発生しました", len(errs))
+	}
+
+	fmt.Println("✅ パッケージ一覧の適用が完了しました")
+
+	return nil
+}
+
+// collectPackageTargets は packages が宣言された有効なマネージャを適用対象として返します。
+// prune が nil の場合は各マネージャの remove_unlisted を使用します。
+func collectPackageTargets(enabled []updater.Updater, sysCfg *config.SysConfig, prune *bool) ([]packageTarget, []string) {
+	targets := make([]packageTarget, 0, len(enabled))
+	warnings := make([]string, 0)
+	enabledSet := make(map[string]bool, len(enabled))
+
+	for _, u := range enabled {
+		enabledSet[u.Name()] = true
+		managerCfg := sysCfg.Managers[u.Name()]
+
+		desired := updater.DesiredPackages(u.Name(), managerCfg)
+		if len(desired) == 0 {
+			continue
+		}
+
+		installer, ok := u.(updater.PackageInstaller)
+		if !ok {
+			warnings = append(warnings, fmt.Sprintf("%s は packages による宣言的な管理に対応していません", u.Name()))
+			continue
+		}
+
+		target := packageTarget{
+			installer: installer,
+			desired:   desired,
+			prune:     updater.RemoveUnlistedEnabled(managerCfg),
+		}
+		if prune != nil {
+			target.prune = *prune
+		}
+
+		targets = append(targets, target)
+	}
+
+	names := make([]string, 0, len(sysCfg.Managers))
+	for name := range sysCfg.Managers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		if enabledSet[name] {
+			continue
+		}
+
+		if _, ok := sysCfg.Managers[name]["packages"]; ok {
+			warnings = append(warnings, fmt.Sprintf("%s は sys.enable に含まれていない（または利用できない）ため packages を適用しません", name))
+		}
+	}
+
+	return targets, warnings
+}
+
+func printNoPackageTargetHelp() {
+	fmt.Println("📝 packages が宣言された有効なマネージャがありません。")
+	fmt.Println()
+	fmt.Println("💡 config.yaml の sys.managers.<name>.packages にパッケージを宣言してください。")
+	fmt.Println("   例:")
+	fmt.Println("     sys:")
+	fmt.Println("       enable: [\"npm\"]")
+	fmt.Println("       managers:")
+	fmt.Println("         npm:")
+	fmt.Println("           packages: [\"typescript\", \"@biomejs/biome\"]")
+}
+
+func printApplyResult(w io.Writer, result *updater.ApplyResult, prune, dryRun bool) {
+	if !result.Drift.HasDrift() {
+		fmt.Fprintln(w, "  ✅ 宣言どおりです")
+		return
+	}
+
+	installLabel, removeLabel := "インストール", "削除"
+	if dryRun {
+		installLabel, removeLabel = "インストール予定", "削除予定"
+	}
+
+	for _, spec := range result.Installed {
+		fmt.Fprintf(w, "  ＋ %s（%s）\n", spec, installLabel)
+	}
+
+	for _, name := range result.Removed {
+		fmt.Fprintf(w, "  － %s（%s）\n", name, removeLabel)
+	}
+
+	if !prune && len(result.Drift.Unlisted) > 0 {
+		fmt.Fprintf(w, "  ・ 一覧にないパッケージ: %s（--prune で削除）\n", strings.Join(result.Drift.Unlisted, ", "))
+	}
+}
+
+// packageDriftReport はマネージャごとの差分確認結果です。
+type packageDriftReport struct {
+	Manager string
+	Drift   *updater.PackageDrift
+	Err     error
+}
+
+// checkPackageDrift は各対象マネージャの宣言一覧との差分を確認します。
+// 一覧にないパッケージは prune が有効なマネージャのみ差分として扱います。
+func checkPackageDrift(ctx context.Context, targets []packageTarget) []packageDriftReport {
+	reports := make([]packageDriftReport, 0, len(targets))
+
+	for _, target := range targets {
+		drift, err := updater.CheckDrift(ctx, target.installer, target.desired)
+		if drift != nil && !target.prune {
+			drift.Unlisted = nil
+		}
+
+		reports = append(reports, packageDriftReport{
+			Manager: target.installer.Name(),
+			Drift:   drift,
+			Err:     err,
+		})
+	}
+
+	return reports
+}
+
+// writePackageDriftTable は差分を表形式で出力し、差分の件数を返します。
+func writePackageDriftTable(w io.Writer, reports []packageDriftReport) int {
+	count := 0
+
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "MANAGER\tSTATE\tPACKAGE")
+
+	for _, report := range reports {
+		if report.Err != nil {
+			fmt.Fprintf(tw, "%s\t確認失敗\t%v\n", report.Manager, report.Err)
+			continue
+		}
+
+		for _, spec := range report.Drift.Missing {
+			fmt.Fprintf(tw, "%s\t未インストール\t%s\n", report.Manager, spec)
+			count++
+		}
+
+		for _, name := range report.Drift.Unlisted {
+			fmt.Fprintf(tw, "%s\t一覧外\t%s\n", report.Manager, name)
+			count++
+		}
+	}
+
+	_ = tw.Flush()
+
+	return count
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/scottlz0310/devsync/internal/config"
+	"github.com/scottlz0310/devsync/internal/updater"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubInstaller は宣言的パッケージ管理に対応したテスト用の Updater です。
+type stubInstaller struct {
+	stubUpdater
+	installed []string
+	listErr   error
+}
+
+func (s stubInstaller) PackageName(spec string) string {
+	return spec
+}
+
+func (s stubInstaller) InstalledPackages(context.Context) ([]string, error) {
+	return s.installed, s.listErr
+}
+
+func (s stubInstaller) InstallPackage(context.Context, string) error {
+	return nil
+}
+
+func (s stubInstaller) RemovePackage(context.Context, string) error {
+	return nil
+}
+
+func TestCollectPackageTargets(t *testing.T) {
+	enabled := []updater.Updater{
+		stubInstaller{stubUpdater: stubUpdater{name: "npm"}},
+		stubInstaller{stubUpdater: stubUpdater{name: "pipx"}},
+		stubUpdater{name: "apt"},
+		stubInstaller{stubUpdater: stubUpdater{name: "cargo"}},
+	}
+	sysCfg := &config.SysConfig{
+		Managers: map[string]config.ManagerConfig{
+			"npm":   {"packages": []interface{}{"typescript"}, "remove_unlisted": true},
+			"pipx":  {"packages": []interface{}{"ruff"}},
+			"apt":   {"packages": []interface{}{"git"}},
+			"brew":  {"packages": []interface{}{"jq"}},
+			"cargo": {"use_sudo": false},
+		},
+	}
+
+	t.Run("remove_unlistedを反映", func(t *testing.T) {
+		targets, warnings := collectPackageTargets(enabled, sysCfg, nil)
+		require.Len(t, targets, 2)
+		assert.Equal(t, "npm", targets[0].installer.Name())
+		assert.True(t, targets[0].prune)
+		assert.Equal(t, "pipx", targets[1].installer.Name())
+		assert.False(t, targets[1].prune)
+		assert.Equal(t, []string{
+			"apt は packages による宣言的な管理に対応していません",
+			"brew は sys.enable に含まれていない（または利用できない）ため packages を適用しません",
+		}, warnings)
+	})
+
+	t.Run("フラグ指定が設定より優先", func(t *testing.T) {
+		prune := false
+		targets, _ := collectPackageTargets(enabled, sysCfg, &prune)
+		require.Len(t, targets, 2)
+		assert.False(t, targets[0].prune)
+	})
+}
+
+func TestPrintApplyResult(t *testing.T) {
+	result := &updater.ApplyResult{
+		Drift:     &updater.PackageDrift{Missing: []string{"eslint"}, Unlisted: []string{"zx"}},
+		Installed: []string{"eslint"},
+	}
+
+	var buf bytes.Buffer
+
+	printApplyResult(&buf, result, false, true)
+	assert.Contains(t, buf.String(), "＋ eslint（インストール予定）")
+	assert.Contains(t, buf.String(), "一覧にないパッケージ: zx（--prune で削除）")
+
+	buf.Reset()
+	printApplyResult(&buf, &updater.ApplyResult{Drift: &updater.PackageDrift{}}, false, false)
+	assert.Contains(t, buf.String(), "宣言どおりです")
+}
+
+func TestCheckPackageDriftAndTable(t *testing.T) {
+	targets := []packageTarget{
+		{
+			installer: stubInstaller{stubUpdater: stubUpdater{name: "npm"}, installed: []string{"typescript", "zx"}},
+			desired:   []string{"typescript", "eslint"},
+		},
+		{
+			installer: stubInstaller{stubUpdater: stubUpdater{name: "cargo"}, installed: []string{"ripgrep", "bat"}},
+			desired:   []string{"ripgrep"},
+			prune:     true,
+		},
+		{
+			installer: stubInstaller{stubUpdater: stubUpdater{name: "pipx"}, listErr: errors.New("pipx list failed")},
+			desired:   []string{"ruff"},
+		},
+	}
+
+	reports := checkPackageDrift(context.Background(), targets)
+	require.Len(t, reports, 3)
+	assert.Empty(t, reports[0].Drift.Unlisted, "prune 無効時は一覧外を差分にしない")
+	assert.Equal(t, []string{"bat"}, reports[1].Drift.Unlisted)
+	assert.Error(t, reports[2].Err)
+
+	var buf bytes.Buffer
+
+	count := writePackageDriftTable(&buf, reports)
+	assert.Equal(t, 2, count)
+
+	output := buf.String()
+	assert.Contains(t, output, "MANAGER")
+	assert.Regexp(t, `npm\s+未インストール\s+eslint`, output)
+	assert.Regexp(t, `cargo\s+一覧外\s+bat`, output)
+	assert.Regexp(t, `pipx\s+確認失敗\s+pipx list failed`, output)
+	assert.NotContains(t, output, "zx")
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/scottlz0310/devsync/internal/runner"
+	"github.com/scottlz0310/devsync/internal/updater"
+)
+
+// maxReleaseNoteBodyLines はリリースノート本文の表示行数の上限です（空行は数えません）。
+const maxReleaseNoteBodyLines = 15
+
+// releaseNotesFetcher はリリースノートの取得元です（テストで差し替えるためのインターフェース）。
+type releaseNotesFetcher interface {
+	Fetch(ctx context.Context, manager string, pkg updater.PackageInfo) (*updater.ReleaseNotes, error)
+}
+
+// packageReleaseNotes は 1 パッケージのリリースノートの取得結果です。
+type packageReleaseNotes struct {
+	Manager string
+	Package updater.PackageInfo
+	Notes   *updater.ReleaseNotes
+	Err     error
+}
+
+// validateSysChangelog は --changelog が DryRun と併用されているかを確認します。
+func validateSysChangelog(changelog bool, opts updater.UpdateOptions) error {
+	if changelog && !opts.DryRun {
+		return fmt.Errorf("--changelog は --dry-run（-n）と併用してください")
+	}
+
+	return nil
+}
+
+// collectReleaseNotes は対応マネージャの更新予定パッケージのリリースノートを並列に取得し、計画の順に返します。
+func collectReleaseNotes(ctx context.Context, fetcher releaseNotesFetcher, planned []plannedPackages, jobs int) []packageReleaseNotes {
+	var entries []packageReleaseNotes
+
+	for _, group := range planned {
+		if !updater.SupportsReleaseNotes(group.Manager) {
+			continue
+		}
+
+		for _, pkg := range group.Packages {
+			entries = append(entries, packageReleaseNotes{Manager: group.Manager, Package: pkg})
+		}
+	}
+
+	execJobs := make([]runner.Job, 0, len(entries))
+
+	for i := range entries {
+		entry := &entries[i]
+
+		execJobs = append(execJobs, runner.Job{
+			Name: entry.Manager + ":" + entry.Package.Name,
+			Run: func(jobCtx context.Context) error {
+				entry.Notes, entry.Err = fetcher.Fetch(jobCtx, entry.Manager, entry.Package)
+				return entry.Err
+			},
+		})
+	}
+
+	if jobs <= 0 {
+		jobs = 1
+	}
+
+	summary := runner.Execute(ctx, jobs, execJobs)
+
+	for i, result := range summary.Results {
+		if result.Status == runner.StatusSkipped && entries[i].Err == nil && entries[i].Notes == nil {
+			entries[i].Err = fmt.Errorf("キャンセルまたはタイムアウトによりスキップしました")
+		}
+	}
+
+	return entries
+}
+
+// printReleaseNotes は更新予定パッケージごとのリリースノートを出力します。
+func printReleaseNotes(w io.Writer, entries []packageReleaseNotes) {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	fmt.Fprintln(w, "📝 リリースノート")
+	fmt.Fprintln(w, "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+
+	if len(entries) == 0 {
+		fmt.Fprintln(w, "  リリースノートを取得できる更新予定のパッケージはありません（対応: npm / pnpm / go / cargo / brew）")
+		return
+	}
+
+	for _, entry := range entries {
+		fmt.Fprintf(w, "📦 %s: %s %s → %s\n", entry.Manager, entry.Package.Name, entry.Package.CurrentVersion, entry.Package.NewVersion)
+
+		switch {
+		case entry.Err != nil:
+			fmt.Fprintf(w, "  ⚠️  リリースノートを取得できません: %v\n", entry.Err)
+		case len(entry.Notes.Notes) == 0 && entry.Notes.Repository != "":
+			fmt.Fprintf(w, "  該当するリリースノートはありません（%s）\n", entry.Notes.Repository)
+		case len(entry.Notes.Notes) == 0:
+			fmt.Fprintln(w, "  該当するリリースノートはありません")
+		default:
+			for _, note := range entry.Notes.Notes {
+				writeReleaseNote(w, note)
+			}
+		}
+
+		fmt.Fprintln(w)
+	}
+}
+
+// writeReleaseNote は 1 つのリリースの見出しと本文（先頭 maxReleaseNoteBodyLines 行）を出力します。
+func writeReleaseNote(w io.Writer, note updater.ReleaseNote) {
+	heading := note.Version
+	if note.Title != "" {
+		heading += " " + note.Title
+	}
+
+	if note.Published != "" {
+		heading += " (" + note.Published + ")"
+	}
+
+	fmt.Fprintf(w, "  ▸ %s\n", heading)
+
+	lines := releaseNoteBodyLines(note.Body)
+	for i, line := range lines {
+		if i == maxReleaseNoteBodyLines {
+			fmt.Fprintf(w, "    …（以下 %d 行省略）\n", len(lines)-maxReleaseNoteBodyLines)
+			break
+		}
+
+		fmt.Fprintf(w, "    %s\n", line)
+	}
+
+	if note.URL != "" {
+		fmt.Fprintf(w, "    🔗 %s\n", note.URL)
+	}
+}
+
+// releaseNoteBodyLines は本文を行に分け、空行と HTML コメント（リリースノートのテンプレートなど）を除きます。
+func releaseNoteBodyLines(body string) []string {
+	var lines []string
+
+	for _, line := range strings.Split(strings.ReplaceAll(body, "\r\n", "\n"), "\n") {
+		line = strings.TrimRight(line, " \t")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(strings.TrimSpace(line), "<!--") {
+			continue
+		}
+
+		lines = append(lines, line)
+	}
+
+	return lines
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/scottlz0310/devsync/internal/updater"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubReleaseNotesFetcher struct {
+	notes map[string]*updater.ReleaseNotes
+}
+
+func (s stubReleaseNotesFetcher) Fetch(_ context.Context, manager string, pkg updater.PackageInfo) (*updater.ReleaseNotes, error) {
+	notes, ok := s.notes[manager+"/"+pkg.Name]
+	if !ok {
+		return nil, errors.New("パッケージが見つかりません")
+	}
+
+	return notes, nil
+}
+
+func TestValidateSysChangelog(t *testing.T) {
+	require.NoError(t, validateSysChangelog(false, updater.UpdateOptions{}))
+	require.NoError(t, validateSysChangelog(true, updater.UpdateOptions{DryRun: true}))
+	require.Error(t, validateSysChangelog(true, updater.UpdateOptions{}), "DryRun 以外では指定できない")
+}
+
+func TestCollectReleaseNotes(t *testing.T) {
+	fetcher := stubReleaseNotesFetcher{notes: map[string]*updater.ReleaseNotes{
+		"npm/typescript": {Notes: []updater.ReleaseNote{{Version: "v5.5.2"}}},
+		"cargo/ripgrep":  {Repository: "https://github.com/BurntSushi/ripgrep/releases"},
+	}}
+
+	planned := []plannedPackages{
+		{Manager: "apt", Packages: []updater.PackageInfo{{Name: "curl", CurrentVersion: "7", NewVersion: "8"}}},
+		{Manager: "npm", Packages: []updater.PackageInfo{
+			{Name: "typescript", CurrentVersion: "5.4.5", NewVersion: "5.5.2"},
+			{Name: "missing", CurrentVersion: "1.0.0", NewVersion: "2.0.0"},
+		}},
+		{Manager: "cargo", Packages: []updater.PackageInfo{{Name: "ripgrep", CurrentVersion: "14.0.3", NewVersion: "14.1.1"}}},
+	}
+
+	entries := collectReleaseNotes(context.Background(), fetcher, planned, 2)
+	require.Len(t, entries, 3, "リリースノート未対応のマネージャ（apt）は対象外")
+
+	assert.Equal(t, "typescript", entries[0].Package.Name)
+	require.NoError(t, entries[0].Err)
+	assert.Equal(t, "v5.5.2", entries[0].Notes.Notes[0].Version)
+	assert.Equal(t, "missing", entries[1].Package.Name)
+	require.Error(t, entries[1].Err)
+	assert.Equal(t, "ripgrep", entries[2].Package.Name)
+	require.NoError(t, entries[2].Err)
+}
+
+func TestPrintReleaseNotes(t *testing.T) {
+	longBody := make([]string, 0, maxReleaseNoteBodyLines+3)
+	for i := 1; i <= maxReleaseNoteBodyLines+3; i++ {
+		longBody = append(longBody, fmt.Sprintf("- 変更 %d", i), "")
+	}
+
+	entries := []packageReleaseNotes{
+		{
+			Manager: "npm",
+			Package: updater.PackageInfo{Name: "typescript", CurrentVersion: "5.4.5", NewVersion: "5.5.2"},
+			Notes: &updater.ReleaseNotes{Notes: []updater.ReleaseNote{
+				{
+					Version:   "v5.5.2",
+					Title:     "TypeScript 5.5.2",
+					Body:      "<!-- テンプレート -->\r\n## 修正\r\n\r\n- バグ修正",
+					URL:       "https://github.com/microsoft/TypeScript/releases/tag/v5.5.2",
+					Published: "2024-06-20",
+				},
+				{Version: "v5.5.0", Body: strings.Join(longBody, "\n")},
+			}},
+		},
+		{
+			Manager: "cargo",
+			Package: updater.PackageInfo{Name: "ripgrep", CurrentVersion: "14.0.3", NewVersion: "14.1.1"},
+			Notes:   &updater.ReleaseNotes{Repository: "https://github.com/BurntSushi/ripgrep/releases"},
+		},
+		{
+			Manager: "go",
+			Package: updater.PackageInfo{Name: "gopls", CurrentVersion: "v0.16.1", NewVersion: "v0.16.2"},
+			Notes:   &updater.ReleaseNotes{},
+		},
+		{
+			Manager: "brew",
+			Package: updater.PackageInfo{Name: "missing", CurrentVersion: "1.0", NewVersion: "1.1"},
+			Err:     errors.New("パッケージが見つかりません"),
+		},
+	}
+
+	var buf bytes.Buffer
+
+	printReleaseNotes(&buf, entries)
+	output := buf.String()
+
+	for _, want := range []string{
+		"📝 リリースノート",
+		"📦 npm: typescript 5.4.5 → 5.5.2",
+		"  ▸ v5.5.2 TypeScript 5.5.2 (2024-06-20)\n    ## 修正\n    - バグ修正\n    🔗 https://github.com/microsoft/TypeScript/releases/tag/v5.5.2\n",
+		fmt.Sprintf("    - 変更 %d\n    …（以下 3 行省略）\n", maxReleaseNoteBodyLines),
+		"  該当するリリースノートはありません（https://github.com/BurntSushi/ripgrep/releases）",
+		"📦 go: gopls v0.16.1 → v0.16.2\n  該当するリリースノートはありません\n",
+		"  ⚠️  リリースノートを取得できません: パッケージが見つかりません",
+	} {
+		assert.Contains(t, output, want)
+	}
+
+	assert.NotContains(t, output, "テンプレート", "HTML コメントは表示しない")
+	assert.NotContains(t, output, fmt.Sprintf("変更 %d\n", maxReleaseNoteBodyLines+1))
+
+	buf.Reset()
+	printReleaseNotes(&buf, nil)
+	assert.Contains(t, buf.String(), "リリースノートを取得できる更新予定のパッケージはありません")
+}
+
+func TestAppendPlannedPackages(t *testing.T) {
+	var planned []plannedPackages
+
+	planned = appendPlannedPackages(planned, "apt", &updater.UpdateResult{})
+	assert.Empty(t, planned, "パッケージのない結果は記録しない")
+
+	planned = appendPlannedPackages(planned, "npm", &updater.UpdateResult{Packages: []updater.PackageInfo{{Name: "typescript"}}})
+	assert.Equal(t, []plannedPackages{{Manager: "npm", Packages: []updater.PackageInfo{{Name: "typescript"}}}}, planned)
+}
@@ -0,0 +1,398 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/scottlz0310/devsync/internal/config"
+	"github.com/scottlz0310/devsync/internal/runner"
+	"github.com/scottlz0310/devsync/internal/updater"
+	"github.com/spf13/cobra"
+)
+
+// sys check の終了コード
+const (
+	sysCheckExitPending = 1
+	sysCheckExitFailed  = 2
+)
+
+// sysCheckCmd は更新可能なパッケージと宣言一覧との差分を確認します
+var sysCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "更新可能なパッケージと宣言されたパッケージ一覧との差分を確認します",
+	Long: `有効なマネージャの更新確認（Check）のみを並列実行し、更新可能なパッケージを一覧表示します。
+パッケージの更新や apt update などの状態を変更する処理は行いません。
+sys.managers.<name>.packages が宣言されている場合は、宣言一覧との差分も表示します。
+OSV の脆弱性データ（sys.osv_dir）がある場合は、更新で修正される既知の脆弱性も表示します。
+
+終了コード:
+  0  更新可能なパッケージ・差分なし
+  1  更新可能なパッケージまたは宣言一覧との差分あり
+  2  確認に失敗したマネージャあり`,
+	Example: `  devsync sys check                    # 更新可能なパッケージを確認
+  devsync sys check -j 4               # 4並列で確認
+  devsync sys check --vulnerable-only  # 既知の脆弱性を修正する更新のみ表示`,
+	RunE: runSysCheck,
+}
+
+// managerCheckReport はマネージャごとの更新確認結果です。
+type managerCheckReport struct {
+	Manager string
+	Result  *updater.CheckResult
+	Err     error
+}
+
+// maxListedVulnerabilities は表に列挙する脆弱性 ID の上限です。
+const maxListedVulnerabilities = 3
+
+var sysCheckVulnerableOnly bool
+
+func init() {
+	sysCmd.AddCommand(sysCheckCmd)
+
+	sysCheckCmd.Flags().IntVarP(&sysJobs, "jobs", "j", 0, "並列実行数（0以下の場合は設定値または1を使用）")
+	sysCheckCmd.Flags().StringVarP(&sysTimeout, "timeout", "t", "10m", "全体のタイムアウト時間")
+	sysCheckCmd.Flags().BoolVar(&sysCheckVulnerableOnly, "vulnerable-only", false, "既知の脆弱性（OSV）を修正する更新のみを表示")
+}
+
+func runSysCheck(cmd *cobra.Command, args []string) error {
+	cfg, _ := loadSysUpdateConfig(cmd)
+
+	ctx, cancel := setupContext()
+	defer cancel()
+
+	enabledUpdaters, err := updater.GetEnabled(ctx, &cfg.Sys)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
+	}
+
+	if len(enabledUpdaters) == 0 {
+		printNoManagerHelp()
+		return nil
+	}
+
+	fmt.Println("🔍 更新可能なパッケージを確認しています...")
+	fmt.Println()
+
+	jobs := resolveSysJobs(cfg.Control.Concurrency, sysJobs)
+	reports := runUpdaterChecks(ctx, enabledUpdaters, jobs)
+
+	if err := enrichCheckReports(reports, &cfg.Sys, sysCheckVulnerableOnly); err != nil {
+		return err
+	}
+
+	pending, failed := printCheckReports(os.Stdout, reports, sysCheckVulnerableOnly)
+
+	// 脆弱性の修正に絞り込む場合は、宣言一覧との差分は確認しない
+	if sysCheckVulnerableOnly {
+		return sysCheckResult(pending, 0, failed)
+	}
+
+	drift, driftFailed := printPackageDriftSection(ctx, enabledUpdaters, &cfg.Sys)
+
+	return sysCheckResult(pending, drift, failed+driftFailed)
+}
+
+// enrichCheckReports は OSV の脆弱性データと照合し、更新で修正される脆弱性を各パッケージに設定します。
+// vulnerableOnly の場合は脆弱性を修正する更新のみに絞り込み、データを読み込めなければエラーにします。
+// sys.osv_dir が未設定で既定のディレクトリもない場合は照合を省略します。
+func enrichCheckReports(reports []managerCheckReport, sysCfg *config.SysConfig, vulnerableOnly bool) error {
+	managers := make([]string, 0, len(reports))
+
+	for _, report := range reports {
+		if report.Result != nil && updater.SupportsVulnerabilityLookup(report.Manager) {
+			managers = append(managers, report.Manager)
+		}
+	}
+
+	if len(managers) > 0 {
+		dir, err := resolveOSVDir(sysCfg.OSVDir)
+		if err != nil {
+			return err
+		}
+
+		db, err := updater.LoadVulnerabilityDatabase(dir, managers)
+
+		switch {
+		case err == nil:
+			for _, report := range reports {
+				db.Enrich(report.Manager, report.Result)
+			}
+		case vulnerableOnly:
+			return fmt.Errorf("%w\n💡 OSV のダンプ（例: https://osv-vulnerabilities.storage.googleapis.com/npm/all.zip）を %s に配置してください", err, dir)
+		case sysCfg.OSVDir != "" || !errors.Is(err, fs.ErrNotExist):
+			fmt.Fprintf(os.Stderr, "⚠️  脆弱性データとの照合を省略します: %v\n", err)
+		}
+	}
+
+	if vulnerableOnly {
+		for _, report := range reports {
+			filterVulnerablePackages(report.Result)
+		}
+	}
+
+	return nil
+}
+
+// resolveOSVDir は OSV データのディレクトリを返します（未設定の場合はユーザーキャッシュ配下の devsync/osv）。
+func resolveOSVDir(configured string) (string, error) {
+	if strings.TrimSpace(configured) != "" {
+		return expandHomePath(strings.TrimSpace(configured))
+	}
+
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("キャッシュディレクトリの取得に失敗: %w", err)
+	}
+
+	return filepath.Join(cacheDir, "devsync", "osv"), nil
+}
+
+// filterVulnerablePackages は既知の脆弱性を修正する更新のみを残します。
+func filterVulnerablePackages(result *updater.CheckResult) {
+	if result == nil {
+		return
+	}
+
+	packages := make([]updater.PackageInfo, 0, len(result.Packages))
+
+	for _, pkg := range result.Packages {
+		if len(pkg.Vulnerabilities) > 0 {
+			packages = append(packages, pkg)
+		}
+	}
+
+	result.Packages = packages
+	result.AvailableUpdates = len(packages)
+}
+
+// runUpdaterChecks は各マネージャの Check を並列実行し、有効化順に結果を返します。
+func runUpdaterChecks(ctx context.Context, updaters []updater.Updater, jobs int) []managerCheckReport {
+	reports := make([]managerCheckReport, len(updaters))
+	execJobs := make([]runner.Job, 0, len(updaters))
+
+	for i, updaterItem := range updaters {
+		index, u := i, updaterItem
+		reports[index].Manager = u.Name()
+
+		execJobs = append(execJobs, runner.Job{
+			Name: u.Name(),
+			Run: func(jobCtx context.Context) error {
+				result, err := u.Check(jobCtx)
+				reports[index].Result = result
+				reports[index].Err = err
+
+				return err
+			},
+		})
+	}
+
+	summary := runner.Execute(ctx, jobs, execJobs)
+
+	// Results はジョブの投入順に並ぶため、添字で対応付けられる
+	for i, result := range summary.Results {
+		if result.Status == runner.StatusSkipped && reports[i].Err == nil {
+			reports[i].Err = fmt.Errorf("キャンセルまたはタイムアウトによりスキップしました")
+		}
+	}
+
+	return reports
+}
+
+// printCheckReports は更新可能なパッケージの表と確認失敗を出力し、更新可能件数と失敗件数を返します。
+// vulnerableOnly の場合は、既知の脆弱性を修正する更新に絞り込んだ結果として要約します。
+func printCheckReports(w io.Writer, reports []managerCheckReport, vulnerableOnly bool) (pending, failed int) {
+	security, vulnerable := 0, 0
+
+	for _, report := range reports {
+		switch {
+		case report.Err != nil:
+			failed++
+		case report.Result != nil:
+			pending += report.Result.AvailableUpdates
+
+			for _, pkg := range report.Result.Packages {
+				if pkg.Security {
+					security++
+				}
+
+				if len(pkg.Vulnerabilities) > 0 {
+					vulnerable++
+				}
+			}
+		}
+	}
+
+	if pending > 0 {
+		writeCheckTable(w, reports)
+		fmt.Fprintln(w)
+	}
+
+	for _, report := range reports {
+		if report.Err != nil {
+			fmt.Fprintf(w, "❌ %s: 更新確認に失敗: %v\n", report.Manager, report.Err)
+		}
+	}
+
+	switch {
+	case vulnerableOnly && pending > 0:
+		fmt.Fprintf(w, "🛡️  %d 件のパッケージの更新で既知の脆弱性が修正されます（devsync sys update で更新できます）\n", pending)
+	case vulnerableOnly && failed == 0:
+		fmt.Fprintln(w, "✅ 既知の脆弱性を修正する保留中の更新はありません")
+	case pending > 0 && security > 0:
+		fmt.Fprintf(w, "📦 %d 件のパッケージが更新可能です（うちセキュリティ更新 %d 件。devsync sys update --security-only で適用できます）\n", pending, security)
+	case pending > 0:
+		fmt.Fprintf(w, "📦 %d 件のパッケージが更新可能です（devsync sys update で更新できます）\n", pending)
+	case failed == 0:
+		fmt.Fprintln(w, "✅ すべてのパッケージは最新です")
+	}
+
+	if !vulnerableOnly && vulnerable > 0 {
+		fmt.Fprintf(w, "🛡️  うち %d 件は既知の脆弱性を修正する更新です（--vulnerable-only で絞り込めます）\n", vulnerable)
+	}
+
+	return pending, failed
+}
+
+// writeCheckTable は更新可能なパッケージをマネージャごとに表形式で出力します。
+// 脆弱性データと照合できた場合は、更新で修正される脆弱性の列を追加します。
+func writeCheckTable(w io.Writer, reports []managerCheckReport) {
+	withVulnerabilities := hasVulnerabilities(reports)
+
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+
+	if withVulnerabilities {
+		fmt.Fprintln(tw, "MANAGER\tPACKAGE\tCURRENT\tNEW\tFIXES")
+	} else {
+		fmt.Fprintln(tw, "MANAGER\tPACKAGE\tCURRENT\tNEW")
+	}
+
+	for _, report := range reports {
+		if report.Err != nil || report.Result == nil || report.Result.AvailableUpdates == 0 {
+			continue
+		}
+
+		if len(report.Result.Packages) == 0 {
+			fmt.Fprintf(tw, "%s\t(%d 件)\t-\t-\n", report.Manager, report.Result.AvailableUpdates)
+			continue
+		}
+
+		for _, pkg := range report.Result.Packages {
+			row := fmt.Sprintf("%s\t%s\t%s\t%s", report.Manager, pkg.Name, valueOrDash(pkg.CurrentVersion), valueOrDash(pkg.NewVersion)+securityMark(pkg))
+			if withVulnerabilities {
+				row += "\t" + valueOrDash(formatVulnerabilities(pkg.Vulnerabilities))
+			}
+
+			fmt.Fprintln(tw, row)
+		}
+	}
+
+	_ = tw.Flush()
+}
+
+func hasVulnerabilities(reports []managerCheckReport) bool {
+	for _, report := range reports {
+		if report.Err != nil || report.Result == nil {
+			continue
+		}
+
+		for _, pkg := range report.Result.Packages {
+			if len(pkg.Vulnerabilities) > 0 {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// formatVulnerabilities は脆弱性 ID を列挙します（多い場合は件数で省略）。
+func formatVulnerabilities(vulnerabilities []updater.Vulnerability) string {
+	ids := make([]string, 0, maxListedVulnerabilities)
+
+	for i, vulnerability := range vulnerabilities {
+		if i == maxListedVulnerabilities {
+			return strings.Join(ids, ", ") + fmt.Sprintf(" 他 %d 件", len(vulnerabilities)-maxListedVulnerabilities)
+		}
+
+		ids = append(ids, vulnerability.ID)
+	}
+
+	return strings.Join(ids, ", ")
+}
+
+func valueOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+
+	return value
+}
+
+// printPackageDriftSection は宣言されたパッケージ一覧との差分を出力し、差分件数と確認失敗件数を返します。
+func printPackageDriftSection(ctx context.Context, enabled []updater.Updater, sysCfg *config.SysConfig) (drift, failed int) {
+	targets, warnings := collectPackageTargets(enabled, sysCfg, nil)
+	for _, warning := range warnings {
+		fmt.Fprintf(os.Stderr, "⚠️  %s\n", warning)
+	}
+
+	if len(targets) == 0 {
+		return 0, 0
+	}
+
+	fmt.Println()
+	fmt.Println("🔍 宣言されたパッケージ一覧との差分を確認します...")
+	fmt.Println()
+
+	reports := checkPackageDrift(ctx, targets)
+
+	hasDrift := false
+
+	for _, report := range reports {
+		switch {
+		case report.Err != nil:
+			failed++
+		case report.Drift.HasDrift():
+			hasDrift = true
+		}
+	}
+
+	if !hasDrift && failed == 0 {
+		fmt.Println("✅ すべてのマネージャが宣言どおりです")
+		return 0, 0
+	}
+
+	drift = writePackageDriftTable(os.Stdout, reports)
+
+	if drift > 0 {
+		fmt.Println()
+		fmt.Println("💡 devsync sys apply で宣言どおりに揃えられます。")
+	}
+
+	return drift, failed
+}
+
+// sysCheckResult は確認結果から終了コード付きのエラーを返します。
+func sysCheckResult(pending, drift, failed int) error {
+	switch {
+	case failed > 0:
+		return &exitCodeError{
+			code: sysCheckExitFailed,
+			err:  fmt.Errorf("%d 件のマネージャで確認に失敗しました", failed),
+		}
+	case pending > 0 || drift > 0:
+		return &exitCodeError{
+			code: sysCheckExitPending,
+			err:  fmt.Errorf("更新可能なパッケージ %d 件、宣言一覧との差分 %d 件があります", pending, drift),
+		}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/scottlz0310/devsync/internal/config"
+	"github.com/scottlz0310/devsync/internal/updater"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunUpdaterChecks(t *testing.T) {
+	updaters := []updater.Updater{
+		stubUpdater{name: "apt", checkResult: &updater.CheckResult{
+			AvailableUpdates: 2,
+			Packages: []updater.PackageInfo{
+				{Name: "vim", CurrentVersion: "8.2.1", NewVersion: "9.0.2"},
+				{Name: "curl", CurrentVersion: "7.88.1", NewVersion: "8.5.0"},
+			},
+		}},
+		stubUpdater{name: "npm", checkErr: errors.New("npm outdated failed")},
+		stubUpdater{name: "go"},
+	}
+
+	reports := runUpdaterChecks(context.Background(), updaters, 3)
+	require.Len(t, reports, 3)
+	assert.Equal(t, "apt", reports[0].Manager)
+	assert.Equal(t, 2, reports[0].Result.AvailableUpdates)
+	assert.Equal(t, "npm", reports[1].Manager)
+	assert.Error(t, reports[1].Err)
+	assert.Equal(t, "go", reports[2].Manager)
+	assert.NoError(t, reports[2].Err)
+}
+
+func TestRunUpdaterChecks_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	reports := runUpdaterChecks(ctx, []updater.Updater{stubUpdater{name: "apt"}}, 1)
+	require.Len(t, reports, 1)
+	assert.Error(t, reports[0].Err)
+}
+
+func TestPrintCheckReports(t *testing.T) {
+	testCases := []struct {
+		name        string
+		reports     []managerCheckReport
+		wantPending int
+		wantFailed  int
+		contains    []string
+		notContains []string
+	}{
+		{
+			name: "更新可能なパッケージを表で表示",
+			reports: []managerCheckReport{
+				{Manager: "apt", Result: &updater.CheckResult{
+					AvailableUpdates: 1,
+					Packages:         []updater.PackageInfo{{Name: "vim", CurrentVersion: "8.2.1", NewVersion: "9.0.2"}},
+				}},
+				{Manager: "snap", Result: &updater.CheckResult{AvailableUpdates: 3}},
+				{Manager: "pipx", Result: &updater.CheckResult{
+					Packages: []updater.PackageInfo{{Name: "ruff", CurrentVersion: "0.6.0"}},
+				}},
+			},
+			wantPending: 4,
+			contains: []string{
+				"MANAGER",
+				"4 件のパッケージが更新可能です",
+			},
+			notContains: []string{"ruff", "すべてのパッケージは最新です"},
+		},
+		{
+			name: "セキュリティ更新の件数を表示",
+			reports: []managerCheckReport{
+				{Manager: "apt", Result: &updater.CheckResult{
+					AvailableUpdates: 2,
+					Packages: []updater.PackageInfo{
+						{Name: "openssl", CurrentVersion: "3.0.2-0ubuntu1.17", NewVersion: "3.0.2-0ubuntu1.18", Security: true},
+						{Name: "vim", CurrentVersion: "8.2.1", NewVersion: "9.0.2"},
+					},
+				}},
+			},
+			wantPending: 2,
+			contains: []string{
+				"3.0.2-0ubuntu1.18 [security]",
+				"うちセキュリティ更新 1 件",
+			},
+			notContains: []string{"9.0.2 [security]"},
+		},
+		{
+			name: "すべて最新",
+			reports: []managerCheckReport{
+				{Manager: "apt", Result: &updater.CheckResult{}},
+			},
+			contains:    []string{"すべてのパッケージは最新です"},
+			notContains: []string{"MANAGER"},
+		},
+		{
+			name: "確認失敗を表示",
+			reports: []managerCheckReport{
+				{Manager: "npm", Err: errors.New("npm outdated failed")},
+			},
+			wantFailed:  1,
+			contains:    []string{"❌ npm: 更新確認に失敗: npm outdated failed"},
+			notContains: []string{"すべてのパッケージは最新です"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			pending, failed := printCheckReports(&buf, tc.reports, false)
+			assert.Equal(t, tc.wantPending, pending)
+			assert.Equal(t, tc.wantFailed, failed)
+
+			for _, want := range tc.contains {
+				assert.Contains(t, buf.String(), want)
+			}
+
+			for _, unwanted := range tc.notContains {
+				assert.NotContains(t, buf.String(), unwanted)
+			}
+		})
+	}
+}
+
+func TestWriteCheckTable(t *testing.T) {
+	var buf bytes.Buffer
+
+	writeCheckTable(&buf, []managerCheckReport{
+		{Manager: "apt", Result: &updater.CheckResult{
+			AvailableUpdates: 1,
+			Packages:         []updater.PackageInfo{{Name: "vim", CurrentVersion: "8.2.1", NewVersion: "9.0.2"}},
+		}},
+		{Manager: "snap", Result: &updater.CheckResult{AvailableUpdates: 3}},
+		{Manager: "go", Result: &updater.CheckResult{
+			AvailableUpdates: 1,
+			Packages:         []updater.PackageInfo{{Name: "gopls", NewVersion: "@latest"}},
+		}},
+	})
+
+	output := buf.String()
+	assert.Regexp(t, `apt\s+vim\s+8\.2\.1\s+9\.0\.2`, output)
+	assert.Regexp(t, `snap\s+\(3 件\)\s+-\s+-`, output)
+	assert.Regexp(t, `go\s+gopls\s+-\s+@latest`, output)
+}
+
+func TestEnrichCheckReports(t *testing.T) {
+	osvDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(osvDir, "GHSA-35jh-r3h4-6jhm.json"), []byte(`{
+  "id": "GHSA-35jh-r3h4-6jhm",
+  "affected": [{
+    "package": {"ecosystem": "npm", "name": "lodash"},
+    "ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "4.17.21"}]}]
+  }]
+}`), 0o644))
+
+	newReports := func() []managerCheckReport {
+		return []managerCheckReport{
+			{Manager: "npm", Result: &updater.CheckResult{
+				AvailableUpdates: 2,
+				Packages: []updater.PackageInfo{
+					{Name: "lodash", CurrentVersion: "4.17.20", NewVersion: "4.17.21"},
+					{Name: "typescript", CurrentVersion: "5.4.0", NewVersion: "5.6.3"},
+				},
+			}},
+			{Manager: "snap", Result: &updater.CheckResult{AvailableUpdates: 3}},
+			{Manager: "pipx", Err: errors.New("pipx failed")},
+		}
+	}
+
+	t.Run("脆弱性を付与する", func(t *testing.T) {
+		reports := newReports()
+		require.NoError(t, enrichCheckReports(reports, &config.SysConfig{OSVDir: osvDir}, false))
+
+		assert.Equal(t, "GHSA-35jh-r3h4-6jhm", reports[0].Result.Packages[0].Vulnerabilities[0].ID)
+		assert.Empty(t, reports[0].Result.Packages[1].Vulnerabilities)
+		assert.Equal(t, 3, reports[1].Result.AvailableUpdates)
+	})
+
+	t.Run("vulnerable-only は脆弱性を修正する更新のみ残す", func(t *testing.T) {
+		reports := newReports()
+		require.NoError(t, enrichCheckReports(reports, &config.SysConfig{OSVDir: osvDir}, true))
+
+		assert.Equal(t, 1, reports[0].Result.AvailableUpdates)
+		assert.Equal(t, "lodash", reports[0].Result.Packages[0].Name)
+		assert.Equal(t, 0, reports[1].Result.AvailableUpdates, "照合できないマネージャは対象外")
+
+		var buf bytes.Buffer
+
+		pending, failed := printCheckReports(&buf, reports, true)
+		assert.Equal(t, 1, pending)
+		assert.Equal(t, 1, failed)
+		assert.Contains(t, buf.String(), "1 件のパッケージの更新で既知の脆弱性が修正されます")
+		assert.Regexp(t, `npm\s+lodash\s+4\.17\.20\s+4\.17\.21\s+GHSA-35jh-r3h4-6jhm`, buf.String())
+	})
+
+	t.Run("既定のディレクトリがなければ照合を省略", func(t *testing.T) {
+		t.Setenv("XDG_CACHE_HOME", t.TempDir())
+		t.Setenv("HOME", t.TempDir())
+
+		reports := newReports()
+		require.NoError(t, enrichCheckReports(reports, &config.SysConfig{}, false))
+		assert.Empty(t, reports[0].Result.Packages[0].Vulnerabilities)
+	})
+
+	t.Run("vulnerable-only でデータがなければエラー", func(t *testing.T) {
+		err := enrichCheckReports(newReports(), &config.SysConfig{OSVDir: filepath.Join(osvDir, "missing")}, true)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "OSV のダンプ")
+	})
+}
+
+func TestFormatVulnerabilities(t *testing.T) {
+	vulnerabilities := []updater.Vulnerability{{ID: "A"}, {ID: "B"}, {ID: "C"}, {ID: "D"}, {ID: "E"}}
+
+	assert.Equal(t, "A, B", formatVulnerabilities(vulnerabilities[:2]))
+	assert.Equal(t, "A, B, C 他 2 件", formatVulnerabilities(vulnerabilities))
+	assert.Empty(t, formatVulnerabilities(nil))
+}
+
+func TestSysCheckResult(t *testing.T) {
+	testCases := []struct {
+		name     string
+		pending  int
+		drift    int
+		failed   int
+		wantCode int
+	}{
+		{name: "更新なしは正常終了", wantCode: 0},
+		{name: "更新ありは終了コード1", pending: 2, wantCode: sysCheckExitPending},
+		{name: "差分ありは終了コード1", drift: 1, wantCode: sysCheckExitPending},
+		{name: "確認失敗は終了コード2（更新ありより優先）", pending: 2, failed: 1, wantCode: sysCheckExitFailed},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := sysCheckResult(tc.pending, tc.drift, tc.failed)
+			if tc.wantCode == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			var exitErr *exitCodeError
+
+			require.True(t, errors.As(fmt.Errorf("wrapped: %w", err), &exitErr))
+			assert.Equal(t, tc.wantCode, exitErr.code)
+		})
+	}
+}
@@ -11,8 +11,10 @@ import (
 )
 
 type stubUpdater struct {
-	name      string
-	updateErr error
+	name        string
+	updateErr   error
+	checkResult *updater.CheckResult
+	checkErr    error
 }
 
 func (s stubUpdater) Name() string {
@@ -28,6 +30,14 @@ func (s stubUpdater) IsAvailable() bool {
 }
 
 func (s stubUpdater) Check(context.Context) (*updater.CheckResult, error) {
+	if s.checkErr != nil {
+		return nil, s.checkErr
+	}
+
+	if s.checkResult != nil {
+		return s.checkResult, nil
+	}
+
 	return &updater.CheckResult{}, nil
 }
 
@@ -43,6 +53,21 @@ func (s stubUpdater) Configure(config.ManagerConfig) error {
 	return nil
 }
 
+// stubCustomUpdater は sudo の要否と単独実行を自身で判断するテスト用の Updater です。
+type stubCustomUpdater struct {
+	stubUpdater
+	sudo      bool
+	exclusive bool
+}
+
+func (s stubCustomUpdater) RequiresSudo() bool {
+	return s.sudo
+}
+
+func (s stubCustomUpdater) RunsExclusively() bool {
+	return s.exclusive
+}
+
 func TestResolveSysJobs(t *testing.T) {
 	t.Parallel()
 
@@ -91,6 +116,96 @@ func TestResolveSysJobs(t *testing.T) {
 	}
 }
 
+// stubSecurityUpdater はセキュリティ更新のみの適用に対応するテスト用の Updater です。
+type stubSecurityUpdater struct {
+	stubUpdater
+	supported bool
+}
+
+func (s stubSecurityUpdater) SupportsSecurityOnly() bool {
+	return s.supported
+}
+
+func TestFilterSecurityOnlyUpdaters(t *testing.T) {
+	t.Parallel()
+
+	input := []updater.Updater{
+		stubSecurityUpdater{stubUpdater: stubUpdater{name: "apt"}, supported: true},
+		stubUpdater{name: "npm"},
+		stubSecurityUpdater{stubUpdater: stubUpdater{name: "dnf"}, supported: true},
+		stubSecurityUpdater{stubUpdater: stubUpdater{name: "plugin"}, supported: false},
+	}
+
+	supported, skipped := filterSecurityOnlyUpdaters(input)
+
+	if got := strings.Join(updaterNames(supported), ","); got != "apt,dnf" {
+		t.Fatalf("supported = %s, want apt,dnf", got)
+	}
+
+	if got := strings.Join(updaterNames(skipped), ","); got != "npm,plugin" {
+		t.Fatalf("skipped = %s, want npm,plugin", got)
+	}
+}
+
+func TestSecurityMark(t *testing.T) {
+	t.Parallel()
+
+	if got := securityMark(updater.PackageInfo{Name: "openssl", Security: true}); got != " [security]" {
+		t.Fatalf("securityMark(security) = %q, want %q", got, " [security]")
+	}
+
+	if got := securityMark(updater.PackageInfo{Name: "vim"}); got != "" {
+		t.Fatalf("securityMark(normal) = %q, want empty", got)
+	}
+}
+
+func TestHeldUpdateLine(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name string
+		pkg  updater.PackageInfo
+		want string
+	}{
+		{
+			name: "メジャー更新",
+			pkg:  updater.PackageInfo{Name: "typescript", CurrentVersion: "4.9.5", NewVersion: "5.5.2"},
+			want: "typescript: 4.9.5 → 5.5.2（メジャー更新があります。--allow-major で適用）",
+		},
+		{
+			name: "マイナー更新",
+			pkg:  updater.PackageInfo{Name: "eslint", CurrentVersion: "8.40.0", NewVersion: "8.56.0"},
+			want: "eslint: 8.40.0 → 8.56.0（マイナー更新があります。--allow-major で適用）",
+		},
+		{
+			name: "パッチ更新",
+			pkg:  updater.PackageInfo{Name: "prettier", CurrentVersion: "3.3.2", NewVersion: "3.3.3"},
+			want: "prettier: 3.3.2 → 3.3.3（パッチ更新があります。--allow-major で適用）",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := heldUpdateLine(tc.pkg); got != tc.want {
+				t.Fatalf("heldUpdateLine() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMergeUpdateStats_Held(t *testing.T) {
+	t.Parallel()
+
+	dst := updateStats{Held: []updater.PackageInfo{{Name: "typescript"}}}
+	mergeUpdateStats(&dst, updateStats{Held: []updater.PackageInfo{{Name: "ripgrep"}}})
+
+	if got := len(dst.Held); got != 2 {
+		t.Fatalf("Held length = %d, want 2", got)
+	}
+}
+
 func TestSplitUpdatersForExecution(t *testing.T) {
 	t.Parallel()
 
@@ -132,11 +247,46 @@ func TestMustRunExclusively(t *testing.T) {
 			in:   stubUpdater{name: "apt"},
 			want: true,
 		},
+		{
+			name: "dnfは単独実行",
+			in:   stubUpdater{name: "dnf"},
+			want: true,
+		},
+		{
+			name: "pacmanは単独実行",
+			in:   stubUpdater{name: "pacman"},
+			want: true,
+		},
 		{
 			name: "brewは並列可",
 			in:   stubUpdater{name: "brew"},
 			want: false,
 		},
+		{
+			name: "gh拡張機能は並列可",
+			in:   stubUpdater{name: "gh"},
+			want: false,
+		},
+		{
+			name: "krewは並列可",
+			in:   stubUpdater{name: "krew"},
+			want: false,
+		},
+		{
+			name: "helmプラグインは並列可",
+			in:   stubUpdater{name: "helm"},
+			want: false,
+		},
+		{
+			name: "exclusive指定のカスタムは単独実行",
+			in:   stubCustomUpdater{stubUpdater: stubUpdater{name: "omz"}, exclusive: true},
+			want: true,
+		},
+		{
+			name: "exclusive未指定のカスタムは並列可",
+			in:   stubCustomUpdater{stubUpdater: stubUpdater{name: "tldr"}},
+			want: false,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -315,6 +465,37 @@ func TestPhaseRequiresSudo(t *testing.T) {
 			},
 			want: false,
 		},
+		{
+			name: "zypperを含む場合はsudo必要",
+			updaters: []updater.Updater{
+				stubUpdater{name: "zypper"},
+			},
+			want: true,
+		},
+		{
+			name: "apkがsudo無効なら不要",
+			updaters: []updater.Updater{
+				stubUpdater{name: "apk"},
+			},
+			managers: map[string]config.ManagerConfig{
+				"apk": {"use_sudo": false},
+			},
+			want: false,
+		},
+		{
+			name: "use_sudoのカスタムはsudo必要",
+			updaters: []updater.Updater{
+				stubCustomUpdater{stubUpdater: stubUpdater{name: "firmware"}, sudo: true},
+			},
+			want: true,
+		},
+		{
+			name: "カスタムは自身の判定を優先",
+			updaters: []updater.Updater{
+				stubCustomUpdater{stubUpdater: stubUpdater{name: "snap"}},
+			},
+			want: false,
+		},
 		{
 			name: "sudo対象がなければ不要",
 			updaters: []updater.Updater{
@@ -337,3 +518,49 @@ func TestPhaseRequiresSudo(t *testing.T) {
 		})
 	}
 }
+
+func TestPrepareSudoPhase_NonInteractive(t *testing.T) {
+	originalNonInteractive := sysNonInteractive
+	originalCached := sudoCredentialsCachedStep
+
+	t.Cleanup(func() {
+		sysNonInteractive = originalNonInteractive
+		sudoCredentialsCachedStep = originalCached
+	})
+
+	sysNonInteractive = true
+	cfg := config.Default()
+	updaters := []updater.Updater{
+		stubUpdater{name: "apt"},
+		stubUpdater{name: "go"},
+		stubCustomUpdater{stubUpdater: stubUpdater{name: "firmware"}, sudo: true},
+	}
+
+	testCases := []struct {
+		name   string
+		cached bool
+		opts   updater.UpdateOptions
+		want   string
+	}{
+		{name: "認証情報がキャッシュ済みならすべて実行", cached: true, want: "apt,go,firmware"},
+		{name: "キャッシュがなければ sudo が必要なマネージャを除外", cached: false, want: "go"},
+		{name: "DryRun では除外しない", cached: false, opts: updater.UpdateOptions{DryRun: true}, want: "apt,go,firmware"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sudoCredentialsCachedStep = func(context.Context) bool {
+				return tc.cached
+			}
+
+			got, err := prepareSudoPhase(context.Background(), cfg, tc.opts, updaters, "並列実行フェーズ", false)
+			if err != nil {
+				t.Fatalf("prepareSudoPhase() unexpected error: %v", err)
+			}
+
+			if names := strings.Join(updaterNames(got), ","); names != tc.want {
+				t.Fatalf("prepareSudoPhase() = %s, want %s", names, tc.want)
+			}
+		})
+	}
+}
@@ -115,8 +115,9 @@ func Default() *Config {
 			Managers: map[string]ManagerConfig{},
 		},
 		Secrets: SecretsConfig{
-			Enabled:  false,
-			Provider: "bitwarden",
+			Enabled:           false,
+			Provider:          "bitwarden",
+			ExpiryWarningDays: DefaultExpiryWarningDays,
 		},
 	}
 }
@@ -156,10 +157,14 @@ func setDefaults(v *viper.Viper) {
 	// Sys defaults (managers are enabled per environment usually, but defaults can be empty)
 	v.SetDefault("sys.enable", []string{})
 	v.SetDefault("sys.managers", map[string]interface{}{})
+	v.SetDefault("sys.osv_dir", "")
 
 	// Secrets
 	v.SetDefault("secrets.enabled", false)
 	v.SetDefault("secrets.provider", "bitwarden")
+	v.SetDefault("secrets.mask_output", false)
+	v.SetDefault("secrets.expiry_warning_days", DefaultExpiryWarningDays)
+	v.SetDefault("secrets.rotation_days", 0)
 	v.SetDefault("secrets.items", []string{})
 }
 
@@ -173,6 +178,21 @@ func ConfigPath() (string, error) {
 	return filepath.Join(home, ".config", "devsync", "config.yaml"), nil
 }
 
+// StateDir はログなどの実行時の状態を保存するディレクトリを返します。
+// XDG_STATE_HOME が設定されていれば $XDG_STATE_HOME/devsync、なければ ~/.local/state/devsync です。
+func StateDir() (string, error) {
+	if stateHome := strings.TrimSpace(os.Getenv("XDG_STATE_HOME")); stateHome != "" && filepath.IsAbs(stateHome) {
+		return filepath.Join(stateHome, "devsync"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("ホームディレクトリの取得に失敗: %w", err)
+	}
+
+	return filepath.Join(home, ".local", "state", "devsync"), nil
+}
+
 // ConfigFileExists は設定ファイルの存在有無を返します。
 // path には判定対象の設定ファイルパスが入ります。
 func ConfigFileExists() (exists bool, path string, err error) {
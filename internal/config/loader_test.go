@@ -50,6 +50,7 @@ func TestDefault(t *testing.T) {
 		// Secrets defaults
 		assert.False(t, cfg.Secrets.Enabled)
 		assert.Equal(t, "bitwarden", cfg.Secrets.Provider)
+		assert.Equal(t, DefaultExpiryWarningDays, cfg.Secrets.ExpiryWarningDays)
 	})
 }
 
@@ -69,6 +70,7 @@ func TestLoad(t *testing.T) {
 		// デフォルト値が設定されていることを確認
 		assert.Equal(t, 1, cfg.Version)
 		assert.Equal(t, 8, cfg.Control.Concurrency)
+		assert.Equal(t, DefaultExpiryWarningDays, cfg.Secrets.ExpiryWarningDays)
 	})
 
 	t.Run("有効なYAML設定ファイルの読み込み", func(t *testing.T) {
@@ -101,6 +103,7 @@ sys:
 secrets:
   enabled: true
   provider: bitwarden
+  expiry_warning_days: 0
 `
 		err = os.WriteFile(filepath.Join(configDir, "config.yaml"), []byte(configContent), 0o644)
 		require.NoError(t, err)
@@ -125,6 +128,7 @@ secrets:
 		assert.Contains(t, cfg.Sys.Enable, "apt")
 		assert.Contains(t, cfg.Sys.Enable, "brew")
 		assert.True(t, cfg.Secrets.Enabled)
+		assert.Zero(t, cfg.Secrets.ExpiryWarningDays, "0 を明示した場合は既定値で上書きしない")
 	})
 
 	t.Run("不正なYAMLの場合はエラー", func(t *testing.T) {
@@ -217,6 +221,27 @@ func TestConfigPath(t *testing.T) {
 	})
 }
 
+func TestStateDir(t *testing.T) {
+	t.Run("XDG_STATE_HOME 未設定時は ~/.local/state/devsync", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		testutil.SetTestHome(t, tmpDir)
+		t.Setenv("XDG_STATE_HOME", "")
+
+		dir, err := StateDir()
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(tmpDir, ".local", "state", "devsync"), dir)
+	})
+
+	t.Run("XDG_STATE_HOME を優先", func(t *testing.T) {
+		stateHome := t.TempDir()
+		t.Setenv("XDG_STATE_HOME", stateHome)
+
+		dir, err := StateDir()
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(stateHome, "devsync"), dir)
+	})
+}
+
 func TestConfigFileExists(t *testing.T) {
 	t.Run("設定ファイルがない場合", func(t *testing.T) {
 		tmpDir := t.TempDir()
@@ -182,4 +182,24 @@ func TestSave(t *testing.T) {
 		assert.Equal(t, cfg.Sys.Enable, loaded.Sys.Enable)
 		assert.Equal(t, cfg.Secrets.Enabled, loaded.Secrets.Enabled)
 	})
+
+	t.Run("シークレット参照は参照のまま保存される", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+
+		cfg := &Config{
+			Version: 1,
+			Sys: SysConfig{
+				Enable: []string{"npm"},
+				Managers: map[string]ManagerConfig{
+					"npm": {"token": "${secret:bitwarden/Registry/token}"},
+				},
+			},
+		}
+
+		require.NoError(t, Save(cfg, path))
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), "${secret:bitwarden/Registry/token}")
+	})
 }
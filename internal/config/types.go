@@ -8,6 +8,8 @@ type Config struct {
 	Repo    RepoConfig    `mapstructure:"repo" yaml:"repo"`
 	Sys     SysConfig     `mapstructure:"sys" yaml:"sys"`
 	Secrets SecretsConfig `mapstructure:"secrets" yaml:"secrets"`
+	Run     RunConfig     `mapstructure:"run" yaml:"run,omitempty"`
+	Notify  NotifyConfig  `mapstructure:"notify" yaml:"notify,omitempty"`
 }
 
 // UIConfig はUI表示に関する設定です。
@@ -22,8 +24,18 @@ type UIConfig struct {
 type SecretsConfig struct {
 	Enabled  bool   `mapstructure:"enabled" yaml:"enabled"`
 	Provider string `mapstructure:"provider" yaml:"provider"` // "bitwarden"
+	// MaskOutput は env run の子プロセス出力に含まれるシークレット値を *** に置換します。
+	MaskOutput bool `mapstructure:"mask_output" yaml:"mask_output"`
+	// ExpiryWarningDays は expires フィールドの期限までの残り日数がこの値以下で警告します（0 で無効）。
+	// 未設定の場合は DefaultExpiryWarningDays です。
+	ExpiryWarningDays int `mapstructure:"expiry_warning_days" yaml:"expiry_warning_days"`
+	// RotationDays は最終更新からこの日数を超えた項目にローテーションを促します（0 で無効）。
+	RotationDays int `mapstructure:"rotation_days" yaml:"rotation_days"`
 }
 
+// DefaultExpiryWarningDays は secrets.expiry_warning_days の既定値です。
+const DefaultExpiryWarningDays = 14
+
 // ControlConfig は実行制御に関する設定です。
 type ControlConfig struct {
 	Concurrency int    `mapstructure:"concurrency" yaml:"concurrency"`
@@ -60,8 +72,82 @@ type RepoCleanupConfig struct {
 type SysConfig struct {
 	Enable   []string                 `mapstructure:"enable" yaml:"enable"`     // 有効化するマネージャ名のリスト
 	Managers map[string]ManagerConfig `mapstructure:"managers" yaml:"managers"` // マネージャごとの個別設定
+	// OSVDir は sys check で照合する OSV 脆弱性データのダンプのディレクトリです（空の場合は ~/.cache/devsync/osv）。
+	OSVDir string `mapstructure:"osv_dir" yaml:"osv_dir"`
 }
 
 // ManagerConfig は各パッケージマネージャの汎用的な設定マップです。
 // Go側で map[string]interface{} として受け取り、必要に応じてキャストします。
+// 文字列値には `${secret:bitwarden/項目名/フィールド}` や `bw://項目名/フィールド` の
+// シークレット参照を記述でき、マネージャへ適用する直前に解決されます（設定自体は参照のまま保持）。
 type ManagerConfig map[string]interface{}
+
+// RunConfig は devsync run で実行するフェーズの構成です。
+type RunConfig struct {
+	// Phases は実行するフェーズを実行順に並べたものです（空の場合は secrets / sys / repo-update）。
+	Phases []RunPhaseConfig `mapstructure:"phases" yaml:"phases,omitempty"`
+}
+
+// RunPhaseConfig は run の 1 フェーズです。
+// Name が組み込みフェーズ（secrets / sys / repo-update / repo-cleanup / doctor）の場合はその処理を、
+// それ以外の場合は Run のシェルコマンドを実行します。
+type RunPhaseConfig struct {
+	Name string `mapstructure:"name" yaml:"name"`
+	Run  string `mapstructure:"run" yaml:"run,omitempty"`
+	// ContinueOnError を false にすると、このフェーズが失敗した時点で以降のフェーズを中止します（既定: true）。
+	ContinueOnError *bool              `mapstructure:"continue_on_error" yaml:"continue_on_error,omitempty"`
+	When            RunConditionConfig `mapstructure:"when" yaml:"when,omitempty"`
+	// Pre / Post はフェーズの前後に実行するシェルコマンドです（Post はフェーズが成功した場合のみ）。
+	Pre  []string `mapstructure:"pre" yaml:"pre,omitempty"`
+	Post []string `mapstructure:"post" yaml:"post,omitempty"`
+}
+
+// RunConditionConfig はフェーズを実行する条件です。指定した条件をすべて満たす場合のみ実行します。
+type RunConditionConfig struct {
+	Host    []string `mapstructure:"host" yaml:"host,omitempty"`       // いずれかのホスト名に一致
+	WSL     *bool    `mapstructure:"wsl" yaml:"wsl,omitempty"`         // true: WSL のみ、false: WSL 以外のみ
+	Weekday []string `mapstructure:"weekday" yaml:"weekday,omitempty"` // 例: ["sat", "sun"]
+}
+
+// 通知を送る条件（notify.on / 各通知先の on）
+const (
+	NotifyOnFailure = "on_failure" // 失敗した場合のみ（既定）
+	NotifyOnUpdates = "on_updates" // パッケージを更新した場合と失敗した場合
+	NotifyAlways    = "always"     // 毎回
+)
+
+// NotifyConfig は devsync run の完了時に送る通知の設定です。
+type NotifyConfig struct {
+	// On は通知先ごとの on が未指定の場合に使う通知条件です（既定: on_failure）。
+	On       string                `mapstructure:"on" yaml:"on,omitempty"`
+	Desktop  DesktopNotifyConfig   `mapstructure:"desktop" yaml:"desktop,omitempty"`
+	Webhooks []WebhookNotifyConfig `mapstructure:"webhooks" yaml:"webhooks,omitempty"`
+	Email    EmailNotifyConfig     `mapstructure:"email" yaml:"email,omitempty"`
+}
+
+// DesktopNotifyConfig はデスクトップ通知（notify-send / D-Bus、macOS は osascript）の設定です。
+type DesktopNotifyConfig struct {
+	Enabled bool   `mapstructure:"enabled" yaml:"enabled,omitempty"`
+	On      string `mapstructure:"on" yaml:"on,omitempty"`
+}
+
+// WebhookNotifyConfig は Webhook への通知の設定です。
+// URL の ${VAR} は環境変数で展開します（secrets で読み込んだ値も使用可）。
+type WebhookNotifyConfig struct {
+	URL    string `mapstructure:"url" yaml:"url"`
+	Format string `mapstructure:"format" yaml:"format,omitempty"` // json（既定）/ slack / discord
+	On     string `mapstructure:"on" yaml:"on,omitempty"`
+}
+
+// EmailNotifyConfig は SMTP によるメール通知の設定です。
+type EmailNotifyConfig struct {
+	Enabled  bool   `mapstructure:"enabled" yaml:"enabled,omitempty"`
+	Host     string `mapstructure:"host" yaml:"host,omitempty"`
+	Port     int    `mapstructure:"port" yaml:"port,omitempty"` // 既定: 587
+	Username string `mapstructure:"username" yaml:"username,omitempty"`
+	// PasswordEnv はパスワードを読み込む環境変数名です（設定ファイルにパスワードを書かないため）。
+	PasswordEnv string   `mapstructure:"password_env" yaml:"password_env,omitempty"`
+	From        string   `mapstructure:"from" yaml:"from,omitempty"`
+	To          []string `mapstructure:"to" yaml:"to,omitempty"`
+	On          string   `mapstructure:"on" yaml:"on,omitempty"`
+}
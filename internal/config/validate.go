@@ -14,6 +14,12 @@ type ValidateOptions struct {
 	// KnownSysManagers を指定すると、sys.enable の未知マネージャを警告します。
 	// nil/空の場合はチェックしません。
 	KnownSysManagers map[string]struct{}
+	// KnownRunPhases を指定すると、run.phases の組み込みフェーズ名を検証します。
+	// nil/空の場合は run.phases をチェックしません。
+	KnownRunPhases map[string]struct{}
+	// VersionPolicyManagers を指定すると、allow / allow_packages に対応しないマネージャへの設定を警告します。
+	// nil/空の場合はチェックしません。
+	VersionPolicyManagers map[string]struct{}
 }
 
 // ValidationIssue は設定検証で見つかった問題（エラー/警告）です。
@@ -57,6 +63,9 @@ func Validate(cfg *Config, opts ValidateOptions) ValidationResult {
 	validateRepo(&result, cfg)
 	validateSecrets(&result, cfg)
 	validateSys(&result, cfg, opts)
+	validateSysVersionPolicy(&result, cfg, opts)
+	validateRun(&result, cfg, opts)
+	validateNotify(&result, cfg)
 
 	return result
 }
@@ -183,6 +192,20 @@ func validateSecrets(result *ValidationResult, cfg *Config) {
 		return
 	}
 
+	if cfg.Secrets.ExpiryWarningDays < 0 {
+		result.Errors = append(result.Errors, ValidationIssue{
+			Field:   "secrets.expiry_warning_days",
+			Message: fmt.Sprintf("0 以上を指定してください: %d", cfg.Secrets.ExpiryWarningDays),
+		})
+	}
+
+	if cfg.Secrets.RotationDays < 0 {
+		result.Errors = append(result.Errors, ValidationIssue{
+			Field:   "secrets.rotation_days",
+			Message: fmt.Sprintf("0 以上を指定してください（0 で無効）: %d", cfg.Secrets.RotationDays),
+		})
+	}
+
 	provider := strings.ToLower(strings.TrimSpace(cfg.Secrets.Provider))
 	if provider == "" {
 		result.Errors = append(result.Errors, ValidationIssue{
@@ -253,6 +276,40 @@ func validateSys(result *ValidationResult, cfg *Config, opts ValidateOptions) {
 	}
 }
 
+// validateSysVersionPolicy は allow / allow_packages が無視されるマネージャへの設定を警告します。
+func validateSysVersionPolicy(result *ValidationResult, cfg *Config, opts ValidateOptions) {
+	if len(opts.VersionPolicyManagers) == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(cfg.Sys.Managers))
+	for name := range cfg.Sys.Managers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	supported := keysOfStringSet(opts.VersionPolicyManagers)
+	sort.Strings(supported)
+
+	for _, name := range names {
+		if _, ok := opts.VersionPolicyManagers[name]; ok {
+			continue
+		}
+
+		for _, key := range []string{"allow", "allow_packages"} {
+			if _, ok := cfg.Sys.Managers[name][key]; !ok {
+				continue
+			}
+
+			result.Warnings = append(result.Warnings, ValidationIssue{
+				Field:   fmt.Sprintf("sys.managers.%s.%s", name, key),
+				Message: fmt.Sprintf("%s は許可するバージョンの範囲に対応していないため無視されます（対応: %s）", name, strings.Join(supported, ", ")),
+			})
+		}
+	}
+}
+
 func keysOfStringSet(set map[string]struct{}) []string {
 	if len(set) == 0 {
 		return nil
@@ -265,3 +322,153 @@ func keysOfStringSet(set map[string]struct{}) []string {
 
 	return keys
 }
+
+func validateRun(result *ValidationResult, cfg *Config, opts ValidateOptions) {
+	if len(opts.KnownRunPhases) == 0 {
+		return
+	}
+
+	seen := make(map[string]struct{}, len(cfg.Run.Phases))
+
+	for i, phase := range cfg.Run.Phases {
+		field := fmt.Sprintf("run.phases[%d]", i)
+		if err := ValidateRunPhase(phase, opts.KnownRunPhases); err != nil {
+			result.Errors = append(result.Errors, ValidationIssue{Field: field, Message: err.Error()})
+			continue
+		}
+
+		name := strings.TrimSpace(phase.Name)
+		if _, ok := seen[name]; ok {
+			result.Errors = append(result.Errors, ValidationIssue{
+				Field:   field,
+				Message: fmt.Sprintf("フェーズ名が重複しています: %q", name),
+			})
+		}
+
+		seen[name] = struct{}{}
+	}
+}
+
+func validateNotify(result *ValidationResult, cfg *Config) {
+	notify := cfg.Notify
+
+	validateNotifyOn(result, "notify.on", notify.On)
+	validateNotifyOn(result, "notify.desktop.on", notify.Desktop.On)
+
+	for i, webhook := range notify.Webhooks {
+		field := fmt.Sprintf("notify.webhooks[%d]", i)
+
+		if strings.TrimSpace(webhook.URL) == "" {
+			result.Errors = append(result.Errors, ValidationIssue{Field: field + ".url", Message: "空です"})
+		}
+
+		switch strings.ToLower(strings.TrimSpace(webhook.Format)) {
+		case "", "json", "slack", "discord":
+			// ok
+		default:
+			result.Errors = append(result.Errors, ValidationIssue{
+				Field:   field + ".format",
+				Message: fmt.Sprintf("不正な値です: %q（json / slack / discord を指定してください）", webhook.Format),
+			})
+		}
+
+		validateNotifyOn(result, field+".on", webhook.On)
+	}
+
+	if notify.Email.Enabled {
+		validateNotifyEmail(result, notify.Email)
+	}
+}
+
+func validateNotifyOn(result *ValidationResult, field, on string) {
+	switch strings.TrimSpace(on) {
+	case "", NotifyOnFailure, NotifyOnUpdates, NotifyAlways:
+		return
+	}
+
+	result.Errors = append(result.Errors, ValidationIssue{
+		Field:   field,
+		Message: fmt.Sprintf("不正な値です: %q（%s / %s / %s を指定してください）", on, NotifyOnFailure, NotifyOnUpdates, NotifyAlways),
+	})
+}
+
+func validateNotifyEmail(result *ValidationResult, email EmailNotifyConfig) {
+	required := []struct {
+		field string
+		empty bool
+	}{
+		{field: "notify.email.host", empty: strings.TrimSpace(email.Host) == ""},
+		{field: "notify.email.from", empty: strings.TrimSpace(email.From) == ""},
+		{field: "notify.email.to", empty: len(email.To) == 0},
+	}
+
+	for _, r := range required {
+		if r.empty {
+			result.Errors = append(result.Errors, ValidationIssue{Field: r.field, Message: "notify.email.enabled=true の場合は必須です"})
+		}
+	}
+
+	if email.Port < 0 || email.Port > 65535 {
+		result.Errors = append(result.Errors, ValidationIssue{
+			Field:   "notify.email.port",
+			Message: fmt.Sprintf("不正なポート番号です: %d", email.Port),
+		})
+	}
+
+	if email.Username != "" && strings.TrimSpace(email.PasswordEnv) == "" {
+		result.Warnings = append(result.Warnings, ValidationIssue{
+			Field:   "notify.email.password_env",
+			Message: "username を指定していますが、パスワードを読み込む環境変数が未指定です",
+		})
+	}
+
+	validateNotifyOn(result, "notify.email.on", email.On)
+}
+
+// ValidateRunPhase は run.phases の 1 フェーズを検証します（フェーズ名の重複は対象外）。
+// builtin には組み込みフェーズ名を指定します。
+func ValidateRunPhase(phase RunPhaseConfig, builtin map[string]struct{}) error {
+	name := strings.TrimSpace(phase.Name)
+	_, isBuiltin := builtin[name]
+
+	switch {
+	case name == "":
+		return fmt.Errorf("name が空です")
+	case isBuiltin && strings.TrimSpace(phase.Run) != "":
+		return fmt.Errorf("組み込みフェーズ %q には run を指定できません（別の name を付けてください）", name)
+	case !isBuiltin && strings.TrimSpace(phase.Run) == "":
+		return fmt.Errorf("未知のフェーズです: %q（組み込み: %s。独自のフェーズは run にコマンドを指定してください）", name, strings.Join(sortedKeys(builtin), ", "))
+	}
+
+	for _, day := range phase.When.Weekday {
+		if _, ok := ParseWeekday(day); !ok {
+			return fmt.Errorf("when.weekday: 不正な曜日です: %q（例: mon, sat, sunday）", day)
+		}
+	}
+
+	return nil
+}
+
+// ParseWeekday は曜日名（sun / sunday など、大文字小文字を区別しない）を time.Weekday に変換します。
+func ParseWeekday(value string) (time.Weekday, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if len(value) < 3 {
+		return 0, false
+	}
+
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		name := strings.ToLower(day.String())
+		if value == name || value == name[:3] {
+			return day, true
+		}
+	}
+
+	return 0, false
+}
+
+func sortedKeys(set map[string]struct{}) []string {
+	keys := keysOfStringSet(set)
+	sort.Strings(keys)
+
+	return keys
+}
@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestValidate(t *testing.T) {
@@ -15,6 +16,11 @@ func TestValidate(t *testing.T) {
 		"brew": {},
 	}
 
+	knownRunPhases := map[string]struct{}{
+		"sys":         {},
+		"repo-update": {},
+	}
+
 	existingDir := t.TempDir()
 	existingFile := filepath.Join(t.TempDir(), "not-a-dir.txt")
 
@@ -206,6 +212,17 @@ func TestValidate(t *testing.T) {
 			}(),
 			wantErrorSubstrs: []string{"secrets.provider", "未対応"},
 		},
+		{
+			name: "secrets.rotation_days が負ならエラー",
+			cfg: func() *Config {
+				c := newValidConfig(existingDir)
+				c.Secrets.Enabled = true
+				c.Secrets.Provider = "bitwarden"
+				c.Secrets.RotationDays = -1
+				return c
+			}(),
+			wantErrorSubstrs: []string{"secrets.rotation_days", "0 以上"},
+		},
 		{
 			name: "sys.enable の未知マネージャは警告（KnownSysManagers指定時）",
 			cfg: func() *Config {
@@ -226,6 +243,106 @@ func TestValidate(t *testing.T) {
 			opts:               ValidateOptions{KnownSysManagers: knownManagers},
 			wantWarningSubstrs: []string{"sys.enable", "重複"},
 		},
+		{
+			name: "allow は対応していないマネージャに設定すると警告（VersionPolicyManagers指定時）",
+			cfg: func() *Config {
+				c := newValidConfig(existingDir)
+				c.Sys.Managers = map[string]ManagerConfig{
+					"apt": {"allow": "minor"},
+					"npm": {"allow": "minor", "allow_packages": map[string]interface{}{"typescript": "major"}},
+				}
+				return c
+			}(),
+			opts:               ValidateOptions{VersionPolicyManagers: map[string]struct{}{"npm": {}}},
+			wantWarningSubstrs: []string{"sys.managers.apt.allow", "無視されます"},
+		},
+		{
+			name: "run.phases の正しい設定はエラーなし",
+			cfg: func() *Config {
+				c := newValidConfig(existingDir)
+				c.Run.Phases = []RunPhaseConfig{
+					{Name: "sys", Post: []string{"brew bundle dump --force"}},
+					{Name: "backup", Run: "rsync -a ~/notes /mnt/backup", When: RunConditionConfig{Weekday: []string{"Sat", "sunday"}}},
+				}
+				return c
+			}(),
+			opts: ValidateOptions{KnownRunPhases: knownRunPhases},
+		},
+		{
+			name: "run.phases の未知のフェーズはエラー（KnownRunPhases指定時）",
+			cfg: func() *Config {
+				c := newValidConfig(existingDir)
+				c.Run.Phases = []RunPhaseConfig{{Name: "repo-sync"}}
+				return c
+			}(),
+			opts:             ValidateOptions{KnownRunPhases: knownRunPhases},
+			wantErrorSubstrs: []string{"run.phases[0]", "未知のフェーズ", "repo-update, sys"},
+		},
+		{
+			name: "run.phases の組み込みフェーズに run を指定するとエラー",
+			cfg: func() *Config {
+				c := newValidConfig(existingDir)
+				c.Run.Phases = []RunPhaseConfig{{Name: "sys", Run: "apt upgrade"}}
+				return c
+			}(),
+			opts:             ValidateOptions{KnownRunPhases: knownRunPhases},
+			wantErrorSubstrs: []string{"run.phases[0]", "run を指定できません"},
+		},
+		{
+			name: "run.phases のフェーズ名の重複はエラー",
+			cfg: func() *Config {
+				c := newValidConfig(existingDir)
+				c.Run.Phases = []RunPhaseConfig{{Name: "sys"}, {Name: "sys"}}
+				return c
+			}(),
+			opts:             ValidateOptions{KnownRunPhases: knownRunPhases},
+			wantErrorSubstrs: []string{"run.phases[1]", "重複"},
+		},
+		{
+			name: "run.phases の不正な曜日はエラー",
+			cfg: func() *Config {
+				c := newValidConfig(existingDir)
+				c.Run.Phases = []RunPhaseConfig{{Name: "sys", When: RunConditionConfig{Weekday: []string{"weekend"}}}}
+				return c
+			}(),
+			opts:             ValidateOptions{KnownRunPhases: knownRunPhases},
+			wantErrorSubstrs: []string{"run.phases[0]", "when.weekday"},
+		},
+		{
+			name: "notify の有効な設定",
+			cfg: func() *Config {
+				c := newValidConfig(existingDir)
+				c.Notify = NotifyConfig{
+					On:       NotifyOnUpdates,
+					Desktop:  DesktopNotifyConfig{Enabled: true, On: NotifyAlways},
+					Webhooks: []WebhookNotifyConfig{{URL: "${SLACK_WEBHOOK_URL}", Format: "slack"}},
+					Email:    EmailNotifyConfig{Enabled: true, Host: "smtp.example.com", Username: "me", PasswordEnv: "SMTP_PASSWORD", From: "devsync@example.com", To: []string{"me@example.com"}},
+				}
+				return c
+			}(),
+		},
+		{
+			name: "notify の不正な通知条件・形式はエラー",
+			cfg: func() *Config {
+				c := newValidConfig(existingDir)
+				c.Notify = NotifyConfig{
+					On:       "on_error",
+					Webhooks: []WebhookNotifyConfig{{URL: "", Format: "teams", On: "never"}},
+				}
+				return c
+			}(),
+			wantErrorSubstrs: []string{"notify.on", "notify.webhooks[0].url", "notify.webhooks[0].format", "notify.webhooks[0].on"},
+		},
+		{
+			name: "notify.email の必須項目の不足はエラー",
+			cfg: func() *Config {
+				c := newValidConfig(existingDir)
+				c.Notify.Email = EmailNotifyConfig{Enabled: true, Port: 70000, Username: "me"}
+				return c
+			}(),
+			wantErrorSubstrs:   []string{"notify.email.host", "notify.email.from", "notify.email.to", "notify.email.port"},
+			wantWarningSubstrs: []string{"notify.email.password_env"},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -267,3 +384,27 @@ func containsIssueSubstr(issues []ValidationIssue, substr string) bool {
 
 	return false
 }
+
+func TestParseWeekday(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		input  string
+		want   time.Weekday
+		wantOK bool
+	}{
+		{input: "sun", want: time.Sunday, wantOK: true},
+		{input: "Saturday", want: time.Saturday, wantOK: true},
+		{input: " MON ", want: time.Monday, wantOK: true},
+		{input: "tues", wantOK: false},
+		{input: "mo", wantOK: false},
+		{input: "", wantOK: false},
+	}
+
+	for _, tc := range testCases {
+		got, ok := ParseWeekday(tc.input)
+		if ok != tc.wantOK || got != tc.want {
+			t.Fatalf("ParseWeekday(%q) = (%v, %v), want (%v, %v)", tc.input, got, ok, tc.want, tc.wantOK)
+		}
+	}
+}
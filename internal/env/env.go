@@ -60,21 +60,24 @@ func GetRecommendedManagers() []string {
 		}
 	case IsContainer():
 		// Container environment usually relies on apt/apk but user might not want to update system packages directly.
-		// Use apt if it's Debian/Ubuntu based.
-		if isDebianLike() {
-			managers = append(managers, "apt")
+		// Use the distribution's package manager if it can be detected.
+		if manager := systemPackageManager(); manager != "" {
+			managers = append(managers, manager)
 		}
 	case IsWSL():
 		// WSL environment
-		if isDebianLike() {
-			managers = append(managers, "apt")
+		if manager := systemPackageManager(); manager != "" {
+			managers = append(managers, manager)
 		}
 
 		managers = append(managers, "brew") // Linuxbrew is common in WSL
 	default:
 		// Host Linux/macOS
-		if isDebianLike() {
-			managers = append(managers, "apt", "snap")
+		if manager := systemPackageManager(); manager != "" {
+			managers = append(managers, manager)
+			if manager == "apt" {
+				managers = append(managers, "snap")
+			}
 		}
 		// Mac/Linux common
 		managers = append(managers, "brew")
@@ -88,3 +91,106 @@ func isDebianLike() bool {
 	_, err := os.Stat("/usr/bin/apt-get")
 	return err == nil
 }
+
+// osReleasePath はディストリビューション情報を読み取るファイルです（テストで差し替え可能）。
+var osReleasePath = "/etc/os-release"
+
+// ディストリビューションの系統
+const (
+	DistroFamilyDebian = "debian"
+	DistroFamilyFedora = "fedora"
+	DistroFamilyArch   = "arch"
+	DistroFamilySUSE   = "suse"
+	DistroFamilyAlpine = "alpine"
+)
+
+// LinuxDistro は /etc/os-release から読み取ったディストリビューション情報です。
+type LinuxDistro struct {
+	ID        string
+	IDLike    []string
+	Name      string
+	VersionID string
+}
+
+// DetectLinuxDistro は /etc/os-release からディストリビューション情報を読み取ります。
+// ファイルが存在しない場合（macOS / Windows など）は false を返します。
+func DetectLinuxDistro() (LinuxDistro, bool) {
+	data, err := os.ReadFile(osReleasePath)
+	if err != nil {
+		return LinuxDistro{}, false
+	}
+
+	return parseOSRelease(string(data)), true
+}
+
+// Family は ID / ID_LIKE からディストリビューションの系統を返します（判定できない場合は空文字）。
+func (d LinuxDistro) Family() string {
+	for _, id := range append([]string{d.ID}, d.IDLike...) {
+		switch {
+		case id == "debian" || id == "ubuntu":
+			return DistroFamilyDebian
+		case id == "fedora" || id == "rhel" || id == "centos":
+			return DistroFamilyFedora
+		case id == "arch":
+			return DistroFamilyArch
+		case strings.Contains(id, "suse") || id == "sles":
+			return DistroFamilySUSE
+		case id == "alpine":
+			return DistroFamilyAlpine
+		}
+	}
+
+	return ""
+}
+
+// parseOSRelease は os-release 形式（KEY=value、値は引用符付きの場合あり）をパースします。
+func parseOSRelease(content string) LinuxDistro {
+	var distro LinuxDistro
+
+	for _, line := range strings.Split(content, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), "=")
+		if !ok || strings.HasPrefix(key, "#") {
+			continue
+		}
+
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+
+		switch key {
+		case "ID":
+			distro.ID = strings.ToLower(value)
+		case "ID_LIKE":
+			distro.IDLike = strings.Fields(strings.ToLower(value))
+		case "NAME":
+			distro.Name = value
+		case "VERSION_ID":
+			distro.VersionID = value
+		}
+	}
+
+	return distro
+}
+
+// systemPackageManager はディストリビューションのパッケージマネージャ名を返します。
+// os-release で判定できない場合は apt-get の有無で Debian 系かを判定します。
+func systemPackageManager() string {
+	if distro, ok := DetectLinuxDistro(); ok {
+		switch distro.Family() {
+		case DistroFamilyDebian:
+			return "apt"
+		case DistroFamilyFedora:
+			return "dnf"
+		case DistroFamilyArch:
+			return "pacman"
+		case DistroFamilySUSE:
+			return "zypper"
+		case DistroFamilyAlpine:
+			return "apk"
+		}
+	}
+
+	if isDebianLike() {
+		return "apt"
+	}
+
+	return ""
+}
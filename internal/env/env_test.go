@@ -3,6 +3,7 @@ package env
 import (
 	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime"
 	"testing"
 
@@ -94,3 +95,68 @@ func TestIsDebianLike(t *testing.T) {
 		}
 	})
 }
+
+func TestParseOSRelease(t *testing.T) {
+	content := `NAME="Fedora Linux"
+VERSION="40 (Workstation Edition)"
+# comment
+ID=fedora
+ID_LIKE="rhel centos"
+VERSION_ID=40
+`
+
+	distro := parseOSRelease(content)
+	assert.Equal(t, "fedora", distro.ID)
+	assert.Equal(t, []string{"rhel", "centos"}, distro.IDLike)
+	assert.Equal(t, "Fedora Linux", distro.Name)
+	assert.Equal(t, "40", distro.VersionID)
+}
+
+func TestLinuxDistroFamily(t *testing.T) {
+	testCases := []struct {
+		name   string
+		distro LinuxDistro
+		want   string
+	}{
+		{name: "Ubuntu", distro: LinuxDistro{ID: "ubuntu", IDLike: []string{"debian"}}, want: DistroFamilyDebian},
+		{name: "Rocky Linux", distro: LinuxDistro{ID: "rocky", IDLike: []string{"rhel", "centos", "fedora"}}, want: DistroFamilyFedora},
+		{name: "EndeavourOS", distro: LinuxDistro{ID: "endeavouros", IDLike: []string{"arch"}}, want: DistroFamilyArch},
+		{name: "openSUSE Tumbleweed", distro: LinuxDistro{ID: "opensuse-tumbleweed", IDLike: []string{"opensuse", "suse"}}, want: DistroFamilySUSE},
+		{name: "Alpine", distro: LinuxDistro{ID: "alpine"}, want: DistroFamilyAlpine},
+		{name: "不明", distro: LinuxDistro{ID: "nixos"}, want: ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.distro.Family())
+		})
+	}
+}
+
+func TestSystemPackageManager(t *testing.T) {
+	testCases := []struct {
+		name      string
+		osRelease string
+		want      string
+	}{
+		{name: "Fedoraはdnf", osRelease: "ID=fedora\n", want: "dnf"},
+		{name: "Manjaroはpacman", osRelease: "ID=manjaro\nID_LIKE=arch\n", want: "pacman"},
+		{name: "openSUSEはzypper", osRelease: "ID=\"opensuse-leap\"\nID_LIKE=\"suse opensuse\"\n", want: "zypper"},
+		{name: "Alpineはapk", osRelease: "ID=alpine\n", want: "apk"},
+		{name: "Debianはapt", osRelease: "ID=debian\n", want: "apt"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "os-release")
+			assert.NoError(t, os.WriteFile(path, []byte(tc.osRelease), 0o644))
+
+			original := osReleasePath
+			osReleasePath = path
+
+			t.Cleanup(func() { osReleasePath = original })
+
+			assert.Equal(t, tc.want, systemPackageManager())
+		})
+	}
+}
@@ -0,0 +1,262 @@
+// Package lock は devsync の多重起動を防ぐためのファイルロックを提供します。
+//
+// ロックファイルには保持しているプロセスの PID・コマンド・開始時刻を JSON で記録します。
+// 保持していたプロセスが終了済み（同一ホストで PID が存在しない、または再起動や PID の再利用で
+// 同じ PID が別のプロセスを指している）の場合は、古いロックとして削除してから取得し直します。
+package lock
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+const defaultPollInterval = 500 * time.Millisecond
+
+// Info はロックを保持しているプロセスの情報です。
+type Info struct {
+	PID       int       `json:"pid"`
+	Command   string    `json:"command"`
+	StartedAt time.Time `json:"started_at"`
+	Hostname  string    `json:"hostname,omitempty"`
+	// ProcessStart は保持プロセスの起動を一意に表す値です（Linux はブート ID と starttime、
+	// macOS / Windows はプロセスの起動時刻）。取得できない環境では空です。
+	ProcessStart string `json:"process_start,omitempty"`
+}
+
+// LockedError は別のプロセスがロックを保持しているため取得できなかったことを表します。
+type LockedError struct {
+	Path   string
+	Holder Info
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("%s は PID %d（%s、%s から実行中）がロックしています",
+		e.Path, e.Holder.PID, e.Holder.Command, e.Holder.StartedAt.Local().Format("2006-01-02 15:04:05"))
+}
+
+// Options はロック取得の動作を指定します。
+type Options struct {
+	// Command はロックファイルに記録するコマンドです。
+	Command string
+	// Wait が true の場合、ロックが解放されるまで待機します（false の場合は LockedError を返します）。
+	Wait bool
+	// PollInterval は待機中にロックの解放を確認する間隔です（0 の場合は 500ms）。
+	PollInterval time.Duration
+	// OnWait は待機を開始するときに 1 度だけ呼ばれます。
+	OnWait func(holder Info)
+}
+
+// Lock は取得したロックです。
+type Lock struct {
+	path  string
+	info  Info
+	owned bool
+}
+
+// Path はロックファイルのパスを返します。
+func (l *Lock) Path() string {
+	return l.path
+}
+
+// Acquire は path のロックを取得します。
+// 同じプロセスが既に保持している場合は、解放しても元のロックに影響しない Lock を返します。
+func Acquire(ctx context.Context, path string, opts Options) (*Lock, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("ロックディレクトリの作成に失敗: %w", err)
+	}
+
+	info := Info{PID: os.Getpid(), Command: opts.Command, StartedAt: time.Now()}
+	if hostname, err := os.Hostname(); err == nil {
+		info.Hostname = hostname
+	}
+
+	if start, ok := processStartID(info.PID); ok {
+		info.ProcessStart = start
+	}
+
+	interval := opts.PollInterval
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+
+	waiting := false
+
+	for {
+		l, holder, err := tryAcquire(path, info)
+		if err != nil || l != nil {
+			return l, err
+		}
+
+		if !opts.Wait {
+			return nil, &LockedError{Path: path, Holder: holder}
+		}
+
+		if !waiting && opts.OnWait != nil {
+			opts.OnWait(holder)
+		}
+
+		waiting = true
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("ロックの解放待ちを中断しました: %w", ctx.Err())
+		case <-time.After(interval):
+		}
+	}
+}
+
+// tryAcquire はロックの取得を 1 度試みます。
+// 別のプロセスが保持している場合は Lock を nil、holder にその情報を返します。
+func tryAcquire(path string, info Info) (l *Lock, holder Info, err error) {
+	for {
+		created, err := create(path, info)
+		if err != nil {
+			return nil, Info{}, err
+		}
+
+		if created {
+			return &Lock{path: path, info: info, owned: true}, Info{}, nil
+		}
+
+		holder, readErr := ReadInfo(path)
+		if errors.Is(readErr, os.ErrNotExist) {
+			// 読み込む前に解放された
+			continue
+		}
+
+		if readErr == nil && holder.PID == info.PID && holder.Hostname == info.Hostname &&
+			(holder.ProcessStart == "" || holder.ProcessStart == info.ProcessStart) {
+			return &Lock{path: path, info: holder}, Info{}, nil
+		}
+
+		if readErr == nil && !isStale(holder, info.Hostname) {
+			return nil, holder, nil
+		}
+
+		// 保持していたプロセスが終了済み、または内容が壊れているロックは削除して取得し直す
+		if err := removeStale(path, holder); err != nil {
+			return nil, Info{}, err
+		}
+	}
+}
+
+// create は内容を書き込んだ一時ファイルをハードリンクすることで、
+// 内容が揃った状態のロックファイルを排他的に作成します。
+func create(path string, info Info) (bool, error) {
+	data, err := json.Marshal(info)
+	if err != nil {
+		return false, fmt.Errorf("ロック情報の生成に失敗: %w", err)
+	}
+
+	tmp := path + "." + strconv.Itoa(info.PID) + "." + strconv.FormatInt(time.Now().UnixNano(), 36) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return false, fmt.Errorf("ロックファイルの作成に失敗: %w", err)
+	}
+
+	defer func() {
+		//nolint:errcheck // 一時ファイルが残ってもロックの取得結果には影響しない
+		os.Remove(tmp)
+	}()
+
+	if err := os.Link(tmp, path); err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("ロックファイルの作成に失敗: %w", err)
+	}
+
+	return true, nil
+}
+
+// ReadInfo はロックファイルに記録された保持プロセスの情報を読み込みます。
+func ReadInfo(path string) (Info, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Info{}, err
+	}
+
+	var info Info
+	if err := json.Unmarshal(data, &info); err != nil {
+		return Info{}, fmt.Errorf("ロックファイルの内容が不正です: %w", err)
+	}
+
+	return info, nil
+}
+
+// isStale は保持プロセスが終了済みかを返します。
+// PID が存在していても、起動を表す値（ProcessStart）が記録と異なる場合は、再起動や PID の再利用で
+// 無関係なプロセスが同じ PID を持っているため終了済みとみなします。
+// 別のホスト（共有ディレクトリ上のロック）のプロセスは確認できないため、終了済みとは判定しません。
+func isStale(holder Info, hostname string) bool {
+	if holder.Hostname != "" && holder.Hostname != hostname {
+		return false
+	}
+
+	if !processAlive(holder.PID) {
+		return true
+	}
+
+	if holder.ProcessStart == "" {
+		return false
+	}
+
+	current, ok := processStartID(holder.PID)
+
+	return ok && current != holder.ProcessStart
+}
+
+// removeStale は古いロックを削除します。
+// 確認してから削除するまでの間に別のプロセスが取得し直した場合は、そのロックを残します。
+func removeStale(path string, stale Info) error {
+	current, err := ReadInfo(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	if err == nil && !sameHolder(current, stale) {
+		return nil
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("古いロックファイルの削除に失敗: %w", err)
+	}
+
+	return nil
+}
+
+func sameHolder(a, b Info) bool {
+	return a.PID == b.PID && a.Hostname == b.Hostname && a.Command == b.Command && a.StartedAt.Equal(b.StartedAt) &&
+		a.ProcessStart == b.ProcessStart
+}
+
+// Release はロックを解放します。
+// 同じプロセス内で重ねて取得した Lock や、既に別のプロセスが取得し直したロックは削除しません。
+func (l *Lock) Release() error {
+	if l == nil || !l.owned {
+		return nil
+	}
+
+	l.owned = false
+
+	current, err := ReadInfo(l.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	if err == nil && !sameHolder(current, l.info) {
+		return nil
+	}
+
+	if err := os.Remove(l.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("ロックファイルの削除に失敗: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,258 @@
+package lock
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeLockFile は別のプロセスが保持しているロックファイルを作成します。
+func writeLockFile(t *testing.T, path string, info Info) {
+	t.Helper()
+
+	data, err := json.Marshal(info)
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, data, 0o644))
+}
+
+func currentHostname(t *testing.T) string {
+	t.Helper()
+
+	hostname, err := os.Hostname()
+	require.NoError(t, err)
+
+	return hostname
+}
+
+// exitedPID は終了済みのプロセスの PID を返します。
+func exitedPID(t *testing.T) int {
+	t.Helper()
+
+	cmd := exec.Command(os.Args[0], "-test.run=^$")
+	require.NoError(t, cmd.Run())
+
+	return cmd.Process.Pid
+}
+
+func TestAcquire_CreatesAndReleases(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "locks", "devsync.lock")
+
+	l, err := Acquire(context.Background(), path, Options{Command: "devsync sys update"})
+	require.NoError(t, err)
+
+	info, err := ReadInfo(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.Getpid(), info.PID)
+	assert.Equal(t, "devsync sys update", info.Command)
+	assert.False(t, info.StartedAt.IsZero())
+
+	require.NoError(t, l.Release())
+	assert.NoFileExists(t, path)
+
+	// 2 回目の解放は何もしない
+	require.NoError(t, l.Release())
+}
+
+func TestAcquire_HeldByAnotherProcess(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "devsync.lock")
+	holder := Info{PID: os.Getppid(), Command: "devsync run", StartedAt: time.Date(2024, 6, 1, 7, 30, 0, 0, time.UTC), Hostname: currentHostname(t)}
+	writeLockFile(t, path, holder)
+
+	_, err := Acquire(context.Background(), path, Options{Command: "devsync sys update"})
+
+	var lockedErr *LockedError
+
+	require.ErrorAs(t, err, &lockedErr)
+	assert.Equal(t, holder.PID, lockedErr.Holder.PID)
+	assert.Equal(t, "devsync run", lockedErr.Holder.Command)
+	assert.Contains(t, err.Error(), "devsync run")
+
+	// 保持しているプロセスのロックは残る
+	info, err := ReadInfo(path)
+	require.NoError(t, err)
+	assert.Equal(t, holder.PID, info.PID)
+}
+
+func TestAcquire_RemovesStaleLock(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		content func(t *testing.T) []byte
+	}{
+		{
+			name: "終了済みのプロセス",
+			content: func(t *testing.T) []byte {
+				data, err := json.Marshal(Info{PID: exitedPID(t), Command: "devsync run", StartedAt: time.Now(), Hostname: currentHostname(t)})
+				require.NoError(t, err)
+
+				return data
+			},
+		},
+		{
+			name:    "内容が壊れている",
+			content: func(*testing.T) []byte { return []byte("{") },
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "devsync.lock")
+			require.NoError(t, os.WriteFile(path, tc.content(t), 0o644))
+
+			l, err := Acquire(context.Background(), path, Options{Command: "devsync sys update"})
+			require.NoError(t, err)
+
+			t.Cleanup(func() { require.NoError(t, l.Release()) })
+
+			info, err := ReadInfo(path)
+			require.NoError(t, err)
+			assert.Equal(t, os.Getpid(), info.PID)
+		})
+	}
+}
+
+func TestAcquire_ChecksProcessStart(t *testing.T) {
+	t.Parallel()
+
+	start, ok := processStartID(os.Getppid())
+	if !ok {
+		t.Skip("この環境ではプロセスの起動時刻を取得できません")
+	}
+
+	t.Run("PID が別のプロセスに再利用されている", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "devsync.lock")
+		writeLockFile(t, path, Info{PID: os.Getppid(), Command: "devsync run", StartedAt: time.Now(), Hostname: currentHostname(t), ProcessStart: "reused"})
+
+		l, err := Acquire(context.Background(), path, Options{Command: "devsync sys update"})
+		require.NoError(t, err)
+
+		t.Cleanup(func() { require.NoError(t, l.Release()) })
+
+		info, err := ReadInfo(path)
+		require.NoError(t, err)
+		assert.Equal(t, os.Getpid(), info.PID)
+		assert.NotEmpty(t, info.ProcessStart)
+	})
+
+	t.Run("同じプロセスが保持している", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "devsync.lock")
+		writeLockFile(t, path, Info{PID: os.Getppid(), Command: "devsync run", StartedAt: time.Now(), Hostname: currentHostname(t), ProcessStart: start})
+
+		_, err := Acquire(context.Background(), path, Options{Command: "devsync sys update"})
+
+		var lockedErr *LockedError
+
+		require.ErrorAs(t, err, &lockedErr)
+	})
+}
+
+func TestAcquire_KeepsLockOfOtherHost(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "devsync.lock")
+	writeLockFile(t, path, Info{PID: exitedPID(t), Command: "devsync run", StartedAt: time.Now(), Hostname: "other-host.invalid"})
+
+	_, err := Acquire(context.Background(), path, Options{})
+
+	var lockedErr *LockedError
+
+	require.ErrorAs(t, err, &lockedErr)
+	assert.Equal(t, "other-host.invalid", lockedErr.Holder.Hostname)
+}
+
+func TestAcquire_ReentrantInSameProcess(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "devsync.lock")
+
+	outer, err := Acquire(context.Background(), path, Options{Command: "devsync run"})
+	require.NoError(t, err)
+
+	inner, err := Acquire(context.Background(), path, Options{Command: "devsync sys update"})
+	require.NoError(t, err)
+
+	// 内側の解放では外側のロックを削除しない
+	require.NoError(t, inner.Release())
+
+	info, err := ReadInfo(path)
+	require.NoError(t, err)
+	assert.Equal(t, "devsync run", info.Command)
+
+	require.NoError(t, outer.Release())
+	assert.NoFileExists(t, path)
+}
+
+func TestAcquire_WaitsUntilReleased(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "devsync.lock")
+	writeLockFile(t, path, Info{PID: os.Getppid(), Command: "devsync run", StartedAt: time.Now(), Hostname: currentHostname(t)})
+
+	waited := make(chan Info, 1)
+
+	go func() {
+		holder := <-waited
+		assert.Equal(t, "devsync run", holder.Command)
+		assert.NoError(t, os.Remove(path))
+	}()
+
+	l, err := Acquire(context.Background(), path, Options{
+		Command:      "devsync sys update",
+		Wait:         true,
+		PollInterval: 10 * time.Millisecond,
+		OnWait:       func(holder Info) { waited <- holder },
+	})
+	require.NoError(t, err)
+	require.NoError(t, l.Release())
+}
+
+func TestAcquire_WaitCanceled(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "devsync.lock")
+	writeLockFile(t, path, Info{PID: os.Getppid(), Command: "devsync run", StartedAt: time.Now(), Hostname: currentHostname(t)})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := Acquire(ctx, path, Options{Wait: true, PollInterval: 10 * time.Millisecond})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestRelease_KeepsLockTakenOverByAnotherProcess(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "devsync.lock")
+
+	l, err := Acquire(context.Background(), path, Options{Command: "devsync run"})
+	require.NoError(t, err)
+
+	// 古いロックとして削除され、別のプロセスが取得し直した状態
+	other := Info{PID: os.Getppid(), Command: "devsync sys update", StartedAt: time.Now(), Hostname: currentHostname(t)}
+	writeLockFile(t, path, other)
+
+	require.NoError(t, l.Release())
+
+	info, err := ReadInfo(path)
+	require.NoError(t, err)
+	assert.Equal(t, other.PID, info.PID)
+}
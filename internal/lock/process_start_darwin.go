@@ -0,0 +1,22 @@
+//go:build darwin
+
+package lock
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+// processStartID はプロセスの起動時刻を一意に表す値として返します。
+// PID の再利用や再起動で別のプロセスが同じ PID を持っていても区別できます。
+func processStartID(pid int) (string, bool) {
+	info, err := unix.SysctlKinfoProc("kern.proc.pid", pid)
+	if err != nil || info.Proc.P_pid != int32(pid) {
+		return "", false
+	}
+
+	start := info.Proc.P_starttime
+
+	return fmt.Sprintf("%d.%06d", start.Sec, start.Usec), true
+}
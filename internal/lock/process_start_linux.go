@@ -0,0 +1,38 @@
+//go:build linux
+
+package lock
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// processStartID はプロセスの起動を一意に表す値を返します。
+// ブート ID と /proc/<pid>/stat の starttime（起動後のクロック数）を組み合わせるため、
+// 再起動後や PID の再利用で別のプロセスが同じ PID を持っていても区別できます。
+func processStartID(pid int) (string, bool) {
+	bootID, err := os.ReadFile("/proc/sys/kernel/random/boot_id")
+	if err != nil {
+		return "", false
+	}
+
+	stat, err := os.ReadFile("/proc/" + strconv.Itoa(pid) + "/stat")
+	if err != nil {
+		return "", false
+	}
+
+	// comm（2 番目の項目）は空白や括弧を含みうるため、最後の ")" より後ろを読む
+	idx := strings.LastIndexByte(string(stat), ')')
+	if idx == -1 {
+		return "", false
+	}
+
+	// ")" の後ろは state（3 番目の項目）から始まり、starttime は 22 番目の項目
+	fields := strings.Fields(string(stat[idx+1:]))
+	if len(fields) < 20 {
+		return "", false
+	}
+
+	return strings.TrimSpace(string(bootID)) + ":" + fields[19], true
+}
@@ -0,0 +1,8 @@
+//go:build !linux && !darwin && !windows
+
+package lock
+
+// processStartID はこのプラットフォームでは取得できないため false を返します（PID の存在のみで判定します）。
+func processStartID(int) (string, bool) {
+	return "", false
+}
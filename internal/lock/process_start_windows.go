@@ -0,0 +1,28 @@
+//go:build windows
+
+package lock
+
+import (
+	"strconv"
+
+	"golang.org/x/sys/windows"
+)
+
+// processStartID はプロセスの作成時刻を一意に表す値として返します。
+// PID の再利用や再起動で別のプロセスが同じ PID を持っていても区別できます。
+func processStartID(pid int) (string, bool) {
+	handle, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if err != nil {
+		return "", false
+	}
+
+	//nolint:errcheck // ハンドルの解放に失敗しても作成時刻の取得結果には影響しない
+	defer windows.CloseHandle(handle)
+
+	var creation, exit, kernel, user windows.Filetime
+	if err := windows.GetProcessTimes(handle, &creation, &exit, &kernel, &user); err != nil {
+		return "", false
+	}
+
+	return strconv.FormatInt(creation.Nanoseconds(), 10), true
+}
@@ -0,0 +1,23 @@
+//go:build windows
+
+package lock
+
+import "os"
+
+// processAlive は PID のプロセスが存在するかを返します。
+// Windows の os.FindProcess はプロセスを開けない場合（終了済みなど）にエラーを返します。
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+
+	//nolint:errcheck // ハンドルの解放に失敗しても存在確認の結果には影響しない
+	p.Release()
+
+	return true
+}
@@ -0,0 +1,79 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// テストで差し替えるための外部コマンド実行
+var (
+	lookPathFunc   = exec.LookPath
+	runCommandFunc = runCommand
+	desktopGOOS    = runtime.GOOS
+)
+
+// SendDesktop はデスクトップ通知を表示します。
+// Linux は notify-send（なければ gdbus で D-Bus の通知サービスを直接呼び出し）、macOS は osascript を使います。
+func SendDesktop(ctx context.Context, r *Report) error {
+	name, args, err := desktopCommand(r)
+	if err != nil {
+		return err
+	}
+
+	return runCommandFunc(ctx, name, args...)
+}
+
+func desktopCommand(r *Report) (name string, args []string, err error) {
+	title, body := r.Title(), r.Text()
+
+	switch desktopGOOS {
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s", appleScriptQuote(body), appleScriptQuote(title))
+		return "osascript", []string{"-e", script}, nil
+	case "windows":
+		return "", nil, errors.New("デスクトップ通知は Windows に対応していません")
+	}
+
+	if _, err := lookPathFunc("notify-send"); err == nil {
+		urgency := "normal"
+		if !r.Success {
+			urgency = "critical"
+		}
+
+		return "notify-send", []string{"--app-name=devsync", "--urgency=" + urgency, title, body}, nil
+	}
+
+	if _, err := lookPathFunc("gdbus"); err == nil {
+		return "gdbus", []string{
+			"call", "--session",
+			"--dest", "org.freedesktop.Notifications",
+			"--object-path", "/org/freedesktop/Notifications",
+			"--method", "org.freedesktop.Notifications.Notify",
+			"devsync", "0", "", title, body, "[]", "{}", "-1",
+		}, nil
+	}
+
+	return "", nil, errors.New("notify-send / gdbus が見つかりません（libnotify-bin などをインストールしてください）")
+}
+
+// appleScriptQuote は AppleScript の文字列リテラルに変換します。
+func appleScriptQuote(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
+
+func runCommand(ctx context.Context, name string, args ...string) error {
+	output, err := exec.CommandContext(ctx, name, args...).CombinedOutput()
+	if err != nil {
+		if msg := strings.TrimSpace(string(output)); msg != "" {
+			return fmt.Errorf("%s の実行に失敗: %w: %s", name, err, msg)
+		}
+
+		return fmt.Errorf("%s の実行に失敗: %w", name, err)
+	}
+
+	return nil
+}
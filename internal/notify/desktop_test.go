@@ -0,0 +1,108 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubDesktop はデスクトップ通知の実行環境を差し替え、実行したコマンドを記録します。
+func stubDesktop(t *testing.T, goos string, available map[string]bool) *[]string {
+	t.Helper()
+
+	originalLookPath, originalRun, originalGOOS := lookPathFunc, runCommandFunc, desktopGOOS
+
+	t.Cleanup(func() {
+		lookPathFunc, runCommandFunc, desktopGOOS = originalLookPath, originalRun, originalGOOS
+	})
+
+	commands := make([]string, 0, 1)
+	desktopGOOS = goos
+	lookPathFunc = func(name string) (string, error) {
+		if available[name] {
+			return "/usr/bin/" + name, nil
+		}
+
+		return "", errors.New("not found")
+	}
+	runCommandFunc = func(_ context.Context, name string, args ...string) error {
+		commands = append(commands, name+" "+strings.Join(args, " "))
+		return nil
+	}
+
+	return &commands
+}
+
+func TestSendDesktop(t *testing.T) {
+	testCases := []struct {
+		name      string
+		goos      string
+		available map[string]bool
+		success   bool
+		wantCmd   string
+		wantErr   string
+	}{
+		{
+			name:      "notify-send（失敗時は critical）",
+			goos:      "linux",
+			available: map[string]bool{"notify-send": true, "gdbus": true},
+			wantCmd:   "notify-send --app-name=devsync --urgency=critical ❌ devsync run: 1 件のエラー（work-laptop）",
+		},
+		{
+			name:      "notify-send（成功時は normal）",
+			goos:      "linux",
+			available: map[string]bool{"notify-send": true},
+			success:   true,
+			wantCmd:   "notify-send --app-name=devsync --urgency=normal ✅ devsync run: 完了（work-laptop）",
+		},
+		{
+			name:      "notify-send がなければ gdbus で D-Bus を呼び出す",
+			goos:      "linux",
+			available: map[string]bool{"gdbus": true},
+			wantCmd:   "gdbus call --session --dest org.freedesktop.Notifications --object-path /org/freedesktop/Notifications --method org.freedesktop.Notifications.Notify devsync 0  ❌ devsync run",
+		},
+		{
+			name:    "macOS は osascript",
+			goos:    "darwin",
+			wantCmd: `osascript -e display notification "開始: `,
+		},
+		{
+			name:    "どちらもなければエラー",
+			goos:    "linux",
+			wantErr: "notify-send / gdbus が見つかりません",
+		},
+		{
+			name:    "Windows は未対応",
+			goos:    "windows",
+			wantErr: "Windows に対応していません",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			commands := stubDesktop(t, tc.goos, tc.available)
+
+			err := SendDesktop(context.Background(), newTestReport(tc.success, 0))
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+				assert.Empty(t, *commands)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, *commands, 1)
+			assert.True(t, strings.HasPrefix((*commands)[0], tc.wantCmd), "command = %s", (*commands)[0])
+		})
+	}
+}
+
+func TestAppleScriptQuote(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, `"say \"hi\" \\ bye"`, appleScriptQuote(`say "hi" \ bye`))
+}
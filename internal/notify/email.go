@@ -0,0 +1,203 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/scottlz0310/devsync/internal/config"
+)
+
+const (
+	defaultSMTPPort = 587
+	// smtpsPort は接続直後から TLS で通信する SMTPS（implicit TLS）のポートです。
+	smtpsPort    = 465
+	emailTimeout = 30 * time.Second
+)
+
+// SendEmail は report を SMTP でメール送信します。
+// ポート 465 は接続直後から TLS で、それ以外はサーバーが STARTTLS に対応していれば暗号化し、
+// username を指定した場合は PLAIN 認証を行います。応答のないサーバーで止まらないよう、送信全体に期限を設けます。
+func SendEmail(ctx context.Context, cfg config.EmailNotifyConfig, r *Report) error {
+	host := strings.TrimSpace(cfg.Host)
+	if host == "" || strings.TrimSpace(cfg.From) == "" || len(cfg.To) == 0 {
+		return errors.New("host / from / to を指定してください")
+	}
+
+	port := cfg.Port
+	if port == 0 {
+		port = defaultSMTPPort
+	}
+
+	var auth smtp.Auth
+
+	if cfg.Username != "" {
+		password := os.Getenv(cfg.PasswordEnv)
+		if password == "" {
+			return fmt.Errorf("パスワードの環境変数 %q が設定されていません", cfg.PasswordEnv)
+		}
+
+		auth = smtp.PlainAuth("", cfg.Username, password, host)
+	}
+
+	msg, err := buildEmailMessage(cfg.From, cfg.To, r, time.Now())
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, emailTimeout)
+	defer cancel()
+
+	if err := sendSMTP(ctx, host, port, auth, cfg.From, cfg.To, msg); err != nil {
+		return fmt.Errorf("送信に失敗: %w", err)
+	}
+
+	return nil
+}
+
+// sendSMTP は smtp.SendMail と同じ手順で送信します。
+// net/smtp は context に対応しないため、期限を接続に設定し、context の取り消し時は接続を閉じます。
+func sendSMTP(ctx context.Context, host string, port int, auth smtp.Auth, from string, to []string, msg []byte) error {
+	conn, err := dialSMTP(ctx, host, port)
+	if err != nil {
+		return err
+	}
+
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if err := startSMTPSession(client, host, port, auth); err != nil {
+		return err
+	}
+
+	if err := writeSMTPMessage(client, from, to, msg); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+// dialSMTP は SMTP サーバーに接続します。ポート 465 の場合は TLS で接続します。
+func dialSMTP(ctx context.Context, host string, port int) (net.Conn, error) {
+	var dialer net.Dialer
+
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		return nil, err
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+
+	if port == smtpsPort {
+		return tls.Client(conn, smtpTLSConfig(host)), nil
+	}
+
+	return conn, nil
+}
+
+// startSMTPSession は STARTTLS（ポート 465 以外でサーバーが対応している場合）と認証を行います。
+func startSMTPSession(client *smtp.Client, host string, port int, auth smtp.Auth) error {
+	if port != smtpsPort {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(smtpTLSConfig(host)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if auth == nil {
+		return nil
+	}
+
+	if ok, _ := client.Extension("AUTH"); !ok {
+		return errors.New("サーバーが認証（AUTH）に対応していません")
+	}
+
+	return client.Auth(auth)
+}
+
+func writeSMTPMessage(client *smtp.Client, from string, to []string, msg []byte) error {
+	if err := client.Mail(from); err != nil {
+		return err
+	}
+
+	for _, addr := range to {
+		if err := client.Rcpt(addr); err != nil {
+			return err
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+
+	return w.Close()
+}
+
+func smtpTLSConfig(host string) *tls.Config {
+	return &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}
+}
+
+// buildEmailMessage は件名を MIME エンコードし、本文を quoted-printable にしたメールを生成します。
+func buildEmailMessage(from string, to []string, r *Report, now time.Time) ([]byte, error) {
+	var b bytes.Buffer
+
+	headers := [][2]string{
+		{"From", headerValue(from)},
+		{"To", headerValue(strings.Join(to, ", "))},
+		{"Subject", mime.QEncoding.Encode("utf-8", headerValue("[devsync] "+r.Title()))},
+		{"Date", now.Format(time.RFC1123Z)},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", "text/plain; charset=UTF-8"},
+		{"Content-Transfer-Encoding", "quoted-printable"},
+	}
+
+	for _, h := range headers {
+		fmt.Fprintf(&b, "%s: %s\r\n", h[0], h[1])
+	}
+
+	b.WriteString("\r\n")
+
+	w := quotedprintable.NewWriter(&b)
+	if _, err := w.Write([]byte(r.Text() + "\n")); err != nil {
+		return nil, fmt.Errorf("本文の生成に失敗: %w", err)
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("本文の生成に失敗: %w", err)
+	}
+
+	return b.Bytes(), nil
+}
+
+// headerValue はヘッダーの改行を取り除きます（設定値によるヘッダーの挿入を防ぐ）。
+func headerValue(value string) string {
+	return strings.NewReplacer("\r", "", "\n", " ").Replace(value)
+}
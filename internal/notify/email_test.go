@@ -0,0 +1,230 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/scottlz0310/devsync/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// smtpStandIn はテスト用の最小限の SMTP サーバーです（STARTTLS なし、AUTH PLAIN のみ）。
+type smtpStandIn struct {
+	addr string
+
+	mu     sync.Mutex
+	auth   string
+	from   string
+	rcpts  []string
+	data   string
+	closed chan struct{}
+}
+
+func startSMTPStandIn(t *testing.T) *smtpStandIn {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, ln.Close()) })
+
+	s := &smtpStandIn{addr: ln.Addr().String(), closed: make(chan struct{})}
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+
+		defer close(s.closed)
+		defer conn.Close()
+
+		s.serve(conn)
+	}()
+
+	return s
+}
+
+func (s *smtpStandIn) serve(conn net.Conn) {
+	r := bufio.NewReader(conn)
+	reply := func(line string) { fmt.Fprint(conn, line+"\r\n") }
+
+	reply("220 localhost ESMTP stand-in")
+
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+
+		line = strings.TrimRight(line, "\r\n")
+		verb := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+
+		s.mu.Lock()
+
+		switch verb {
+		case "EHLO", "HELO":
+			reply("250-localhost")
+			reply("250-8BITMIME")
+			reply("250 AUTH PLAIN")
+		case "AUTH":
+			s.auth = strings.TrimPrefix(line, "AUTH PLAIN ")
+			reply("235 2.7.0 Authentication successful")
+		case "MAIL":
+			s.from = line
+			reply("250 OK")
+		case "RCPT":
+			s.rcpts = append(s.rcpts, line)
+			reply("250 OK")
+		case "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			s.data = readSMTPData(r)
+			reply("250 OK")
+		case "QUIT":
+			reply("221 Bye")
+			s.mu.Unlock()
+
+			return
+		default:
+			reply("250 OK")
+		}
+
+		s.mu.Unlock()
+	}
+}
+
+func readSMTPData(r *bufio.Reader) string {
+	var b strings.Builder
+
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil || line == ".\r\n" {
+			return b.String()
+		}
+
+		b.WriteString(strings.TrimPrefix(line, "."))
+	}
+}
+
+func (s *smtpStandIn) wait(t *testing.T) {
+	t.Helper()
+
+	select {
+	case <-s.closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("SMTP の通信が終了しませんでした")
+	}
+}
+
+func TestSendEmail_WithSMTPStandIn(t *testing.T) {
+	server := startSMTPStandIn(t)
+	host, portText, err := net.SplitHostPort(server.addr)
+	require.NoError(t, err)
+
+	port, err := strconv.Atoi(portText)
+	require.NoError(t, err)
+
+	t.Setenv("DEVSYNC_TEST_SMTP_PASSWORD", "s3cret")
+
+	cfg := config.EmailNotifyConfig{
+		Enabled:     true,
+		Host:        host,
+		Port:        port,
+		Username:    "devsync",
+		PasswordEnv: "DEVSYNC_TEST_SMTP_PASSWORD",
+		From:        "devsync@example.com",
+		To:          []string{"me@example.com", "ops@example.com"},
+	}
+
+	require.NoError(t, SendEmail(context.Background(), cfg, newTestReport(false, 0)))
+	server.wait(t)
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	credentials, err := base64.StdEncoding.DecodeString(server.auth)
+	require.NoError(t, err)
+	assert.Equal(t, "\x00devsync\x00s3cret", string(credentials))
+	assert.Equal(t, "MAIL FROM:<devsync@example.com> BODY=8BITMIME", server.from)
+	assert.Equal(t, []string{"RCPT TO:<me@example.com>", "RCPT TO:<ops@example.com>"}, server.rcpts)
+
+	msg, err := mail.ReadMessage(strings.NewReader(server.data))
+	require.NoError(t, err)
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, "[devsync] ❌ devsync run: 1 件のエラー（work-laptop）", subject)
+	assert.Equal(t, "me@example.com, ops@example.com", msg.Header.Get("To"))
+
+	body, err := io.ReadAll(quotedprintable.NewReader(msg.Body))
+	require.NoError(t, err)
+	assert.Contains(t, string(body), "❌ npm: exit status 1")
+}
+
+func TestSendEmail_MissingPassword(t *testing.T) {
+	t.Setenv("DEVSYNC_TEST_SMTP_PASSWORD", "")
+
+	err := SendEmail(context.Background(), config.EmailNotifyConfig{
+		Enabled: true, Host: "127.0.0.1", Username: "devsync", PasswordEnv: "DEVSYNC_TEST_SMTP_PASSWORD",
+		From: "devsync@example.com", To: []string{"me@example.com"},
+	}, newTestReport(true, 0))
+	require.ErrorContains(t, err, `"DEVSYNC_TEST_SMTP_PASSWORD" が設定されていません`)
+}
+
+func TestSendEmail_UnresponsiveServerTimesOut(t *testing.T) {
+	// 接続は受け付けるが応答しないサーバー
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, ln.Close()) })
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+
+		t.Cleanup(func() { conn.Close() })
+	}()
+
+	host, portText, err := net.SplitHostPort(ln.Addr().String())
+	require.NoError(t, err)
+
+	port, err := strconv.Atoi(portText)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	started := time.Now()
+	err = SendEmail(ctx, config.EmailNotifyConfig{
+		Enabled: true, Host: host, Port: port, From: "devsync@example.com", To: []string{"me@example.com"},
+	}, newTestReport(false, 0))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "送信に失敗")
+	assert.Less(t, time.Since(started), 5*time.Second)
+}
+
+func TestBuildEmailMessage_StripsHeaderNewlines(t *testing.T) {
+	t.Parallel()
+
+	msg, err := buildEmailMessage("devsync@example.com\r\nBcc: evil@example.com", []string{"me@example.com"}, newTestReport(true, 0), time.Date(2024, 6, 1, 7, 32, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	parsed, err := mail.ReadMessage(strings.NewReader(string(msg)))
+	require.NoError(t, err)
+	assert.Empty(t, parsed.Header.Get("Bcc"))
+	assert.Equal(t, "Sat, 01 Jun 2024 07:32:00 +0000", parsed.Header.Get("Date"))
+}
@@ -0,0 +1,163 @@
+// Package notify は devsync run の完了を通知します（デスクトップ通知・Webhook・メール）。
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/scottlz0310/devsync/internal/config"
+	"github.com/scottlz0310/devsync/internal/runner"
+)
+
+// Report は devsync run の実行結果です（Webhook の json 形式ではそのまま送信します）。
+type Report struct {
+	Command    string    `json:"command"`
+	Hostname   string    `json:"hostname"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	Success    bool      `json:"success"`
+	// Updated は更新したパッケージの数です。
+	Updated int `json:"updated"`
+	// Errors は失敗したフェーズとそのエラーです。
+	Errors []string     `json:"errors,omitempty"`
+	Jobs   []JobSummary `json:"jobs"`
+}
+
+// JobSummary は sys update / repo update などのジョブ実行の集計です。
+type JobSummary struct {
+	Name    string         `json:"name"`
+	Summary runner.Summary `json:"summary"`
+}
+
+// NewReport は開始時刻とホスト名を設定した Report を返します。
+func NewReport(command string, startedAt time.Time) *Report {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = ""
+	}
+
+	return &Report{Command: command, Hostname: hostname, StartedAt: startedAt, Success: true, Jobs: []JobSummary{}}
+}
+
+// AddJobs はジョブ実行の集計を追加します。同じ名前の集計が既にあればまとめます。
+func (r *Report) AddJobs(name string, summary runner.Summary) {
+	for i := range r.Jobs {
+		if r.Jobs[i].Name != name {
+			continue
+		}
+
+		merged := &r.Jobs[i].Summary
+		merged.Total += summary.Total
+		merged.Success += summary.Success
+		merged.Failed += summary.Failed
+		merged.Skipped += summary.Skipped
+		merged.Results = append(merged.Results, summary.Results...)
+
+		return
+	}
+
+	r.Jobs = append(r.Jobs, JobSummary{Name: name, Summary: summary})
+}
+
+// Title は通知のタイトル（メールの件名）です。
+func (r *Report) Title() string {
+	if !r.Success {
+		return fmt.Sprintf("❌ %s: %d 件のエラー（%s）", r.Command, len(r.Errors), r.Hostname)
+	}
+
+	if r.Updated > 0 {
+		return fmt.Sprintf("✅ %s: %d 件を更新（%s）", r.Command, r.Updated, r.Hostname)
+	}
+
+	return fmt.Sprintf("✅ %s: 完了（%s）", r.Command, r.Hostname)
+}
+
+// Text は通知の本文です。
+func (r *Report) Text() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "開始: %s（所要時間 %s）\n", r.StartedAt.Local().Format("2006-01-02 15:04:05"), r.FinishedAt.Sub(r.StartedAt).Round(time.Second))
+	fmt.Fprintf(&b, "更新したパッケージ: %d 件\n", r.Updated)
+
+	for _, job := range r.Jobs {
+		s := job.Summary
+		fmt.Fprintf(&b, "%s: 成功 %d / 失敗 %d / スキップ %d\n", job.Name, s.Success, s.Failed, s.Skipped)
+
+		for _, result := range s.Results {
+			if result.Status == runner.StatusFailed {
+				fmt.Fprintf(&b, "  ❌ %s: %v\n", result.Name, result.Err)
+			}
+		}
+	}
+
+	if len(r.Errors) > 0 {
+		b.WriteString("エラー:\n")
+
+		for _, e := range r.Errors {
+			fmt.Fprintf(&b, "  %s\n", e)
+		}
+	}
+
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// ShouldNotify は通知条件 on（空の場合は on_failure）で report を通知するかを返します。
+func ShouldNotify(on string, r *Report) bool {
+	switch strings.TrimSpace(on) {
+	case config.NotifyAlways:
+		return true
+	case config.NotifyOnUpdates:
+		return !r.Success || r.Updated > 0
+	default:
+		return !r.Success
+	}
+}
+
+// Enabled は通知先が 1 つ以上設定されているかを返します。
+func Enabled(cfg config.NotifyConfig) bool {
+	return cfg.Desktop.Enabled || len(cfg.Webhooks) > 0 || cfg.Email.Enabled
+}
+
+// Send は通知条件を満たす通知先に report を送り、送信した通知先の数を返します。
+// 一部の通知先で失敗しても残りの通知先には送信し、失敗をまとめて返します。
+func Send(ctx context.Context, cfg config.NotifyConfig, r *Report) (int, error) {
+	var (
+		sent int
+		errs []error
+	)
+
+	send := func(name, on string, fn func() error) {
+		if on == "" {
+			on = cfg.On
+		}
+
+		if !ShouldNotify(on, r) {
+			return
+		}
+
+		if err := fn(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			return
+		}
+
+		sent++
+	}
+
+	if cfg.Desktop.Enabled {
+		send("デスクトップ通知", cfg.Desktop.On, func() error { return SendDesktop(ctx, r) })
+	}
+
+	for i, webhook := range cfg.Webhooks {
+		send(fmt.Sprintf("webhooks[%d]", i), webhook.On, func() error { return SendWebhook(ctx, webhook, r) })
+	}
+
+	if cfg.Email.Enabled {
+		send("メール", cfg.Email.On, func() error { return SendEmail(ctx, cfg.Email, r) })
+	}
+
+	return sent, errors.Join(errs...)
+}
@@ -0,0 +1,152 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/scottlz0310/devsync/internal/config"
+	"github.com/scottlz0310/devsync/internal/runner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestReport(success bool, updated int) *Report {
+	r := &Report{
+		Command:    "devsync run",
+		Hostname:   "work-laptop",
+		StartedAt:  time.Date(2024, 6, 1, 7, 30, 0, 0, time.UTC),
+		FinishedAt: time.Date(2024, 6, 1, 7, 32, 5, 0, time.UTC),
+		Success:    success,
+		Updated:    updated,
+		Jobs:       []JobSummary{},
+	}
+
+	if !success {
+		r.Errors = []string{"システム更新: 1 件のエラーが発生しました"}
+		r.AddJobs("sys update", runner.Summary{
+			Total: 2, Success: 1, Failed: 1,
+			Results: []runner.Result{
+				{Name: "apt", Status: runner.StatusSuccess},
+				{Name: "npm", Status: runner.StatusFailed, Err: errors.New("exit status 1")},
+			},
+		})
+	}
+
+	return r
+}
+
+func TestShouldNotify(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		on      string
+		success bool
+		updated int
+		want    bool
+	}{
+		{name: "既定は失敗時のみ（成功）", on: "", success: true, updated: 3, want: false},
+		{name: "既定は失敗時のみ（失敗）", on: "", success: false, want: true},
+		{name: "on_failure で成功", on: config.NotifyOnFailure, success: true, want: false},
+		{name: "on_updates で更新あり", on: config.NotifyOnUpdates, success: true, updated: 1, want: true},
+		{name: "on_updates で更新なし", on: config.NotifyOnUpdates, success: true, updated: 0, want: false},
+		{name: "on_updates で失敗", on: config.NotifyOnUpdates, success: false, want: true},
+		{name: "always は毎回", on: config.NotifyAlways, success: true, want: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.want, ShouldNotify(tc.on, newTestReport(tc.success, tc.updated)))
+		})
+	}
+}
+
+func TestReportTitleAndText(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "✅ devsync run: 完了（work-laptop）", newTestReport(true, 0).Title())
+	assert.Equal(t, "✅ devsync run: 3 件を更新（work-laptop）", newTestReport(true, 3).Title())
+
+	failed := newTestReport(false, 0)
+	assert.Equal(t, "❌ devsync run: 1 件のエラー（work-laptop）", failed.Title())
+
+	text := failed.Text()
+	assert.Contains(t, text, "所要時間 2m5s")
+	assert.Contains(t, text, "sys update: 成功 1 / 失敗 1 / スキップ 0")
+	assert.Contains(t, text, "❌ npm: exit status 1")
+	assert.Contains(t, text, "システム更新: 1 件のエラーが発生しました")
+}
+
+func TestSend_UsesPerSinkTrigger(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		received []string
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		received = append(received, r.URL.Path)
+		mu.Unlock()
+
+		if r.URL.Path == "/broken" {
+			http.Error(w, "invalid_token", http.StatusForbidden)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+
+	commands := stubDesktop(t, "linux", map[string]bool{"notify-send": true})
+
+	cfg := config.NotifyConfig{
+		On:      config.NotifyOnFailure,
+		Desktop: config.DesktopNotifyConfig{Enabled: true, On: config.NotifyAlways},
+		Webhooks: []config.WebhookNotifyConfig{
+			{URL: server.URL + "/failure"},
+			{URL: server.URL + "/updates", Format: "slack", On: config.NotifyOnUpdates},
+			{URL: server.URL + "/broken", Format: "discord", On: config.NotifyAlways},
+		},
+	}
+
+	sent, err := Send(context.Background(), cfg, newTestReport(true, 2))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "webhooks[2]: 送信に失敗: HTTP 403: invalid_token")
+	assert.Equal(t, 2, sent) // デスクトップ通知と /updates（/failure は成功時のため送らない）
+	assert.Equal(t, []string{"/updates", "/broken"}, received)
+	assert.Len(t, *commands, 1)
+}
+
+func TestReportJSON(t *testing.T) {
+	t.Parallel()
+
+	data, err := json.Marshal(newTestReport(false, 0))
+	require.NoError(t, err)
+
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(data, &decoded))
+
+	assert.Equal(t, "devsync run", decoded["command"])
+	assert.Equal(t, false, decoded["success"])
+
+	jobs, ok := decoded["jobs"].([]any)
+	require.True(t, ok)
+	require.Len(t, jobs, 1)
+
+	job, ok := jobs[0].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "sys update", job["name"])
+
+	summary, ok := job["summary"].(map[string]any)
+	require.True(t, ok)
+	assert.InDelta(t, 1, summary["failed"], 0)
+}
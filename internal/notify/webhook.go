@@ -0,0 +1,104 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/scottlz0310/devsync/internal/config"
+)
+
+const webhookTimeout = 10 * time.Second
+
+// SendWebhook は report を Webhook に POST します。
+// format が json（既定）の場合は Report をそのまま、slack / discord の場合は各サービスの受信 Webhook の形式で送信します。
+func SendWebhook(ctx context.Context, cfg config.WebhookNotifyConfig, r *Report) error {
+	endpoint := strings.TrimSpace(os.ExpandEnv(cfg.URL))
+	if endpoint == "" {
+		return fmt.Errorf("URL が空です（%q）", cfg.URL)
+	}
+
+	body, err := webhookPayload(cfg.Format, r)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, webhookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("リクエストの作成に失敗: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "devsync")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		// URL にトークンが含まれることがあるため、エラーには含めない
+		return fmt.Errorf("送信に失敗: %w", unwrapURLError(err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, readErr := io.ReadAll(io.LimitReader(resp.Body, 512))
+		if readErr != nil || len(bytes.TrimSpace(detail)) == 0 {
+			return fmt.Errorf("送信に失敗: HTTP %d", resp.StatusCode)
+		}
+
+		return fmt.Errorf("送信に失敗: HTTP %d: %s", resp.StatusCode, bytes.TrimSpace(detail))
+	}
+
+	return nil
+}
+
+func webhookPayload(format string, r *Report) ([]byte, error) {
+	var payload any
+
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "", "json":
+		payload = r
+	case "slack":
+		payload = map[string]string{"text": "*" + r.Title() + "*\n" + r.Text()}
+	case "discord":
+		payload = map[string]string{"content": "**" + r.Title() + "**\n" + truncate(r.Text(), 1800)}
+	default:
+		return nil, fmt.Errorf("未対応の形式です: %q（json / slack / discord）", format)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("通知内容の生成に失敗: %w", err)
+	}
+
+	return body, nil
+}
+
+// truncate は Discord のメッセージ長の上限（2000 文字）を超えないよう本文を切り詰めます。
+func truncate(value string, limit int) string {
+	runes := []rune(value)
+	if len(runes) <= limit {
+		return value
+	}
+
+	return string(runes[:limit]) + "\n…"
+}
+
+// unwrapURLError は URL を含まない下位のエラーを返します。
+func unwrapURLError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+
+	return err
+}
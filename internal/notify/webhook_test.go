@@ -0,0 +1,120 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/scottlz0310/devsync/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startWebhookServer はリクエストの本文を記録する Webhook の受信先を起動します。
+func startWebhookServer(t *testing.T, status int) (server *httptest.Server, bodies chan map[string]any) {
+	t.Helper()
+
+	bodies = make(chan map[string]any, 1)
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		data, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+
+		var body map[string]any
+		assert.NoError(t, json.Unmarshal(data, &body))
+		bodies <- body
+
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, bodies
+}
+
+func TestSendWebhook_Formats(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		format string
+		check  func(t *testing.T, body map[string]any)
+	}{
+		{
+			name:   "json は Report をそのまま送る",
+			format: "",
+			check: func(t *testing.T, body map[string]any) {
+				assert.Equal(t, "devsync run", body["command"])
+				assert.Equal(t, false, body["success"])
+				assert.Contains(t, body, "jobs")
+			},
+		},
+		{
+			name:   "slack は text",
+			format: "slack",
+			check: func(t *testing.T, body map[string]any) {
+				text, ok := body["text"].(string)
+				require.True(t, ok)
+				assert.True(t, strings.HasPrefix(text, "*❌ devsync run: 1 件のエラー（work-laptop）*\n"), text)
+			},
+		},
+		{
+			name:   "discord は content",
+			format: "Discord",
+			check: func(t *testing.T, body map[string]any) {
+				content, ok := body["content"].(string)
+				require.True(t, ok)
+				assert.Contains(t, content, "❌ npm: exit status 1")
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			server, bodies := startWebhookServer(t, http.StatusOK)
+
+			err := SendWebhook(context.Background(), config.WebhookNotifyConfig{URL: server.URL, Format: tc.format}, newTestReport(false, 0))
+			require.NoError(t, err)
+
+			tc.check(t, <-bodies)
+		})
+	}
+}
+
+func TestSendWebhook_ExpandsEnvInURL(t *testing.T) {
+	server, bodies := startWebhookServer(t, http.StatusOK)
+	t.Setenv("DEVSYNC_TEST_WEBHOOK_URL", server.URL)
+
+	err := SendWebhook(context.Background(), config.WebhookNotifyConfig{URL: "${DEVSYNC_TEST_WEBHOOK_URL}"}, newTestReport(true, 0))
+	require.NoError(t, err)
+	assert.Equal(t, true, (<-bodies)["success"])
+}
+
+func TestSendWebhook_Errors(t *testing.T) {
+	t.Setenv("DEVSYNC_TEST_WEBHOOK_URL", "")
+
+	server, _ := startWebhookServer(t, http.StatusInternalServerError)
+
+	err := SendWebhook(context.Background(), config.WebhookNotifyConfig{URL: server.URL}, newTestReport(true, 0))
+	require.ErrorContains(t, err, "HTTP 500")
+
+	err = SendWebhook(context.Background(), config.WebhookNotifyConfig{URL: "${DEVSYNC_TEST_WEBHOOK_URL}"}, newTestReport(true, 0))
+	require.ErrorContains(t, err, "URL が空です")
+
+	err = SendWebhook(context.Background(), config.WebhookNotifyConfig{URL: server.URL, Format: "teams"}, newTestReport(true, 0))
+	require.ErrorContains(t, err, "未対応の形式です")
+}
+
+func TestTruncate(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "abc", truncate("abc", 3))
+	assert.Equal(t, "あい\n…", truncate("あいう", 2))
+}
@@ -3,6 +3,7 @@ package runner
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"sync"
@@ -37,13 +38,33 @@ type Result struct {
 	Duration time.Duration
 }
 
+// MarshalJSON は Err を文字列、Duration を秒数に変換して JSON にします（通知の Webhook などで使用）。
+func (r Result) MarshalJSON() ([]byte, error) {
+	payload := struct {
+		Name     string       `json:"name"`
+		Status   ResultStatus `json:"status"`
+		Error    string       `json:"error,omitempty"`
+		Duration float64      `json:"duration_seconds"`
+	}{
+		Name:     r.Name,
+		Status:   r.Status,
+		Duration: r.Duration.Seconds(),
+	}
+
+	if r.Err != nil {
+		payload.Error = r.Err.Error()
+	}
+
+	return json.Marshal(payload)
+}
+
 // Summary は全ジョブの実行集計です。
 type Summary struct {
-	Total   int
-	Success int
-	Failed  int
-	Skipped int
-	Results []Result
+	Total   int      `json:"total"`
+	Success int      `json:"success"`
+	Failed  int      `json:"failed"`
+	Skipped int      `json:"skipped"`
+	Results []Result `json:"results"`
 }
 
 // EventType は実行中に通知されるイベント種別です。
@@ -2,6 +2,7 @@ package runner
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"sync/atomic"
 	"testing"
@@ -387,3 +388,29 @@ func TestExecuteWithEvents_EventTimestamp(t *testing.T) {
 		t.Fatalf("Success = %d, want 1", summary.Success)
 	}
 }
+
+func TestSummaryJSON(t *testing.T) {
+	t.Parallel()
+
+	summary := Summary{
+		Total:   2,
+		Success: 1,
+		Failed:  1,
+		Results: []Result{
+			{Name: "apt", Status: StatusSuccess, Duration: 1500 * time.Millisecond},
+			{Name: "npm", Status: StatusFailed, Err: errors.New("exit status 1"), Duration: 2 * time.Second},
+		},
+	}
+
+	data, err := json.Marshal(summary)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	want := `{"total":2,"success":1,"failed":1,"skipped":0,"results":[` +
+		`{"name":"apt","status":"success","duration_seconds":1.5},` +
+		`{"name":"npm","status":"failed","error":"exit status 1","duration_seconds":2}]}`
+	if string(data) != want {
+		t.Fatalf("json.Marshal() = %s, want %s", data, want)
+	}
+}
@@ -0,0 +1,211 @@
+package secret
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// AuditStatus はシークレットの期限・ローテーション状態です。
+type AuditStatus string
+
+// AuditStatus の定数
+const (
+	AuditStatusOK            AuditStatus = "ok"
+	AuditStatusExpiring      AuditStatus = "expiring"
+	AuditStatusExpired       AuditStatus = "expired"
+	AuditStatusRotationDue   AuditStatus = "rotation_due"
+	AuditStatusInvalidExpiry AuditStatus = "invalid_expiry"
+)
+
+const (
+	// expiresFieldName は有効期限を記録するカスタムフィールド名です。
+	expiresFieldName = "expires"
+)
+
+// expiresLayouts は expires フィールドで受け付ける日付形式です。
+var expiresLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006/01/02",
+}
+
+// AuditPolicy は期限・ローテーション判定の基準です。
+type AuditPolicy struct {
+	// ExpiryWarningDays は期限までの残り日数がこの値以下の項目を期限間近とします。0 以下で無効です
+	// （期限切れの判定は常に行います）。
+	ExpiryWarningDays int
+	// RotationDays は最終更新からこの日数を超えた項目をローテーション推奨とします。0 以下で無効です。
+	RotationDays int
+	// Now は判定の基準時刻です。ゼロ値の場合は現在時刻を使用します。
+	Now time.Time
+}
+
+// AuditEntry は env: 項目1件の監査結果です。シークレットの値は含みません。
+type AuditEntry struct {
+	Name    string
+	VarName string
+	// RevisionDate は Bitwarden 上の最終更新日時です。不明な場合はゼロ値です。
+	RevisionDate time.Time
+	// AgeDays は最終更新からの経過日数です。不明な場合は -1 です。
+	AgeDays int
+	// Expires は expires フィールドの期限です。未設定の場合はゼロ値です。
+	Expires time.Time
+	// DaysUntilExpiry は期限までの残り日数です（期限切れの場合は負の値）。
+	DaysUntilExpiry int
+	Status          AuditStatus
+	Message         string
+}
+
+// NeedsAttention は警告対象（期限切れ・期限間近・ローテーション推奨・期限形式不正）かを返します。
+func (e AuditEntry) NeedsAttention() bool {
+	return e.Status != AuditStatusOK
+}
+
+// AuditStatusLabel は状態を日本語ラベルに変換します。
+func AuditStatusLabel(status AuditStatus) string {
+	switch status {
+	case AuditStatusOK:
+		return "OK"
+	case AuditStatusExpiring:
+		return "期限間近"
+	case AuditStatusExpired:
+		return "期限切れ"
+	case AuditStatusRotationDue:
+		return "ローテーション推奨"
+	case AuditStatusInvalidExpiry:
+		return "期限形式不正"
+	default:
+		return "不明"
+	}
+}
+
+// Audit は Bitwarden の env: 項目を取得し、期限・ローテーション状態を返します。
+// サーバーとの同期は行わないため、最新の状態が必要な場合は事前に Sync を呼び出してください。
+func Audit(policy AuditPolicy) ([]AuditEntry, error) {
+	defer debugTimerStart("Audit 全体")()
+
+	if err := checkBitwardenSession(); err != nil {
+		return nil, err
+	}
+
+	items, err := listEnvItemsFunc()
+	if err != nil {
+		return nil, err
+	}
+
+	return AuditItems(items, policy), nil
+}
+
+// AuditItems は env: 項目の期限・ローテーション状態を項目名順に返します。
+func AuditItems(items []BitwardenItem, policy AuditPolicy) []AuditEntry {
+	now := policy.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	entries := make([]AuditEntry, 0, len(items))
+
+	for i := range items {
+		if !strings.HasPrefix(items[i].Name, "env:") {
+			continue
+		}
+
+		entries = append(entries, auditItem(&items[i], now, policy.ExpiryWarningDays, policy.RotationDays))
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name < entries[j].Name
+	})
+
+	return entries
+}
+
+// FilterAuditWarnings は警告対象の監査結果のみを返します。
+func FilterAuditWarnings(entries []AuditEntry) []AuditEntry {
+	warnings := make([]AuditEntry, 0, len(entries))
+
+	for _, entry := range entries {
+		if entry.NeedsAttention() {
+			warnings = append(warnings, entry)
+		}
+	}
+
+	return warnings
+}
+
+func auditItem(item *BitwardenItem, now time.Time, warningDays, rotationDays int) AuditEntry {
+	entry := AuditEntry{
+		Name:         item.Name,
+		VarName:      strings.TrimPrefix(item.Name, "env:"),
+		RevisionDate: item.RevisionDate,
+		AgeDays:      -1,
+		Status:       AuditStatusOK,
+	}
+
+	if !item.RevisionDate.IsZero() {
+		entry.AgeDays = int(now.Sub(item.RevisionDate).Hours() / 24)
+	}
+
+	rawExpires := strings.TrimSpace(getCustomFieldValue(item.Fields, expiresFieldName))
+	if rawExpires != "" {
+		expires, err := parseExpires(rawExpires, now.Location())
+		if err != nil {
+			entry.Status = AuditStatusInvalidExpiry
+			entry.Message = fmt.Sprintf("%s フィールドの日付形式が不正です: %q（例: 2026-12-31）", expiresFieldName, rawExpires)
+
+			return entry
+		}
+
+		entry.Expires = expires
+		entry.DaysUntilExpiry = int(expires.Sub(now).Hours() / 24)
+
+		switch {
+		case !now.Before(expires):
+			entry.Status = AuditStatusExpired
+			entry.Message = fmt.Sprintf("%s に期限切れになりました", expires.Format("2006-01-02"))
+
+			return entry
+		case warningDays > 0 && entry.DaysUntilExpiry <= warningDays:
+			entry.Status = AuditStatusExpiring
+			entry.Message = fmt.Sprintf("あと %d 日で期限切れです（%s）", entry.DaysUntilExpiry, expires.Format("2006-01-02"))
+
+			return entry
+		}
+	}
+
+	if rotationDays > 0 && entry.AgeDays > rotationDays {
+		entry.Status = AuditStatusRotationDue
+		entry.Message = fmt.Sprintf("最終更新から %d 日経過しています（ローテーション基準: %d 日）", entry.AgeDays, rotationDays)
+
+		return entry
+	}
+
+	if !entry.Expires.IsZero() {
+		entry.Message = fmt.Sprintf("期限: %s", entry.Expires.Format("2006-01-02"))
+	}
+
+	return entry
+}
+
+// parseExpires は expires フィールドの値を解析します。
+// 日付のみの場合は、その日の終わり（loc における 23:59:59）まで有効とみなします。
+func parseExpires(value string, loc *time.Location) (time.Time, error) {
+	for _, layout := range expiresLayouts {
+		parsed, err := time.ParseInLocation(layout, value, loc)
+		if err != nil {
+			continue
+		}
+
+		if layout == "2006-01-02" || layout == "2006/01/02" {
+			parsed = parsed.Add(24*time.Hour - time.Second)
+		}
+
+		return parsed, nil
+	}
+
+	return time.Time{}, fmt.Errorf("日付形式が不正です: %q", value)
+}
@@ -0,0 +1,134 @@
+package secret
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditItems(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	daysAgo := func(days int) time.Time { return now.AddDate(0, 0, -days) }
+	withExpires := func(value string) []BitwardenCustomField {
+		return []BitwardenCustomField{{Name: "value", Value: "secret-value"}, {Name: "Expires", Value: value}}
+	}
+
+	items := []BitwardenItem{
+		{Name: "env:OK_TOKEN", RevisionDate: daysAgo(10), Fields: withExpires("2027-01-31")},
+		{Name: "env:EXPIRING", RevisionDate: daysAgo(5), Fields: withExpires("2026-10-25")},
+		{Name: "env:EXPIRED", RevisionDate: daysAgo(400), Fields: withExpires("2026-10-18")},
+		{Name: "env:TODAY", RevisionDate: daysAgo(1), Fields: withExpires("2026-10-19")},
+		{Name: "env:OLD", RevisionDate: daysAgo(120)},
+		{Name: "env:BAD_DATE", RevisionDate: daysAgo(1), Fields: withExpires("next month")},
+		{Name: "env:NO_DATE"},
+		{Name: "not-env-item", RevisionDate: daysAgo(1000)},
+	}
+
+	entries := AuditItems(items, AuditPolicy{ExpiryWarningDays: 7, RotationDays: 90, Now: now})
+
+	byName := make(map[string]AuditEntry, len(entries))
+	names := make([]string, 0, len(entries))
+
+	for _, entry := range entries {
+		byName[entry.Name] = entry
+		names = append(names, entry.Name)
+	}
+
+	assert.Equal(t, []string{
+		"env:BAD_DATE", "env:EXPIRED", "env:EXPIRING", "env:NO_DATE", "env:OK_TOKEN", "env:OLD", "env:TODAY",
+	}, names, "env: 項目のみを項目名順に返す")
+
+	tests := []struct {
+		name       string
+		wantStatus AuditStatus
+		wantAge    int
+		wantMsg    string
+	}{
+		{name: "env:OK_TOKEN", wantStatus: AuditStatusOK, wantAge: 10, wantMsg: "期限: 2027-01-31"},
+		{name: "env:EXPIRING", wantStatus: AuditStatusExpiring, wantAge: 5, wantMsg: "あと 6 日"},
+		{name: "env:EXPIRED", wantStatus: AuditStatusExpired, wantAge: 400, wantMsg: "2026-10-18"},
+		{name: "env:TODAY", wantStatus: AuditStatusExpiring, wantAge: 1, wantMsg: "あと 0 日"},
+		{name: "env:OLD", wantStatus: AuditStatusRotationDue, wantAge: 120, wantMsg: "120 日経過"},
+		{name: "env:BAD_DATE", wantStatus: AuditStatusInvalidExpiry, wantAge: 1, wantMsg: "next month"},
+		{name: "env:NO_DATE", wantStatus: AuditStatusOK, wantAge: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, ok := byName[tt.name]
+			require.True(t, ok)
+			assert.Equal(t, tt.wantStatus, entry.Status)
+			assert.Equal(t, tt.wantAge, entry.AgeDays)
+			assert.Contains(t, entry.Message, tt.wantMsg)
+			assert.NotContains(t, entry.Message, "secret-value", "値を含めない")
+		})
+	}
+
+	warnings := FilterAuditWarnings(entries)
+	assert.Len(t, warnings, 5)
+}
+
+func TestAuditItemsDefaults(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	items := []BitwardenItem{
+		{Name: "env:IN_TWO_WEEKS", Fields: []BitwardenCustomField{{Name: "expires", Value: "2026-11-01"}}},
+		{Name: "env:VERY_OLD", RevisionDate: now.AddDate(-3, 0, 0)},
+	}
+
+	entries := AuditItems(items, AuditPolicy{Now: now})
+	require.Len(t, entries, 2)
+
+	assert.Equal(t, AuditStatusOK, entries[0].Status, "expiry_warning_days が 0 なら期限間近を警告しない")
+	assert.Equal(t, AuditStatusOK, entries[1].Status, "rotation_days 未設定ならローテーション判定しない")
+}
+
+func TestParseExpires(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{"2026-12-31", time.Date(2026, 12, 31, 23, 59, 59, 0, time.UTC)},
+		{"2026/12/31", time.Date(2026, 12, 31, 23, 59, 59, 0, time.UTC)},
+		{"2026-12-31 09:30", time.Date(2026, 12, 31, 9, 30, 0, 0, time.UTC)},
+		{"2026-12-31T09:30:00Z", time.Date(2026, 12, 31, 9, 30, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseExpires(tt.value, time.UTC)
+			require.NoError(t, err)
+			assert.True(t, tt.want.Equal(got), "got %s", got)
+		})
+	}
+
+	_, err := parseExpires("31.12.2026", time.UTC)
+	assert.Error(t, err)
+}
+
+func TestGetEnvVarsWithAuditItemsFromSameListing(t *testing.T) {
+	items := []BitwardenItem{
+		{Name: "env:API_KEY", Fields: []BitwardenCustomField{{Name: "value", Value: "k"}, {Name: "expires", Value: "2000-01-01"}}},
+	}
+
+	envVars, err := envVarsFromItems(items)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"API_KEY": "k"}, envVars, "expires フィールドは値に影響しない")
+
+	entries := AuditItems(items, AuditPolicy{})
+	require.Len(t, entries, 1)
+	assert.Equal(t, AuditStatusExpired, entries[0].Status)
+}
+
+func TestBitwardenItemRevisionDateJSON(t *testing.T) {
+	var items []BitwardenItem
+
+	data := `[{"name":"env:A","revisionDate":"2026-01-15T10:20:30.123Z"},{"name":"env:B","revisionDate":null}]`
+	require.NoError(t, json.Unmarshal([]byte(data), &items))
+
+	assert.Equal(t, time.Date(2026, 1, 15, 10, 20, 30, 123000000, time.UTC), items[0].RevisionDate)
+	assert.True(t, items[1].RevisionDate.IsZero())
+}
@@ -19,6 +19,9 @@ const (
 // syncFunc はテストで差し替え可能な同期処理の関数変数です。
 var syncFunc = Sync
 
+// listEnvItemsFunc はテストで差し替え可能な env: 項目一覧の取得処理の関数変数です。
+var listEnvItemsFunc = listBitwardenEnvItems
+
 // debugLog はデバッグログを出力します。DEVSYNC_DEBUG=1 で有効化されます。
 func debugLog(format string, args ...interface{}) {
 	if os.Getenv("DEVSYNC_DEBUG") != "1" {
@@ -51,6 +54,8 @@ type BitwardenItem struct {
 	Notes  string                 `json:"notes"`
 	Fields []BitwardenCustomField `json:"fields"`
 	Login  *BitwardenLogin        `json:"login,omitempty"`
+	// RevisionDate は項目の最終更新日時です（ローテーション判定に使用）。
+	RevisionDate time.Time `json:"revisionDate"`
 }
 
 // BitwardenCustomField はカスタムフィールドの構造体です。
@@ -345,34 +350,73 @@ func printLoadStats(stats *LoadStats) error {
 func GetEnvVars() (map[string]string, error) {
 	defer debugTimerStart("GetEnvVars 全体")()
 
-	envVars := make(map[string]string)
+	items, err := fetchEnvItemsWithSync()
+	if err != nil {
+		return nil, err
+	}
+
+	return envVarsFromItems(items)
+}
+
+// GetEnvVarsWithAudit は GetEnvVars と同じ環境変数に加え、
+// 同じ項目一覧から作成した期限・ローテーションの監査結果を返します（bw の追加呼び出しなし）。
+func GetEnvVarsWithAudit(policy AuditPolicy) (map[string]string, []AuditEntry, error) {
+	defer debugTimerStart("GetEnvVarsWithAudit 全体")()
+
+	items, err := fetchEnvItemsWithSync()
+	if err != nil {
+		return nil, nil, err
+	}
 
+	envVars, err := envVarsFromItems(items)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return envVars, AuditItems(items, policy), nil
+}
+
+// fetchEnvItemsWithSync はセッションを確認し、サーバーと同期してから env: 項目を取得します。
+func fetchEnvItemsWithSync() ([]BitwardenItem, error) {
+	if err := checkBitwardenSession(); err != nil {
+		return nil, err
+	}
+
+	// サーバーと同期して最新データを取得（参照実装に合わせ、失敗時は中断）
+	if syncErr := syncFunc(); syncErr != nil {
+		return nil, syncErr
+	}
+
+	return listEnvItemsFunc()
+}
+
+// checkBitwardenSession は bw コマンド・BW_SESSION・アンロック状態を確認します。
+func checkBitwardenSession() error {
 	// bwコマンドの存在確認
 	if _, err := exec.LookPath("bw"); err != nil {
-		return nil, fmt.Errorf("bw コマンドが見つかりません")
+		return fmt.Errorf("bw コマンドが見つかりません")
 	}
 
 	// BW_SESSIONが設定されていない場合はエラー
 	if os.Getenv("BW_SESSION") == "" {
-		return nil, fmt.Errorf("BW_SESSION が設定されていません。bitwarden をアンロックしてください")
+		return fmt.Errorf("BW_SESSION が設定されていません。bitwarden をアンロックしてください")
 	}
 
 	// ステータス確認
 	status, err := getBitwardenStatus()
 	if err != nil {
-		return nil, fmt.Errorf("bitwarden のステータス確認に失敗しました: %w", err)
+		return fmt.Errorf("bitwarden のステータス確認に失敗しました: %w", err)
 	}
 
 	if status != statusUnlocked {
-		return nil, fmt.Errorf("bitwarden がロックされています。'bw unlock' を実行してください")
+		return fmt.Errorf("bitwarden がロックされています。'bw unlock' を実行してください")
 	}
 
-	// サーバーと同期して最新データを取得（参照実装に合わせ、失敗時は中断）
-	if syncErr := syncFunc(); syncErr != nil {
-		return nil, syncErr
-	}
+	return nil
+}
 
-	// env: プレフィックス付きの項目を検索
+// listBitwardenEnvItems は `bw list items --search env:` の結果を進捗表示なしで返します。
+func listBitwardenEnvItems() ([]BitwardenItem, error) {
 	done := debugTimerStart("bw list items --search env: (GetEnvVars)")
 	cmd := exec.CommandContext(context.Background(), "bw", "list", "items", "--search", "env:")
 
@@ -389,6 +433,13 @@ func GetEnvVars() (map[string]string, error) {
 		return nil, fmt.Errorf("JSON のパースに失敗しました: %w", err)
 	}
 
+	return items, nil
+}
+
+// envVarsFromItems は env: 項目から環境変数の map を作成します。
+func envVarsFromItems(items []BitwardenItem) (map[string]string, error) {
+	envVars := make(map[string]string)
+
 	// 各項目を処理
 	for _, item := range items {
 		if !strings.HasPrefix(item.Name, "env:") {
@@ -1,12 +1,16 @@
 package secret
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"sort"
 	"strings"
 )
 
@@ -24,8 +28,67 @@ const (
 	ShellBash       ShellType = "bash"
 	ShellZsh        ShellType = "zsh"
 	ShellPowerShell ShellType = "powershell"
+	ShellFish       ShellType = "fish"
+	ShellNushell    ShellType = "nushell"
+	ShellCmd        ShellType = "cmd"
+	// 以下はシェルではなくファイル/ツール向けの出力形式です。
+	ShellDotenv    ShellType = "dotenv"
+	ShellDockerEnv ShellType = "docker"
+	ShellDirenv    ShellType = "direnv"
+	ShellJSON      ShellType = "json"
+	ShellGitHubEnv ShellType = "github-env"
 )
 
+// supportedExportFormats は --format で指定可能な形式の一覧です（表示順）。
+var supportedExportFormats = []ShellType{
+	ShellBash, ShellZsh, ShellFish, ShellNushell, ShellPowerShell, ShellCmd,
+	ShellDotenv, ShellDockerEnv, ShellDirenv, ShellJSON, ShellGitHubEnv,
+}
+
+// exportFormatAliases は --format で受け付ける別名です。
+var exportFormatAliases = map[string]ShellType{
+	"sh":         ShellBash,
+	"posix":      ShellBash,
+	"pwsh":       ShellPowerShell,
+	"nu":         ShellNushell,
+	"cmd.exe":    ShellCmd,
+	"env":        ShellDotenv,
+	"env-file":   ShellDockerEnv,
+	"envrc":      ShellDirenv,
+	"github":     ShellGitHubEnv,
+	"github_env": ShellGitHubEnv,
+}
+
+// githubEnvDelimiterFunc はテストで差し替え可能な GITHUB_ENV 複数行区切り文字の生成処理です。
+var githubEnvDelimiterFunc = randomGitHubEnvDelimiter
+
+// SupportedExportFormats は --format で指定可能な形式名の一覧を返します。
+func SupportedExportFormats() []string {
+	names := make([]string, 0, len(supportedExportFormats))
+	for _, format := range supportedExportFormats {
+		names = append(names, string(format))
+	}
+
+	return names
+}
+
+// ParseExportFormat は --format の値を ShellType に変換します。
+func ParseExportFormat(name string) (ShellType, error) {
+	normalized := strings.ToLower(strings.TrimSpace(name))
+
+	for _, format := range supportedExportFormats {
+		if normalized == string(format) {
+			return format, nil
+		}
+	}
+
+	if format, ok := exportFormatAliases[normalized]; ok {
+		return format, nil
+	}
+
+	return "", fmt.Errorf("未対応の出力形式です: %q（対応: %s）", name, strings.Join(SupportedExportFormats(), ", "))
+}
+
 // ExportFormat はenv exportの出力を生成します。
 func ExportFormat(envVars map[string]string) (string, error) {
 	shellType := DetectShell()
@@ -50,17 +113,35 @@ func DetectShell() ShellType {
 		if baseName == "bash" {
 			return ShellBash
 		}
+
+		if baseName == "fish" {
+			return ShellFish
+		}
+
+		if baseName == "nu" {
+			return ShellNushell
+		}
 	}
 
 	// デフォルトはbash
 	return ShellBash
 }
 
-// FormatForShell は指定されたシェル用の export 文を生成します。
+// FormatForShell は指定されたシェル（または出力形式）用の export 文を生成します。
+// 出力はキー名の昇順に並びます。
 func FormatForShell(envVars map[string]string, shellType ShellType) (string, error) {
-	lines := make([]string, 0, len(envVars))
+	keys := make([]string, 0, len(envVars))
+	for key := range envVars {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	valid := make([]string, 0, len(keys))
+
+	for _, key := range keys {
+		value := envVars[key]
 
-	for key, value := range envVars {
 		// KEY名の検証
 		if !IsValidExportKey(key) {
 			// 無効なKEY名はスキップ（標準エラーに警告を出す）
@@ -68,31 +149,69 @@ func FormatForShell(envVars map[string]string, shellType ShellType) (string, err
 			continue
 		}
 
-		// 改行を含む値は拒否
-		if strings.Contains(value, "\n") || strings.Contains(value, "\r") {
+		// 改行を含む値は複数行に対応した形式以外では拒否
+		if !supportsMultilineValue(shellType) && (strings.Contains(value, "\n") || strings.Contains(value, "\r")) {
 			fmt.Fprintf(os.Stderr, "⚠️  改行を含む値はサポートされていません: %s\n", key)
 			continue
 		}
 
-		var line string
-
-		switch shellType {
-		case ShellPowerShell:
-			line = formatPowerShellExport(key, value)
-		default: // ShellBash, ShellZsh
-			line = formatPosixExport(key, value)
+		// cmd.exe は二重引用符を含む値を安全にクオートできないため拒否
+		if shellType == ShellCmd && strings.Contains(value, `"`) {
+			fmt.Fprintf(os.Stderr, "⚠️  cmd 形式では二重引用符を含む値はサポートされていません: %s\n", key)
+			continue
 		}
 
-		lines = append(lines, line)
+		valid = append(valid, key)
 	}
 
-	if len(lines) == 0 {
+	if len(valid) == 0 {
 		return "", fmt.Errorf("有効な環境変数がありません")
 	}
 
+	if shellType == ShellJSON {
+		return formatJSONExport(valid, envVars)
+	}
+
+	lines := make([]string, 0, len(valid))
+
+	for _, key := range valid {
+		line, err := formatExportLine(shellType, key, envVars[key])
+		if err != nil {
+			return "", err
+		}
+
+		lines = append(lines, line)
+	}
+
 	return strings.Join(lines, "\n"), nil
 }
 
+func formatExportLine(shellType ShellType, key, value string) (string, error) {
+	switch shellType {
+	case ShellPowerShell:
+		return formatPowerShellExport(key, value), nil
+	case ShellFish:
+		return formatFishExport(key, value), nil
+	case ShellNushell:
+		return formatNushellExport(key, value), nil
+	case ShellCmd:
+		return formatCmdExport(key, value), nil
+	case ShellDotenv:
+		return formatDotenvExport(key, value), nil
+	case ShellDockerEnv:
+		return formatDockerEnvExport(key, value), nil
+	case ShellGitHubEnv:
+		return formatGitHubEnvExport(key, value)
+	default: // ShellBash, ShellZsh, ShellDirenv
+		return formatPosixExport(key, value), nil
+	}
+}
+
+// supportsMultilineValue は改行を含む値を安全に表現できる形式かを返します。
+func supportsMultilineValue(shellType ShellType) bool {
+	return shellType == ShellJSON || shellType == ShellGitHubEnv
+}
+
 // IsValidExportKey は環境変数名がエクスポートに適しているかを検証します。
 // 大文字・アンダースコア・数字のみを許可（POSIX互換 + 慣例的に大文字推奨）
 // 注意: bitwarden.go の isValidEnvVarName は小文字も許可しますが、
@@ -118,6 +237,94 @@ func formatPowerShellExport(key, value string) string {
 	return fmt.Sprintf("$env:%s = '%s'", key, escapedValue)
 }
 
+// formatFishExport は fish 用の set -gx 文を生成します。
+// fish の単一引用符内では \\ と \' のみがエスケープとして解釈されます。
+func formatFishExport(key, value string) string {
+	escapedValue := strings.ReplaceAll(value, `\`, `\\`)
+	escapedValue = strings.ReplaceAll(escapedValue, "'", `\'`)
+
+	return fmt.Sprintf("set -gx %s '%s'", key, escapedValue)
+}
+
+// formatNushellExport は nushell 用の $env 代入文を生成します。
+// 二重引用符でクオートし、バックスラッシュと二重引用符をエスケープします。
+func formatNushellExport(key, value string) string {
+	escapedValue := strings.ReplaceAll(value, `\`, `\\`)
+	escapedValue = strings.ReplaceAll(escapedValue, `"`, `\"`)
+
+	return fmt.Sprintf("$env.%s = \"%s\"", key, escapedValue)
+}
+
+// formatCmdExport は cmd.exe（バッチファイル）用の set 文を生成します。
+// `set "KEY=value"` 形式で & | < > ^ を保護し、% は %% でエスケープします。
+func formatCmdExport(key, value string) string {
+	escapedValue := strings.ReplaceAll(value, "%", "%%")
+	return fmt.Sprintf(`set "%s=%s"`, key, escapedValue)
+}
+
+// formatDotenvExport は .env ファイル用の行を生成します。
+// 単一引用符を含まない値は展開されない単一引用符で、含む値は二重引用符でクオートします。
+func formatDotenvExport(key, value string) string {
+	if !strings.Contains(value, "'") {
+		return fmt.Sprintf("%s='%s'", key, value)
+	}
+
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "$", `\$`)
+
+	return fmt.Sprintf("%s=\"%s\"", key, replacer.Replace(value))
+}
+
+// formatDockerEnvExport は docker --env-file 用の行を生成します。
+// docker はクオートを解釈せず値をそのまま扱うため、エスケープは行いません。
+func formatDockerEnvExport(key, value string) string {
+	return fmt.Sprintf("%s=%s", key, value)
+}
+
+// formatGitHubEnvExport は GitHub Actions の $GITHUB_ENV 用の行を生成します。
+// 改行を含む値は、値に含まれない区切り文字を使ったヒアドキュメント形式で出力します。
+func formatGitHubEnvExport(key, value string) (string, error) {
+	if !strings.ContainsAny(value, "\r\n") {
+		return fmt.Sprintf("%s=%s", key, value), nil
+	}
+
+	delimiter, err := githubEnvDelimiterFunc()
+	if err != nil {
+		return "", fmt.Errorf("GITHUB_ENV 区切り文字の生成に失敗: %w", err)
+	}
+
+	if strings.Contains(value, delimiter) {
+		return "", fmt.Errorf("値に区切り文字が含まれているため出力できません: %s", key)
+	}
+
+	normalized := strings.ReplaceAll(value, "\r\n", "\n")
+
+	return fmt.Sprintf("%s<<%s\n%s\n%s", key, delimiter, normalized, delimiter), nil
+}
+
+func randomGitHubEnvDelimiter() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return "ghadelimiter_" + hex.EncodeToString(buf), nil
+}
+
+// formatJSONExport はキー昇順の JSON オブジェクトを生成します。
+func formatJSONExport(keys []string, envVars map[string]string) (string, error) {
+	selected := make(map[string]string, len(keys))
+	for _, key := range keys {
+		selected[key] = envVars[key]
+	}
+
+	data, err := json.MarshalIndent(selected, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("JSON の生成に失敗しました: %w", err)
+	}
+
+	return string(data), nil
+}
+
 // GetShellName はシェル判定用のデバッグ情報を返します。
 func GetShellName() string {
 	shellType := DetectShell()
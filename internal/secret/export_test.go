@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestIsValidExportKey(t *testing.T) {
@@ -141,6 +142,103 @@ func TestFormatPowerShellExport(t *testing.T) {
 	}
 }
 
+func TestFormatExportLine(t *testing.T) {
+	tests := []struct {
+		name      string
+		shellType ShellType
+		value     string
+		expected  string
+	}{
+		// fish: 単一引用符内では \ と \' のみ解釈される
+		{name: "fish 単純な値", shellType: ShellFish, value: "simple", expected: "set -gx MY_VAR 'simple'"},
+		{name: "fish 単一引用符", shellType: ShellFish, value: "it's", expected: `set -gx MY_VAR 'it\'s'`},
+		{name: "fish バックスラッシュ", shellType: ShellFish, value: `C:\dir\`, expected: `set -gx MY_VAR 'C:\\dir\\'`},
+		{name: "fish 変数展開しない", shellType: ShellFish, value: "$HOME (pwd)", expected: "set -gx MY_VAR '$HOME (pwd)'"},
+		// nushell: 二重引用符内で \ と " をエスケープ
+		{name: "nushell 単純な値", shellType: ShellNushell, value: "simple", expected: `$env.MY_VAR = "simple"`},
+		{name: "nushell 二重引用符", shellType: ShellNushell, value: `say "hi"`, expected: `$env.MY_VAR = "say \"hi\""`},
+		{name: "nushell バックスラッシュ", shellType: ShellNushell, value: `a\nb`, expected: `$env.MY_VAR = "a\\nb"`},
+		{name: "nushell 単一引用符はそのまま", shellType: ShellNushell, value: "it's", expected: `$env.MY_VAR = "it's"`},
+		// cmd.exe: set "KEY=value" で & | < > ^ を保護し、% を二重化
+		{name: "cmd 単純な値", shellType: ShellCmd, value: "simple", expected: `set "MY_VAR=simple"`},
+		{name: "cmd メタ文字", shellType: ShellCmd, value: "a&b|c<d>e^f", expected: `set "MY_VAR=a&b|c<d>e^f"`},
+		{name: "cmd パーセント", shellType: ShellCmd, value: "100%PATH%", expected: `set "MY_VAR=100%%PATH%%"`},
+		// dotenv: 単一引用符、含む場合は二重引用符でエスケープ
+		{name: "dotenv 単純な値", shellType: ShellDotenv, value: "simple", expected: "MY_VAR='simple'"},
+		{name: "dotenv 変数展開しない", shellType: ShellDotenv, value: "$HOME", expected: "MY_VAR='$HOME'"},
+		{name: "dotenv 単一引用符", shellType: ShellDotenv, value: `it's "$X" \`, expected: `MY_VAR="it's \"\$X\" \\"`},
+		// docker --env-file: クオートを解釈しないため値はそのまま
+		{name: "docker 単純な値", shellType: ShellDockerEnv, value: "simple", expected: "MY_VAR=simple"},
+		{name: "docker 引用符と空白", shellType: ShellDockerEnv, value: `a 'b' "c"`, expected: `MY_VAR=a 'b' "c"`},
+		// direnv: .envrc は bash として評価される
+		{name: "direnv 単一引用符", shellType: ShellDirenv, value: "it's", expected: "export MY_VAR='it'\\''s'"},
+		// GitHub Actions: 単一行は KEY=value
+		{name: "github-env 単純な値", shellType: ShellGitHubEnv, value: "a=b c", expected: "MY_VAR=a=b c"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := formatExportLine(tt.shellType, "MY_VAR", tt.value)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestFormatGitHubEnvExportMultiline(t *testing.T) {
+	original := githubEnvDelimiterFunc
+	githubEnvDelimiterFunc = func() (string, error) { return "ghadelimiter_test", nil }
+
+	t.Cleanup(func() {
+		githubEnvDelimiterFunc = original
+	})
+
+	t.Run("複数行はヒアドキュメント形式", func(t *testing.T) {
+		result, err := formatGitHubEnvExport("CERT", "line1\r\nline2")
+		require.NoError(t, err)
+		assert.Equal(t, "CERT<<ghadelimiter_test\nline1\nline2\nghadelimiter_test", result)
+	})
+
+	t.Run("値に区切り文字を含む場合はエラー", func(t *testing.T) {
+		_, err := formatGitHubEnvExport("CERT", "line1\nghadelimiter_test")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "区切り文字")
+	})
+}
+
+func TestParseExportFormat(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected ShellType
+	}{
+		{"fish", ShellFish},
+		{"nu", ShellNushell},
+		{"NUSHELL", ShellNushell},
+		{"pwsh", ShellPowerShell},
+		{"cmd", ShellCmd},
+		{"dotenv", ShellDotenv},
+		{"docker", ShellDockerEnv},
+		{"direnv", ShellDirenv},
+		{"json", ShellJSON},
+		{"github-env", ShellGitHubEnv},
+		{" zsh ", ShellZsh},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result, err := ParseExportFormat(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+
+	t.Run("未対応の形式", func(t *testing.T) {
+		_, err := ParseExportFormat("tcsh")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "未対応の出力形式")
+	})
+}
+
 func TestFormatForShell(t *testing.T) {
 	envVars := map[string]string{
 		"VALID_VAR":   "value1",
@@ -208,6 +306,30 @@ func TestFormatForShell(t *testing.T) {
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "有効な環境変数がありません")
 	})
+
+	t.Run("出力はキー名順", func(t *testing.T) {
+		output, err := FormatForShell(map[string]string{"B_VAR": "b", "A_VAR": "a"}, ShellDockerEnv)
+		assert.NoError(t, err)
+		assert.Equal(t, "A_VAR=a\nB_VAR=b", output)
+	})
+
+	t.Run("JSON形式は改行を含む値も出力", func(t *testing.T) {
+		output, err := FormatForShell(map[string]string{"B_VAR": "line1\nline2", "A_VAR": `"q"`, "bad": "x"}, ShellJSON)
+		assert.NoError(t, err)
+		assert.Equal(t, "{\n  \"A_VAR\": \"\\\"q\\\"\",\n  \"B_VAR\": \"line1\\nline2\"\n}", output)
+	})
+
+	t.Run("fish形式でも改行を含む値はスキップ", func(t *testing.T) {
+		output, err := FormatForShell(map[string]string{"OK_VAR": "ok", "NL_VAR": "a\nb"}, ShellFish)
+		assert.NoError(t, err)
+		assert.Equal(t, "set -gx OK_VAR 'ok'", output)
+	})
+
+	t.Run("cmd形式では二重引用符を含む値をスキップ", func(t *testing.T) {
+		output, err := FormatForShell(map[string]string{"OK_VAR": "ok", "QUOTE_VAR": `a"b`}, ShellCmd)
+		assert.NoError(t, err)
+		assert.Equal(t, `set "OK_VAR=ok"`, output)
+	})
 }
 
 func TestDetectShell(t *testing.T) {
@@ -256,12 +378,26 @@ func TestDetectShell(t *testing.T) {
 			expected:    ShellBash,
 			description: "SHELL が設定されていない場合はデフォルト bash",
 		},
+		{
+			name:        "SHELL環境変数がfishの場合",
+			psModPath:   "",
+			shell:       "/usr/bin/fish",
+			expected:    ShellFish,
+			description: "SHELL=/usr/bin/fish の場合は fish",
+		},
+		{
+			name:        "SHELL環境変数がnushellの場合",
+			psModPath:   "",
+			shell:       "/usr/local/bin/nu",
+			expected:    ShellNushell,
+			description: "SHELL=.../nu の場合は nushell",
+		},
 		{
 			name:        "不明なシェルの場合はデフォルトbash",
 			psModPath:   "",
-			shell:       "/bin/fish",
+			shell:       "/bin/tcsh",
 			expected:    ShellBash,
-			description: "fish など未対応シェルはデフォルト bash",
+			description: "tcsh など未対応シェルはデフォルト bash",
 		},
 		{
 			name:        "カスタムシェルパス",
@@ -0,0 +1,159 @@
+package secret
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"net/url"
+	"sort"
+	"sync"
+)
+
+const (
+	// MaskReplacement はマスク対象の値を置き換える文字列です。
+	MaskReplacement = "***"
+	// minMaskValueLength 未満の短い値は誤検出が多いためマスク対象にしません。
+	minMaskValueLength = 4
+)
+
+// MaskPatterns はシークレット値と、その base64 / URL エンコード表現を重複なく返します。
+// 短すぎる値は除外し、長いパターンから順に並べます（長い一致を優先するため）。
+func MaskPatterns(values []string) []string {
+	seen := make(map[string]struct{})
+	patterns := make([]string, 0, len(values)*5)
+
+	add := func(pattern string) {
+		if len(pattern) < minMaskValueLength {
+			return
+		}
+
+		if _, ok := seen[pattern]; ok {
+			return
+		}
+
+		seen[pattern] = struct{}{}
+		patterns = append(patterns, pattern)
+	}
+
+	for _, value := range values {
+		if len(value) < minMaskValueLength {
+			continue
+		}
+
+		add(value)
+		add(base64.StdEncoding.EncodeToString([]byte(value)))
+		add(base64.RawStdEncoding.EncodeToString([]byte(value)))
+		add(base64.URLEncoding.EncodeToString([]byte(value)))
+		add(base64.RawURLEncoding.EncodeToString([]byte(value)))
+		add(url.QueryEscape(value))
+		add(url.PathEscape(value))
+	}
+
+	sort.SliceStable(patterns, func(i, j int) bool {
+		return len(patterns[i]) > len(patterns[j])
+	})
+
+	return patterns
+}
+
+// MaskingWriter は書き込まれたデータ中のシークレット値を MaskReplacement に置換して
+// 下位の Writer へ転送します。
+// ストリーミング出力に対応するため、シークレットの先頭部分と一致する末尾のみを保留し、
+// それ以外は即座に書き出します。保留分は Flush で書き出されます。
+type MaskingWriter struct {
+	mu       sync.Mutex
+	out      io.Writer
+	patterns [][]byte
+	pending  []byte
+}
+
+// NewMaskingWriter は values（およびそのエンコード表現）をマスクする Writer を返します。
+func NewMaskingWriter(out io.Writer, values []string) *MaskingWriter {
+	patterns := MaskPatterns(values)
+
+	w := &MaskingWriter{
+		out:      out,
+		patterns: make([][]byte, 0, len(patterns)),
+	}
+
+	for _, pattern := range patterns {
+		w.patterns = append(w.patterns, []byte(pattern))
+	}
+
+	return w
+}
+
+// Write はマスク済みのデータを書き出します。保留したバイトも書き込み済みとして len(p) を返します。
+func (w *MaskingWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.pending = append(w.pending, p...)
+
+	if err := w.process(false); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// Flush は保留中のデータをマスクして書き出します。プロセス終了後に呼び出してください。
+func (w *MaskingWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.process(true)
+}
+
+// process は保留バッファを走査してマスク済みデータを書き出します。
+// final が false の場合、末尾がいずれかのパターンの途中までと一致していれば
+// 続きのデータを待つためにその位置以降を保留します。
+func (w *MaskingWriter) process(final bool) error {
+	var masked bytes.Buffer
+
+	i := 0
+
+scan:
+	for i < len(w.pending) {
+		rest := w.pending[i:]
+
+		if !final && w.isPartialMatch(rest) {
+			break
+		}
+
+		for _, pattern := range w.patterns {
+			if bytes.HasPrefix(rest, pattern) {
+				masked.WriteString(MaskReplacement)
+
+				i += len(pattern)
+
+				continue scan
+			}
+		}
+
+		masked.WriteByte(rest[0])
+
+		i++
+	}
+
+	w.pending = append(w.pending[:0], w.pending[i:]...)
+
+	if masked.Len() == 0 {
+		return nil
+	}
+
+	_, err := w.out.Write(masked.Bytes())
+
+	return err
+}
+
+// isPartialMatch は data がいずれかのパターンの真の接頭辞（途中まで一致）かを判定します。
+func (w *MaskingWriter) isPartialMatch(data []byte) bool {
+	for _, pattern := range w.patterns {
+		if len(data) < len(pattern) && bytes.HasPrefix(pattern, data) {
+			return true
+		}
+	}
+
+	return false
+}
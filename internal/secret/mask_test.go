@@ -0,0 +1,155 @@
+package secret
+
+import (
+	"bytes"
+	"encoding/base64"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaskPatterns(t *testing.T) {
+	t.Run("エンコード表現を含めて長い順に返す", func(t *testing.T) {
+		patterns := MaskPatterns([]string{"p@ss word/1"})
+
+		assert.Contains(t, patterns, "p@ss word/1")
+		assert.Contains(t, patterns, base64.StdEncoding.EncodeToString([]byte("p@ss word/1")))
+		assert.Contains(t, patterns, base64.RawURLEncoding.EncodeToString([]byte("p@ss word/1")))
+		assert.Contains(t, patterns, url.QueryEscape("p@ss word/1"))
+		assert.Contains(t, patterns, url.PathEscape("p@ss word/1"))
+
+		for i := 1; i < len(patterns); i++ {
+			assert.GreaterOrEqual(t, len(patterns[i-1]), len(patterns[i]))
+		}
+	})
+
+	t.Run("短い値と重複は除外", func(t *testing.T) {
+		patterns := MaskPatterns([]string{"abc", "", "token1", "token1"})
+
+		assert.NotContains(t, patterns, "abc")
+		assert.NotContains(t, patterns, "")
+
+		count := 0
+
+		for _, pattern := range patterns {
+			if pattern == "token1" {
+				count++
+			}
+		}
+
+		assert.Equal(t, 1, count)
+	})
+}
+
+func TestMaskingWriter(t *testing.T) {
+	secretValue := "s3cr3t-value"
+	encoded := base64.StdEncoding.EncodeToString([]byte(secretValue))
+
+	tests := []struct {
+		name     string
+		chunks   []string
+		expected string
+	}{
+		{
+			name:     "値をそのまま含む",
+			chunks:   []string{"token=" + secretValue + "\n"},
+			expected: "token=***\n",
+		},
+		{
+			name:     "base64 表現",
+			chunks:   []string{"Authorization: Basic " + encoded + "\n"},
+			expected: "Authorization: Basic ***\n",
+		},
+		{
+			name:     "URL エンコード表現",
+			chunks:   []string{"https://x/?t=" + url.QueryEscape("a b&c=d") + "\n"},
+			expected: "https://x/?t=***\n",
+		},
+		{
+			name:     "チャンク境界をまたぐ値",
+			chunks:   []string{"before s3cr", "3t-va", "lue after"},
+			expected: "before *** after",
+		},
+		{
+			name:     "1バイトずつ書き込み",
+			chunks:   splitBytes("x" + secretValue + "y"),
+			expected: "x***y",
+		},
+		{
+			name:     "途中まで一致して終わる出力はそのまま",
+			chunks:   []string{"prefix s3cr3t"},
+			expected: "prefix s3cr3t",
+		},
+		{
+			name:     "複数回の出現",
+			chunks:   []string{secretValue + secretValue + " " + secretValue},
+			expected: "****** ***",
+		},
+		{
+			name:     "無関係な出力",
+			chunks:   []string{"hello\n", "world\n"},
+			expected: "hello\nworld\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+
+			w := NewMaskingWriter(&out, []string{secretValue, "a b&c=d"})
+
+			for _, chunk := range tt.chunks {
+				n, err := w.Write([]byte(chunk))
+				require.NoError(t, err)
+				assert.Equal(t, len(chunk), n)
+			}
+
+			require.NoError(t, w.Flush())
+			assert.Equal(t, tt.expected, out.String())
+		})
+	}
+}
+
+func TestMaskingWriterStreamsWithoutWaitingForFlush(t *testing.T) {
+	var out bytes.Buffer
+
+	w := NewMaskingWriter(&out, []string{"s3cr3t-value"})
+
+	_, err := w.Write([]byte("progress 10%\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "progress 10%\n", out.String(), "一致しない出力は即座に書き出す")
+
+	_, err = w.Write([]byte("key=s3cr"))
+	require.NoError(t, err)
+	assert.Equal(t, "progress 10%\nkey=", out.String(), "途中まで一致する末尾のみ保留する")
+
+	_, err = w.Write([]byte("3t-value\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "progress 10%\nkey=***\n", out.String())
+}
+
+func TestMaskingWriterPrefersLongerMatch(t *testing.T) {
+	var out bytes.Buffer
+
+	w := NewMaskingWriter(&out, []string{"abcd", "abcdefgh"})
+
+	_, err := w.Write([]byte("abcde"))
+	require.NoError(t, err)
+	assert.Empty(t, out.String(), "より長い値の途中まで一致している間は保留する")
+
+	_, err = w.Write([]byte("fgh!"))
+	require.NoError(t, err)
+	require.NoError(t, w.Flush())
+	assert.Equal(t, "***!", out.String())
+}
+
+func splitBytes(s string) []string {
+	chunks := make([]string, 0, len(s))
+	for i := range len(s) {
+		chunks = append(chunks, s[i:i+1])
+	}
+
+	return chunks
+}
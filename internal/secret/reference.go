@@ -0,0 +1,325 @@
+package secret
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// referenceURIPrefix は値全体をシークレット参照として扱う URI 形式のプレフィックスです。
+	referenceURIPrefix = "bw://"
+	providerBitwarden  = "bitwarden"
+	// bwGetItemTimeout は `bw get item` 1 回あたりの待ち時間の上限です。
+	bwGetItemTimeout = 30 * time.Second
+)
+
+// referencePattern は `${secret:<provider>/<item>/<field>}` 形式の参照を検出します。
+var referencePattern = regexp.MustCompile(`\$\{secret:([^}]+)\}`)
+
+// fetchItemFunc はテストで差し替え可能な Bitwarden 項目取得処理の関数変数です。
+var fetchItemFunc = fetchBitwardenItem
+
+// Reference はシークレット参照（プロバイダ・項目名・フィールド名）を表します。
+type Reference struct {
+	Provider string
+	Item     string
+	// Field は取得するフィールド名です。空の場合は env: 項目と同じく
+	// カスタムフィールド "value"（なければ login.password）を参照します。
+	Field string
+}
+
+// String は参照を `${secret:...}` 形式で返します。値は含みません。
+func (r Reference) String() string {
+	if r.Field == "" {
+		return fmt.Sprintf("${secret:%s/%s}", r.Provider, r.Item)
+	}
+
+	return fmt.Sprintf("${secret:%s/%s/%s}", r.Provider, r.Item, r.Field)
+}
+
+// ParseReference は `bitwarden/Item Name/field` 形式の参照本体を解析します。
+// 項目名に "/" を含む場合は、先頭をプロバイダ・末尾をフィールドとして残りを項目名とみなします。
+func ParseReference(body string) (Reference, error) {
+	parts := strings.Split(strings.TrimSpace(body), "/")
+	if len(parts) < 2 {
+		return Reference{}, fmt.Errorf("シークレット参照の形式が不正です: %q（例: bitwarden/Item Name/field）", body)
+	}
+
+	ref := Reference{Provider: strings.ToLower(strings.TrimSpace(parts[0]))}
+
+	switch len(parts) {
+	case 2:
+		ref.Item = parts[1]
+	default:
+		ref.Item = strings.Join(parts[1:len(parts)-1], "/")
+		ref.Field = strings.TrimSpace(parts[len(parts)-1])
+	}
+
+	ref.Item = strings.TrimSpace(ref.Item)
+
+	if ref.Provider == "bw" {
+		ref.Provider = providerBitwarden
+	}
+
+	if ref.Provider != providerBitwarden {
+		return Reference{}, fmt.Errorf("未対応のシークレットプロバイダです: %q（対応: bitwarden）", parts[0])
+	}
+
+	if ref.Item == "" {
+		return Reference{}, fmt.Errorf("シークレット参照に項目名がありません: %q", body)
+	}
+
+	return ref, nil
+}
+
+// parseReferenceURI は `bw://Item Name/field` 形式の URI を解析します。
+// 項目名・フィールド名はパーセントエンコードされていても構いません。
+func parseReferenceURI(value string) (Reference, error) {
+	rest := strings.TrimPrefix(strings.TrimSpace(value), referenceURIPrefix)
+
+	item, field := rest, ""
+	if idx := strings.LastIndex(rest, "/"); idx != -1 {
+		item, field = rest[:idx], rest[idx+1:]
+	}
+
+	ref := Reference{
+		Provider: providerBitwarden,
+		Item:     strings.TrimSpace(unescapeReferencePart(item)),
+		Field:    strings.TrimSpace(unescapeReferencePart(field)),
+	}
+
+	if ref.Item == "" {
+		return Reference{}, fmt.Errorf("シークレット参照に項目名がありません: %q", value)
+	}
+
+	return ref, nil
+}
+
+func unescapeReferencePart(part string) string {
+	unescaped, err := url.PathUnescape(part)
+	if err != nil {
+		return part
+	}
+
+	return unescaped
+}
+
+// ContainsReference は文字列にシークレット参照が含まれるかを判定します。
+func ContainsReference(value string) bool {
+	return isReferenceURI(value) || referencePattern.MatchString(value)
+}
+
+func isReferenceURI(value string) bool {
+	return strings.HasPrefix(strings.TrimSpace(value), referenceURIPrefix)
+}
+
+// Resolver はシークレット参照を遅延解決します。
+// 同一項目への参照は1回の bw 呼び出しで済むよう、取得結果をプロセス内でキャッシュします。
+type Resolver struct {
+	mu       sync.Mutex
+	items    map[string]*BitwardenItem
+	resolved []string
+}
+
+// NewResolver は空のキャッシュを持つ Resolver を返します。
+func NewResolver() *Resolver {
+	return &Resolver{items: make(map[string]*BitwardenItem)}
+}
+
+// defaultResolver はパッケージ関数から利用される共有 Resolver です。
+var defaultResolver = NewResolver()
+
+// ExpandReferences は文字列内のシークレット参照を値に置換します。
+// 参照を含まない文字列はそのまま返し、Bitwarden へのアクセスも行いません。
+func ExpandReferences(ctx context.Context, value string) (string, error) {
+	return defaultResolver.Expand(ctx, value)
+}
+
+// ExpandArgs はコマンド引数それぞれのシークレット参照を値に置換した新しいスライスを返します。
+func ExpandArgs(ctx context.Context, args []string) ([]string, error) {
+	expanded := make([]string, 0, len(args))
+
+	for _, arg := range args {
+		value, err := defaultResolver.Expand(ctx, arg)
+		if err != nil {
+			return nil, err
+		}
+
+		expanded = append(expanded, value)
+	}
+
+	return expanded, nil
+}
+
+// Expand は文字列内のシークレット参照を値に置換します。
+// 値全体が `bw://` で始まる場合は URI 形式として、それ以外は `${secret:...}` を置換します。
+func (r *Resolver) Expand(ctx context.Context, value string) (string, error) {
+	if isReferenceURI(value) {
+		ref, err := parseReferenceURI(value)
+		if err != nil {
+			return "", err
+		}
+
+		return r.Resolve(ctx, ref)
+	}
+
+	if !referencePattern.MatchString(value) {
+		return value, nil
+	}
+
+	var firstErr error
+
+	expanded := referencePattern.ReplaceAllStringFunc(value, func(match string) string {
+		if firstErr != nil {
+			return match
+		}
+
+		body := referencePattern.FindStringSubmatch(match)[1]
+
+		ref, err := ParseReference(body)
+		if err != nil {
+			firstErr = err
+			return match
+		}
+
+		resolved, err := r.Resolve(ctx, ref)
+		if err != nil {
+			firstErr = err
+			return match
+		}
+
+		return resolved
+	})
+
+	if firstErr != nil {
+		return "", firstErr
+	}
+
+	return expanded, nil
+}
+
+// Resolve は単一のシークレット参照を値に解決します。
+func (r *Resolver) Resolve(ctx context.Context, ref Reference) (string, error) {
+	item, err := r.item(ctx, ref.Item)
+	if err != nil {
+		return "", err
+	}
+
+	value := lookupItemField(item, ref.Field)
+	if value == "" {
+		return "", fmt.Errorf("シークレット参照 %s の値が見つかりません", ref)
+	}
+
+	r.mu.Lock()
+	r.resolved = append(r.resolved, value)
+	r.mu.Unlock()
+
+	return value, nil
+}
+
+// ResolvedValues はこれまでに解決したシークレット値の一覧を返します（出力マスク用）。
+func (r *Resolver) ResolvedValues() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]string(nil), r.resolved...)
+}
+
+// ResolvedValues は ExpandReferences / ExpandArgs で解決したシークレット値の一覧を返します。
+func ResolvedValues() []string {
+	return defaultResolver.ResolvedValues()
+}
+
+func (r *Resolver) item(ctx context.Context, name string) (*BitwardenItem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if item, ok := r.items[name]; ok {
+		return item, nil
+	}
+
+	item, err := fetchItemFunc(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	r.items[name] = item
+
+	return item, nil
+}
+
+// lookupItemField は項目から指定フィールドの値を取得します。
+// カスタムフィールドを優先し、見つからない場合は username/password/notes の組み込み項目を参照します。
+func lookupItemField(item *BitwardenItem, field string) string {
+	if field == "" {
+		value := getCustomFieldValue(item.Fields, "value")
+		if value == "" && item.Login != nil {
+			value = strings.TrimSpace(item.Login.Password)
+		}
+
+		return value
+	}
+
+	if value := getCustomFieldValue(item.Fields, field); value != "" {
+		return value
+	}
+
+	switch strings.ToLower(field) {
+	case "password":
+		if item.Login != nil {
+			return strings.TrimSpace(item.Login.Password)
+		}
+	case "username":
+		if item.Login != nil {
+			return strings.TrimSpace(item.Login.Username)
+		}
+	case "notes":
+		return item.Notes
+	}
+
+	return ""
+}
+
+// fetchBitwardenItem は `bw get item` で単一項目を取得します。
+// bw が応答しない場合（サーバーとの同期待ちなど）に処理全体が止まらないよう、bwGetItemTimeout で打ち切ります。
+func fetchBitwardenItem(ctx context.Context, name string) (*BitwardenItem, error) {
+	defer debugTimerStart(fmt.Sprintf("bw get item %q", name))()
+
+	if _, err := exec.LookPath("bw"); err != nil {
+		return nil, fmt.Errorf("bw コマンドが見つかりません")
+	}
+
+	if os.Getenv("BW_SESSION") == "" {
+		return nil, fmt.Errorf("BW_SESSION が設定されていません。bitwarden をアンロックしてください")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, bwGetItemTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "bw", "get", "item", name)
+
+	output, err := cmd.Output()
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("bw get item %q がタイムアウトしました: %w", name, ctx.Err())
+		}
+
+		return nil, fmt.Errorf("bw get item %q が失敗しました: %w", name, err)
+	}
+
+	var item BitwardenItem
+	if err := json.Unmarshal(output, &item); err != nil {
+		return nil, fmt.Errorf("JSON のパースに失敗しました: %w", err)
+	}
+
+	return &item, nil
+}
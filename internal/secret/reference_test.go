@@ -0,0 +1,221 @@
+package secret
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubFetchItem は fetchItemFunc を差し替え、呼び出し回数を返すテストヘルパーです。
+func stubFetchItem(t *testing.T, items map[string]*BitwardenItem) *int {
+	t.Helper()
+
+	original := fetchItemFunc
+	calls := 0
+
+	fetchItemFunc = func(_ context.Context, name string) (*BitwardenItem, error) {
+		calls++
+
+		item, ok := items[name]
+		if !ok {
+			return nil, errors.New("not found: " + name)
+		}
+
+		return item, nil
+	}
+
+	t.Cleanup(func() {
+		fetchItemFunc = original
+	})
+
+	return &calls
+}
+
+func TestParseReference(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    Reference
+		wantErr string
+	}{
+		{
+			name: "項目名とフィールド",
+			body: "bitwarden/GitHub Token/token",
+			want: Reference{Provider: "bitwarden", Item: "GitHub Token", Field: "token"},
+		},
+		{
+			name: "フィールド省略",
+			body: "bitwarden/GitHub Token",
+			want: Reference{Provider: "bitwarden", Item: "GitHub Token"},
+		},
+		{
+			name: "項目名にスラッシュを含む",
+			body: "bitwarden/team/registry/password",
+			want: Reference{Provider: "bitwarden", Item: "team/registry", Field: "password"},
+		},
+		{
+			name: "bw は bitwarden の別名",
+			body: "bw/Item/field",
+			want: Reference{Provider: "bitwarden", Item: "Item", Field: "field"},
+		},
+		{
+			name:    "プロバイダのみ",
+			body:    "bitwarden",
+			wantErr: "形式が不正",
+		},
+		{
+			name:    "未対応プロバイダ",
+			body:    "vault/Item/field",
+			wantErr: "未対応のシークレットプロバイダ",
+		},
+		{
+			name:    "項目名が空",
+			body:    "bitwarden//field",
+			wantErr: "項目名がありません",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseReference(tt.body)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestContainsReference(t *testing.T) {
+	assert.True(t, ContainsReference("${secret:bitwarden/Item/field}"))
+	assert.True(t, ContainsReference("Bearer ${secret:bitwarden/Item}"))
+	assert.True(t, ContainsReference("bw://Item/field"))
+	assert.False(t, ContainsReference("plain value"))
+	assert.False(t, ContainsReference("${HOME}/bin"))
+}
+
+func TestResolverExpand(t *testing.T) {
+	items := map[string]*BitwardenItem{
+		"GitHub": {
+			Name:   "GitHub",
+			Fields: []BitwardenCustomField{{Name: "token", Value: "ghp_secret"}},
+			Login:  &BitwardenLogin{Username: "octocat", Password: "pw"},
+		},
+		"Registry": {
+			Name:   "Registry",
+			Fields: []BitwardenCustomField{{Name: "value", Value: "reg-token"}},
+			Notes:  "note text",
+		},
+		"Login Only": {
+			Name:  "Login Only",
+			Login: &BitwardenLogin{Password: "login-pw"},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr string
+	}{
+		{name: "参照なしはそのまま", input: "plain", want: "plain"},
+		{name: "カスタムフィールド", input: "${secret:bitwarden/GitHub/token}", want: "ghp_secret"},
+		{name: "文字列中の参照", input: "Bearer ${secret:bitwarden/GitHub/token}!", want: "Bearer ghp_secret!"},
+		{name: "複数参照", input: "${secret:bitwarden/GitHub/username}:${secret:bitwarden/GitHub/password}", want: "octocat:pw"},
+		{name: "フィールド省略はvalue", input: "${secret:bitwarden/Registry}", want: "reg-token"},
+		{name: "フィールド省略でvalueがなければpassword", input: "${secret:bitwarden/Login Only}", want: "login-pw"},
+		{name: "notes", input: "${secret:bitwarden/Registry/notes}", want: "note text"},
+		{name: "URI形式", input: "bw://GitHub/token", want: "ghp_secret"},
+		{name: "URI形式のパーセントエンコード", input: "bw://Login%20Only", want: "login-pw"},
+		{name: "存在しないフィールド", input: "${secret:bitwarden/GitHub/missing}", wantErr: "値が見つかりません"},
+		{name: "存在しない項目", input: "${secret:bitwarden/Nope/token}", wantErr: "not found"},
+		{name: "不正な参照", input: "${secret:vault/GitHub/token}", wantErr: "未対応"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stubFetchItem(t, items)
+
+			got, err := NewResolver().Expand(context.Background(), tt.input)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				assert.NotContains(t, err.Error(), "ghp_secret")
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestResolverCachesItems(t *testing.T) {
+	calls := stubFetchItem(t, map[string]*BitwardenItem{
+		"GitHub": {Fields: []BitwardenCustomField{{Name: "token", Value: "t"}, {Name: "user", Value: "u"}}},
+	})
+
+	resolver := NewResolver()
+
+	_, err := resolver.Expand(context.Background(), "${secret:bitwarden/GitHub/token}")
+	require.NoError(t, err)
+
+	_, err = resolver.Expand(context.Background(), "${secret:bitwarden/GitHub/user}")
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, *calls)
+}
+
+func TestExpandArgs(t *testing.T) {
+	calls := stubFetchItem(t, map[string]*BitwardenItem{
+		"Args Item": {Fields: []BitwardenCustomField{{Name: "token", Value: "args-token"}}},
+	})
+
+	t.Run("参照がなければ取得しない", func(t *testing.T) {
+		got, err := ExpandArgs(context.Background(), []string{"echo", "hello"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"echo", "hello"}, got)
+		assert.Equal(t, 0, *calls)
+	})
+
+	t.Run("参照を含む引数を置換する", func(t *testing.T) {
+		got, err := ExpandArgs(context.Background(), []string{"curl", "-H", "Authorization: ${secret:bitwarden/Args Item/token}"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"curl", "-H", "Authorization: args-token"}, got)
+	})
+}
+
+func TestFetchBitwardenItem_StopsWithContext(t *testing.T) {
+	if runtime.GOOS == goosWindows {
+		t.Skip("fake bw は POSIX シェル前提")
+	}
+
+	// 応答しない bw を再現する
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bw"), []byte("#!/bin/sh\nexec sleep 10\n"), 0o755))
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("BW_SESSION", "test-session")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+
+	_, err := fetchBitwardenItem(ctx, "GitHub")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Contains(t, err.Error(), "タイムアウト")
+	assert.Less(t, time.Since(start), 5*time.Second, "呼び出し元のコンテキストで打ち切る")
+}
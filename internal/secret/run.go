@@ -2,14 +2,34 @@ package secret
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"strings"
 )
 
+// RunOptions は RunWithEnvOptions の実行オプションです。
+type RunOptions struct {
+	// MaskOutput が true の場合、子プロセスの stdout/stderr に含まれる
+	// 注入した環境変数の値（および MaskValues）を *** に置換して出力します。
+	MaskOutput bool
+	// MaskValues は環境変数以外に追加でマスクする値です（引数で解決したシークレット参照など）。
+	MaskValues []string
+	// Stdout / Stderr は出力先です。nil の場合は os.Stdout / os.Stderr を使用します。
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
 // RunWithEnv は環境変数を注入してコマンドを実行します。
 func RunWithEnv(args []string, envVars map[string]string) error {
+	return RunWithEnvOptions(args, envVars, RunOptions{})
+}
+
+// RunWithEnvOptions は環境変数を注入し、オプションに従ってコマンドを実行します。
+// 子プロセスが異常終了した場合は *exec.ExitError をそのまま返すため、呼び出し側で終了コードを引き継げます。
+func RunWithEnvOptions(args []string, envVars map[string]string, opts RunOptions) error {
 	if len(args) == 0 {
 		return fmt.Errorf("コマンドが指定されていません")
 	}
@@ -30,13 +50,44 @@ func RunWithEnv(args []string, envVars map[string]string) error {
 	cmd := exec.CommandContext(context.Background(), cmdPath, cmdArgs...)
 	cmd.Env = currentEnv
 	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	cmd.Stdout = opts.Stdout
+	cmd.Stderr = opts.Stderr
 
-	// コマンドを実行
-	err = cmd.Run()
-	if err != nil {
-		return err
+	if cmd.Stdout == nil {
+		cmd.Stdout = os.Stdout
+	}
+
+	if cmd.Stderr == nil {
+		cmd.Stderr = os.Stderr
+	}
+
+	if !opts.MaskOutput {
+		// コマンドを実行
+		return cmd.Run()
+	}
+
+	maskValues := make([]string, 0, len(envVars)+len(opts.MaskValues))
+	for _, value := range envVars {
+		maskValues = append(maskValues, value)
+	}
+
+	maskValues = append(maskValues, opts.MaskValues...)
+
+	stdout := NewMaskingWriter(cmd.Stdout, maskValues)
+	stderr := NewMaskingWriter(cmd.Stderr, maskValues)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	// コマンドを実行し、終了コードに関わらず保留中の出力を書き出す
+	runErr := cmd.Run()
+
+	flushErr := errors.Join(stdout.Flush(), stderr.Flush())
+	if runErr != nil {
+		return runErr
+	}
+
+	if flushErr != nil {
+		return fmt.Errorf("出力の書き込みに失敗しました: %w", flushErr)
 	}
 
 	return nil
@@ -1,7 +1,11 @@
 package secret
 
 import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
 	"os"
+	"os/exec"
 	"strings"
 	"testing"
 
@@ -66,6 +70,63 @@ func TestRunWithEnv(t *testing.T) {
 	})
 }
 
+func TestRunWithEnvOptionsMaskOutput(t *testing.T) {
+	exe, err := os.Executable()
+	require.NoError(t, err)
+
+	args := []string{
+		exe,
+		"-test.run=TestRunWithEnvMaskHelperProcess",
+	}
+
+	envVars := map[string]string{
+		"DEVSYNC_TEST_MASK_HELPER_PROCESS": "1",
+		"DEVSYNC_TEST_SECRET":              "injected-token",
+	}
+
+	t.Run("stdout/stderr の値をマスクし終了コードを維持する", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+
+		err := RunWithEnvOptions(args, envVars, RunOptions{
+			MaskOutput: true,
+			MaskValues: []string{"resolved-ref-value"},
+			Stdout:     &stdout,
+			Stderr:     &stderr,
+		})
+
+		var exitErr *exec.ExitError
+		require.ErrorAs(t, err, &exitErr)
+		assert.Equal(t, 3, exitErr.ExitCode())
+
+		assert.Contains(t, stdout.String(), "token=***")
+		assert.Contains(t, stdout.String(), "ref=***")
+		assert.Contains(t, stderr.String(), "b64=***")
+		assert.NotContains(t, stdout.String()+stderr.String(), "injected-token")
+		assert.NotContains(t, stdout.String()+stderr.String(), "resolved-ref-value")
+	})
+
+	t.Run("マスク無効時はそのまま出力する", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+
+		err := RunWithEnvOptions(args, envVars, RunOptions{Stdout: &stdout, Stderr: &stderr})
+		require.Error(t, err)
+		assert.Contains(t, stdout.String(), "token=injected-token")
+	})
+}
+
+func TestRunWithEnvMaskHelperProcess(t *testing.T) {
+	if os.Getenv("DEVSYNC_TEST_MASK_HELPER_PROCESS") != "1" {
+		return
+	}
+
+	value := os.Getenv("DEVSYNC_TEST_SECRET")
+
+	fmt.Fprintf(os.Stdout, "token=%s\n", value)
+	fmt.Fprintln(os.Stdout, "ref=resolved-ref-value")
+	fmt.Fprintf(os.Stderr, "b64=%s\n", base64.StdEncoding.EncodeToString([]byte(value)))
+	os.Exit(3)
+}
+
 func TestRunWithEnvHelperProcess(t *testing.T) {
 	if os.Getenv("DEVSYNC_TEST_HELPER_PROCESS") != "1" {
 		return
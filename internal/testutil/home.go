@@ -14,6 +14,8 @@ func SetTestHome(t *testing.T, home string) {
 
 	t.Setenv("HOME", home)
 	t.Setenv("USERPROFILE", home)
+	// 状態ディレクトリ（ログ・ロック）もテスト用の HOME 配下を使うようにする
+	t.Setenv("XDG_STATE_HOME", "")
 
 	// Go の実装は USERPROFILE を優先しますが、念のため従来の変数も合わせて揃えます。
 	if runtime.GOOS != "windows" {
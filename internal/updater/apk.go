@@ -0,0 +1,130 @@
+package updater
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/scottlz0310/devsync/internal/config"
+)
+
+// ApkUpdater は apk パッケージマネージャ (Alpine Linux) の実装です。
+type ApkUpdater struct {
+	useSudo bool
+}
+
+// 起動時にレジストリに登録
+func init() {
+	Register(&ApkUpdater{useSudo: true})
+}
+
+func (a *ApkUpdater) Name() string {
+	return "apk"
+}
+
+func (a *ApkUpdater) DisplayName() string {
+	return "apk (Alpine Linux)"
+}
+
+func (a *ApkUpdater) IsAvailable() bool {
+	_, err := exec.LookPath("apk")
+	return err == nil
+}
+
+func (a *ApkUpdater) Configure(cfg config.ManagerConfig) error {
+	if cfg == nil {
+		return nil
+	}
+
+	a.useSudo = configureUseSudo(cfg, a.useSudo)
+
+	return nil
+}
+
+// Check はキャッシュ済みのインデックスから更新可能なパッケージを確認します。
+// apk update は実行しないため、システムの状態を変更せず sudo も不要です。
+func (a *ApkUpdater) Check(ctx context.Context) (*CheckResult, error) {
+	output, err := runCommandOutputWithLocaleC(ctx, "apk", []string{"version", "-l", "<"}, "apk version の実行に失敗: %w")
+	if err != nil {
+		return nil, err
+	}
+
+	packages := a.parseVersionList(string(output))
+
+	return &CheckResult{
+		AvailableUpdates: len(packages),
+		Packages:         packages,
+	}, nil
+}
+
+func (a *ApkUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateResult, error) {
+	// DryRun 以外はインデックスを最新化してから更新対象を確認する
+	if !opts.DryRun {
+		if err := runSystemCommand(ctx, a.useSudo, "apk", "update"); err != nil {
+			return nil, fmt.Errorf("apk update に失敗: %w", err)
+		}
+	}
+
+	checkResult, err := a.Check(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	command, args := systemCommandLine(a.useSudo, "apk", "upgrade")
+
+	return runCountBasedUpdate(
+		ctx,
+		opts,
+		checkResult,
+		"すべてのパッケージは最新です",
+		func(count int) string {
+			return fmt.Sprintf("%d 件のパッケージが更新可能です（DryRunモード）", count)
+		},
+		command,
+		args,
+		"apk upgrade に失敗: %w",
+		func(count int) string {
+			return fmt.Sprintf("%d 件のパッケージを更新しました", count)
+		},
+	)
+}
+
+// parseVersionList は "apk version -l <" の出力をパースします
+// 形式: "name-version-rN  < new-version"（先頭行は "Installed: Available:" のヘッダー）
+func (a *ApkUpdater) parseVersionList(output string) []PackageInfo {
+	lines := strings.Split(output, "\n")
+	packages := make([]PackageInfo, 0, len(lines))
+
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) < 3 || fields[1] != "<" {
+			continue
+		}
+
+		name, current := splitApkPackageVersion(fields[0])
+		packages = append(packages, PackageInfo{
+			Name:           name,
+			CurrentVersion: current,
+			NewVersion:     fields[2],
+		})
+	}
+
+	return packages
+}
+
+// splitApkPackageVersion は "name-version-rN" をパッケージ名とバージョンに分割します。
+// パッケージ名にもハイフンを含むため、末尾の "-version-rN" の 2 区切りをバージョンとみなします。
+func splitApkPackageVersion(value string) (string, string) {
+	releaseIndex := strings.LastIndex(value, "-")
+	if releaseIndex <= 0 {
+		return value, ""
+	}
+
+	versionIndex := strings.LastIndex(value[:releaseIndex], "-")
+	if versionIndex <= 0 {
+		return value, ""
+	}
+
+	return value[:versionIndex], value[versionIndex+1:]
+}
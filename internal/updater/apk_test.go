@@ -0,0 +1,103 @@
+package updater
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApkUpdater_parseVersionList(t *testing.T) {
+	output := `Installed:                                Available:
+busybox-1.36.1-r28                      < 1.36.1-r29
+py3-setuptools-70.0.0-r0                < 70.1.0-r0
+`
+
+	a := &ApkUpdater{}
+	assert.Equal(t, []PackageInfo{
+		{Name: "busybox", CurrentVersion: "1.36.1-r28", NewVersion: "1.36.1-r29"},
+		{Name: "py3-setuptools", CurrentVersion: "70.0.0-r0", NewVersion: "70.1.0-r0"},
+	}, a.parseVersionList(output))
+}
+
+func TestApkUpdater_Update(t *testing.T) {
+	testCases := []struct {
+		name        string
+		mode        string
+		opts        UpdateOptions
+		wantUpdated int
+		errContains string
+		msgContains string
+	}{
+		{name: "DryRunではapk updateを実行しない", mode: "update_error", opts: UpdateOptions{DryRun: true}, msgContains: "DryRunモード"},
+		{name: "更新なし", mode: "none", msgContains: "すべてのパッケージは最新です"},
+		{name: "更新成功", mode: "updates", wantUpdated: 2, msgContains: "2 件のパッケージを更新しました"},
+		{name: "apk update失敗", mode: "update_error", errContains: "apk update に失敗"},
+		{name: "apk upgrade失敗", mode: "upgrade_error", errContains: "apk upgrade に失敗"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			writeFakeApkCommand(t)
+			t.Setenv("DEVSYNC_TEST_APK_MODE", tc.mode)
+
+			got, err := (&ApkUpdater{}).Update(context.Background(), tc.opts)
+			if tc.errContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.errContains)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantUpdated, got.UpdatedCount)
+			assert.Contains(t, got.Message, tc.msgContains)
+		})
+	}
+}
+
+func writeFakeApkCommand(t *testing.T) {
+	t.Helper()
+
+	if runtime.GOOS == windowsOS {
+		t.Skip("fake apk は POSIX シェル前提")
+	}
+
+	script := `#!/bin/sh
+mode="${DEVSYNC_TEST_APK_MODE}"
+case "$1" in
+  update)
+    if [ "${mode}" = "update_error" ]; then
+      echo "ERROR: unable to select packages" 1>&2
+      exit 1
+    fi
+    exit 0
+    ;;
+  version)
+    echo "Installed:                                Available:"
+    if [ "${mode}" != "none" ]; then
+      echo "busybox-1.36.1-r28                      < 1.36.1-r29"
+      echo "curl-8.7.1-r0                           < 8.8.0-r0"
+    fi
+    exit 0
+    ;;
+  upgrade)
+    if [ "${mode}" = "upgrade_error" ]; then
+      echo "apk upgrade failed" 1>&2
+      exit 1
+    fi
+    exit 0
+    ;;
+esac
+echo "invalid args" 1>&2
+exit 1
+`
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "apk"), []byte(script), 0o755))
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
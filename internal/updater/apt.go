@@ -11,10 +11,14 @@ import (
 )
 
 // AptUpdater は APT パッケージマネージャ (Debian/Ubuntu) の実装です。
+// security_only を有効にすると、-security ポケットから配布される更新のみを適用します。
 type AptUpdater struct {
-	useSudo bool
+	useSudo      bool
+	securityOnly bool
 }
 
+var _ SecurityOnlyUpdater = (*AptUpdater)(nil)
+
 // 起動時にレジストリに登録
 func init() {
 	Register(&AptUpdater{useSudo: true})
@@ -38,6 +42,10 @@ func (a *AptUpdater) Configure(cfg config.ManagerConfig) error {
 		return nil
 	}
 
+	if securityOnly, ok := cfg["security_only"].(bool); ok {
+		a.securityOnly = securityOnly
+	}
+
 	if useSudo, ok := cfg["use_sudo"].(bool); ok {
 		a.useSudo = useSudo
 		return nil
@@ -51,13 +59,17 @@ func (a *AptUpdater) Configure(cfg config.ManagerConfig) error {
 	return nil
 }
 
+func (a *AptUpdater) SupportsSecurityOnly() bool {
+	return true
+}
+
+// Check はキャッシュ済みのパッケージリストから更新可能なパッケージを確認します。
+// apt update は実行しないため、システムの状態を変更せず sudo も不要です。
 func (a *AptUpdater) Check(ctx context.Context) (*CheckResult, error) {
-	// パッケージリストを更新
-	if err := a.runCommand(ctx, "update"); err != nil {
-		return nil, fmt.Errorf("apt update に失敗: %w", err)
-	}
+	return a.check(ctx, a.securityOnly)
+}
 
-	// 更新可能なパッケージを取得
+func (a *AptUpdater) check(ctx context.Context, securityOnly bool) (*CheckResult, error) {
 	cmd := exec.CommandContext(ctx, "apt", "list", "--upgradable")
 
 	output, err := cmd.Output()
@@ -66,6 +78,11 @@ func (a *AptUpdater) Check(ctx context.Context) (*CheckResult, error) {
 	}
 
 	packages := a.parseUpgradableList(string(output))
+	if securityOnly {
+		packages = securityPackages(packages)
+	}
+
+	fillAptSourcePackages(ctx, packages)
 
 	return &CheckResult{
 		AvailableUpdates: len(packages),
@@ -75,20 +92,28 @@ func (a *AptUpdater) Check(ctx context.Context) (*CheckResult, error) {
 
 func (a *AptUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateResult, error) {
 	result := &UpdateResult{}
+	securityOnly := opts.SecurityOnly || a.securityOnly
+	upToDate, dryRunMessage, updatedMessage := securityUpdateMessages(securityOnly)
 
-	// まず更新確認
-	checkResult, err := a.Check(ctx)
+	// DryRun 以外はパッケージリストを最新化してから更新対象を確認する
+	if !opts.DryRun {
+		if err := a.runCommand(ctx, "update"); err != nil {
+			return nil, fmt.Errorf("apt update に失敗: %w", err)
+		}
+	}
+
+	checkResult, err := a.check(ctx, securityOnly)
 	if err != nil {
 		return nil, err
 	}
 
 	if checkResult.AvailableUpdates == 0 {
-		result.Message = "すべてのパッケージは最新です"
+		result.Message = upToDate
 		return result, nil
 	}
 
 	if opts.DryRun {
-		result.Message = fmt.Sprintf("%d 件のパッケージが更新可能です（DryRunモード）", checkResult.AvailableUpdates)
+		result.Message = dryRunMessage(checkResult.AvailableUpdates)
 		result.Packages = checkResult.Packages
 
 		return result, nil
@@ -96,14 +121,26 @@ func (a *AptUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateRes
 
 	// 実際の更新を実行
 	args := []string{"upgrade", "-y"}
+	subcommand := "upgrade"
+
+	// セキュリティ更新のみの場合は対象パッケージだけを既存パッケージの更新として入れ直す
+	if securityOnly {
+		args = []string{"install", "--only-upgrade", "-y"}
+		subcommand = "install --only-upgrade"
+
+		for _, pkg := range checkResult.Packages {
+			args = append(args, pkg.Name)
+		}
+	}
+
 	if err := a.runCommand(ctx, args...); err != nil {
 		result.Errors = append(result.Errors, err)
-		return result, fmt.Errorf("apt upgrade に失敗: %w", err)
+		return result, fmt.Errorf("apt %s に失敗: %w", subcommand, err)
 	}
 
 	result.UpdatedCount = checkResult.AvailableUpdates
 	result.Packages = checkResult.Packages
-	result.Message = fmt.Sprintf("%d 件のパッケージを更新しました", result.UpdatedCount)
+	result.Message = updatedMessage(result.UpdatedCount)
 
 	return result, nil
 }
@@ -127,6 +164,50 @@ func (a *AptUpdater) runCommand(ctx context.Context, args ...string) error {
 }
 
 // parseUpgradableList は "apt list --upgradable" の出力をパースします
+// fillAptSourcePackages は dpkg-query でバイナリパッケージのソースパッケージ名を調べ、PackageInfo.Source に設定します。
+// Debian / Ubuntu の OSV データはソースパッケージ名（例: libssl3 -> openssl）で記録されているため、脆弱性の照合に使用します。
+// dpkg-query を実行できない場合は設定しません。
+func fillAptSourcePackages(ctx context.Context, packages []PackageInfo) {
+	if len(packages) == 0 {
+		return
+	}
+
+	args := []string{"-W", "--showformat=${Package}\t${source:Package}\n"}
+	for _, pkg := range packages {
+		args = append(args, pkg.Name)
+	}
+
+	// 一部のパッケージが見つからない場合も終了コード 1 になるが、見つかった分は出力される
+	output, err := exec.CommandContext(ctx, "dpkg-query", args...).Output()
+	if err != nil && len(output) == 0 {
+		return
+	}
+
+	sources := parseDpkgSourcePackages(string(output))
+
+	for i := range packages {
+		if source, ok := sources[packages[i].Name]; ok && source != packages[i].Name {
+			packages[i].Source = source
+		}
+	}
+}
+
+// parseDpkgSourcePackages は "パッケージ名<TAB>ソースパッケージ名" 形式の出力をパースします。
+func parseDpkgSourcePackages(output string) map[string]string {
+	sources := make(map[string]string)
+
+	for _, line := range strings.Split(output, "\n") {
+		name, source, ok := strings.Cut(strings.TrimSpace(line), "\t")
+		if !ok || name == "" || source == "" {
+			continue
+		}
+
+		sources[name] = source
+	}
+
+	return sources
+}
+
 func (a *AptUpdater) parseUpgradableList(output string) []PackageInfo {
 	lines := strings.Split(output, "\n")
 	packages := make([]PackageInfo, 0, len(lines))
@@ -153,6 +234,11 @@ func (a *AptUpdater) parseUpgradableList(output string) []PackageInfo {
 			NewVersion: parts[1],
 		}
 
+		// 配布元のスイート（例: jammy-updates,jammy-security）に -security を含むものはセキュリティ更新
+		if len(nameParts) > 1 && strings.Contains(nameParts[1], "-security") {
+			pkg.Security = true
+		}
+
 		// 旧バージョンを取得
 		if idx := strings.Index(line, "upgradable from:"); idx != -1 {
 			oldVersion := strings.TrimSuffix(line[idx+len("upgradable from:"):], "]")
@@ -9,6 +9,7 @@ import (
 
 	"github.com/scottlz0310/devsync/internal/config"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAptUpdater_Name(t *testing.T) {
@@ -114,7 +115,7 @@ curl/jammy-security 7.81.0-1ubuntu1.14 amd64 [upgradable from: 7.81.0-1ubuntu1.1
 git/jammy-updates 1:2.34.1-1ubuntu1.10 amd64 [upgradable from: 1:2.34.1-1ubuntu1.9]`,
 			expected: []PackageInfo{
 				{Name: "vim", NewVersion: "2:8.2.3995-1ubuntu2.11", CurrentVersion: "2:8.2.3995-1ubuntu2.10"},
-				{Name: "curl", NewVersion: "7.81.0-1ubuntu1.14", CurrentVersion: "7.81.0-1ubuntu1.13"},
+				{Name: "curl", NewVersion: "7.81.0-1ubuntu1.14", CurrentVersion: "7.81.0-1ubuntu1.13", Security: true},
 				{Name: "git", NewVersion: "1:2.34.1-1ubuntu1.10", CurrentVersion: "1:2.34.1-1ubuntu1.9"},
 			},
 		},
@@ -128,7 +129,17 @@ curl/jammy-security 7.81.0-1ubuntu1.14 amd64 [upgradable from: 7.81.0-1ubuntu1.1
 `,
 			expected: []PackageInfo{
 				{Name: "vim", NewVersion: "2:8.2.3995-1ubuntu2.11", CurrentVersion: "2:8.2.3995-1ubuntu2.10"},
-				{Name: "curl", NewVersion: "7.81.0-1ubuntu1.14", CurrentVersion: "7.81.0-1ubuntu1.13"},
+				{Name: "curl", NewVersion: "7.81.0-1ubuntu1.14", CurrentVersion: "7.81.0-1ubuntu1.13", Security: true},
+			},
+		},
+		{
+			name: "セキュリティポケットの更新",
+			output: `Listing... Done
+openssl/jammy-updates,jammy-security 3.0.2-0ubuntu1.18 amd64 [upgradable from: 3.0.2-0ubuntu1.17]
+libssl3/bookworm-security 3.0.15-1~deb12u1 amd64 [upgradable from: 3.0.14-1~deb12u2]`,
+			expected: []PackageInfo{
+				{Name: "openssl", NewVersion: "3.0.2-0ubuntu1.18", CurrentVersion: "3.0.2-0ubuntu1.17", Security: true},
+				{Name: "libssl3", NewVersion: "3.0.15-1~deb12u1", CurrentVersion: "3.0.14-1~deb12u2", Security: true},
 			},
 		},
 		{
@@ -158,6 +169,7 @@ invalid-line-without-space`,
 				assert.Equal(t, expected.Name, result[i].Name, "Package name mismatch at index %d", i)
 				assert.Equal(t, expected.NewVersion, result[i].NewVersion, "New version mismatch at index %d", i)
 				assert.Equal(t, expected.CurrentVersion, result[i].CurrentVersion, "Current version mismatch at index %d", i)
+				assert.Equal(t, expected.Security, result[i].Security, "Security mismatch at index %d", i)
 			}
 		})
 	}
@@ -184,11 +196,10 @@ func TestAptUpdater_Check(t *testing.T) {
 			wantErr:     false,
 		},
 		{
-			name:        "apt update 失敗",
+			name:        "Checkではapt updateを実行しない",
 			mode:        "update_error",
-			wantUpdates: 0,
-			wantErr:     true,
-			errContains: "apt update に失敗",
+			wantUpdates: 2,
+			wantErr:     false,
 		},
 		{
 			name:        "apt list 失敗",
@@ -261,6 +272,22 @@ func TestAptUpdater_Update(t *testing.T) {
 			wantErr:     false,
 			msgContains: "2 件のパッケージを更新しました",
 		},
+		{
+			name:        "DryRunではapt updateを実行しない",
+			mode:        "update_error",
+			opts:        UpdateOptions{DryRun: true},
+			wantUpdated: 0,
+			wantErr:     false,
+			msgContains: "DryRunモード",
+		},
+		{
+			name:        "apt update 失敗",
+			mode:        "update_error",
+			opts:        UpdateOptions{},
+			wantUpdated: 0,
+			wantErr:     true,
+			errContains: "apt update に失敗",
+		},
 		{
 			name:        "更新失敗",
 			mode:        "upgrade_error",
@@ -304,6 +331,80 @@ func TestAptUpdater_Update(t *testing.T) {
 	}
 }
 
+func TestAptUpdater_SecurityOnly(t *testing.T) {
+	if runtime.GOOS == windowsOS {
+		t.Skip("実行ログの確認は POSIX シェル前提")
+	}
+
+	testCases := []struct {
+		name        string
+		cfg         config.ManagerConfig
+		opts        UpdateOptions
+		wantLog     string
+		msgContains string
+	}{
+		{
+			name:        "オプションでセキュリティ更新のみ",
+			opts:        UpdateOptions{SecurityOnly: true},
+			wantLog:     "install --only-upgrade -y curl",
+			msgContains: "1 件のセキュリティ更新を適用しました",
+		},
+		{
+			name:        "設定でセキュリティ更新のみ",
+			cfg:         config.ManagerConfig{"security_only": true},
+			wantLog:     "install --only-upgrade -y curl",
+			msgContains: "1 件のセキュリティ更新を適用しました",
+		},
+		{
+			name:        "既定はすべて更新",
+			wantLog:     "upgrade -y",
+			msgContains: "2 件のパッケージを更新しました",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeDir := t.TempDir()
+			writeFakeAptCommand(t, fakeDir)
+
+			logPath := filepath.Join(t.TempDir(), "apt.log")
+
+			t.Setenv("PATH", fakeDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+			t.Setenv("DEVSYNC_TEST_APT_MODE", "updates")
+			t.Setenv("DEVSYNC_TEST_APT_LOG", logPath)
+
+			a := &AptUpdater{useSudo: false}
+			assert.NoError(t, a.Configure(tc.cfg))
+
+			got, err := a.Update(context.Background(), tc.opts)
+			assert.NoError(t, err)
+			assert.Contains(t, got.Message, tc.msgContains)
+
+			data, err := os.ReadFile(logPath)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantLog+"\n", string(data))
+		})
+	}
+}
+
+func TestAptUpdater_CheckSecurityOnly(t *testing.T) {
+	fakeDir := t.TempDir()
+	writeFakeAptCommand(t, fakeDir)
+
+	t.Setenv("PATH", fakeDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("DEVSYNC_TEST_APT_MODE", "updates")
+
+	a := &AptUpdater{}
+	assert.NoError(t, a.Configure(config.ManagerConfig{"security_only": true}))
+	assert.True(t, a.SupportsSecurityOnly())
+
+	got, err := a.Check(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []PackageInfo{
+		{Name: "curl", CurrentVersion: "7.88.1", NewVersion: "8.5.0", Security: true},
+	}, got.Packages)
+}
+
 func writeFakeAptCommand(t *testing.T, dir string) {
 	t.Helper()
 
@@ -319,6 +420,7 @@ set mode=%DEVSYNC_TEST_APT_MODE%
 if "%1"=="update" goto doupdate
 if "%1"=="list" goto dolist
 if "%1"=="upgrade" goto doupgrade
+if "%1"=="install" goto doupgrade
 echo invalid args 1>&2
 exit /b 1
 :doupdate
@@ -338,7 +440,7 @@ if "%mode%"=="none" (
 )
 echo Listing... Done
 echo vim/stable 9.0.2 amd64 [upgradable from: 8.2.1]
-echo curl/stable 8.5.0 amd64 [upgradable from: 7.88.1]
+echo curl/stable,stable-security 8.5.0 amd64 [upgradable from: 7.88.1]
 exit /b 0
 :doupgrade
 if "%mode%"=="upgrade_error" (
@@ -369,10 +471,13 @@ if [ "$1" = "list" ]; then
   fi
   echo "Listing... Done"
   echo "vim/stable 9.0.2 amd64 [upgradable from: 8.2.1]"
-  echo "curl/stable 8.5.0 amd64 [upgradable from: 7.88.1]"
+  echo "curl/stable,stable-security 8.5.0 amd64 [upgradable from: 7.88.1]"
   exit 0
 fi
-if [ "$1" = "upgrade" ]; then
+if [ "$1" = "upgrade" ] || [ "$1" = "install" ]; then
+  if [ -n "${DEVSYNC_TEST_APT_LOG}" ]; then
+    echo "$*" >> "${DEVSYNC_TEST_APT_LOG}"
+  fi
   if [ "${mode}" = "upgrade_error" ]; then
     echo "apt upgrade failed" 1>&2
     exit 1
@@ -395,3 +500,25 @@ exit 1
 		}
 	}
 }
+
+func TestFillAptSourcePackages(t *testing.T) {
+	if runtime.GOOS == windowsOS {
+		t.Skip("fake dpkg-query は POSIX シェル前提")
+	}
+
+	// 見つからないパッケージがあると終了コード 1 になるが、見つかった分は出力される
+	script := `#!/bin/sh
+printf 'libssl3\topenssl\ncurl\tcurl\n'
+echo "dpkg-query: no packages found matching missing" 1>&2
+exit 1
+`
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "dpkg-query"), []byte(script), 0o755))
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	packages := []PackageInfo{{Name: "libssl3"}, {Name: "curl"}, {Name: "missing"}}
+	fillAptSourcePackages(context.Background(), packages)
+
+	assert.Equal(t, []PackageInfo{{Name: "libssl3", Source: "openssl"}, {Name: "curl"}, {Name: "missing"}}, packages)
+}
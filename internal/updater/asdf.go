@@ -0,0 +1,173 @@
+package updater
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/scottlz0310/devsync/internal/config"
+)
+
+// AsdfUpdater は asdf（複数言語のランタイムバージョン管理）の実装です。
+// asdf 本体は導入方法（git / Homebrew / バイナリ）ごとに更新手段が異なるため、プラグインのみ更新します。
+type AsdfUpdater struct {
+	settings runtimeUpdateSettings
+}
+
+var _ runtimeManager = (*AsdfUpdater)(nil)
+
+// 起動時にレジストリに登録
+func init() {
+	Register(&AsdfUpdater{settings: defaultRuntimeUpdateSettings()})
+}
+
+func (a *AsdfUpdater) Name() string {
+	return "asdf"
+}
+
+func (a *AsdfUpdater) DisplayName() string {
+	return "asdf (ランタイムバージョン管理)"
+}
+
+func (a *AsdfUpdater) IsAvailable() bool {
+	_, err := exec.LookPath("asdf")
+	return err == nil
+}
+
+func (a *AsdfUpdater) Configure(cfg config.ManagerConfig) error {
+	if cfg == nil {
+		return nil
+	}
+
+	a.settings.configure(cfg)
+
+	return nil
+}
+
+func (a *AsdfUpdater) Check(ctx context.Context) (*CheckResult, error) {
+	return checkRuntimeUpdates(ctx, a, a.settings)
+}
+
+func (a *AsdfUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateResult, error) {
+	return updateRuntimeVersions(ctx, opts, a.Name(), a, a.settings)
+}
+
+func (a *AsdfUpdater) selfUpdate(ctx context.Context) error {
+	if err := runSystemCommand(ctx, false, "asdf", "plugin", "update", "--all"); err != nil {
+		return fmt.Errorf("asdf plugin update --all に失敗: %w", err)
+	}
+
+	return nil
+}
+
+func (a *AsdfUpdater) installedVersions(ctx context.Context) (map[string][]string, error) {
+	output, err := runCommandOutputWithLocaleC(ctx, "asdf", []string{"plugin", "list"}, "asdf plugin list の実行に失敗: %w")
+	if err != nil {
+		return nil, err
+	}
+
+	installed := make(map[string][]string)
+
+	for _, tool := range strings.Fields(string(output)) {
+		// バージョン未インストールのプラグインは asdf list が失敗するため対象外とする
+		versions, err := runCommandOutputWithLocaleC(ctx, "asdf", []string{"list", tool}, "asdf list の実行に失敗: %w")
+		if err != nil {
+			continue
+		}
+
+		installed[tool] = runtimeVersionTokens(string(versions))
+	}
+
+	return installed, nil
+}
+
+func (a *AsdfUpdater) availableVersions(ctx context.Context, tool string) ([]string, error) {
+	output, err := runCommandOutputWithLocaleC(ctx, "asdf", []string{"list", "all", tool}, "asdf list all の実行に失敗: %w")
+	if err != nil {
+		return nil, err
+	}
+
+	return runtimeVersionTokens(string(output)), nil
+}
+
+// globalVersion はホームディレクトリの .tool-versions に記載された先頭のバージョンを返します。
+func (a *AsdfUpdater) globalVersion(_ context.Context, tool string) (string, error) {
+	path, err := asdfToolVersionsPath()
+	if err != nil {
+		return "", err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("%s の読み込みに失敗: %w", path, err)
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && fields[0] == tool {
+			return fields[1], nil
+		}
+	}
+
+	return "", nil
+}
+
+func (a *AsdfUpdater) installVersion(ctx context.Context, tool, version string) error {
+	return runSystemCommand(ctx, false, "asdf", "install", tool, version)
+}
+
+// setGlobalVersion はホームディレクトリの .tool-versions の先頭のバージョンを書き換えます。
+// asdf のバージョンにより global / set コマンドが異なるため、ファイルを直接更新します。
+func (a *AsdfUpdater) setGlobalVersion(_ context.Context, tool, version string) error {
+	path, err := asdfToolVersionsPath()
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("%s の読み込みに失敗: %w", path, err)
+	}
+
+	lines := strings.Split(string(data), "\n")
+	for i, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && fields[0] == tool {
+			fields[1] = version
+			lines[i] = strings.Join(fields, " ")
+		}
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("%s の読み込みに失敗: %w", path, err)
+	}
+
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")), info.Mode().Perm()); err != nil {
+		return fmt.Errorf("%s の書き込みに失敗: %w", path, err)
+	}
+
+	return nil
+}
+
+func asdfToolVersionsPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("ホームディレクトリの取得に失敗: %w", err)
+	}
+
+	name := strings.TrimSpace(os.Getenv("ASDF_DEFAULT_TOOL_VERSIONS_FILENAME"))
+	if name == "" {
+		name = ".tool-versions"
+	}
+
+	return filepath.Join(home, name), nil
+}
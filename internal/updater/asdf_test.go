@@ -0,0 +1,76 @@
+package updater
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/scottlz0310/devsync/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAsdfUpdater_CheckAndUpdate(t *testing.T) {
+	logPath := writeFakeAsdfCommand(t)
+
+	home := t.TempDir()
+	testutil.SetTestHome(t, home)
+	t.Setenv("ASDF_DEFAULT_TOOL_VERSIONS_FILENAME", "")
+
+	toolVersions := filepath.Join(home, ".tool-versions")
+	require.NoError(t, os.WriteFile(toolVersions, []byte("python 3.12.3 3.11.9\nnodejs 22.4.0\n"), 0o644))
+
+	a := &AsdfUpdater{settings: defaultRuntimeUpdateSettings()}
+
+	checkResult, err := a.Check(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []PackageInfo{{Name: "python 3.12", CurrentVersion: "3.12.3", NewVersion: "3.12.5"}}, checkResult.Packages,
+		"バージョン未インストールのプラグインは対象外")
+
+	a.settings.install = true
+	a.settings.setGlobal = true
+
+	result, err := a.Update(context.Background(), UpdateOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.UpdatedCount)
+
+	data, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "asdf plugin update --all")
+	assert.Contains(t, string(data), "asdf install python 3.12.5")
+
+	updated, err := os.ReadFile(toolVersions)
+	require.NoError(t, err)
+	assert.Equal(t, "python 3.12.5 3.11.9\nnodejs 22.4.0\n", string(updated))
+}
+
+func writeFakeAsdfCommand(t *testing.T) string {
+	t.Helper()
+
+	if runtime.GOOS == windowsOS {
+		t.Skip("fake asdf は POSIX シェル前提")
+	}
+
+	logPath := filepath.Join(t.TempDir(), "commands.log")
+	t.Setenv("DEVSYNC_TEST_ASDF_LOG", logPath)
+
+	script := `#!/bin/sh
+case "$*" in
+  "plugin list") printf 'python\nnodejs\nterraform\n' ;;
+  "list python") printf '  3.11.9\n *3.12.3\n' ;;
+  "list nodejs") printf ' *22.4.0\n' ;;
+  "list terraform") echo "  No versions installed" 1>&2; exit 1 ;;
+  "list all python") printf '3.11.9\n3.12.3\n3.12.5\n3.13.0\n' ;;
+  "list all nodejs") printf '22.3.0\n22.4.0\n' ;;
+  *) echo "asdf $*" >> "${DEVSYNC_TEST_ASDF_LOG}" ;;
+esac
+`
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "asdf"), []byte(script), 0o755))
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	return logPath
+}
@@ -189,3 +189,27 @@ func (b *BrewUpdater) parseOutdatedList(output string) []PackageInfo {
 
 	return packages
 }
+
+var _ PackageInstaller = (*BrewUpdater)(nil)
+
+func (b *BrewUpdater) PackageName(spec string) string {
+	return strings.TrimSpace(spec)
+}
+
+// InstalledPackages は明示的にインストールされたフォーミュラ（依存として入ったものを除く）を返します。
+func (b *BrewUpdater) InstalledPackages(ctx context.Context) ([]string, error) {
+	output, err := runPackageListCommand(ctx, "brew", "leaves", "--installed-on-request")
+	if err != nil {
+		return nil, err
+	}
+
+	return strings.Fields(string(output)), nil
+}
+
+func (b *BrewUpdater) InstallPackage(ctx context.Context, spec string) error {
+	return runPackageCommand(ctx, "brew", "install", spec)
+}
+
+func (b *BrewUpdater) RemovePackage(ctx context.Context, name string) error {
+	return runPackageCommand(ctx, "brew", "uninstall", name)
+}
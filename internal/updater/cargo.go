@@ -2,17 +2,38 @@ package updater
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 
 	"github.com/scottlz0310/devsync/internal/config"
 )
 
 // CargoUpdater は cargo (Rust パッケージ) の実装です。
+// allow（許可するバージョンの範囲）を設定した場合は crates.io で最新バージョンを確認し、
+// 範囲内のクレートのみを個別に更新します。git / ローカルパスからインストールしたクレートは更新しません。
 type CargoUpdater struct {
-	// 将来的な拡張のための設定フィールド
+	policy versionPolicy
+	// cratesIOURL は crates.io API のベース URL です（空の場合は既定値）。
+	cratesIOURL string
+}
+
+// cargoCrate は "cargo install --list" の 1 エントリです。
+type cargoCrate struct {
+	Name    string
+	Version string
+	// Source は crates.io 以外のインストール元（git の URL やローカルパス）です。crates.io の場合は空です。
+	Source string
+}
+
+// cargoInstallOptions は $CARGO_HOME/.crates2.json に記録されたインストール時のオプションです。
+type cargoInstallOptions struct {
+	Features          []string `json:"features"`
+	AllFeatures       bool     `json:"all_features"`
+	NoDefaultFeatures bool     `json:"no_default_features"`
 }
 
 // 起動時にレジストリに登録
@@ -33,21 +54,33 @@ func (c *CargoUpdater) IsAvailable() bool {
 	return err == nil
 }
 
+// SupportsVersionPolicy は allow / allow_packages に対応していることを返します。
+func (c *CargoUpdater) SupportsVersionPolicy() bool {
+	return true
+}
+
 func (c *CargoUpdater) Configure(cfg config.ManagerConfig) error {
-	// 現時点では設定項目なし
+	if cfg == nil {
+		return nil
+	}
+
+	policy, err := parseVersionPolicy(c.Name(), cfg)
+	if err != nil {
+		return err
+	}
+
+	c.policy = policy
+
 	return nil
 }
 
 func (c *CargoUpdater) Check(ctx context.Context) (*CheckResult, error) {
-	// cargo install --list でインストール済みパッケージを取得
-	cmd := exec.CommandContext(ctx, "cargo", "install", "--list")
-
-	output, err := cmd.Output()
+	crates, err := c.listCrates(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("cargo install --list の実行に失敗: %w", err)
+		return nil, err
 	}
 
-	packages := c.parseInstallList(string(output))
+	packages := cargoCratesToPackages(crates)
 
 	// cargo は個別の outdated チェックがないため、
 	// AvailableUpdates は 0 とし、インストール済みパッケージのみ返す
@@ -62,29 +95,42 @@ func (c *CargoUpdater) Check(ctx context.Context) (*CheckResult, error) {
 func (c *CargoUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateResult, error) {
 	result := &UpdateResult{}
 
-	// まず更新確認
-	checkResult, err := c.Check(ctx)
+	crates, err := c.listCrates(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	if len(checkResult.Packages) == 0 {
+	if len(crates) == 0 {
 		result.Message = "cargo でインストールされたパッケージがありません"
 		return result, nil
 	}
 
+	// cargo-update がインストールされているか確認
+	// cargo-update は cargo のサブコマンドとして動作するため、
+	// cargo install-update --help で確認
+	hasInstallUpdate := exec.CommandContext(ctx, "cargo", "install-update", "--help").Run() == nil
+
+	if c.policy.restricted() && !opts.AllowMajor {
+		// git / ローカルパスからのクレートは crates.io の同名クレートで置き換えないよう対象外にする
+		return updateWithRegistryPolicy(ctx, opts, c.policy, cargoCratesToPackages(registryCargoCrates(crates)),
+			func(ctx context.Context, name string) (string, error) {
+				return latestCrateVersion(ctx, c.cratesIOURL, name)
+			},
+			func(ctx context.Context, pkg PackageInfo) error {
+				return installCargoCrate(ctx, pkg.Name, pkg.NewVersion, hasInstallUpdate)
+			})
+	}
+
+	packages := cargoCratesToPackages(crates)
+
 	if opts.DryRun {
-		result.Message = fmt.Sprintf("%d 件のインストール済みパッケージについて更新を確認します（DryRunモード）", len(checkResult.Packages))
-		result.Packages = checkResult.Packages
+		result.Message = fmt.Sprintf("%d 件のインストール済みパッケージについて更新を確認します（DryRunモード）", len(packages))
+		result.Packages = packages
 
 		return result, nil
 	}
 
-	// cargo-update がインストールされているか確認
-	// cargo-update は cargo のサブコマンドとして動作するため、
-	// cargo install-update --help で確認
-	checkCmd := exec.CommandContext(ctx, "cargo", "install-update", "--help")
-	if err := checkCmd.Run(); err == nil {
+	if hasInstallUpdate {
 		// cargo-update を使用（推奨）
 		cmd := exec.CommandContext(ctx, "cargo", "install-update", "-a")
 		cmd.Stdout = os.Stdout
@@ -96,16 +142,11 @@ func (c *CargoUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateR
 			return result, fmt.Errorf("cargo install-update -a に失敗: %w", err)
 		}
 	} else {
-		// cargo-update がない場合は個別に再インストール
-		for _, pkg := range checkResult.Packages {
-			cmd := exec.CommandContext(ctx, "cargo", "install", "--force", pkg.Name)
-			cmd.Stdout = os.Stdout
-			cmd.Stderr = os.Stderr
-			cmd.Stdin = os.Stdin
-
-			if err := cmd.Run(); err != nil {
+		// cargo-update がない場合は crates.io のクレートのみ個別に再インストール
+		for _, crate := range registryCargoCrates(crates) {
+			if err := installCargoCrate(ctx, crate.Name, "", false); err != nil {
 				result.FailedCount++
-				result.Errors = append(result.Errors, fmt.Errorf("%s: %w", pkg.Name, err))
+				result.Errors = append(result.Errors, fmt.Errorf("%s: %w", crate.Name, err))
 
 				continue
 			}
@@ -114,19 +155,134 @@ func (c *CargoUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateR
 		}
 
 		if len(result.Errors) > 0 {
-			result.Packages = checkResult.Packages
+			result.Packages = packages
 			result.Message = fmt.Sprintf("%d 件更新、%d 件失敗", result.UpdatedCount, result.FailedCount)
 
 			return result, fmt.Errorf("一部のパッケージ更新に失敗しました")
 		}
 	}
 
-	result.Packages = checkResult.Packages
+	result.Packages = packages
 	result.Message = fmt.Sprintf("%d 件のパッケージを確認・更新しました", result.UpdatedCount)
 
 	return result, nil
 }
 
+// listCrates は "cargo install --list" でインストール済みクレートを取得します。
+func (c *CargoUpdater) listCrates(ctx context.Context) ([]cargoCrate, error) {
+	output, err := exec.CommandContext(ctx, "cargo", "install", "--list").Output()
+	if err != nil {
+		return nil, fmt.Errorf("cargo install --list の実行に失敗: %w", err)
+	}
+
+	return c.parseInstallList(string(output)), nil
+}
+
+// installCargoCrate は crates.io のクレートを更新します（version が空の場合は最新）。
+// cargo-update があれば "cargo install-update name:version" を使い、記録済みの features などを引き継ぎます。
+// ない場合は .crates2.json に記録されたインストール時の features を指定して "cargo install --force --locked" で再インストールします。
+func installCargoCrate(ctx context.Context, name, version string, hasInstallUpdate bool) error {
+	if hasInstallUpdate {
+		target := name
+		if version != "" {
+			target += ":" + version
+		}
+
+		return runPackageCommand(ctx, "cargo", "install-update", target)
+	}
+
+	args := []string{"install", "--force", "--locked"}
+
+	if options, ok := readCargoInstallOptions()[name]; ok {
+		args = append(args, options.args()...)
+	}
+
+	if version != "" {
+		args = append(args, "--version", version)
+	}
+
+	return runPackageCommand(ctx, "cargo", append(args, name)...)
+}
+
+func (o cargoInstallOptions) args() []string {
+	args := make([]string, 0, 3)
+
+	if o.AllFeatures {
+		args = append(args, "--all-features")
+	} else if len(o.Features) > 0 {
+		args = append(args, "--features", strings.Join(o.Features, ","))
+	}
+
+	if o.NoDefaultFeatures {
+		args = append(args, "--no-default-features")
+	}
+
+	return args
+}
+
+// readCargoInstallOptions は $CARGO_HOME/.crates2.json からクレート名ごとのインストール時のオプションを読み取ります。
+// 読み取れない場合は nil を返します。
+func readCargoInstallOptions() map[string]cargoInstallOptions {
+	home := os.Getenv("CARGO_HOME")
+	if home == "" {
+		userHome, err := os.UserHomeDir()
+		if err != nil {
+			return nil
+		}
+
+		home = filepath.Join(userHome, ".cargo")
+	}
+
+	data, err := os.ReadFile(filepath.Join(home, ".crates2.json"))
+	if err != nil {
+		return nil
+	}
+
+	var crates2 struct {
+		Installs map[string]cargoInstallOptions `json:"installs"`
+	}
+
+	if err := json.Unmarshal(data, &crates2); err != nil {
+		return nil
+	}
+
+	// キーは "ripgrep 14.1.1 (registry+https://github.com/rust-lang/crates.io-index)" 形式
+	options := make(map[string]cargoInstallOptions, len(crates2.Installs))
+	for key, value := range crates2.Installs {
+		name, _, _ := strings.Cut(key, " ")
+		options[name] = value
+	}
+
+	return options
+}
+
+// registryCargoCrates は crates.io からインストールしたクレートのみを返します。
+func registryCargoCrates(crates []cargoCrate) []cargoCrate {
+	result := make([]cargoCrate, 0, len(crates))
+
+	for _, crate := range crates {
+		if crate.Source == "" {
+			result = append(result, crate)
+		}
+	}
+
+	return result
+}
+
+func cargoCratesToPackages(crates []cargoCrate) []PackageInfo {
+	packages := make([]PackageInfo, 0, len(crates))
+
+	for _, crate := range crates {
+		packages = append(packages, PackageInfo{
+			Name:           crate.Name,
+			CurrentVersion: crate.Version,
+			NewVersion:     "", // cargo は事前に新バージョンを知る手段がない
+		})
+	}
+
+	return packages
+}
+
 // parseInstallList は "cargo install --list" の出力をパースします
 // 形式:
 // package-name v1.0.0:
@@ -134,13 +290,15 @@ func (c *CargoUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateR
 //	binary1
 //	binary2
 //
-// another-package v2.0.0:
+// another-package v2.0.0 (https://github.com/owner/repo#0123abcd):
 //
 //	binary3
-func (c *CargoUpdater) parseInstallList(output string) []PackageInfo {
+//
+// crates.io 以外からのインストールはバージョンの後ろに括弧付きでインストール元が付きます。
+func (c *CargoUpdater) parseInstallList(output string) []cargoCrate {
 	output = strings.ReplaceAll(output, "\r\n", "\n")
 	lines := strings.Split(output, "\n")
-	packages := make([]PackageInfo, 0, len(lines))
+	crates := make([]cargoCrate, 0, len(lines))
 
 	for _, line := range lines {
 		// インデントされた行（バイナリ名）をスキップ
@@ -161,16 +319,50 @@ func (c *CargoUpdater) parseInstallList(output string) []PackageInfo {
 			continue
 		}
 
-		name := parts[0]
-		version := strings.TrimPrefix(parts[1], "v")
+		crate := cargoCrate{
+			Name:    parts[0],
+			Version: strings.TrimPrefix(parts[1], "v"),
+		}
 
-		pkg := PackageInfo{
-			Name:           name,
-			CurrentVersion: version,
-			NewVersion:     "", // cargo は事前に新バージョンを知る手段がない
+		if len(parts) > 2 {
+			source := strings.Join(parts[2:], " ")
+			crate.Source = strings.TrimSuffix(strings.TrimPrefix(source, "("), ")")
 		}
-		packages = append(packages, pkg)
+
+		crates = append(crates, crate)
 	}
 
-	return packages
+	return crates
+}
+
+var _ PackageInstaller = (*CargoUpdater)(nil)
+
+func (c *CargoUpdater) PackageName(spec string) string {
+	return stripPackageVersion(spec)
+}
+
+// InstalledPackages は "cargo install --list" からインストール済みクレート名を取得します。
+func (c *CargoUpdater) InstalledPackages(ctx context.Context) ([]string, error) {
+	output, err := runPackageListCommand(ctx, "cargo", "install", "--list")
+	if err != nil {
+		return nil, err
+	}
+
+	crates := c.parseInstallList(string(output))
+	names := make([]string, 0, len(crates))
+
+	for _, crate := range crates {
+		names = append(names, crate.Name)
+	}
+
+	return names, nil
+}
+
+// InstallPackage は "cargo install" でクレートをインストールします（"name@version" 形式も指定可能）。
+func (c *CargoUpdater) InstallPackage(ctx context.Context, spec string) error {
+	return runPackageCommand(ctx, "cargo", "install", "--locked", spec)
+}
+
+func (c *CargoUpdater) RemovePackage(ctx context.Context, name string) error {
+	return runPackageCommand(ctx, "cargo", "uninstall", name)
 }
@@ -2,19 +2,23 @@ package updater
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"runtime"
 	"testing"
 
+	"github.com/scottlz0310/devsync/internal/config"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCargoUpdater_parseInstallList(t *testing.T) {
 	tests := []struct {
 		name     string
 		output   string
-		expected []PackageInfo
+		expected []cargoCrate
 	}{
 		{
 			name:     "空の出力",
@@ -26,8 +30,8 @@ func TestCargoUpdater_parseInstallList(t *testing.T) {
 			output: `ripgrep v13.0.0:
     rg
 `,
-			expected: []PackageInfo{
-				{Name: "ripgrep", CurrentVersion: "13.0.0"},
+			expected: []cargoCrate{
+				{Name: "ripgrep", Version: "13.0.0"},
 			},
 		},
 		{
@@ -39,9 +43,9 @@ cargo-update v16.0.0:
     cargo-install-update
     cargo-install-update-config
 `,
-			expected: []PackageInfo{
-				{Name: "ripgrep", CurrentVersion: "13.0.0"},
-				{Name: "cargo-update", CurrentVersion: "16.0.0"},
+			expected: []cargoCrate{
+				{Name: "ripgrep", Version: "13.0.0"},
+				{Name: "cargo-update", Version: "16.0.0"},
 			},
 		},
 		{
@@ -52,8 +56,23 @@ pkg 1.2.3
 pkg 1.2.3:
     bin
 `,
-			expected: []PackageInfo{
-				{Name: "pkg", CurrentVersion: "1.2.3"},
+			expected: []cargoCrate{
+				{Name: "pkg", Version: "1.2.3"},
+			},
+		},
+		{
+			name: "git / ローカルパスのインストール元を保持",
+			output: `ripgrep v14.1.1:
+    rg
+tool v0.1.0 (https://github.com/owner/tool#0123abcd):
+    tool
+local v0.2.0 (/home/user/src/local):
+    local
+`,
+			expected: []cargoCrate{
+				{Name: "ripgrep", Version: "14.1.1"},
+				{Name: "tool", Version: "0.1.0", Source: "https://github.com/owner/tool#0123abcd"},
+				{Name: "local", Version: "0.2.0", Source: "/home/user/src/local"},
 			},
 		},
 		{
@@ -61,8 +80,8 @@ pkg 1.2.3:
 			output: `pkg 1.2.3:
     bin
 `,
-			expected: []PackageInfo{
-				{Name: "pkg", CurrentVersion: "1.2.3"},
+			expected: []cargoCrate{
+				{Name: "pkg", Version: "1.2.3"},
 			},
 		},
 	}
@@ -75,9 +94,7 @@ pkg 1.2.3:
 			assert.Len(t, got, len(tt.expected))
 
 			for i := range tt.expected {
-				assert.Equal(t, tt.expected[i].Name, got[i].Name)
-				assert.Equal(t, tt.expected[i].CurrentVersion, got[i].CurrentVersion)
-				assert.Equal(t, "", got[i].NewVersion)
+				assert.Equal(t, tt.expected[i], got[i])
 			}
 		})
 	}
@@ -289,9 +306,16 @@ case "$1" in
         echo "    rg"
         echo "bat v0.24.0:"
         echo "    bat"
+        if [ "${mode}" = "git" ]; then
+          echo "fd-find v8.0.0 (https://github.com/owner/fd#0123abcd):"
+          echo "    fd"
+        fi
         exit 0
         ;;
       --force)
+        if [ -n "${DEVSYNC_TEST_CARGO_LOG}" ]; then
+          echo "$*" >> "${DEVSYNC_TEST_CARGO_LOG}"
+        fi
         exit 0
         ;;
       *)
@@ -303,6 +327,9 @@ case "$1" in
   install-update)
     case "$2" in
       --help)
+        if [ -n "${DEVSYNC_TEST_CARGO_NO_INSTALL_UPDATE}" ]; then
+          exit 101
+        fi
         exit 0
         ;;
       -a)
@@ -313,8 +340,10 @@ case "$1" in
         exit 0
         ;;
       *)
-        echo "invalid args" 1>&2
-        exit 1
+        if [ -n "${DEVSYNC_TEST_CARGO_LOG}" ]; then
+          echo "$*" >> "${DEVSYNC_TEST_CARGO_LOG}"
+        fi
+        exit 0
         ;;
     esac
     ;;
@@ -349,3 +378,84 @@ exit 0
 		}
 	}
 }
+
+func TestCargoUpdater_UpdateWithVersionPolicy(t *testing.T) {
+	if runtime.GOOS == windowsOS {
+		t.Skip("fake cargo の引数記録は POSIX シェルのみ")
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/crates/ripgrep":
+			_, _ = w.Write([]byte(`{"crate":{"max_version":"15.0.0-rc.1","max_stable_version":"14.1.1"}}`))
+		case "/api/v1/crates/bat":
+			_, _ = w.Write([]byte(`{"crate":{"max_version":"0.25.0","max_stable_version":"0.25.0"}}`))
+		case "/api/v1/crates/fd-find":
+			_, _ = w.Write([]byte(`{"crate":{"max_version":"8.7.1","max_stable_version":"8.7.1"}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	setup := func(t *testing.T) string {
+		t.Helper()
+
+		fakeDir := t.TempDir()
+		writeFakeCargoCommand(t, fakeDir)
+
+		logPath := filepath.Join(t.TempDir(), "cargo.log")
+
+		t.Setenv("PATH", fakeDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+		t.Setenv("DEVSYNC_TEST_CARGO_MODE", "git")
+		t.Setenv("DEVSYNC_TEST_CARGO_LOG", logPath)
+
+		return logPath
+	}
+
+	readLog := func(t *testing.T, logPath string) string {
+		t.Helper()
+
+		logged, err := os.ReadFile(logPath)
+		require.NoError(t, err)
+
+		return string(logged)
+	}
+
+	t.Run("cargo-update があれば install-update でバージョンを固定して更新", func(t *testing.T) {
+		logPath := setup(t)
+
+		c := &CargoUpdater{cratesIOURL: server.URL}
+		require.NoError(t, c.Configure(config.ManagerConfig{"allow": "minor"}))
+
+		got, err := c.Update(context.Background(), UpdateOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, 1, got.UpdatedCount)
+		assert.Equal(t, []PackageInfo{{Name: "ripgrep", CurrentVersion: "14.0.0", NewVersion: "14.1.1"}}, got.Packages, "git からのクレートは crates.io の同名クレートで置き換えない")
+		assert.Equal(t, []PackageInfo{{Name: "bat", CurrentVersion: "0.24.0", NewVersion: "0.25.0"}}, got.Held, "0.x 系のマイナー更新はメジャー更新として保留")
+		assert.Equal(t, "install-update ripgrep:14.1.1\n", readLog(t, logPath))
+
+		got, err = c.Update(context.Background(), UpdateOptions{AllowMajor: true})
+		require.NoError(t, err)
+		assert.Contains(t, got.Message, "確認・更新しました", "--allow-major では従来どおり一括で更新する")
+	})
+
+	t.Run("cargo-update がなければインストール時の features を引き継いで再インストール", func(t *testing.T) {
+		logPath := setup(t)
+		t.Setenv("DEVSYNC_TEST_CARGO_NO_INSTALL_UPDATE", "1")
+
+		cargoHome := t.TempDir()
+		t.Setenv("CARGO_HOME", cargoHome)
+		require.NoError(t, os.WriteFile(filepath.Join(cargoHome, ".crates2.json"), []byte(`{"installs":{
+			"ripgrep 14.0.0 (registry+https://github.com/rust-lang/crates.io-index)":{"features":["pcre2"],"all_features":false,"no_default_features":true}
+		}}`), 0o644))
+
+		c := &CargoUpdater{cratesIOURL: server.URL}
+		require.NoError(t, c.Configure(config.ManagerConfig{"allow": "minor"}))
+
+		got, err := c.Update(context.Background(), UpdateOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, 1, got.UpdatedCount)
+		assert.Equal(t, "install --force --locked --features pcre2 --no-default-features --version 14.1.1 ripgrep\n", readLog(t, logPath))
+	})
+}
@@ -0,0 +1,268 @@
+package updater
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/scottlz0310/devsync/internal/config"
+)
+
+// CondaUpdater は conda / mamba の環境ごとの更新（update --all）の実装です。
+//
+//	sys:
+//	  managers:
+//	    conda:
+//	      envs: ["base", "ds"]  # 更新する環境（既定: base）
+type CondaUpdater struct {
+	command     string
+	displayName string
+	envs        []string
+}
+
+// condaDryRun は `conda update --all --dry-run --json` の出力です。
+type condaDryRun struct {
+	Success bool   `json:"success"`
+	Error   string `json:"error"`
+	Actions struct {
+		Link   []condaPackage `json:"LINK"`
+		Unlink []condaPackage `json:"UNLINK"`
+	} `json:"actions"`
+}
+
+type condaPackage struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// 起動時にレジストリに登録
+func init() {
+	for _, c := range builtinCondaUpdaters() {
+		Register(c)
+	}
+}
+
+// builtinCondaUpdaters は組み込みの conda 系 Updater を返します。
+func builtinCondaUpdaters() []*CondaUpdater {
+	return []*CondaUpdater{
+		NewCondaUpdater("conda", "conda (Python 環境)"),
+		NewCondaUpdater("mamba", "mamba (Python 環境)"),
+	}
+}
+
+// NewCondaUpdater は conda 互換の CLI（conda / mamba）で環境を更新する Updater を作成します。
+func NewCondaUpdater(command, displayName string) *CondaUpdater {
+	return &CondaUpdater{command: command, displayName: displayName, envs: []string{"base"}}
+}
+
+// mergeCondaUpdaters は conda と mamba の両方が有効な場合に 1 つの mamba の Updater にまとめます。
+// どちらも同じインストール（同じ環境のプレフィックス）を操作するため、別々に実行すると
+// 同じ環境の update --all が並列に二重実行されます。環境は mamba、conda の順に重複を除いて統合します。
+func mergeCondaUpdaters(updaters []Updater) []Updater {
+	var conda, mamba *CondaUpdater
+
+	for _, u := range updaters {
+		c, ok := u.(*CondaUpdater)
+		if !ok {
+			continue
+		}
+
+		switch c.command {
+		case "conda":
+			conda = c
+		case "mamba":
+			mamba = c
+		}
+	}
+
+	if conda == nil || mamba == nil {
+		return updaters
+	}
+
+	envs := append([]string(nil), mamba.envs...)
+
+	for _, env := range conda.envs {
+		if !containsString(envs, env) {
+			envs = append(envs, env)
+		}
+	}
+
+	merged := &CondaUpdater{command: mamba.command, displayName: mamba.displayName, envs: envs}
+	result := make([]Updater, 0, len(updaters)-1)
+
+	for _, u := range updaters {
+		switch u {
+		case Updater(conda):
+			continue
+		case Updater(mamba):
+			result = append(result, merged)
+		default:
+			result = append(result, u)
+		}
+	}
+
+	return result
+}
+
+func (c *CondaUpdater) Name() string {
+	return c.command
+}
+
+func (c *CondaUpdater) DisplayName() string {
+	return c.displayName
+}
+
+func (c *CondaUpdater) IsAvailable() bool {
+	_, err := exec.LookPath(c.command)
+	return err == nil
+}
+
+func (c *CondaUpdater) Configure(cfg config.ManagerConfig) error {
+	if cfg == nil {
+		return nil
+	}
+
+	if envs, ok := toStringList(cfg["envs"]); ok && len(envs) > 0 {
+		c.envs = envs
+	}
+
+	return nil
+}
+
+func (c *CondaUpdater) Check(ctx context.Context) (*CheckResult, error) {
+	packages := make([]PackageInfo, 0)
+
+	for _, env := range c.envs {
+		envPackages, err := c.planEnvUpdates(ctx, env)
+		if err != nil {
+			return nil, err
+		}
+
+		packages = append(packages, envPackages...)
+	}
+
+	message := fmt.Sprintf("%d 件のパッケージが更新可能です", len(packages))
+	if len(packages) == 0 {
+		message = "すべてのパッケージは最新です"
+	}
+
+	return &CheckResult{
+		AvailableUpdates: len(packages),
+		Packages:         packages,
+		Message:          message,
+	}, nil
+}
+
+func (c *CondaUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateResult, error) {
+	result := &UpdateResult{}
+
+	for _, env := range c.envs {
+		packages, err := c.planEnvUpdates(ctx, env)
+		if err != nil {
+			result.FailedCount++
+			result.Errors = append(result.Errors, err)
+
+			continue
+		}
+
+		if len(packages) == 0 {
+			continue
+		}
+
+		if opts.DryRun {
+			result.Packages = append(result.Packages, packages...)
+			continue
+		}
+
+		if err := runPackageCommand(ctx, c.command, "update", "--all", "--yes", "--name", env); err != nil {
+			result.FailedCount += len(packages)
+			result.Errors = append(result.Errors, err)
+
+			continue
+		}
+
+		result.UpdatedCount += len(packages)
+		result.Packages = append(result.Packages, packages...)
+	}
+
+	switch {
+	case opts.DryRun && len(result.Packages) > 0:
+		result.Message = fmt.Sprintf("%d 件のパッケージが更新可能です（DryRunモード）", len(result.Packages))
+	case result.FailedCount > 0:
+		result.Message = fmt.Sprintf("%d 件更新、%d 件失敗", result.UpdatedCount, result.FailedCount)
+	case result.UpdatedCount > 0:
+		result.Message = fmt.Sprintf("%d 件のパッケージを更新しました", result.UpdatedCount)
+	default:
+		result.Message = "すべてのパッケージは最新です"
+	}
+
+	return result, nil
+}
+
+// planEnvUpdates は環境 env の `update --all --dry-run --json` から更新されるパッケージを求めます。
+func (c *CondaUpdater) planEnvUpdates(ctx context.Context, env string) ([]PackageInfo, error) {
+	cmd := exec.CommandContext(ctx, c.command, "update", "--all", "--dry-run", "--json", "--name", env)
+	cmd.Env = append(os.Environ(), "LANG=C", "LC_ALL=C")
+
+	var stderr bytes.Buffer
+
+	cmd.Stderr = &stderr
+
+	// 失敗時も JSON でエラー内容を返すため、終了コードより先に出力を解釈する
+	output, runErr := cmd.Output()
+
+	packages, err := parseCondaDryRun(output, env)
+	if err != nil {
+		if runErr != nil {
+			err = buildCommandOutputErr(runErr, combineCommandOutputs(output, stderr.Bytes()))
+		}
+
+		return nil, fmt.Errorf("%s update --dry-run（環境: %s）に失敗: %w", c.command, env, err)
+	}
+
+	return packages, nil
+}
+
+// parseCondaDryRun は dry-run の JSON から、UNLINK と LINK の対応で現在→新バージョンを求めます。
+// 新たに追加される依存パッケージは現在のバージョンを空にします。
+func parseCondaDryRun(output []byte, env string) ([]PackageInfo, error) {
+	var dryRun condaDryRun
+	if err := json.Unmarshal(bytes.TrimSpace(output), &dryRun); err != nil {
+		return nil, fmt.Errorf("JSON の解析に失敗: %w", err)
+	}
+
+	if dryRun.Error != "" {
+		return nil, fmt.Errorf("%s", dryRun.Error)
+	}
+
+	current := make(map[string]string, len(dryRun.Actions.Unlink))
+	for _, pkg := range dryRun.Actions.Unlink {
+		current[pkg.Name] = pkg.Version
+	}
+
+	packages := make([]PackageInfo, 0, len(dryRun.Actions.Link))
+
+	for _, pkg := range dryRun.Actions.Link {
+		// ビルド番号のみの差し替えは更新として数えない
+		if current[pkg.Name] == pkg.Version {
+			continue
+		}
+
+		packages = append(packages, PackageInfo{
+			Name:           env + "/" + pkg.Name,
+			CurrentVersion: current[pkg.Name],
+			NewVersion:     pkg.Version,
+		})
+	}
+
+	sort.Slice(packages, func(i, j int) bool {
+		return strings.Compare(packages[i].Name, packages[j].Name) < 0
+	})
+
+	return packages, nil
+}
@@ -0,0 +1,132 @@
+package updater
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/scottlz0310/devsync/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCondaDryRun(t *testing.T) {
+	testCases := []struct {
+		name        string
+		output      string
+		want        []PackageInfo
+		errContains string
+	}{
+		{
+			name: "更新と新規の依存パッケージ",
+			output: `{"actions":{
+				"LINK":[{"name":"numpy","version":"1.26.4"},{"name":"libblas","version":"3.9.0"},{"name":"openssl","version":"3.0.13"}],
+				"UNLINK":[{"name":"numpy","version":"1.26.2"},{"name":"openssl","version":"3.0.13"}]
+			},"success":true}`,
+			want: []PackageInfo{
+				{Name: "base/libblas", NewVersion: "3.9.0"},
+				{Name: "base/numpy", CurrentVersion: "1.26.2", NewVersion: "1.26.4"},
+			},
+		},
+		{
+			name:   "更新なし",
+			output: `{"message":"All requested packages already installed.","success":true}`,
+			want:   []PackageInfo{},
+		},
+		{
+			name:        "エラー応答",
+			output:      `{"error":"EnvironmentLocationNotFound: Not a conda environment","success":false}`,
+			errContains: "EnvironmentLocationNotFound",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parseCondaDryRun([]byte(tc.output), "base")
+			if tc.errContains != "" {
+				assert.ErrorContains(t, err, tc.errContains)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestCondaUpdater_Update(t *testing.T) {
+	logPath := writeFakeCondaCommand(t, "mamba")
+
+	c := NewCondaUpdater("mamba", "mamba")
+	require.NoError(t, c.Configure(config.ManagerConfig{"envs": []interface{}{"base", "ds", "missing"}}))
+
+	result, err := c.Update(context.Background(), UpdateOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.UpdatedCount)
+	assert.Equal(t, 1, result.FailedCount)
+	require.Len(t, result.Errors, 1)
+	assert.Contains(t, result.Errors[0].Error(), "環境: missing")
+
+	data, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	assert.Equal(t, "mamba update --all --yes --name base\nmamba update --all --yes --name ds\n", string(data))
+}
+
+func TestMergeCondaUpdaters(t *testing.T) {
+	conda := NewCondaUpdater("conda", "conda")
+	require.NoError(t, conda.Configure(config.ManagerConfig{"envs": []interface{}{"base", "ml"}}))
+
+	mamba := NewCondaUpdater("mamba", "mamba")
+	require.NoError(t, mamba.Configure(config.ManagerConfig{"envs": []interface{}{"ds", "base"}}))
+
+	pip := &PipUserUpdater{}
+
+	t.Run("両方が有効なら mamba にまとめる", func(t *testing.T) {
+		got := mergeCondaUpdaters([]Updater{conda, pip, mamba})
+		require.Len(t, got, 2)
+		assert.Same(t, pip, got[0])
+
+		merged, ok := got[1].(*CondaUpdater)
+		require.True(t, ok)
+		assert.Equal(t, "mamba", merged.Name())
+		assert.Equal(t, []string{"ds", "base", "ml"}, merged.envs)
+		assert.Equal(t, []string{"ds", "base"}, mamba.envs, "登録済みの Updater は変更しない")
+	})
+
+	t.Run("片方のみならそのまま", func(t *testing.T) {
+		got := mergeCondaUpdaters([]Updater{conda, pip})
+		assert.Equal(t, []Updater{conda, pip}, got)
+	})
+}
+
+// writeFakeCondaCommand は環境 base / ds に 1 件ずつ更新がある fake の conda 互換 CLI を配置します。
+func writeFakeCondaCommand(t *testing.T, command string) string {
+	t.Helper()
+
+	if runtime.GOOS == windowsOS {
+		t.Skip("fake " + command + " は POSIX シェル前提")
+	}
+
+	logPath := filepath.Join(t.TempDir(), "commands.log")
+	t.Setenv("DEVSYNC_TEST_CONDA_LOG", logPath)
+
+	script := `#!/bin/sh
+if [ "$3" = "--dry-run" ]; then
+  case "$6" in
+    base) echo '{"actions":{"LINK":[{"name":"conda","version":"24.5.0"}],"UNLINK":[{"name":"conda","version":"24.4.0"}]},"success":true}' ;;
+    ds) echo '{"actions":{"LINK":[{"name":"pandas","version":"2.2.2"}],"UNLINK":[{"name":"pandas","version":"2.2.1"}]},"success":true}' ;;
+    *) echo '{"error":"EnvironmentLocationNotFound","success":false}'; exit 1 ;;
+  esac
+  exit 0
+fi
+echo "` + command + ` $*" >> "${DEVSYNC_TEST_CONDA_LOG}"
+`
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, command), []byte(script), 0o755))
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	return logPath
+}
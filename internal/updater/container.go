@@ -0,0 +1,342 @@
+package updater
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/scottlz0310/devsync/internal/config"
+)
+
+// ContainerImageUpdater は Docker / Podman のローカルイメージの更新を扱う実装です。
+// ローカルの RepoDigests とレジストリ上のタグのダイジェストを比較し、差分があるイメージを pull します。
+//
+//	sys:
+//	  managers:
+//	    docker:
+//	      images: ["postgres:16", "redis"]  # 対象のイメージを限定（省略時はローカルのタグ付きイメージすべて）
+//	      prune: true                        # 更新後に dangling イメージを削除（既定: false）
+type ContainerImageUpdater struct {
+	command     string
+	displayName string
+	images      []string
+	prune       bool
+}
+
+// containerImageUpdate は更新が必要なイメージです。
+type containerImageUpdate struct {
+	ref     string
+	current string
+	latest  string
+}
+
+// 起動時にレジストリに登録
+func init() {
+	for _, c := range builtinContainerUpdaters() {
+		Register(c)
+	}
+}
+
+// builtinContainerUpdaters は組み込みのコンテナ CLI 向け Updater を返します。
+func builtinContainerUpdaters() []*ContainerImageUpdater {
+	return []*ContainerImageUpdater{
+		NewContainerImageUpdater("docker", "Docker (コンテナイメージ)"),
+		NewContainerImageUpdater("podman", "Podman (コンテナイメージ)"),
+	}
+}
+
+// NewContainerImageUpdater は docker 互換の CLI（docker / podman）でイメージを更新する Updater を作成します。
+func NewContainerImageUpdater(command, displayName string) *ContainerImageUpdater {
+	return &ContainerImageUpdater{command: command, displayName: displayName}
+}
+
+func (c *ContainerImageUpdater) Name() string {
+	return c.command
+}
+
+func (c *ContainerImageUpdater) DisplayName() string {
+	return c.displayName
+}
+
+func (c *ContainerImageUpdater) IsAvailable() bool {
+	_, err := exec.LookPath(c.command)
+	return err == nil
+}
+
+func (c *ContainerImageUpdater) Configure(cfg config.ManagerConfig) error {
+	if cfg == nil {
+		return nil
+	}
+
+	if values, ok := cfg["images"].([]interface{}); ok {
+		c.images = c.images[:0]
+
+		for _, value := range values {
+			image, ok := value.(string)
+			if !ok || strings.TrimSpace(image) == "" {
+				continue
+			}
+
+			if _, err := parseImageReference(image); err != nil {
+				return fmt.Errorf("sys.managers.%s.images: %w", c.command, err)
+			}
+
+			c.images = append(c.images, strings.TrimSpace(image))
+		}
+	}
+
+	if prune, ok := cfg["prune"].(bool); ok {
+		c.prune = prune
+	}
+
+	return nil
+}
+
+func (c *ContainerImageUpdater) Check(ctx context.Context) (*CheckResult, error) {
+	updates, failed, skipped, err := c.planUpdates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	packages := containerUpdatesToPackages(updates)
+
+	message := fmt.Sprintf("%d 件のイメージが更新可能です", len(packages))
+	if len(packages) == 0 {
+		message = "すべてのイメージは最新です"
+	}
+
+	if failed > 0 {
+		message += fmt.Sprintf("（%d 件はレジストリのダイジェストを確認できませんでした）", failed)
+	}
+
+	if skipped > 0 {
+		message += fmt.Sprintf("（%d 件は認証情報がないため確認をスキップしました）", skipped)
+	}
+
+	return &CheckResult{
+		AvailableUpdates: len(packages),
+		Packages:         packages,
+		Message:          message,
+	}, nil
+}
+
+func (c *ContainerImageUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateResult, error) {
+	result := &UpdateResult{}
+
+	updates, failed, skipped, err := c.planUpdates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if failed > 0 {
+		result.Errors = append(result.Errors, fmt.Errorf("%d 件のイメージはレジストリのダイジェストを確認できなかったためスキップしました", failed))
+	}
+
+	// 認証情報のないプライベートレジストリのイメージは毎回確認できないため、エラーにはしない
+	skippedNote := ""
+	if skipped > 0 {
+		skippedNote = fmt.Sprintf("（%d 件は認証情報がないため確認をスキップしました）", skipped)
+	}
+
+	if len(updates) == 0 {
+		result.Message = "すべてのイメージは最新です" + skippedNote
+		return result, nil
+	}
+
+	if opts.DryRun {
+		result.Packages = containerUpdatesToPackages(updates)
+		result.Message = fmt.Sprintf("%d 件のイメージを更新予定（DryRunモード）", len(updates)) + skippedNote
+
+		return result, nil
+	}
+
+	for _, update := range updates {
+		if err := runSystemCommand(ctx, false, c.command, "pull", update.ref); err != nil {
+			result.FailedCount++
+			result.Errors = append(result.Errors, fmt.Errorf("%s: %w", update.ref, err))
+
+			continue
+		}
+
+		result.UpdatedCount++
+		result.Packages = append(result.Packages, PackageInfo{
+			Name:           update.ref,
+			CurrentVersion: update.current,
+			NewVersion:     update.latest,
+		})
+	}
+
+	// 置き換えられた古いイメージはタグが外れて dangling になる
+	if c.prune && result.UpdatedCount > 0 {
+		if err := runSystemCommand(ctx, false, c.command, "image", "prune", "--force"); err != nil {
+			result.Errors = append(result.Errors, fmt.Errorf("%s image prune に失敗: %w", c.command, err))
+		}
+	}
+
+	if result.FailedCount > 0 {
+		result.Message = fmt.Sprintf("%d 件更新、%d 件失敗", result.UpdatedCount, result.FailedCount)
+	} else {
+		result.Message = fmt.Sprintf("%d 件のイメージを更新しました", result.UpdatedCount)
+	}
+
+	result.Message += skippedNote
+
+	return result, nil
+}
+
+// planUpdates はローカルのイメージとレジストリのダイジェストを比較して更新が必要なイメージを求めます。
+// 戻り値の failed はレジストリでダイジェストを確認できなかった件数、skipped は認証情報がないため確認できなかった件数です。
+// レジストリの認証情報は docker login / podman login で保存されたものを使用します。
+func (c *ContainerImageUpdater) planUpdates(ctx context.Context) (updates []containerImageUpdate, failed, skipped int, err error) {
+	images, err := c.localImages(ctx)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	credentials := make(map[string]*registryCredential)
+
+	for _, image := range images {
+		ref, err := parseImageReference(image)
+		// podman はローカルでビルドしたイメージを localhost/ 付きで表示する
+		if err != nil || ref.registry == "localhost" {
+			continue
+		}
+
+		localDigests, err := c.localDigests(ctx, image)
+		if err != nil {
+			failed++
+			continue
+		}
+
+		// ローカルでビルドしたイメージなど、レジストリ由来でないものは対象外
+		if len(localDigests) == 0 {
+			continue
+		}
+
+		credential, looked := credentials[ref.registry]
+		if !looked {
+			if found, ok := lookupRegistryCredential(ctx, c.command, ref.registry); ok {
+				credential = &found
+			}
+
+			credentials[ref.registry] = credential
+		}
+
+		remote, err := remoteImageDigest(ctx, ref, credential)
+		if errors.Is(err, errRegistryAuthRequired) {
+			skipped++
+			continue
+		}
+
+		if err != nil {
+			failed++
+			continue
+		}
+
+		if containsString(localDigests, remote) {
+			continue
+		}
+
+		updates = append(updates, containerImageUpdate{
+			ref:     image,
+			current: shortImageDigest(localDigests[0]),
+			latest:  shortImageDigest(remote),
+		})
+	}
+
+	return updates, failed, skipped, nil
+}
+
+// localImages はローカルのタグ付きイメージを返します。images が設定されている場合はそれに一致するものに限定します。
+func (c *ContainerImageUpdater) localImages(ctx context.Context) ([]string, error) {
+	output, err := runCommandOutputWithLocaleC(ctx, c.command,
+		[]string{"image", "ls", "--format", "{{.Repository}}:{{.Tag}}"},
+		c.command+" image ls の実行に失敗: %w")
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := make(map[string]bool, len(c.images))
+
+	for _, image := range c.images {
+		if ref, err := parseImageReference(image); err == nil {
+			wanted[ref.String()] = true
+		}
+	}
+
+	seen := make(map[string]bool)
+
+	var images []string
+
+	for _, line := range strings.Split(string(output), "\n") {
+		image := strings.TrimSpace(line)
+		if image == "" || strings.Contains(image, "<none>") || seen[image] {
+			continue
+		}
+
+		seen[image] = true
+
+		if len(wanted) > 0 {
+			ref, err := parseImageReference(image)
+			if err != nil || !wanted[ref.String()] {
+				continue
+			}
+		}
+
+		images = append(images, image)
+	}
+
+	return images, nil
+}
+
+// localDigests はイメージの RepoDigests（name@sha256:...）からダイジェスト部分を返します。
+func (c *ContainerImageUpdater) localDigests(ctx context.Context, image string) ([]string, error) {
+	output, err := runCommandOutputWithLocaleC(ctx, c.command,
+		[]string{"image", "inspect", "--format", "{{json .RepoDigests}}", image},
+		c.command+" image inspect の実行に失敗: %w")
+	if err != nil {
+		return nil, err
+	}
+
+	var repoDigests []string
+	if err := json.Unmarshal([]byte(strings.TrimSpace(string(output))), &repoDigests); err != nil {
+		return nil, fmt.Errorf("%s image inspect の出力解析に失敗: %w", c.command, err)
+	}
+
+	digests := make([]string, 0, len(repoDigests))
+
+	for _, repoDigest := range repoDigests {
+		if _, digest, ok := strings.Cut(repoDigest, "@"); ok {
+			digests = append(digests, digest)
+		}
+	}
+
+	return digests, nil
+}
+
+// shortImageDigest は表示用にダイジェストを短縮します（例: sha256:0123456789ab）。
+func shortImageDigest(digest string) string {
+	algorithm, hex, ok := strings.Cut(digest, ":")
+	if !ok || len(hex) <= 12 {
+		return digest
+	}
+
+	return algorithm + ":" + hex[:12]
+}
+
+func containerUpdatesToPackages(updates []containerImageUpdate) []PackageInfo {
+	packages := make([]PackageInfo, 0, len(updates))
+
+	for _, update := range updates {
+		packages = append(packages, PackageInfo{
+			Name:           update.ref,
+			CurrentVersion: update.current,
+			NewVersion:     update.latest,
+		})
+	}
+
+	return packages
+}
@@ -0,0 +1,172 @@
+package updater
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// errRegistryAuthRequired はレジストリへの問い合わせに認証が必要で、使用できる認証情報がないことを表します。
+var errRegistryAuthRequired = errors.New("認証が必要なレジストリです（認証情報が見つからないか、権限がありません）")
+
+// dockerHubServerURL は Docker Hub の認証情報を保存するときのサーバー名です（docker login の既定）。
+const dockerHubServerURL = "https://index.docker.io/v1/"
+
+// registryCredential はレジストリの認証情報です。
+type registryCredential struct {
+	username string
+	password string
+}
+
+// containerAuthFile は docker の config.json / podman の auth.json のうち認証情報に関する部分です。
+type containerAuthFile struct {
+	Auths map[string]struct {
+		Auth string `json:"auth"`
+	} `json:"auths"`
+	CredsStore  string            `json:"credsStore"`
+	CredHelpers map[string]string `json:"credHelpers"`
+}
+
+// lookupRegistryCredential は docker login / podman login で保存された registry の認証情報を返します。
+// 認証ファイルの auths に加え、credsStore / credHelpers の認証ヘルパー（docker-credential-*）にも対応します。
+func lookupRegistryCredential(ctx context.Context, command, registry string) (registryCredential, bool) {
+	for _, path := range containerAuthFiles(command) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+
+		var file containerAuthFile
+		if err := json.Unmarshal(data, &file); err != nil {
+			continue
+		}
+
+		if credential, ok := file.credential(ctx, registry); ok {
+			return credential, true
+		}
+	}
+
+	return registryCredential{}, false
+}
+
+// containerAuthFiles は command（docker / podman）が参照する認証ファイルを優先順に返します。
+// podman は自身の auth.json に認証情報がない場合、docker の config.json も参照します。
+func containerAuthFiles(command string) []string {
+	var files []string
+
+	home, homeErr := os.UserHomeDir()
+
+	if command == "podman" {
+		if path := os.Getenv("REGISTRY_AUTH_FILE"); path != "" {
+			files = append(files, path)
+		}
+
+		if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+			files = append(files, filepath.Join(dir, "containers", "auth.json"))
+		}
+
+		if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+			files = append(files, filepath.Join(dir, "containers", "auth.json"))
+		} else if homeErr == nil {
+			files = append(files, filepath.Join(home, ".config", "containers", "auth.json"))
+		}
+	}
+
+	if dir := os.Getenv("DOCKER_CONFIG"); dir != "" {
+		files = append(files, filepath.Join(dir, "config.json"))
+	} else if homeErr == nil {
+		files = append(files, filepath.Join(home, ".docker", "config.json"))
+	}
+
+	return files
+}
+
+// credential は registry の認証情報を auths、credHelpers、credsStore の順に探します。
+func (f containerAuthFile) credential(ctx context.Context, registry string) (registryCredential, bool) {
+	for server, entry := range f.Auths {
+		if entry.Auth == "" || normalizeRegistryHost(server) != registry {
+			continue
+		}
+
+		decoded, err := base64.StdEncoding.DecodeString(entry.Auth)
+		if err != nil {
+			continue
+		}
+
+		if username, password, ok := strings.Cut(string(decoded), ":"); ok {
+			return registryCredential{username: username, password: password}, true
+		}
+	}
+
+	helper := f.CredsStore
+
+	for server, name := range f.CredHelpers {
+		if normalizeRegistryHost(server) == registry {
+			helper = name
+			break
+		}
+	}
+
+	if helper == "" {
+		return registryCredential{}, false
+	}
+
+	return credentialFromHelper(ctx, helper, registry)
+}
+
+// credentialFromHelper は docker-credential-<helper> get で認証情報を取得します。
+// ID トークン（Username が "<token>"）はレジストリごとの OAuth 手順が必要なため使用しません。
+func credentialFromHelper(ctx context.Context, helper, registry string) (registryCredential, bool) {
+	server := registry
+	if registry == dockerHubRegistry {
+		server = dockerHubServerURL
+	}
+
+	cmd := exec.CommandContext(ctx, "docker-credential-"+helper, "get")
+	cmd.Stdin = strings.NewReader(server)
+
+	output, err := cmd.Output()
+	if err != nil {
+		return registryCredential{}, false
+	}
+
+	var response struct {
+		Username string `json:"Username"`
+		Secret   string `json:"Secret"`
+	}
+
+	if err := json.Unmarshal(bytes.TrimSpace(output), &response); err != nil {
+		return registryCredential{}, false
+	}
+
+	if response.Secret == "" || response.Username == "<token>" {
+		return registryCredential{}, false
+	}
+
+	return registryCredential{username: response.Username, password: response.Secret}, true
+}
+
+// normalizeRegistryHost は認証ファイルのサーバー名（例: https://index.docker.io/v1/）を
+// imageReference の registry と同じ形式（例: registry-1.docker.io）に揃えます。
+func normalizeRegistryHost(server string) string {
+	host := strings.TrimPrefix(strings.TrimPrefix(server, "https://"), "http://")
+	host, _, _ = strings.Cut(host, "/")
+
+	switch host {
+	case "docker.io", "index.docker.io":
+		return dockerHubRegistry
+	}
+
+	return host
+}
+
+// basicAuthorization は Authorization ヘッダーの Basic 認証の値を返します。
+func (c registryCredential) basicAuthorization() string {
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(c.username+":"+c.password))
+}
@@ -0,0 +1,105 @@
+package updater
+
+import (
+	"context"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeRegistryHost(t *testing.T) {
+	assert.Equal(t, dockerHubRegistry, normalizeRegistryHost("https://index.docker.io/v1/"))
+	assert.Equal(t, dockerHubRegistry, normalizeRegistryHost("docker.io"))
+	assert.Equal(t, "ghcr.io", normalizeRegistryHost("ghcr.io"))
+	assert.Equal(t, "localhost:5000", normalizeRegistryHost("http://localhost:5000"))
+}
+
+func TestLookupRegistryCredential(t *testing.T) {
+	dockerConfig := setupContainerAuthEnv(t)
+
+	auth := base64.StdEncoding.EncodeToString([]byte("docker-user:docker-pass"))
+	writeContainerAuthFile(t, filepath.Join(dockerConfig, "config.json"), `{
+  "auths": {
+    "https://index.docker.io/v1/": {"auth": "`+auth+`"},
+    "ghcr.io": {"auth": "`+auth+`"},
+    "quay.io": {}
+  }
+}`)
+
+	credential, ok := lookupRegistryCredential(context.Background(), "docker", dockerHubRegistry)
+	require.True(t, ok)
+	assert.Equal(t, registryCredential{username: "docker-user", password: "docker-pass"}, credential)
+
+	_, ok = lookupRegistryCredential(context.Background(), "docker", "quay.io")
+	assert.False(t, ok, "auth のないエントリ（認証ヘルパーに保存済み）は使わない")
+
+	t.Run("podman は auth.json を優先する", func(t *testing.T) {
+		podmanAuth := filepath.Join(t.TempDir(), "auth.json")
+		t.Setenv("REGISTRY_AUTH_FILE", podmanAuth)
+		writeContainerAuthFile(t, podmanAuth, `{"auths": {"ghcr.io": {"auth": "`+
+			base64.StdEncoding.EncodeToString([]byte("podman-user:podman-pass"))+`"}}}`)
+
+		credential, ok := lookupRegistryCredential(context.Background(), "podman", "ghcr.io")
+		require.True(t, ok)
+		assert.Equal(t, "podman-user", credential.username)
+
+		credential, ok = lookupRegistryCredential(context.Background(), "podman", dockerHubRegistry)
+		require.True(t, ok, "auth.json にない場合は docker の config.json を参照する")
+		assert.Equal(t, "docker-user", credential.username)
+	})
+}
+
+func TestLookupRegistryCredential_Helper(t *testing.T) {
+	if runtime.GOOS == windowsOS {
+		t.Skip("fake docker-credential-* は POSIX シェル前提")
+	}
+
+	dockerConfig := setupContainerAuthEnv(t)
+	writeContainerAuthFile(t, filepath.Join(dockerConfig, "config.json"), `{
+  "credsStore": "fake",
+  "credHelpers": {"123456789012.dkr.ecr.us-east-1.amazonaws.com": "ecr-fake"}
+}`)
+
+	// 標準入力のサーバー名をユーザー名として返す
+	helper := `#!/bin/sh
+read server
+echo "{\"ServerURL\":\"${server}\",\"Username\":\"${server}\",\"Secret\":\"$(basename "$0")\"}"
+`
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "docker-credential-fake"), []byte(helper), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "docker-credential-ecr-fake"), []byte(helper), 0o755))
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	credential, ok := lookupRegistryCredential(context.Background(), "docker", "123456789012.dkr.ecr.us-east-1.amazonaws.com")
+	require.True(t, ok)
+	assert.Equal(t, registryCredential{username: "123456789012.dkr.ecr.us-east-1.amazonaws.com", password: "docker-credential-ecr-fake"}, credential)
+
+	credential, ok = lookupRegistryCredential(context.Background(), "docker", dockerHubRegistry)
+	require.True(t, ok)
+	assert.Equal(t, registryCredential{username: dockerHubServerURL, password: "docker-credential-fake"}, credential)
+}
+
+// setupContainerAuthEnv は認証ファイルの参照先を一時ディレクトリに向け、docker の設定ディレクトリを返します。
+func setupContainerAuthEnv(t *testing.T) string {
+	t.Helper()
+
+	dockerConfig := t.TempDir()
+	t.Setenv("DOCKER_CONFIG", dockerConfig)
+	t.Setenv("REGISTRY_AUTH_FILE", "")
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	return dockerConfig
+}
+
+func writeContainerAuthFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
@@ -0,0 +1,274 @@
+package updater
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// dockerHubRegistry は Docker Hub のレジストリ API のホストです（docker.io は API を提供しない）。
+const dockerHubRegistry = "registry-1.docker.io"
+
+// containerRegistryHTTPClient はコンテナレジストリへの問い合わせに使用する HTTP クライアントです。
+var containerRegistryHTTPClient = &http.Client{Timeout: 30 * time.Second}
+
+// manifestMediaTypes はダイジェスト取得時に受け付けるマニフェストの種類です。
+// マルチアーキテクチャのイメージはインデックスのダイジェストがローカルの RepoDigests に記録されるため、インデックスを優先します。
+var manifestMediaTypes = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+}
+
+// imageReference はイメージ参照（例: postgres:16）をレジストリ API 用に分解したものです。
+type imageReference struct {
+	registry   string
+	repository string
+	tag        string
+}
+
+// parseImageReference はイメージ参照を解釈します。ダイジェスト指定（name@sha256:...）は更新対象外のためエラーとします。
+func parseImageReference(ref string) (imageReference, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" || strings.Contains(ref, "@") {
+		return imageReference{}, fmt.Errorf("タグ付きのイメージ参照ではありません: %q", ref)
+	}
+
+	name, tag := ref, "latest"
+	if idx := strings.LastIndex(ref, ":"); idx > strings.LastIndex(ref, "/") {
+		name, tag = ref[:idx], ref[idx+1:]
+	}
+
+	registry := dockerHubRegistry
+	repository := name
+
+	if first, rest, ok := strings.Cut(name, "/"); ok && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		registry, repository = first, rest
+	}
+
+	if registry == "docker.io" || registry == "index.docker.io" {
+		registry = dockerHubRegistry
+	}
+
+	if registry == dockerHubRegistry && !strings.Contains(repository, "/") {
+		repository = "library/" + repository
+	}
+
+	if repository == "" || tag == "" {
+		return imageReference{}, fmt.Errorf("不正なイメージ参照です: %q", ref)
+	}
+
+	return imageReference{registry: registry, repository: repository, tag: tag}, nil
+}
+
+// String は正規化した参照（例: registry-1.docker.io/library/postgres:16）を返します。
+func (r imageReference) String() string {
+	return r.registry + "/" + r.repository + ":" + r.tag
+}
+
+// manifestURL はタグのマニフェストの URL を返します。ループバックのレジストリは docker と同様に HTTP で接続します。
+func (r imageReference) manifestURL() string {
+	scheme := "https"
+
+	host := r.registry
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	if host == "localhost" || net.ParseIP(host).IsLoopback() {
+		scheme = "http"
+	}
+
+	return fmt.Sprintf("%s://%s/v2/%s/manifests/%s", scheme, r.registry, r.repository, r.tag)
+}
+
+// remoteImageDigest はレジストリ上のタグのダイジェストを返します。
+// トークン認証（Docker Hub / ghcr.io など）と Basic 認証に対応し、credential が nil の場合は匿名で問い合わせます。
+// 認証情報がない、または権限がない場合は errRegistryAuthRequired を返します。
+func remoteImageDigest(ctx context.Context, ref imageReference, credential *registryCredential) (string, error) {
+	resp, err := headManifest(ctx, ref, "")
+	if err != nil {
+		return "", err
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		authorization, err := registryAuthorization(ctx, resp.Header.Get("WWW-Authenticate"), credential)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", ref, err)
+		}
+
+		if resp, err = headManifest(ctx, ref, authorization); err != nil {
+			return "", err
+		}
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return "", fmt.Errorf("%s: %w", ref, errRegistryAuthRequired)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("レジストリが %s を返しました: %s", resp.Status, ref)
+	}
+
+	digest := strings.TrimSpace(resp.Header.Get("Docker-Content-Digest"))
+	if digest == "" {
+		return "", fmt.Errorf("レジストリの応答にダイジェストがありません: %s", ref)
+	}
+
+	return digest, nil
+}
+
+// registryAuthorization は WWW-Authenticate のチャレンジに応じた Authorization ヘッダーの値を返します。
+func registryAuthorization(ctx context.Context, challenge string, credential *registryCredential) (string, error) {
+	if strings.HasPrefix(strings.TrimSpace(challenge), "Basic") {
+		if credential == nil {
+			return "", errRegistryAuthRequired
+		}
+
+		return credential.basicAuthorization(), nil
+	}
+
+	token, err := fetchRegistryToken(ctx, challenge, credential)
+	if err != nil {
+		return "", err
+	}
+
+	return "Bearer " + token, nil
+}
+
+func headManifest(ctx context.Context, ref imageReference, authorization string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, ref.manifestURL(), http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", strings.Join(manifestMediaTypes, ", "))
+
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+
+	resp, err := containerRegistryHTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("レジストリへの問い合わせに失敗: %w", err)
+	}
+
+	resp.Body.Close()
+
+	return resp, nil
+}
+
+// fetchRegistryToken は WWW-Authenticate の Bearer チャレンジに従ってトークンを取得します。
+// credential がある場合は Basic 認証でトークンを要求し、ない場合は匿名のトークンを要求します。
+func fetchRegistryToken(ctx context.Context, challenge string, credential *registryCredential) (string, error) {
+	params := parseBearerChallenge(challenge)
+
+	realm := params["realm"]
+	if realm == "" {
+		return "", errRegistryAuthRequired
+	}
+
+	query := url.Values{}
+	for _, key := range []string{"service", "scope"} {
+		if value := params[key]; value != "" {
+			query.Set(key, value)
+		}
+	}
+
+	tokenURL := realm
+	if len(query) > 0 {
+		tokenURL += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, tokenURL, http.NoBody)
+	if err != nil {
+		return "", err
+	}
+
+	if credential != nil {
+		req.SetBasicAuth(credential.username, credential.password)
+	}
+
+	resp, err := containerRegistryHTTPClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("トークンの取得に失敗: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return "", errRegistryAuthRequired
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("トークンの取得に失敗: %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("トークンの取得に失敗: %w", err)
+	}
+
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+
+	if err := json.Unmarshal(body, &token); err != nil {
+		return "", fmt.Errorf("トークン応答の解析に失敗: %w", err)
+	}
+
+	if token.Token != "" {
+		return token.Token, nil
+	}
+
+	if token.AccessToken != "" {
+		return token.AccessToken, nil
+	}
+
+	return "", fmt.Errorf("トークン応答にトークンがありません")
+}
+
+// parseBearerChallenge は `Bearer realm="...",service="...",scope="..."` を解釈します。
+func parseBearerChallenge(challenge string) map[string]string {
+	params := make(map[string]string)
+
+	rest, ok := strings.CutPrefix(strings.TrimSpace(challenge), "Bearer ")
+	if !ok {
+		return params
+	}
+
+	for rest != "" {
+		key, value, found := strings.Cut(rest, "=")
+		if !found {
+			break
+		}
+
+		key = strings.ToLower(strings.TrimSpace(strings.TrimLeft(key, ", ")))
+		value = strings.TrimSpace(value)
+
+		if strings.HasPrefix(value, `"`) {
+			end := strings.Index(value[1:], `"`)
+			if end < 0 {
+				params[key] = value[1:]
+				break
+			}
+
+			params[key] = value[1 : end+1]
+			rest = value[end+2:]
+
+			continue
+		}
+
+		value, rest, _ = strings.Cut(value, ",")
+		params[key] = value
+	}
+
+	return params
+}
@@ -0,0 +1,162 @@
+package updater
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseImageReference(t *testing.T) {
+	testCases := []struct {
+		name      string
+		ref       string
+		want      string
+		expectErr bool
+	}{
+		{name: "公式イメージ", ref: "postgres:16", want: "registry-1.docker.io/library/postgres:16"},
+		{name: "タグ省略は latest", ref: "redis", want: "registry-1.docker.io/library/redis:latest"},
+		{name: "Docker Hub のユーザーイメージ", ref: "bitnami/redis:7.2", want: "registry-1.docker.io/bitnami/redis:7.2"},
+		{name: "podman の完全修飾名", ref: "docker.io/library/postgres:16", want: "registry-1.docker.io/library/postgres:16"},
+		{name: "他のレジストリ", ref: "ghcr.io/owner/tool:v1", want: "ghcr.io/owner/tool:v1"},
+		{name: "ポート付きレジストリ", ref: "localhost:5000/app", want: "localhost:5000/app:latest"},
+		{name: "ダイジェスト指定はエラー", ref: "postgres@sha256:0123", expectErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parseImageReference(tc.ref)
+			if tc.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got.String())
+		})
+	}
+}
+
+func TestImageReference_ManifestURL(t *testing.T) {
+	ref, err := parseImageReference("postgres:16")
+	require.NoError(t, err)
+	assert.Equal(t, "https://registry-1.docker.io/v2/library/postgres/manifests/16", ref.manifestURL())
+
+	ref, err = parseImageReference("127.0.0.1:5000/app:1")
+	require.NoError(t, err)
+	assert.Equal(t, "http://127.0.0.1:5000/v2/app/manifests/1", ref.manifestURL(), "ループバックのレジストリは HTTP")
+}
+
+func TestParseBearerChallenge(t *testing.T) {
+	got := parseBearerChallenge(`Bearer realm="https://auth.docker.io/token",service="registry.docker.io",scope="repository:library/postgres:pull"`)
+	assert.Equal(t, map[string]string{
+		"realm":   "https://auth.docker.io/token",
+		"service": "registry.docker.io",
+		"scope":   "repository:library/postgres:pull",
+	}, got)
+
+	assert.Empty(t, parseBearerChallenge(`Basic realm="registry"`))
+}
+
+func TestRemoteImageDigest(t *testing.T) {
+	server := newFakeContainerRegistry(t, map[string]string{"app:1": "sha256:new"})
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	ref, err := parseImageReference(host + "/app:1")
+	require.NoError(t, err)
+
+	digest, err := remoteImageDigest(context.Background(), ref, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "sha256:new", digest, "匿名トークンを取得して再問い合わせする")
+
+	ref, err = parseImageReference(host + "/missing:1")
+	require.NoError(t, err)
+
+	_, err = remoteImageDigest(context.Background(), ref, nil)
+	assert.ErrorContains(t, err, "404")
+}
+
+func TestRemoteImageDigest_PrivateRepository(t *testing.T) {
+	server := newFakeContainerRegistry(t, map[string]string{"private/app:1": "sha256:private"})
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	ref, err := parseImageReference(host + "/private/app:1")
+	require.NoError(t, err)
+
+	_, err = remoteImageDigest(context.Background(), ref, nil)
+	require.ErrorIs(t, err, errRegistryAuthRequired, "匿名トークンでは権限がない")
+
+	_, err = remoteImageDigest(context.Background(), ref, &registryCredential{username: fakeRegistryUser, password: "wrong"})
+	require.ErrorIs(t, err, errRegistryAuthRequired)
+
+	digest, err := remoteImageDigest(context.Background(), ref, &registryCredential{username: fakeRegistryUser, password: fakeRegistryPassword})
+	require.NoError(t, err)
+	assert.Equal(t, "sha256:private", digest)
+}
+
+// fake レジストリの private/ 以下のリポジトリを読み取れる認証情報
+const (
+	fakeRegistryUser     = "devsync"
+	fakeRegistryPassword = "secret"
+)
+
+// newFakeContainerRegistry はトークン認証を要求するレジストリ API のスタンドインを起動します。
+// digests は "リポジトリ:タグ" ごとのダイジェストです。private/ 以下のリポジトリは認証情報付きで取得したトークンが必要です。
+func newFakeContainerRegistry(t *testing.T, digests map[string]string) *httptest.Server {
+	t.Helper()
+
+	var server *httptest.Server
+
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			username, password, ok := r.BasicAuth()
+
+			switch {
+			case !ok:
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"token":"anonymous"}`))
+			case username == fakeRegistryUser && password == fakeRegistryPassword:
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"token":"authorized"}`))
+			default:
+				w.WriteHeader(http.StatusUnauthorized)
+			}
+
+			return
+		}
+
+		repository, tag, ok := strings.Cut(strings.TrimPrefix(r.URL.Path, "/v2/"), "/manifests/")
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		authorization := r.Header.Get("Authorization")
+		allowed := authorization == "Bearer authorized" ||
+			(authorization == "Bearer anonymous" && !strings.HasPrefix(repository, "private/"))
+
+		if !allowed {
+			w.Header().Set("WWW-Authenticate",
+				`Bearer realm="`+server.URL+`/token",service="fake",scope="repository:`+repository+`:pull"`)
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		digest, ok := digests[repository+":"+tag]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Docker-Content-Digest", digest)
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
@@ -0,0 +1,149 @@
+package updater
+
+import (
+	"context"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/scottlz0310/devsync/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuiltinContainerUpdaters(t *testing.T) {
+	names := make([]string, 0, 2)
+	for _, c := range builtinContainerUpdaters() {
+		names = append(names, c.Name())
+	}
+
+	assert.Equal(t, []string{"docker", "podman"}, names)
+}
+
+func TestContainerImageUpdater_Configure(t *testing.T) {
+	c := NewContainerImageUpdater("docker", "Docker")
+
+	require.NoError(t, c.Configure(config.ManagerConfig{"images": []interface{}{"postgres:16", " redis "}, "prune": true}))
+	assert.Equal(t, []string{"postgres:16", "redis"}, c.images)
+	assert.True(t, c.prune)
+
+	assert.Error(t, c.Configure(config.ManagerConfig{"images": []interface{}{"postgres@sha256:0123"}}))
+}
+
+func TestContainerImageUpdater_CheckAndUpdate(t *testing.T) {
+	server := newFakeContainerRegistry(t, map[string]string{
+		"app:1":         "sha256:0123456789abcdef0000",
+		"tool:latest":   "sha256:current",
+		"private/app:1": "sha256:private",
+	})
+	registry := strings.TrimPrefix(server.URL, "http://")
+	logPath := writeFakeContainerCommand(t, "podman", registry)
+
+	c := NewContainerImageUpdater("podman", "Podman")
+
+	checkResult, err := c.Check(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []PackageInfo{
+		{Name: registry + "/app:1", CurrentVersion: "sha256:old", NewVersion: "sha256:0123456789ab"},
+	}, checkResult.Packages, "ダイジェストが一致するイメージ・ローカルビルドのイメージは対象外")
+	assert.Contains(t, checkResult.Message, "1 件は認証情報がないため確認をスキップしました")
+
+	require.NoError(t, c.Configure(config.ManagerConfig{"prune": true}))
+
+	result, err := c.Update(context.Background(), UpdateOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.UpdatedCount)
+	assert.Empty(t, result.Errors, "認証情報がなく確認できないイメージはエラーにしない")
+
+	data, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	assert.Equal(t, "podman pull "+registry+"/app:1\npodman image prune --force\n", string(data))
+}
+
+func TestContainerImageUpdater_UsesRegistryCredential(t *testing.T) {
+	server := newFakeContainerRegistry(t, map[string]string{
+		"app:1":         "sha256:old",
+		"tool:latest":   "sha256:current",
+		"private/app:1": "sha256:newer",
+	})
+	registry := strings.TrimPrefix(server.URL, "http://")
+	writeFakeContainerCommand(t, "docker", registry)
+
+	auth := base64.StdEncoding.EncodeToString([]byte(fakeRegistryUser + ":" + fakeRegistryPassword))
+	writeContainerAuthFile(t, filepath.Join(os.Getenv("DOCKER_CONFIG"), "config.json"),
+		`{"auths": {"`+registry+`": {"auth": "`+auth+`"}}}`)
+
+	checkResult, err := NewContainerImageUpdater("docker", "Docker").Check(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []PackageInfo{
+		{Name: registry + "/private/app:1", CurrentVersion: "sha256:private", NewVersion: "sha256:newer"},
+	}, checkResult.Packages, "docker login で保存した認証情報でプライベートなイメージを確認する")
+	assert.NotContains(t, checkResult.Message, "スキップ")
+}
+
+func TestContainerImageUpdater_ImagesFilter(t *testing.T) {
+	server := newFakeContainerRegistry(t, map[string]string{
+		"app:1":       "sha256:new",
+		"tool:latest": "sha256:new",
+	})
+	registry := strings.TrimPrefix(server.URL, "http://")
+	writeFakeContainerCommand(t, "docker", registry)
+
+	c := NewContainerImageUpdater("docker", "Docker")
+	require.NoError(t, c.Configure(config.ManagerConfig{"images": []interface{}{registry + "/tool"}}))
+
+	result, err := c.Update(context.Background(), UpdateOptions{DryRun: true})
+	require.NoError(t, err)
+	require.Len(t, result.Packages, 1)
+	assert.Equal(t, registry+"/tool:latest", result.Packages[0].Name)
+	assert.Contains(t, result.Message, "DryRun")
+}
+
+// writeFakeContainerCommand は docker 互換の fake CLI を PATH に配置し、実行ログのパスを返します。
+// ローカルには registry/app:1（古いダイジェスト）、registry/tool:latest、registry/private/app:1（認証が必要）、
+// ローカルビルドのイメージがある状態を再現します。認証ファイルは空の一時ディレクトリを参照します。
+func writeFakeContainerCommand(t *testing.T, command, registry string) string {
+	t.Helper()
+
+	if runtime.GOOS == windowsOS {
+		t.Skip("fake " + command + " は POSIX シェル前提")
+	}
+
+	setupContainerAuthEnv(t)
+
+	logPath := filepath.Join(t.TempDir(), "commands.log")
+	t.Setenv("DEVSYNC_TEST_REGISTRY", registry)
+	t.Setenv("DEVSYNC_TEST_CONTAINER_LOG", logPath)
+
+	script := `#!/bin/sh
+case "$1 $2" in
+  "image ls")
+    echo "${DEVSYNC_TEST_REGISTRY}/app:1"
+    echo "${DEVSYNC_TEST_REGISTRY}/tool:latest"
+    echo "${DEVSYNC_TEST_REGISTRY}/private/app:1"
+    echo "${DEVSYNC_TEST_REGISTRY}/app:1"
+    echo "<none>:<none>"
+    echo "localhost/devimage:dev"
+    echo "builder:dev"
+    ;;
+  "image inspect")
+    case "$5" in
+      */private/app:1) echo "[\"${DEVSYNC_TEST_REGISTRY}/private/app@sha256:private\"]" ;;
+      */app:1) echo "[\"${DEVSYNC_TEST_REGISTRY}/app@sha256:old\"]" ;;
+      */tool:latest) echo "[\"${DEVSYNC_TEST_REGISTRY}/tool@sha256:current\"]" ;;
+      *) echo "[]" ;;
+    esac
+    ;;
+  *) echo "` + command + ` $*" >> "${DEVSYNC_TEST_CONTAINER_LOG}" ;;
+esac
+`
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, command), []byte(script), 0o755))
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	return logPath
+}
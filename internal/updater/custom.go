@@ -0,0 +1,355 @@
+package updater
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/scottlz0310/devsync/internal/config"
+)
+
+// customUpdateCommandKey は設定だけで定義するカスタムマネージャの目印となるキーです。
+const customUpdateCommandKey = "update_command"
+
+// SudoRequirer は sudo の要否を自身で判断するマネージャが実装します。
+type SudoRequirer interface {
+	// RequiresSudo は更新に sudo が必要な場合に true を返します。
+	RequiresSudo() bool
+}
+
+// ExclusiveRunner は他のマネージャと並列に実行できないマネージャが実装します。
+type ExclusiveRunner interface {
+	// RunsExclusively は単独実行フェーズで実行する必要がある場合に true を返します。
+	RunsExclusively() bool
+}
+
+// CommandUpdater は sys.managers.<name> の設定だけで定義するカスタムマネージャです。
+// 例: tldr --update、gcloud components update、omz update など専用の Updater がないツール向けです。
+//
+//	sys:
+//	  managers:
+//	    gcloud:
+//	      display_name: "Google Cloud SDK"
+//	      available_command: "gcloud --version"
+//	      check_command: "gcloud components list --filter=state.name='Update Available' --format='value(id,current_version_string,latest_version_string)'"
+//	      outdated_pattern: '^(?P<name>\S+)\s+(?P<current>\S+)\s+(?P<new>\S+)$'
+//	      update_command: "gcloud components update --quiet"
+//	      use_sudo: false
+//	      exclusive: false
+type CommandUpdater struct {
+	name             string
+	displayName      string
+	availableCommand string
+	checkCommand     string
+	updateCommand    string
+	outdatedPattern  *regexp.Regexp
+	useSudo          bool
+	exclusive        bool
+}
+
+// NewCommandUpdater は設定からカスタムマネージャを作成します。
+func NewCommandUpdater(name string, cfg config.ManagerConfig) (*CommandUpdater, error) {
+	c := &CommandUpdater{name: name}
+	if err := c.Configure(cfg); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// IsCustomCommandConfig は設定がカスタムマネージャの定義（update_command を含む）かを判定します。
+func IsCustomCommandConfig(cfg config.ManagerConfig) bool {
+	value, ok := cfg[customUpdateCommandKey].(string)
+
+	return ok && strings.TrimSpace(value) != ""
+}
+
+func (c *CommandUpdater) Name() string {
+	return c.name
+}
+
+func (c *CommandUpdater) DisplayName() string {
+	if c.displayName != "" {
+		return c.displayName
+	}
+
+	return fmt.Sprintf("%s (カスタムコマンド)", c.name)
+}
+
+// IsAvailable は available_command の終了コードが 0 の場合に true を返します。
+// 未指定の場合は update_command の先頭のコマンドが PATH 上にあるかで判定します。
+func (c *CommandUpdater) IsAvailable() bool {
+	if c.availableCommand == "" {
+		fields := strings.Fields(c.updateCommand)
+		if len(fields) == 0 {
+			return false
+		}
+
+		_, err := exec.LookPath(fields[0])
+
+		return err == nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pluginProbeTimeout)
+	defer cancel()
+
+	name, args := shellCommand(c.availableCommand)
+
+	return exec.CommandContext(ctx, name, args...).Run() == nil
+}
+
+func (c *CommandUpdater) Configure(cfg config.ManagerConfig) error {
+	if cfg == nil {
+		return nil
+	}
+
+	c.displayName = stringConfigValue(cfg, "display_name")
+	c.availableCommand = stringConfigValue(cfg, "available_command")
+	c.checkCommand = stringConfigValue(cfg, "check_command")
+	c.updateCommand = stringConfigValue(cfg, customUpdateCommandKey)
+
+	if c.updateCommand == "" {
+		return fmt.Errorf("%s: update_command が指定されていません", c.name)
+	}
+
+	c.outdatedPattern = nil
+
+	if pattern := stringConfigValue(cfg, "outdated_pattern"); pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("%s: outdated_pattern が不正です: %w", c.name, err)
+		}
+
+		c.outdatedPattern = re
+	}
+
+	if useSudo, ok := cfg["use_sudo"].(bool); ok {
+		c.useSudo = useSudo
+	}
+
+	if exclusive, ok := cfg["exclusive"].(bool); ok {
+		c.exclusive = exclusive
+	}
+
+	return nil
+}
+
+// RequiresSudo は use_sudo が有効な場合に true を返します。
+func (c *CommandUpdater) RequiresSudo() bool {
+	return c.useSudo
+}
+
+// RunsExclusively は exclusive が有効な場合に true を返します。
+func (c *CommandUpdater) RunsExclusively() bool {
+	return c.exclusive
+}
+
+// Check は check_command を実行し、outdated_pattern に一致した行を更新可能なパッケージとして返します。
+// check_command が未指定の場合は更新可否を事前に判定できないため、0 件として扱います。
+func (c *CommandUpdater) Check(ctx context.Context) (*CheckResult, error) {
+	if c.checkCommand == "" {
+		return &CheckResult{
+			Message: "check_command が未設定のため更新可否は実行時に判定します",
+		}, nil
+	}
+
+	name, args := shellCommand(c.checkCommand)
+	cmd := exec.CommandContext(ctx, name, args...)
+
+	var stderr bytes.Buffer
+
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf(
+			"%s の check_command の実行に失敗: %w",
+			c.name,
+			buildCommandOutputErr(err, combineCommandOutputs(output, stderr.Bytes())),
+		)
+	}
+
+	packages := c.parseCheckOutput(string(output))
+
+	return &CheckResult{
+		AvailableUpdates: len(packages),
+		Packages:         packages,
+	}, nil
+}
+
+func (c *CommandUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateResult, error) {
+	command, args := c.updateCommandLine()
+
+	if c.checkCommand == "" {
+		return c.updateWithoutCheck(ctx, opts, command, args)
+	}
+
+	checkResult, err := c.Check(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return runCountBasedUpdate(
+		ctx,
+		opts,
+		checkResult,
+		"すべてのパッケージは最新です",
+		func(count int) string {
+			return fmt.Sprintf("%d 件のパッケージが更新可能です（DryRunモード）", count)
+		},
+		command,
+		args,
+		c.name+" の update_command に失敗: %w",
+		func(count int) string {
+			return fmt.Sprintf("%d 件のパッケージを更新しました", count)
+		},
+	)
+}
+
+func (c *CommandUpdater) updateWithoutCheck(ctx context.Context, opts UpdateOptions, command string, args []string) (*UpdateResult, error) {
+	result := &UpdateResult{}
+
+	if opts.DryRun {
+		result.Message = fmt.Sprintf("%s を実行します（DryRunモード）", c.updateCommand)
+		return result, nil
+	}
+
+	cmd := exec.CommandContext(ctx, command, args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Stdin = os.Stdin
+
+	if err := cmd.Run(); err != nil {
+		result.Errors = append(result.Errors, err)
+		return result, fmt.Errorf("%s の update_command に失敗: %w", c.name, err)
+	}
+
+	result.Message = "更新コマンドを実行しました"
+
+	return result, nil
+}
+
+// updateCommandLine は update_command の実行コマンドを返します（use_sudo 時は sudo 経由）。
+func (c *CommandUpdater) updateCommandLine() (string, []string) {
+	name, args := shellCommand(c.updateCommand)
+	if !c.useSudo || runtime.GOOS == windowsOS {
+		return name, args
+	}
+
+	return "sudo", append([]string{name}, args...)
+}
+
+// parseCheckOutput は check_command の出力を解析します。
+// outdated_pattern が未指定の場合は空でない各行をパッケージ名とみなします。
+// パターンは名前付きグループ name / current / new、または先頭から順に 1〜3 番目のグループを使用します。
+func (c *CommandUpdater) parseCheckOutput(output string) []PackageInfo {
+	lines := strings.Split(strings.ReplaceAll(output, "\r\n", "\n"), "\n")
+	packages := make([]PackageInfo, 0, len(lines))
+
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+
+		if c.outdatedPattern == nil {
+			packages = append(packages, PackageInfo{Name: trimmed})
+			continue
+		}
+
+		match := c.outdatedPattern.FindStringSubmatch(trimmed)
+		if match == nil {
+			continue
+		}
+
+		packages = append(packages, PackageInfo{
+			Name:           submatchValue(c.outdatedPattern, match, "name", 1),
+			CurrentVersion: submatchValue(c.outdatedPattern, match, "current", 2),
+			NewVersion:     submatchValue(c.outdatedPattern, match, "new", 3),
+		})
+	}
+
+	return packages
+}
+
+func submatchValue(re *regexp.Regexp, match []string, name string, position int) string {
+	if index := re.SubexpIndex(name); index > 0 {
+		return match[index]
+	}
+
+	// 名前付きグループを使わないパターンは位置で対応付ける
+	for _, subexpName := range re.SubexpNames()[1:] {
+		if subexpName != "" {
+			return ""
+		}
+	}
+
+	if position < len(match) {
+		return match[position]
+	}
+
+	if position == 1 {
+		return match[0]
+	}
+
+	return ""
+}
+
+// shellCommand はコマンド文字列をシェル経由で実行するための引数を返します。
+func shellCommand(command string) (string, []string) {
+	if runtime.GOOS == windowsOS {
+		return "cmd", []string{"/C", command}
+	}
+
+	return "sh", []string{"-c", command}
+}
+
+// registerCustomCommands は update_command を含む sys.managers の定義を CommandUpdater として登録します。
+// 組み込みマネージャ・プラグインと同名の定義は登録せず、問題として返します。
+func registerCustomCommands(cfg *config.SysConfig, plugins map[string]string) []string {
+	if cfg == nil {
+		return nil
+	}
+
+	var problems []string
+
+	for _, name := range sortedManagerNames(cfg.Managers) {
+		managerCfg := cfg.Managers[name]
+		if !IsCustomCommandConfig(managerCfg) {
+			continue
+		}
+
+		if _, isPlugin := plugins[name]; isPlugin {
+			problems = append(problems, fmt.Sprintf("%s: プラグインと update_command は同時に指定できません（プラグインを優先）", name))
+			continue
+		}
+
+		if existing, ok := Get(name); ok {
+			if _, isCustom := existing.(*CommandUpdater); !isCustom {
+				problems = append(problems, fmt.Sprintf("%s: 組み込みマネージャと同名のため update_command を無視しました", name))
+				continue
+			}
+		}
+
+		custom, err := NewCommandUpdater(name, managerCfg)
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+
+		Register(custom)
+	}
+
+	return problems
+}
+
+func stringConfigValue(cfg config.ManagerConfig, key string) string {
+	value, _ := cfg[key].(string)
+
+	return strings.TrimSpace(value)
+}
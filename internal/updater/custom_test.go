@@ -0,0 +1,257 @@
+package updater
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/scottlz0310/devsync/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func skipCustomCommandOnWindows(t *testing.T) {
+	t.Helper()
+
+	if runtime.GOOS == windowsOS {
+		t.Skip("カスタムコマンドのテストは POSIX シェル前提")
+	}
+}
+
+func TestCommandUpdater_Configure(t *testing.T) {
+	testCases := []struct {
+		name        string
+		cfg         config.ManagerConfig
+		errContains string
+	}{
+		{
+			name:        "update_command未指定",
+			cfg:         config.ManagerConfig{"check_command": "echo tldr"},
+			errContains: "update_command が指定されていません",
+		},
+		{
+			name:        "outdated_patternが不正",
+			cfg:         config.ManagerConfig{"update_command": "tldr --update", "outdated_pattern": "("},
+			errContains: "outdated_pattern が不正です",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewCommandUpdater("custom", tc.cfg)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.errContains)
+		})
+	}
+
+	t.Run("表示名・sudo・単独実行", func(t *testing.T) {
+		c, err := NewCommandUpdater("firmware", config.ManagerConfig{
+			"update_command": "fwupdmgr update",
+			"use_sudo":       true,
+			"exclusive":      true,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "firmware (カスタムコマンド)", c.DisplayName())
+		assert.True(t, c.RequiresSudo())
+		assert.True(t, c.RunsExclusively())
+
+		name, args := c.updateCommandLine()
+		if runtime.GOOS != windowsOS {
+			assert.Equal(t, "sudo", name)
+			assert.Equal(t, []string{"sh", "-c", "fwupdmgr update"}, args)
+		}
+	})
+}
+
+func TestCommandUpdater_ParseCheckOutput(t *testing.T) {
+	output := "gcloud 470.0.0 471.0.0\r\nkubectl 1.29.0 1.30.1\n\nnoise\n"
+
+	testCases := []struct {
+		name    string
+		pattern string
+		want    []PackageInfo
+	}{
+		{
+			name:    "名前付きグループ",
+			pattern: `^(?P<name>\S+)\s+(?P<current>\d\S*)\s+(?P<new>\d\S*)$`,
+			want: []PackageInfo{
+				{Name: "gcloud", CurrentVersion: "470.0.0", NewVersion: "471.0.0"},
+				{Name: "kubectl", CurrentVersion: "1.29.0", NewVersion: "1.30.1"},
+			},
+		},
+		{
+			name:    "位置指定のグループ",
+			pattern: `^(\S+)\s+(\d\S*)\s+(\d\S*)$`,
+			want: []PackageInfo{
+				{Name: "gcloud", CurrentVersion: "470.0.0", NewVersion: "471.0.0"},
+				{Name: "kubectl", CurrentVersion: "1.29.0", NewVersion: "1.30.1"},
+			},
+		},
+		{
+			name:    "グループなしは一致全体を名前とする",
+			pattern: `^kubectl`,
+			want:    []PackageInfo{{Name: "kubectl"}},
+		},
+		{
+			name: "パターン未指定は各行を名前とする",
+			want: []PackageInfo{
+				{Name: "gcloud 470.0.0 471.0.0"},
+				{Name: "kubectl 1.29.0 1.30.1"},
+				{Name: "noise"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := config.ManagerConfig{"update_command": "gcloud components update"}
+			if tc.pattern != "" {
+				cfg["outdated_pattern"] = tc.pattern
+			}
+
+			c, err := NewCommandUpdater("gcloud", cfg)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, c.parseCheckOutput(output))
+		})
+	}
+}
+
+func TestCommandUpdater_IsAvailable(t *testing.T) {
+	skipCustomCommandOnWindows(t)
+
+	testCases := []struct {
+		name string
+		cfg  config.ManagerConfig
+		want bool
+	}{
+		{name: "update_commandの先頭コマンドがPATHにある", cfg: config.ManagerConfig{"update_command": "true --update"}, want: true},
+		{name: "update_commandの先頭コマンドがPATHにない", cfg: config.ManagerConfig{"update_command": "devsync-missing-tool --update"}, want: false},
+		{name: "available_commandが成功", cfg: config.ManagerConfig{"update_command": "devsync-missing-tool", "available_command": "exit 0"}, want: true},
+		{name: "available_commandが失敗", cfg: config.ManagerConfig{"update_command": "true", "available_command": "exit 1"}, want: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := NewCommandUpdater("custom", tc.cfg)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, c.IsAvailable())
+		})
+	}
+}
+
+func TestCommandUpdater_CheckAndUpdate(t *testing.T) {
+	skipCustomCommandOnWindows(t)
+
+	marker := filepath.Join(t.TempDir(), "updated")
+
+	t.Run("check_commandの結果で更新", func(t *testing.T) {
+		c, err := NewCommandUpdater("tools", config.ManagerConfig{
+			"check_command":    "printf 'alpha 1.0 1.1\\nbeta 2.0 2.1\\n'",
+			"outdated_pattern": `^(?P<name>\S+) (?P<current>\S+) (?P<new>\S+)$`,
+			"update_command":   "touch " + marker,
+		})
+		require.NoError(t, err)
+
+		checkResult, err := c.Check(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 2, checkResult.AvailableUpdates)
+
+		dryRun, err := c.Update(context.Background(), UpdateOptions{DryRun: true})
+		require.NoError(t, err)
+		assert.Equal(t, "2 件のパッケージが更新可能です（DryRunモード）", dryRun.Message)
+		assert.NoFileExists(t, marker)
+
+		result, err := c.Update(context.Background(), UpdateOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, 2, result.UpdatedCount)
+		assert.FileExists(t, marker)
+	})
+
+	t.Run("check_command未指定は更新コマンドのみ実行", func(t *testing.T) {
+		c, err := NewCommandUpdater("tldr", config.ManagerConfig{"update_command": "true"})
+		require.NoError(t, err)
+
+		checkResult, err := c.Check(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 0, checkResult.AvailableUpdates)
+
+		dryRun, err := c.Update(context.Background(), UpdateOptions{DryRun: true})
+		require.NoError(t, err)
+		assert.Equal(t, "true を実行します（DryRunモード）", dryRun.Message)
+
+		result, err := c.Update(context.Background(), UpdateOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "更新コマンドを実行しました", result.Message)
+	})
+
+	t.Run("check_commandの失敗", func(t *testing.T) {
+		c, err := NewCommandUpdater("broken", config.ManagerConfig{
+			"check_command":  "echo 'network down' 1>&2; exit 2",
+			"update_command": "true",
+		})
+		require.NoError(t, err)
+
+		_, err = c.Check(context.Background())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "network down")
+	})
+
+	t.Run("update_commandの失敗", func(t *testing.T) {
+		c, err := NewCommandUpdater("broken", config.ManagerConfig{"update_command": "exit 1"})
+		require.NoError(t, err)
+
+		result, err := c.Update(context.Background(), UpdateOptions{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "broken の update_command に失敗")
+		assert.Len(t, result.Errors, 1)
+	})
+}
+
+func TestRegisterCustomCommands(t *testing.T) {
+	clearRegistry()
+	t.Cleanup(clearRegistry)
+
+	t.Setenv("PATH", t.TempDir())
+	Register(&mockUpdater{name: "apt", displayName: "APT", available: true})
+
+	pluginPath := filepath.Join(t.TempDir(), PluginPrefix+"company")
+	require.NoError(t, os.WriteFile(pluginPath, []byte("#!/bin/sh\n"), 0o755))
+
+	cfg := &config.SysConfig{
+		Managers: map[string]config.ManagerConfig{
+			"tldr":    {"update_command": "tldr --update", "display_name": "tldr pages"},
+			"apt":     {"update_command": "apt-get upgrade"},
+			"company": {"update_command": "company update", "plugin": pluginPath},
+			"broken":  {"update_command": "broken", "outdated_pattern": "("},
+			"npm":     {"use_sudo": false},
+		},
+	}
+
+	err := RegisterExternal(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "apt: 組み込みマネージャと同名のため update_command を無視しました")
+	assert.Contains(t, err.Error(), "company: プラグインと update_command は同時に指定できません")
+	assert.Contains(t, err.Error(), "broken: outdated_pattern が不正です")
+
+	tldr, ok := Get("tldr")
+	require.True(t, ok)
+	assert.IsType(t, &CommandUpdater{}, tldr)
+	assert.Equal(t, "tldr pages", tldr.DisplayName())
+
+	apt, _ := Get("apt")
+	assert.IsType(t, &mockUpdater{}, apt)
+
+	company, _ := Get("company")
+	assert.IsType(t, &PluginUpdater{}, company)
+
+	_, ok = Get("npm")
+	assert.False(t, ok, "update_command のない設定は登録しない")
+
+	// 再登録では設定を反映した定義に差し替える
+	cfg.Managers["tldr"]["display_name"] = "TLDR"
+	_ = RegisterExternal(cfg)
+	again, _ := Get("tldr")
+	assert.Equal(t, "TLDR", again.DisplayName())
+}
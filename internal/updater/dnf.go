@@ -0,0 +1,187 @@
+package updater
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/scottlz0310/devsync/internal/config"
+)
+
+// dnfCheckUpdateExitCode は dnf check-update が更新可能なパッケージありの場合に返す終了コードです。
+const dnfCheckUpdateExitCode = 100
+
+// DnfUpdater は dnf パッケージマネージャ (Fedora/RHEL) の実装です。
+// security_only を有効にすると、セキュリティ勧告（updateinfo）のある更新のみを適用します。
+type DnfUpdater struct {
+	useSudo      bool
+	securityOnly bool
+}
+
+var _ SecurityOnlyUpdater = (*DnfUpdater)(nil)
+
+// 起動時にレジストリに登録
+func init() {
+	Register(&DnfUpdater{useSudo: true})
+}
+
+func (d *DnfUpdater) Name() string {
+	return "dnf"
+}
+
+func (d *DnfUpdater) DisplayName() string {
+	return "dnf (Fedora/RHEL)"
+}
+
+func (d *DnfUpdater) IsAvailable() bool {
+	_, err := exec.LookPath("dnf")
+	return err == nil
+}
+
+func (d *DnfUpdater) Configure(cfg config.ManagerConfig) error {
+	if cfg == nil {
+		return nil
+	}
+
+	d.useSudo = configureUseSudo(cfg, d.useSudo)
+
+	if securityOnly, ok := cfg["security_only"].(bool); ok {
+		d.securityOnly = securityOnly
+	}
+
+	return nil
+}
+
+func (d *DnfUpdater) SupportsSecurityOnly() bool {
+	return true
+}
+
+// Check は dnf check-update で更新可能なパッケージを確認します。
+// check-update --security の結果と照合し、セキュリティ更新のパッケージに印を付けます。
+func (d *DnfUpdater) Check(ctx context.Context) (*CheckResult, error) {
+	return d.check(ctx, d.securityOnly)
+}
+
+func (d *DnfUpdater) check(ctx context.Context, securityOnly bool) (*CheckResult, error) {
+	packages, err := d.checkUpdate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(packages) > 0 {
+		security, err := d.checkUpdate(ctx, "--security")
+		if err != nil {
+			return nil, err
+		}
+
+		names := make(map[string]bool, len(security))
+		for _, pkg := range security {
+			names[pkg.Name] = true
+		}
+
+		for i := range packages {
+			packages[i].Security = names[packages[i].Name]
+		}
+	}
+
+	if securityOnly {
+		packages = securityPackages(packages)
+	}
+
+	return &CheckResult{
+		AvailableUpdates: len(packages),
+		Packages:         packages,
+	}, nil
+}
+
+// checkUpdate は dnf check-update を実行して更新可能なパッケージを返します。
+// check-update は更新ありの場合に終了コード 100 を返すため、これを成功として扱います。
+func (d *DnfUpdater) checkUpdate(ctx context.Context, extraArgs ...string) ([]PackageInfo, error) {
+	args := append([]string{"check-update", "--quiet"}, extraArgs...)
+
+	cmd := exec.CommandContext(ctx, "dnf", args...)
+	cmd.Env = append(os.Environ(), "LANG=C", "LC_ALL=C")
+
+	var stderr bytes.Buffer
+
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil && exitCodeOf(err) != dnfCheckUpdateExitCode {
+		return nil, fmt.Errorf("dnf check-update の実行に失敗: %w", buildCommandOutputErr(err, combineCommandOutputs(output, stderr.Bytes())))
+	}
+
+	return d.parseCheckUpdate(string(output)), nil
+}
+
+func (d *DnfUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateResult, error) {
+	securityOnly := opts.SecurityOnly || d.securityOnly
+
+	checkResult, err := d.check(ctx, securityOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	upgradeArgs := []string{"upgrade", "-y"}
+	if securityOnly {
+		upgradeArgs = append(upgradeArgs, "--security")
+	}
+
+	command, args := systemCommandLine(d.useSudo, "dnf", upgradeArgs...)
+	upToDate, dryRunMessage, updatedMessage := securityUpdateMessages(securityOnly)
+
+	return runCountBasedUpdate(
+		ctx,
+		opts,
+		checkResult,
+		upToDate,
+		dryRunMessage,
+		command,
+		args,
+		"dnf upgrade に失敗: %w",
+		updatedMessage,
+	)
+}
+
+// parseCheckUpdate は "dnf check-update" の出力をパースします
+// 形式: "name.arch  version  repository"
+// 名前が長い場合は名前の行とバージョン・リポジトリの行に折り返されます。
+func (d *DnfUpdater) parseCheckUpdate(output string) []PackageInfo {
+	lines := strings.Split(output, "\n")
+	packages := make([]PackageInfo, 0, len(lines))
+
+	pendingName := ""
+
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		// "Obsoleting Packages" 以降は置き換えられるパッケージの一覧のため対象外
+		if strings.HasPrefix(line, "Obsoleting") {
+			break
+		}
+
+		if pendingName != "" && len(fields) == 2 {
+			fields = append([]string{pendingName}, fields...)
+		}
+
+		pendingName = ""
+
+		switch {
+		case len(fields) == 1 && strings.Contains(fields[0], "."):
+			pendingName = fields[0]
+		case len(fields) == 3 && strings.Contains(fields[0], "."):
+			packages = append(packages, PackageInfo{
+				Name:       fields[0][:strings.LastIndex(fields[0], ".")],
+				NewVersion: fields[1],
+			})
+		}
+	}
+
+	return packages
+}
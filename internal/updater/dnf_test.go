@@ -0,0 +1,183 @@
+package updater
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/scottlz0310/devsync/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDnfUpdater_NameAndConfigure(t *testing.T) {
+	d := &DnfUpdater{useSudo: true}
+	assert.Equal(t, "dnf", d.Name())
+	assert.Equal(t, "dnf (Fedora/RHEL)", d.DisplayName())
+
+	require.NoError(t, d.Configure(config.ManagerConfig{"use_sudo": false}))
+	assert.False(t, d.useSudo)
+
+	require.NoError(t, d.Configure(config.ManagerConfig{"sudo": true}))
+	assert.True(t, d.useSudo, "旧キーsudoも後方互換で受け付ける")
+}
+
+func TestDnfUpdater_parseCheckUpdate(t *testing.T) {
+	output := `
+kernel.x86_64                         6.8.9-300.fc40                 updates
+python3-very-long-package-name-for-wrapping.noarch
+                                      1.2.3-1.fc40                   updates
+vim-enhanced.x86_64                   2:9.1.393-1.fc40               updates
+Obsoleting Packages
+grub2-tools.x86_64                    1:2.06-121.fc40                updates
+    grub2-tools.x86_64                1:2.06-120.fc40                @updates
+`
+
+	d := &DnfUpdater{}
+	assert.Equal(t, []PackageInfo{
+		{Name: "kernel", NewVersion: "6.8.9-300.fc40"},
+		{Name: "python3-very-long-package-name-for-wrapping", NewVersion: "1.2.3-1.fc40"},
+		{Name: "vim-enhanced", NewVersion: "2:9.1.393-1.fc40"},
+	}, d.parseCheckUpdate(output))
+}
+
+func TestDnfUpdater_CheckAndUpdate(t *testing.T) {
+	testCases := []struct {
+		name        string
+		mode        string
+		opts        UpdateOptions
+		wantUpdated int
+		errContains string
+		msgContains string
+	}{
+		{name: "DryRunは更新せず計画表示", mode: "updates", opts: UpdateOptions{DryRun: true}, msgContains: "2 件のパッケージが更新可能です（DryRunモード）"},
+		{name: "更新なし", mode: "none", msgContains: "すべてのパッケージは最新です"},
+		{name: "更新成功", mode: "updates", wantUpdated: 2, msgContains: "2 件のパッケージを更新しました"},
+		{name: "check-update失敗", mode: "check_error", errContains: "dnf check-update の実行に失敗"},
+		{name: "upgrade失敗", mode: "upgrade_error", errContains: "dnf upgrade に失敗"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			writeFakeDnfCommand(t)
+			t.Setenv("DEVSYNC_TEST_DNF_MODE", tc.mode)
+
+			got, err := (&DnfUpdater{useSudo: false}).Update(context.Background(), tc.opts)
+			if tc.errContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.errContains)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantUpdated, got.UpdatedCount)
+			assert.Contains(t, got.Message, tc.msgContains)
+		})
+	}
+}
+
+func TestDnfUpdater_SecurityOnly(t *testing.T) {
+	testCases := []struct {
+		name         string
+		cfg          config.ManagerConfig
+		opts         UpdateOptions
+		wantPackages []PackageInfo
+		wantLog      string
+		msgContains  string
+	}{
+		{
+			name: "セキュリティ更新に印を付ける",
+			wantPackages: []PackageInfo{
+				{Name: "kernel", NewVersion: "6.8.9-300.fc40", Security: true},
+				{Name: "git", NewVersion: "2.45.1-1.fc40"},
+			},
+			wantLog:     "upgrade -y",
+			msgContains: "2 件のパッケージを更新しました",
+		},
+		{
+			name:         "オプションでセキュリティ更新のみ",
+			opts:         UpdateOptions{SecurityOnly: true},
+			wantPackages: []PackageInfo{{Name: "kernel", NewVersion: "6.8.9-300.fc40", Security: true}},
+			wantLog:      "upgrade -y --security",
+			msgContains:  "1 件のセキュリティ更新を適用しました",
+		},
+		{
+			name:         "設定でセキュリティ更新のみ",
+			cfg:          config.ManagerConfig{"security_only": true},
+			wantPackages: []PackageInfo{{Name: "kernel", NewVersion: "6.8.9-300.fc40", Security: true}},
+			wantLog:      "upgrade -y --security",
+			msgContains:  "1 件のセキュリティ更新を適用しました",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			writeFakeDnfCommand(t)
+
+			logPath := filepath.Join(t.TempDir(), "dnf.log")
+			t.Setenv("DEVSYNC_TEST_DNF_MODE", "updates")
+			t.Setenv("DEVSYNC_TEST_DNF_LOG", logPath)
+
+			d := &DnfUpdater{useSudo: false}
+			require.NoError(t, d.Configure(tc.cfg))
+
+			got, err := d.Update(context.Background(), tc.opts)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantPackages, got.Packages)
+			assert.Contains(t, got.Message, tc.msgContains)
+
+			data, err := os.ReadFile(logPath)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantLog+"\n", string(data))
+		})
+	}
+}
+
+func writeFakeDnfCommand(t *testing.T) {
+	t.Helper()
+
+	if runtime.GOOS == windowsOS {
+		t.Skip("fake dnf は POSIX シェル前提")
+	}
+
+	script := `#!/bin/sh
+mode="${DEVSYNC_TEST_DNF_MODE}"
+case "$1" in
+  check-update)
+    if [ "${mode}" = "check_error" ]; then
+      echo "Error: Failed to download metadata" 1>&2
+      exit 1
+    fi
+    if [ "${mode}" = "none" ]; then
+      exit 0
+    fi
+    echo ""
+    echo "kernel.x86_64   6.8.9-300.fc40   updates"
+    case "$*" in
+      *--security*) ;;
+      *) echo "git.x86_64      2.45.1-1.fc40    updates" ;;
+    esac
+    exit 100
+    ;;
+  upgrade)
+    if [ -n "${DEVSYNC_TEST_DNF_LOG}" ]; then
+      echo "$*" >> "${DEVSYNC_TEST_DNF_LOG}"
+    fi
+    if [ "${mode}" = "upgrade_error" ]; then
+      echo "dnf upgrade failed" 1>&2
+      exit 1
+    fi
+    exit 0
+    ;;
+esac
+echo "invalid args" 1>&2
+exit 1
+`
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "dnf"), []byte(script), 0o755))
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
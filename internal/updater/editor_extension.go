@@ -0,0 +1,337 @@
+package updater
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/scottlz0310/devsync/internal/config"
+)
+
+// EditorExtensionUpdater は VS Code 互換エディタ（VS Code / VSCodium / Cursor）の拡張機能の実装です。
+// インストール済みのバージョンを拡張機能ギャラリーの最新バージョンと比較し、
+// 更新があるものを `<cli> --install-extension <id> --force` で更新します。
+//
+//	sys:
+//	  managers:
+//	    vscode:
+//	      exclude: ["ms-vscode.cpptools"]     # 更新しない拡張機能
+//	      packages: ["golang.go"]             # 宣言的な拡張機能一覧（sys apply / sys check）
+//	      gallery: openvsx                    # marketplace / openvsx（既定はエディタごと）
+//	      gallery_url: https://open-vsx.org   # ギャラリーのベース URL（社内ミラーなど）
+type EditorExtensionUpdater struct {
+	name        string
+	command     string
+	displayName string
+	gallery     extensionGallery
+	exclude     []string
+	// checkEngine は `<cli> --version` が VS Code 互換のバージョンを返し、エンジン要件を確認できることを表します。
+	// Cursor は独自のバージョン番号を返すため確認しません。
+	checkEngine bool
+}
+
+// editorExtension はインストール済みの拡張機能です（id は小文字の publisher.name）。
+type editorExtension struct {
+	id      string
+	version string
+}
+
+var _ PackageInstaller = (*EditorExtensionUpdater)(nil)
+
+// 起動時にレジストリに登録
+func init() {
+	for _, e := range builtinEditorExtensionUpdaters() {
+		Register(e)
+	}
+}
+
+// builtinEditorExtensionUpdaters は組み込みのエディタ向け Updater を返します。
+func builtinEditorExtensionUpdaters() []*EditorExtensionUpdater {
+	return []*EditorExtensionUpdater{
+		NewEditorExtensionUpdater("vscode", "code", "VS Code (拡張機能)", galleryMarketplace),
+		NewEditorExtensionUpdater("vscodium", "codium", "VSCodium (拡張機能)", galleryOpenVSX),
+		newCursorExtensionUpdater(),
+	}
+}
+
+func newCursorExtensionUpdater() *EditorExtensionUpdater {
+	e := NewEditorExtensionUpdater("cursor", "cursor", "Cursor (拡張機能)", galleryOpenVSX)
+	e.checkEngine = false
+
+	return e
+}
+
+// NewEditorExtensionUpdater は command（例: code）の拡張機能を gallery（marketplace / openvsx）と比較する Updater を作成します。
+func NewEditorExtensionUpdater(name, command, displayName, gallery string) *EditorExtensionUpdater {
+	return &EditorExtensionUpdater{
+		name:        name,
+		command:     command,
+		displayName: displayName,
+		gallery:     extensionGallery{kind: gallery, baseURL: defaultGalleryURL(gallery)},
+		checkEngine: true,
+	}
+}
+
+func (e *EditorExtensionUpdater) Name() string {
+	return e.name
+}
+
+func (e *EditorExtensionUpdater) DisplayName() string {
+	return e.displayName
+}
+
+func (e *EditorExtensionUpdater) IsAvailable() bool {
+	_, err := exec.LookPath(e.command)
+	return err == nil
+}
+
+func (e *EditorExtensionUpdater) Configure(cfg config.ManagerConfig) error {
+	if cfg == nil {
+		return nil
+	}
+
+	if exclude, ok := toStringList(cfg["exclude"]); ok {
+		e.exclude = e.exclude[:0]
+
+		for _, id := range exclude {
+			e.exclude = append(e.exclude, strings.ToLower(id))
+		}
+	}
+
+	if gallery := strings.ToLower(stringConfigValue(cfg, "gallery")); gallery != "" {
+		if gallery != galleryMarketplace && gallery != galleryOpenVSX {
+			return fmt.Errorf("sys.managers.%s.gallery の値が不正です: %q（marketplace / openvsx）", e.name, gallery)
+		}
+
+		e.gallery = extensionGallery{kind: gallery, baseURL: defaultGalleryURL(gallery)}
+	}
+
+	if galleryURL := stringConfigValue(cfg, "gallery_url"); galleryURL != "" {
+		e.gallery.baseURL = galleryURL
+	}
+
+	return nil
+}
+
+func (e *EditorExtensionUpdater) Check(ctx context.Context) (*CheckResult, error) {
+	packages, err := e.planUpdates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	message := fmt.Sprintf("%d 件の拡張機能が更新可能です", len(packages))
+	if len(packages) == 0 {
+		message = "すべての拡張機能は最新です"
+	}
+
+	return &CheckResult{
+		AvailableUpdates: len(packages),
+		Packages:         packages,
+		Message:          message,
+	}, nil
+}
+
+func (e *EditorExtensionUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateResult, error) {
+	result := &UpdateResult{}
+
+	packages, err := e.planUpdates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(packages) == 0 {
+		result.Message = "すべての拡張機能は最新です"
+		return result, nil
+	}
+
+	if opts.DryRun {
+		result.Packages = packages
+		result.Message = fmt.Sprintf("%d 件の拡張機能を更新予定（DryRunモード）", len(packages))
+
+		return result, nil
+	}
+
+	installed := make([]PackageInfo, 0, len(packages))
+
+	for _, pkg := range packages {
+		if err := runPackageCommand(ctx, e.command, "--install-extension", pkg.Name, "--force"); err != nil {
+			result.FailedCount++
+			result.Errors = append(result.Errors, err)
+
+			continue
+		}
+
+		installed = append(installed, pkg)
+	}
+
+	updated, unchanged := e.confirmInstalledVersions(ctx, installed)
+	result.UpdatedCount = len(updated)
+	result.Packages = updated
+
+	if result.FailedCount > 0 {
+		result.Message = fmt.Sprintf("%d 件更新、%d 件失敗", result.UpdatedCount, result.FailedCount)
+	} else {
+		result.Message = fmt.Sprintf("%d 件の拡張機能を更新しました", result.UpdatedCount)
+	}
+
+	if unchanged > 0 {
+		result.Message += fmt.Sprintf("（%d 件はバージョンが変わりませんでした）", unchanged)
+	}
+
+	return result, nil
+}
+
+// confirmInstalledVersions はインストール後の一覧を読み直し、実際にインストールされたバージョンで結果を返します。
+// `--install-extension --force` は互換性のある最新のビルドをインストールするため、予定と異なる場合があります。
+// バージョンが変わらなかった拡張機能は updated に含めず、件数を unchanged で返します。
+func (e *EditorExtensionUpdater) confirmInstalledVersions(ctx context.Context, packages []PackageInfo) (updated []PackageInfo, unchanged int) {
+	extensions, err := e.installedExtensions(ctx)
+	if err != nil {
+		// 読み直せない場合は予定どおりのバージョンを報告する
+		return packages, 0
+	}
+
+	versions := make(map[string]string, len(extensions))
+	for _, extension := range extensions {
+		versions[extension.id] = extension.version
+	}
+
+	updated = make([]PackageInfo, 0, len(packages))
+
+	for _, pkg := range packages {
+		if version, ok := versions[pkg.Name]; ok {
+			if version == pkg.CurrentVersion {
+				unchanged++
+				continue
+			}
+
+			pkg.NewVersion = version
+		}
+
+		updated = append(updated, pkg)
+	}
+
+	return updated, unchanged
+}
+
+// planUpdates はギャラリーの最新バージョンより古い拡張機能を返します（exclude に含まれるものを除く）。
+func (e *EditorExtensionUpdater) planUpdates(ctx context.Context) ([]PackageInfo, error) {
+	extensions, err := e.installedExtensions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	targets := make([]editorExtension, 0, len(extensions))
+	ids := make([]string, 0, len(extensions))
+
+	for _, extension := range extensions {
+		if containsString(e.exclude, extension.id) {
+			continue
+		}
+
+		targets = append(targets, extension)
+		ids = append(ids, extension.id)
+	}
+
+	latest, err := e.gallery.LatestVersions(ctx, ids, e.target(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("%s の拡張機能の最新バージョンの取得に失敗: %w", e.displayName, err)
+	}
+
+	packages := make([]PackageInfo, 0)
+
+	for _, extension := range targets {
+		newVersion, ok := latest[extension.id]
+		if !ok || !isExtensionVersionLess(extension.version, newVersion) {
+			continue
+		}
+
+		packages = append(packages, PackageInfo{
+			Name:           extension.id,
+			CurrentVersion: extension.version,
+			NewVersion:     newVersion,
+		})
+	}
+
+	return packages, nil
+}
+
+// target は拡張機能のバージョンの互換性判定に使うエディタのバージョンとプラットフォームを返します。
+func (e *EditorExtensionUpdater) target(ctx context.Context) extensionTarget {
+	target := extensionTarget{platform: localExtensionPlatform()}
+
+	if !e.checkEngine {
+		return target
+	}
+
+	// 出力の 1 行目がバージョン（例: 1.95.3）、2 行目以降はコミットとアーキテクチャ
+	output, err := runPackageListCommand(ctx, e.command, "--version")
+	if err != nil {
+		return target
+	}
+
+	version, _, _ := strings.Cut(strings.TrimSpace(string(output)), "\n")
+	target.engine = strings.TrimSpace(version)
+
+	return target
+}
+
+// installedExtensions は "<cli> --list-extensions --show-versions" の出力（publisher.name@version）を解析します。
+func (e *EditorExtensionUpdater) installedExtensions(ctx context.Context) ([]editorExtension, error) {
+	output, err := runPackageListCommand(ctx, e.command, "--list-extensions", "--show-versions")
+	if err != nil {
+		return nil, err
+	}
+
+	return parseEditorExtensions(string(output)), nil
+}
+
+func parseEditorExtensions(output string) []editorExtension {
+	extensions := make([]editorExtension, 0)
+
+	for _, line := range strings.Split(output, "\n") {
+		id, version, _ := strings.Cut(strings.TrimSpace(line), "@")
+
+		// 拡張機能 ID は publisher.name 形式（警告メッセージなどの行は除外）
+		if !strings.Contains(id, ".") || strings.ContainsAny(id, " \t") {
+			continue
+		}
+
+		extensions = append(extensions, editorExtension{id: strings.ToLower(id), version: version})
+	}
+
+	sort.Slice(extensions, func(i, j int) bool {
+		return extensions[i].id < extensions[j].id
+	})
+
+	return extensions
+}
+
+// PackageName は "publisher.name@version" からバージョン指定を除き、小文字の拡張機能 ID を返します。
+func (e *EditorExtensionUpdater) PackageName(spec string) string {
+	return strings.ToLower(stripPackageVersion(spec))
+}
+
+func (e *EditorExtensionUpdater) InstalledPackages(ctx context.Context) ([]string, error) {
+	extensions, err := e.installedExtensions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(extensions))
+	for _, extension := range extensions {
+		names = append(names, extension.id)
+	}
+
+	return names, nil
+}
+
+func (e *EditorExtensionUpdater) InstallPackage(ctx context.Context, spec string) error {
+	return runPackageCommand(ctx, e.command, "--install-extension", spec)
+}
+
+func (e *EditorExtensionUpdater) RemovePackage(ctx context.Context, name string) error {
+	return runPackageCommand(ctx, e.command, "--uninstall-extension", name)
+}
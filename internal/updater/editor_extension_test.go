@@ -0,0 +1,157 @@
+package updater
+
+import (
+	"context"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/scottlz0310/devsync/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuiltinEditorExtensionUpdaters(t *testing.T) {
+	got := make(map[string]string)
+	for _, e := range builtinEditorExtensionUpdaters() {
+		got[e.Name()] = e.command + " / " + e.gallery.kind
+	}
+
+	assert.Equal(t, map[string]string{
+		"vscode":   "code / marketplace",
+		"vscodium": "codium / openvsx",
+		"cursor":   "cursor / openvsx",
+	}, got)
+}
+
+func TestParseEditorExtensions(t *testing.T) {
+	output := "Golang.Go@0.42.0\nms-python.python@2024.2.1\nExtensions installed on WSL: Ubuntu:\n\n"
+
+	assert.Equal(t, []editorExtension{
+		{id: "golang.go", version: "0.42.0"},
+		{id: "ms-python.python", version: "2024.2.1"},
+	}, parseEditorExtensions(output))
+}
+
+func TestEditorExtensionUpdater_Configure(t *testing.T) {
+	e := NewEditorExtensionUpdater("vscode", "code", "VS Code", galleryMarketplace)
+
+	require.NoError(t, e.Configure(config.ManagerConfig{
+		"exclude":     []interface{}{"MS-VSCode.cpptools"},
+		"gallery":     "openvsx",
+		"gallery_url": "https://openvsx.example.com",
+	}))
+	assert.Equal(t, []string{"ms-vscode.cpptools"}, e.exclude)
+	assert.Equal(t, extensionGallery{kind: galleryOpenVSX, baseURL: "https://openvsx.example.com"}, e.gallery)
+
+	assert.Error(t, e.Configure(config.ManagerConfig{"gallery": "unknown"}))
+}
+
+func TestEditorExtensionUpdater_CheckAndUpdate(t *testing.T) {
+	logPath := writeFakeEditorCommand(t, "codium")
+
+	// golang.go はインストール後に 0.42.1 になる
+	t.Setenv("DEVSYNC_TEST_EDITOR_INSTALLED", "golang.Go@0.42.1\nms-python.python@2024.2.1\nesbenp.prettier-vscode@10.4.0\n")
+
+	server := httptest.NewServer(openVSXTestHandler(map[string]string{
+		"golang.go":              `{"version":"0.43.0","engines":{"vscode":"^1.96.0"}},{"version":"0.42.1","engines":{"vscode":"^1.90.0"}}`,
+		"ms-python.python":       `{"version":"2024.4.0"}`,
+		"esbenp.prettier-vscode": `{"version":"10.4.0"}`,
+	}))
+	t.Cleanup(server.Close)
+
+	e := NewEditorExtensionUpdater("vscodium", "codium", "VSCodium", galleryOpenVSX)
+	require.NoError(t, e.Configure(config.ManagerConfig{
+		"gallery_url": server.URL,
+		"exclude":     []interface{}{"ms-python.python"},
+	}))
+
+	checkResult, err := e.Check(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []PackageInfo{{Name: "golang.go", CurrentVersion: "0.42.0", NewVersion: "0.42.1"}}, checkResult.Packages,
+		"除外した拡張機能・最新の拡張機能・エディタより新しいエンジンが必要なバージョンは対象外")
+
+	result, err := e.Update(context.Background(), UpdateOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.UpdatedCount)
+	assert.Equal(t, []PackageInfo{{Name: "golang.go", CurrentVersion: "0.42.0", NewVersion: "0.42.1"}}, result.Packages)
+
+	data, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	assert.Equal(t, "codium --install-extension golang.go --force\n", string(data))
+}
+
+func TestEditorExtensionUpdater_UpdateReportsInstalledVersion(t *testing.T) {
+	writeFakeEditorCommand(t, "code")
+	// インストール後もバージョンが変わらない（互換性のある新しいビルドがない）
+	t.Setenv("DEVSYNC_TEST_EDITOR_INSTALLED", "golang.Go@0.42.0\nms-python.python@2024.2.1\nesbenp.prettier-vscode@10.4.0\n")
+
+	server := httptest.NewServer(openVSXTestHandler(map[string]string{
+		"golang.go": `{"version":"0.42.1"}`,
+	}))
+	t.Cleanup(server.Close)
+
+	e := NewEditorExtensionUpdater("vscode", "code", "VS Code", galleryOpenVSX)
+	require.NoError(t, e.Configure(config.ManagerConfig{"gallery_url": server.URL}))
+
+	result, err := e.Update(context.Background(), UpdateOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.UpdatedCount)
+	assert.Empty(t, result.Packages)
+	assert.Contains(t, result.Message, "1 件はバージョンが変わりませんでした")
+}
+
+func TestEditorExtensionUpdater_ApplyPackages(t *testing.T) {
+	logPath := writeFakeEditorCommand(t, "code")
+
+	e := NewEditorExtensionUpdater("vscode", "code", "VS Code", galleryMarketplace)
+
+	result, err := ApplyPackages(context.Background(), e,
+		[]string{"golang.go", "dbaeumer.vscode-eslint@3.0.10"}, ApplyOptions{Prune: true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dbaeumer.vscode-eslint@3.0.10"}, result.Installed)
+	assert.Equal(t, []string{"esbenp.prettier-vscode", "ms-python.python"}, result.Removed)
+
+	data, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	assert.Equal(t, "code --install-extension dbaeumer.vscode-eslint@3.0.10\n"+
+		"code --uninstall-extension esbenp.prettier-vscode\n"+
+		"code --uninstall-extension ms-python.python\n", string(data))
+}
+
+// writeFakeEditorCommand は VS Code 互換 CLI の fake を PATH に配置し、実行ログのパスを返します。
+func writeFakeEditorCommand(t *testing.T, command string) string {
+	t.Helper()
+
+	if runtime.GOOS == windowsOS {
+		t.Skip("fake " + command + " は POSIX シェル前提")
+	}
+
+	logPath := filepath.Join(t.TempDir(), "commands.log")
+	t.Setenv("DEVSYNC_TEST_EDITOR_LOG", logPath)
+
+	// --install-extension の実行後は DEVSYNC_TEST_EDITOR_INSTALLED（未設定なら初期状態）を一覧として返す
+	script := `#!/bin/sh
+if [ "$1" = "--version" ]; then
+  printf '1.95.3\n0123abcd\nx64\n'
+  exit 0
+fi
+if [ "$1" = "--list-extensions" ]; then
+  if [ -f "${DEVSYNC_TEST_EDITOR_LOG}" ] && [ -n "${DEVSYNC_TEST_EDITOR_INSTALLED}" ]; then
+    printf "${DEVSYNC_TEST_EDITOR_INSTALLED}"
+    exit 0
+  fi
+  printf 'golang.Go@0.42.0\nms-python.python@2024.2.1\nesbenp.prettier-vscode@10.4.0\n'
+  exit 0
+fi
+echo "` + command + ` $*" >> "${DEVSYNC_TEST_EDITOR_LOG}"
+`
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, command), []byte(script), 0o755))
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	return logPath
+}
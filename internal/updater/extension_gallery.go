@@ -0,0 +1,305 @@
+package updater
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"runtime"
+	"strings"
+	"time"
+)
+
+const (
+	// galleryMarketplace は Visual Studio Marketplace（VS Code）です。
+	galleryMarketplace = "marketplace"
+	// galleryOpenVSX は Open VSX Registry（VSCodium / Cursor など）です。
+	galleryOpenVSX = "openvsx"
+
+	defaultMarketplaceURL = "https://marketplace.visualstudio.com"
+	defaultOpenVSXURL     = "https://open-vsx.org"
+
+	// marketplaceFlags は IncludeVersions (0x1) | IncludeVersionProperties (0x10) です。
+	marketplaceFlags = 0x11
+	// marketplaceFilterExtensionName は "publisher.name" で拡張機能を指定するフィルタです。
+	marketplaceFilterExtensionName = 7
+
+	marketplacePreReleaseProperty = "Microsoft.VisualStudio.Code.PreRelease"
+	marketplaceEngineProperty     = "Microsoft.VisualStudio.Code.Engine"
+)
+
+// extensionGalleryHTTPClient は拡張機能ギャラリーへの問い合わせに使用する HTTP クライアントです。
+var extensionGalleryHTTPClient = &http.Client{Timeout: 30 * time.Second}
+
+// errExtensionNotFound はギャラリーに拡張機能が存在しない（404）ことを表します。
+var errExtensionNotFound = errors.New("拡張機能が見つかりません")
+
+// extensionGallery は拡張機能の最新（安定版）のバージョンを問い合わせます。
+type extensionGallery struct {
+	kind    string
+	baseURL string
+}
+
+// extensionTarget は拡張機能のバージョンが互換かを判定するためのエディタの情報です。
+// `--install-extension --force` はエディタと互換性のある最新のビルドをインストールするため、同じ条件で絞り込みます。
+type extensionTarget struct {
+	// engine はエディタの VS Code 互換バージョンです（空の場合はエンジン要件を確認しません）。
+	engine string
+	// platform は VS Code のターゲットプラットフォーム名（例: linux-x64）です（空の場合は確認しません）。
+	platform string
+}
+
+// compatible はエンジン要件（engines.vscode）とターゲットプラットフォームがエディタと互換かを返します。
+func (t extensionTarget) compatible(engineRange, targetPlatform string) bool {
+	switch targetPlatform {
+	case "", "universal":
+	case "web":
+		return false
+	default:
+		if t.platform != "" && targetPlatform != t.platform {
+			return false
+		}
+	}
+
+	return extensionEngineSatisfied(engineRange, t.engine)
+}
+
+// extensionEngineSatisfied はエディタのバージョンがエンジン要件（例: ^1.85.0、>=1.60.0、*）を満たすかを返します。
+// VS Code と同様に要件は最低バージョンとして扱い、"^" の場合はメジャーバージョンの一致も求めます。
+// 解釈できない場合は互換とみなします。
+func extensionEngineSatisfied(engineRange, version string) bool {
+	engineRange = strings.TrimSpace(engineRange)
+	if engineRange == "" || engineRange == "*" || version == "" {
+		return true
+	}
+
+	caret := strings.HasPrefix(engineRange, "^")
+
+	minimum, ok := versionCore(strings.TrimLeft(engineRange, "^>=~ "))
+	if !ok {
+		return true
+	}
+
+	current, ok := versionCore(version)
+	if !ok {
+		return true
+	}
+
+	if caret && minimum[0] != current[0] {
+		return false
+	}
+
+	less, err := isSemverLess(formatVersionCore(current), formatVersionCore(minimum))
+
+	return err != nil || !less
+}
+
+// localExtensionPlatform は実行中の環境の VS Code ターゲットプラットフォーム名を返します。
+func localExtensionPlatform() string {
+	platforms := map[string]string{"linux": "linux", "darwin": "darwin", "windows": "win32"}
+	arches := map[string]string{"amd64": "x64", "arm64": "arm64", "arm": "armhf"}
+
+	platform, ok := platforms[runtime.GOOS]
+	if !ok {
+		return ""
+	}
+
+	arch, ok := arches[runtime.GOARCH]
+	if !ok {
+		return ""
+	}
+
+	return platform + "-" + arch
+}
+
+func defaultGalleryURL(kind string) string {
+	if kind == galleryOpenVSX {
+		return defaultOpenVSXURL
+	}
+
+	return defaultMarketplaceURL
+}
+
+// LatestVersions は拡張機能 ID（小文字の publisher.name）ごとに、target と互換な最新の安定版のバージョンを返します。
+// ギャラリーに存在しない拡張機能や、互換な安定版がない拡張機能は結果に含まれません。
+func (g extensionGallery) LatestVersions(ctx context.Context, ids []string, target extensionTarget) (map[string]string, error) {
+	if len(ids) == 0 {
+		return map[string]string{}, nil
+	}
+
+	if g.kind == galleryOpenVSX {
+		return g.openVSXLatestVersions(ctx, ids, target)
+	}
+
+	return g.marketplaceLatestVersions(ctx, ids, target)
+}
+
+type marketplaceQueryResponse struct {
+	Results []struct {
+		Extensions []struct {
+			ExtensionName string `json:"extensionName"`
+			Publisher     struct {
+				PublisherName string `json:"publisherName"`
+			} `json:"publisher"`
+			Versions []struct {
+				Version        string `json:"version"`
+				TargetPlatform string `json:"targetPlatform"`
+				Properties     []struct {
+					Key   string `json:"key"`
+					Value string `json:"value"`
+				} `json:"properties"`
+			} `json:"versions"`
+		} `json:"extensions"`
+	} `json:"results"`
+}
+
+// marketplaceLatestVersions は extensionquery API でまとめて問い合わせ、
+// プレリリースと target と互換でないバージョンを除いた最新バージョンを返します。
+func (g extensionGallery) marketplaceLatestVersions(ctx context.Context, ids []string, target extensionTarget) (map[string]string, error) {
+	criteria := make([]map[string]interface{}, 0, len(ids))
+	for _, id := range ids {
+		criteria = append(criteria, map[string]interface{}{"filterType": marketplaceFilterExtensionName, "value": id})
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"filters": []map[string]interface{}{
+			{"criteria": criteria, "pageNumber": 1, "pageSize": len(ids)},
+		},
+		"flags": marketplaceFlags,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	url := strings.TrimRight(g.baseURL, "/") + "/_apis/public/gallery/extensionquery"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json;api-version=3.0-preview.1")
+
+	data, err := doGalleryRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var response marketplaceQueryResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, fmt.Errorf("Marketplace の応答の解析に失敗: %w", err)
+	}
+
+	latest := make(map[string]string)
+
+	for _, result := range response.Results {
+		for _, extension := range result.Extensions {
+			id := strings.ToLower(extension.Publisher.PublisherName + "." + extension.ExtensionName)
+
+			for _, version := range extension.Versions {
+				preRelease := false
+				engine := ""
+
+				for _, property := range version.Properties {
+					switch property.Key {
+					case marketplacePreReleaseProperty:
+						preRelease = property.Value == "true"
+					case marketplaceEngineProperty:
+						engine = property.Value
+					}
+				}
+
+				if preRelease || !target.compatible(engine, version.TargetPlatform) {
+					continue
+				}
+
+				if current, ok := latest[id]; !ok || isExtensionVersionLess(current, version.Version) {
+					latest[id] = version.Version
+				}
+			}
+		}
+	}
+
+	return latest, nil
+}
+
+// openVSXQueryResponse は Open VSX の /api/-/query の応答です（includeAllVersions=true で全バージョンを含みます）。
+type openVSXQueryResponse struct {
+	Extensions []struct {
+		Version        string            `json:"version"`
+		PreRelease     bool              `json:"preRelease"`
+		TargetPlatform string            `json:"targetPlatform"`
+		Engines        map[string]string `json:"engines"`
+	} `json:"extensions"`
+}
+
+// openVSXLatestVersions は拡張機能ごとに /api/-/query で全バージョンを問い合わせ、
+// プレリリースと target と互換でないバージョンを除いた最新バージョンを返します。
+func (g extensionGallery) openVSXLatestVersions(ctx context.Context, ids []string, target extensionTarget) (map[string]string, error) {
+	latest := make(map[string]string, len(ids))
+
+	for _, id := range ids {
+		query := url.Values{"extensionId": {id}, "includeAllVersions": {"true"}}
+		endpoint := strings.TrimRight(g.baseURL, "/") + "/api/-/query?" + query.Encode()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, http.NoBody)
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := doGalleryRequest(req)
+		if errors.Is(err, errExtensionNotFound) {
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		var response openVSXQueryResponse
+		if err := json.Unmarshal(data, &response); err != nil {
+			return nil, fmt.Errorf("Open VSX の応答の解析に失敗: %w", err)
+		}
+
+		for _, extension := range response.Extensions {
+			if extension.Version == "" || extension.PreRelease || !target.compatible(extension.Engines["vscode"], extension.TargetPlatform) {
+				continue
+			}
+
+			if current, ok := latest[id]; !ok || isExtensionVersionLess(current, extension.Version) {
+				latest[id] = extension.Version
+			}
+		}
+	}
+
+	return latest, nil
+}
+
+func doGalleryRequest(req *http.Request) ([]byte, error) {
+	resp, err := extensionGalleryHTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("拡張機能ギャラリーへの問い合わせに失敗: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, errExtensionNotFound
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("拡張機能ギャラリーが %s を返しました: %s", resp.Status, req.URL)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+// isExtensionVersionLess は拡張機能のバージョンを比較します（semver として解釈できない場合は false）。
+func isExtensionVersionLess(left, right string) bool {
+	less, err := isSemverLess(left, right)
+	return err == nil && less
+}
@@ -0,0 +1,109 @@
+package updater
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtensionGallery_Marketplace(t *testing.T) {
+	var criteria []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/_apis/public/gallery/extensionquery" {
+			http.NotFound(w, r)
+			return
+		}
+
+		var query struct {
+			Filters []struct {
+				Criteria []struct {
+					Value string `json:"value"`
+				} `json:"criteria"`
+			} `json:"filters"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&query); err == nil && len(query.Filters) > 0 {
+			for _, c := range query.Filters[0].Criteria {
+				criteria = append(criteria, c.Value)
+			}
+		}
+
+		_, _ = w.Write([]byte(`{"results":[{"extensions":[
+			{"extensionName":"Go","publisher":{"publisherName":"golang"},"versions":[
+				{"version":"0.44.0","properties":[{"key":"Microsoft.VisualStudio.Code.Engine","value":"^1.96.0"}]},
+				{"version":"0.43.0","properties":[{"key":"Microsoft.VisualStudio.Code.PreRelease","value":"true"}]},
+				{"version":"0.42.1","properties":[{"key":"Microsoft.VisualStudio.Code.Engine","value":"^1.90.0"}]},
+				{"version":"0.41.4","properties":[]}
+			]},
+			{"extensionName":"python","publisher":{"publisherName":"ms-python"},"versions":[
+				{"version":"2024.4.0","targetPlatform":"darwin-arm64","properties":[]},
+				{"version":"2024.2.1","targetPlatform":"linux-x64","properties":[]},
+				{"version":"2024.2.0","targetPlatform":"web","properties":[]}
+			]}
+		]}]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	gallery := extensionGallery{kind: galleryMarketplace, baseURL: server.URL}
+	target := extensionTarget{engine: "1.95.3", platform: "linux-x64"}
+
+	got, err := gallery.LatestVersions(context.Background(), []string{"golang.go", "ms-python.python", "unknown.ext"}, target)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"golang.go": "0.42.1", "ms-python.python": "2024.2.1"}, got,
+		"プレリリース・エディタより新しいエンジンが必要なバージョン・他プラットフォーム向けは除外し、ギャラリーにない拡張機能は含めない")
+	assert.Equal(t, []string{"golang.go", "ms-python.python", "unknown.ext"}, criteria)
+}
+
+func TestExtensionGallery_OpenVSX(t *testing.T) {
+	server := httptest.NewServer(openVSXTestHandler(map[string]string{
+		"golang.go": `{"version":"0.43.0","engines":{"vscode":"^1.99.0"}},{"version":"0.42.1","engines":{"vscode":"^1.90.0"}}`,
+		"rust-lang.rust-analyzer": `{"version":"0.4.2200","preRelease":true,"targetPlatform":"linux-x64"},` +
+			`{"version":"0.3.2100","targetPlatform":"linux-x64"},{"version":"0.3.2100","targetPlatform":"win32-x64"}`,
+	}))
+	t.Cleanup(server.Close)
+
+	gallery := extensionGallery{kind: galleryOpenVSX, baseURL: server.URL}
+	target := extensionTarget{engine: "1.95.3", platform: "linux-x64"}
+
+	got, err := gallery.LatestVersions(context.Background(), []string{"golang.go", "rust-lang.rust-analyzer", "ms-vscode.cpptools"}, target)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"golang.go": "0.42.1", "rust-lang.rust-analyzer": "0.3.2100"}, got,
+		"最新がプレリリースの場合は最新の安定版にフォールバックする")
+}
+
+func TestExtensionEngineSatisfied(t *testing.T) {
+	tests := []struct {
+		engine  string
+		version string
+		want    bool
+	}{
+		{engine: "^1.90.0", version: "1.95.3", want: true},
+		{engine: "^1.96.0", version: "1.95.3", want: false},
+		{engine: ">=1.60.0", version: "1.95.3", want: true},
+		{engine: "^1.96.0-insider", version: "1.96.0", want: true},
+		{engine: "*", version: "1.95.3", want: true},
+		{engine: "^1.96.0", version: "", want: true},
+		{engine: "^2.0.0", version: "1.95.3", want: false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, extensionEngineSatisfied(tt.engine, tt.version), tt.engine+" / "+tt.version)
+	}
+}
+
+// openVSXTestHandler は拡張機能 ID ごとのバージョン一覧（JSON オブジェクトのカンマ区切り）を返す /api/-/query の fake です。
+func openVSXTestHandler(versions map[string]string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/-/query" || r.URL.Query().Get("includeAllVersions") != "true" {
+			http.NotFound(w, r)
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"extensions":[` + versions[r.URL.Query().Get("extensionId")] + `]}`))
+	})
+}
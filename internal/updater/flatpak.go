@@ -160,3 +160,40 @@ func (f *FlatpakUpdater) parseRemoteLSOutput(output string) []PackageInfo {
 
 	return packages
 }
+
+var _ PackageInstaller = (*FlatpakUpdater)(nil)
+
+func (f *FlatpakUpdater) PackageName(spec string) string {
+	return strings.TrimSpace(spec)
+}
+
+// InstalledPackages はインストール済みアプリケーション ID を返します（ランタイムは対象外）。
+func (f *FlatpakUpdater) InstalledPackages(ctx context.Context) ([]string, error) {
+	args := f.buildCommandArgs("list", "--app", "--columns=application")
+
+	output, err := runPackageListCommand(ctx, "flatpak", args...)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0)
+
+	for _, line := range strings.Split(string(output), "\n") {
+		name := strings.TrimSpace(line)
+		if name == "" || strings.EqualFold(name, "Application ID") {
+			continue
+		}
+
+		names = append(names, name)
+	}
+
+	return names, nil
+}
+
+func (f *FlatpakUpdater) InstallPackage(ctx context.Context, spec string) error {
+	return runPackageCommand(ctx, "flatpak", f.buildCommandArgs("install", "-y", "--noninteractive", spec)...)
+}
+
+func (f *FlatpakUpdater) RemovePackage(ctx context.Context, name string) error {
+	return runPackageCommand(ctx, "flatpak", f.buildCommandArgs("uninstall", "-y", "--noninteractive", name)...)
+}
@@ -0,0 +1,188 @@
+package updater
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/scottlz0310/devsync/internal/config"
+)
+
+// ghUpgradeDryRunPattern は `gh extension upgrade --all --dry-run` の更新予定行にマッチします。
+var ghUpgradeDryRunPattern = regexp.MustCompile(`^\[([^\]]+)\]: would have upgraded from (\S+) to (\S+)`)
+
+// GhExtensionUpdater は gh (GitHub CLI) 拡張機能の実装です。
+//
+//	sys:
+//	  managers:
+//	    gh:
+//	      exclude: ["dash"]  # 更新しない拡張機能（gh- の接頭辞・owner/ は省略可）
+type GhExtensionUpdater struct {
+	exclude []string
+}
+
+// 起動時にレジストリに登録
+func init() {
+	Register(&GhExtensionUpdater{})
+}
+
+func (g *GhExtensionUpdater) Name() string {
+	return "gh"
+}
+
+func (g *GhExtensionUpdater) DisplayName() string {
+	return "gh (GitHub CLI 拡張機能)"
+}
+
+func (g *GhExtensionUpdater) IsAvailable() bool {
+	_, err := exec.LookPath("gh")
+	return err == nil
+}
+
+func (g *GhExtensionUpdater) Configure(cfg config.ManagerConfig) error {
+	if cfg == nil {
+		return nil
+	}
+
+	if exclude, ok := toStringList(cfg["exclude"]); ok {
+		g.exclude = g.exclude[:0]
+
+		for _, name := range exclude {
+			g.exclude = append(g.exclude, ghExtensionName(name))
+		}
+	}
+
+	return nil
+}
+
+func (g *GhExtensionUpdater) Check(ctx context.Context) (*CheckResult, error) {
+	packages, err := g.planUpdates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	message := fmt.Sprintf("%d 件の拡張機能が更新可能です", len(packages))
+	if len(packages) == 0 {
+		message = "すべての拡張機能は最新です"
+	}
+
+	return &CheckResult{
+		AvailableUpdates: len(packages),
+		Packages:         packages,
+		Message:          message,
+	}, nil
+}
+
+func (g *GhExtensionUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateResult, error) {
+	result := &UpdateResult{}
+
+	packages, err := g.planUpdates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(packages) == 0 {
+		result.Message = "すべての拡張機能は最新です"
+		return result, nil
+	}
+
+	if opts.DryRun {
+		result.Packages = packages
+		result.Message = fmt.Sprintf("%d 件の拡張機能を更新予定（DryRunモード）", len(packages))
+
+		return result, nil
+	}
+
+	// 除外がなければ一括で更新し、除外がある場合は対象の拡張機能を個別に更新する
+	if len(g.exclude) == 0 {
+		if err := runPackageCommand(ctx, "gh", "extension", "upgrade", "--all"); err != nil {
+			result.Errors = append(result.Errors, err)
+			return result, err
+		}
+
+		result.UpdatedCount = len(packages)
+		result.Packages = packages
+		result.Message = fmt.Sprintf("%d 件の拡張機能を更新しました", result.UpdatedCount)
+
+		return result, nil
+	}
+
+	for _, pkg := range packages {
+		if err := runPackageCommand(ctx, "gh", "extension", "upgrade", pkg.Name); err != nil {
+			result.FailedCount++
+			result.Errors = append(result.Errors, err)
+
+			continue
+		}
+
+		result.UpdatedCount++
+		result.Packages = append(result.Packages, pkg)
+	}
+
+	if result.FailedCount > 0 {
+		result.Message = fmt.Sprintf("%d 件更新、%d 件失敗", result.UpdatedCount, result.FailedCount)
+	} else {
+		result.Message = fmt.Sprintf("%d 件の拡張機能を更新しました", result.UpdatedCount)
+	}
+
+	return result, nil
+}
+
+// planUpdates は `gh extension upgrade --all --dry-run` から更新予定の拡張機能を取得します（exclude を除く）。
+func (g *GhExtensionUpdater) planUpdates(ctx context.Context) ([]PackageInfo, error) {
+	cmd := exec.CommandContext(ctx, "gh", "extension", "upgrade", "--all", "--dry-run")
+	cmd.Env = append(os.Environ(), "LANG=C", "LC_ALL=C", "GH_PROMPT_DISABLED=1")
+
+	var stderr bytes.Buffer
+
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	packages := parseGhUpgradeDryRun(string(output))
+
+	// ローカル拡張機能など一部が更新できない場合も終了コードは非 0 になるため、更新予定を読み取れた場合は継続する
+	if err != nil && len(packages) == 0 {
+		return nil, fmt.Errorf("gh extension upgrade --dry-run の実行に失敗: %w", buildCommandOutputErr(err, combineCommandOutputs(output, stderr.Bytes())))
+	}
+
+	filtered := make([]PackageInfo, 0, len(packages))
+
+	for _, pkg := range packages {
+		if containsString(g.exclude, ghExtensionName(pkg.Name)) {
+			continue
+		}
+
+		filtered = append(filtered, pkg)
+	}
+
+	return filtered, nil
+}
+
+func parseGhUpgradeDryRun(output string) []PackageInfo {
+	packages := make([]PackageInfo, 0)
+
+	for _, line := range strings.Split(output, "\n") {
+		match := ghUpgradeDryRunPattern.FindStringSubmatch(strings.TrimSpace(line))
+		if match == nil {
+			continue
+		}
+
+		packages = append(packages, PackageInfo{Name: match[1], CurrentVersion: match[2], NewVersion: match[3]})
+	}
+
+	return packages
+}
+
+// ghExtensionName は "owner/gh-name" / "gh-name" / "name" を比較用の "name" に正規化します。
+func ghExtensionName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if idx := strings.LastIndex(name, "/"); idx != -1 {
+		name = name[idx+1:]
+	}
+
+	return strings.TrimPrefix(name, "gh-")
+}
@@ -0,0 +1,96 @@
+package updater
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/scottlz0310/devsync/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGhUpgradeDryRun(t *testing.T) {
+	output := `[dash]: would have upgraded from v3.14.0 to v4.0.0
+[copilot]: already up to date
+[poi]: would have upgraded from 1a2b3c4 to 5d6e7f8
+[local-ext]: local extensions can not be upgraded
+`
+
+	assert.Equal(t, []PackageInfo{
+		{Name: "dash", CurrentVersion: "v3.14.0", NewVersion: "v4.0.0"},
+		{Name: "poi", CurrentVersion: "1a2b3c4", NewVersion: "5d6e7f8"},
+	}, parseGhUpgradeDryRun(output))
+}
+
+func TestGhExtensionName(t *testing.T) {
+	assert.Equal(t, "dash", ghExtensionName("dlvhdr/gh-dash"))
+	assert.Equal(t, "dash", ghExtensionName("gh-dash"))
+	assert.Equal(t, "dash", ghExtensionName("Dash"))
+}
+
+func TestGhExtensionUpdater_Update(t *testing.T) {
+	testCases := []struct {
+		name    string
+		cfg     config.ManagerConfig
+		wantLog string
+	}{
+		{
+			name:    "除外なしは一括更新",
+			wantLog: "gh extension upgrade --all\n",
+		},
+		{
+			name:    "除外ありは個別に更新",
+			cfg:     config.ManagerConfig{"exclude": []interface{}{"dlvhdr/gh-dash"}},
+			wantLog: "gh extension upgrade poi\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			logPath := writeFakeGhCommand(t)
+
+			g := &GhExtensionUpdater{}
+			require.NoError(t, g.Configure(tc.cfg))
+
+			result, err := g.Update(context.Background(), UpdateOptions{})
+			require.NoError(t, err)
+			assert.Empty(t, result.Errors)
+
+			data, err := os.ReadFile(logPath)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantLog, string(data))
+		})
+	}
+}
+
+// writeFakeGhCommand は fake の gh を PATH に配置し、実行ログのパスを返します。
+// --dry-run はローカル拡張機能を含むため終了コード 1 を返します。
+func writeFakeGhCommand(t *testing.T) string {
+	t.Helper()
+
+	if runtime.GOOS == windowsOS {
+		t.Skip("fake gh は POSIX シェル前提")
+	}
+
+	logPath := filepath.Join(t.TempDir(), "commands.log")
+	t.Setenv("DEVSYNC_TEST_GH_LOG", logPath)
+
+	script := `#!/bin/sh
+if [ "$4" = "--dry-run" ]; then
+  echo "[dash]: would have upgraded from v3.14.0 to v4.0.0"
+  echo "[poi]: would have upgraded from 1a2b3c4 to 5d6e7f8"
+  echo "[local-ext]: local extensions can not be upgraded" 1>&2
+  exit 1
+fi
+echo "gh $*" >> "${DEVSYNC_TEST_GH_LOG}"
+`
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "gh"), []byte(script), 0o755))
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	return logPath
+}
@@ -3,22 +3,57 @@ package updater
 import (
 	"bufio"
 	"context"
+	"debug/buildinfo"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 
 	"github.com/scottlz0310/devsync/internal/config"
+	"golang.org/x/sync/errgroup"
 )
 
 // GoUpdater は Go ツール (go install) の更新を管理します。
-// go install コマンドでインストールしたバイナリを最新版に更新します。
+// $GOBIN（未設定なら $GOPATH/bin）のバイナリに埋め込まれたビルド情報からモジュールとバージョンを読み取り、
+// モジュールプロキシの @latest と比較して古いツールのみを go install で更新します。
 type GoUpdater struct {
 	// targets は更新対象のパッケージパス一覧
 	// 例: ["golang.org/x/tools/gopls@latest", "github.com/golangci/golangci-lint/cmd/golangci-lint@latest"]
+	// 未指定の場合はインストール済みのツールをすべて対象にします。
 	targets []string
+	// proxy はモジュールプロキシの URL です（空の場合は go env の GOPROXY）。
+	proxy string
+	// installed は InstalledPackages で読み取ったツールです（PackageName でバイナリ名の指定を解決するために使用）。
+	installed []goTool
 }
 
+// goTool は GOBIN 配下のバイナリから読み取ったビルド情報です。
+type goTool struct {
+	Name    string
+	Package string
+	Module  string
+	Version string
+}
+
+// goToolUpdate は go install で更新・インストールするツールです。
+type goToolUpdate struct {
+	Name    string
+	Package string
+	Module  string
+	Current string
+	New     string
+	// Unverified はモジュールプロキシで最新バージョンを確認できず、@latest でそのままインストールすることを表します。
+	Unverified bool
+}
+
+// readBuildInfoFunc はテストで差し替え可能なビルド情報の読み取り処理です。
+var readBuildInfoFunc = buildinfo.ReadFile
+
+// goProxyConcurrency はモジュールプロキシへの同時問い合わせ数です。
+const goProxyConcurrency = 8
+
 // 起動時にレジストリに登録
 func init() {
 	Register(&GoUpdater{})
@@ -58,87 +93,87 @@ func (g *GoUpdater) Configure(cfg config.ManagerConfig) error {
 		}
 	}
 
+	if proxy, ok := cfg["proxy"].(string); ok {
+		g.proxy = strings.TrimSpace(proxy)
+	}
+
 	return nil
 }
 
 func (g *GoUpdater) Check(ctx context.Context) (*CheckResult, error) {
-	// Go ツールは明示的なバージョン確認が難しいため、
-	// 設定された targets 数を「更新可能」として返す
-	if len(g.targets) == 0 {
-		return &CheckResult{
-			Message: "更新対象のGoツールが設定されていません",
-		}, nil
+	updates, failures, err := g.planUpdates(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	packages := make([]PackageInfo, 0, len(g.targets))
+	packages := goUpdatesToPackages(updates)
 
-	for _, target := range g.targets {
-		// パッケージパスからツール名を抽出
-		name := extractToolName(target)
-		packages = append(packages, PackageInfo{
-			Name:       name,
-			NewVersion: "@latest",
-		})
+	message := fmt.Sprintf("%d 件のGoツールが更新可能です", len(packages))
+	if len(packages) == 0 {
+		message = "すべてのGoツールは最新です"
+	}
+
+	if unverified := countUnverifiedGoUpdates(updates); unverified > 0 {
+		message += fmt.Sprintf("（%d 件は最新バージョン不明のため @latest で再インストールします）", unverified)
+	}
+
+	if len(failures) > 0 {
+		message += fmt.Sprintf("（%d 件はバージョンを確認できませんでした）", len(failures))
 	}
 
 	return &CheckResult{
 		AvailableUpdates: len(packages),
 		Packages:         packages,
-		Message:          fmt.Sprintf("%d 件のGoツールが更新対象です", len(packages)),
+		Message:          message,
 	}, nil
 }
 
 func (g *GoUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateResult, error) {
 	result := &UpdateResult{}
 
-	if len(g.targets) == 0 {
-		result.Message = "更新対象のGoツールが設定されていません"
+	updates, failures, err := g.planUpdates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(failures) > 0 {
+		result.Errors = append(result.Errors, fmt.Errorf("%d 件のGoツールはバージョンを確認できなかったためスキップしました: %w", len(failures), errors.Join(failures...)))
+	}
+
+	if len(updates) == 0 {
+		result.Message = "すべてのGoツールは最新です"
 		return result, nil
 	}
 
 	if opts.DryRun {
-		packages := make([]PackageInfo, 0, len(g.targets))
-		for _, target := range g.targets {
-			packages = append(packages, PackageInfo{
-				Name:       extractToolName(target),
-				NewVersion: "@latest",
-			})
-		}
-
-		result.Packages = packages
-		result.Message = fmt.Sprintf("%d 件のGoツールを更新予定（DryRunモード）", len(g.targets))
+		result.Packages = goUpdatesToPackages(updates)
+		result.Message = fmt.Sprintf("%d 件のGoツールを更新予定（DryRunモード）", len(updates))
 
 		return result, nil
 	}
 
 	// 各ツールを順番に更新
-	for _, target := range g.targets {
-		toolName := extractToolName(target)
-
-		// @latest が付いていない場合は追加
-		pkg := target
-		if !strings.Contains(pkg, "@") {
-			pkg += "@latest"
-		}
+	for _, update := range updates {
+		fmt.Printf("  📦 %s をインストール中...\n", update.Name)
 
-		fmt.Printf("  📦 %s をインストール中...\n", toolName)
-
-		cmd := exec.CommandContext(ctx, "go", "install", pkg)
+		cmd := exec.CommandContext(ctx, "go", "install", update.Package+"@"+strings.TrimPrefix(update.New, "@"))
 		cmd.Stdout = os.Stdout
 		cmd.Stderr = os.Stderr
 		cmd.Env = os.Environ()
 
 		if err := cmd.Run(); err != nil {
 			result.FailedCount++
-			result.Errors = append(result.Errors, fmt.Errorf("%s: %w", toolName, err))
+			result.Errors = append(result.Errors, fmt.Errorf("%s: %w", update.Name, err))
 
 			continue
 		}
 
 		result.UpdatedCount++
 		result.Packages = append(result.Packages, PackageInfo{
-			Name:       toolName,
-			NewVersion: "@latest",
+			Name:           update.Name,
+			CurrentVersion: update.Current,
+			NewVersion:     update.New,
+			Source:         update.Module,
 		})
 	}
 
@@ -151,6 +186,279 @@ func (g *GoUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateResu
 	return result, nil
 }
 
+// planUpdates はインストール済みツールと targets から更新が必要なツールを求めます。
+// 戻り値の failures はモジュールプロキシへの問い合わせに失敗したツールのエラーです。
+// プロキシで確認できないツール（GOPRIVATE 対象など）は失敗とせず、Unverified の更新として返します。
+func (g *GoUpdater) planUpdates(ctx context.Context) ([]goToolUpdate, []error, error) {
+	env := goEnvFunc(ctx)
+
+	gobin, err := goBinDir(env)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tools, err := discoverGoTools(gobin)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	installed, missing := selectGoTools(tools, g.targets)
+	if len(installed) == 0 {
+		return missing, nil, nil
+	}
+
+	updates, failures := lookupGoToolUpdates(ctx, newGoProxyClient(g.proxy, env), installed)
+
+	return append(updates, missing...), failures, nil
+}
+
+// goToolRequest は更新判定の対象となるインストール済みツールと希望バージョンです。
+type goToolRequest struct {
+	tool goTool
+	// want は targets で指定されたバージョンです（"latest" の場合はプロキシに問い合わせます）。
+	want string
+}
+
+// selectGoTools は targets に従って対象ツールを選択します。
+// targets が空の場合はインストール済みのツールをすべて対象にし、
+// targets のうち未インストールのものは @latest（または指定バージョン）でのインストール対象として返します。
+func selectGoTools(tools []goTool, targets []string) ([]goToolRequest, []goToolUpdate) {
+	if len(targets) == 0 {
+		requests := make([]goToolRequest, 0, len(tools))
+		for _, tool := range tools {
+			requests = append(requests, goToolRequest{tool: tool, want: "latest"})
+		}
+
+		return requests, nil
+	}
+
+	requests := make([]goToolRequest, 0, len(targets))
+	missing := make([]goToolUpdate, 0)
+
+	for _, target := range targets {
+		pkg, want := splitGoTarget(target)
+
+		tool, ok := findGoTool(tools, pkg)
+		if ok {
+			requests = append(requests, goToolRequest{tool: tool, want: want})
+			continue
+		}
+
+		newVersion := want
+		if want == "latest" {
+			newVersion = "@latest"
+		}
+
+		missing = append(missing, goToolUpdate{
+			Name:    extractToolName(pkg),
+			Package: pkg,
+			New:     newVersion,
+		})
+	}
+
+	return requests, missing
+}
+
+func splitGoTarget(target string) (pkg, version string) {
+	pkg, version, found := strings.Cut(strings.TrimSpace(target), "@")
+	if !found || version == "" {
+		version = "latest"
+	}
+
+	return pkg, version
+}
+
+func findGoTool(tools []goTool, pkg string) (goTool, bool) {
+	for _, tool := range tools {
+		if tool.Package == pkg {
+			return tool, true
+		}
+	}
+
+	// パスを含まない指定（例: "gopls"）はバイナリ名で照合する
+	if !strings.Contains(pkg, "/") {
+		for _, tool := range tools {
+			if tool.Name == pkg {
+				return tool, true
+			}
+		}
+	}
+
+	return goTool{}, false
+}
+
+// lookupGoToolUpdates はモジュールプロキシに並列で問い合わせ、古いツールのみを返します。
+func lookupGoToolUpdates(ctx context.Context, client *goProxyClient, requests []goToolRequest) ([]goToolUpdate, []error) {
+	results := make([]*goToolUpdate, len(requests))
+	errs := make([]error, len(requests))
+
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(goProxyConcurrency)
+
+	for i, request := range requests {
+		group.Go(func() error {
+			results[i], errs[i] = checkGoTool(groupCtx, client, request)
+			return nil
+		})
+	}
+
+	//nolint:errcheck // 各ゴルーチンはエラーを errs に格納し nil を返す
+	group.Wait()
+
+	updates := make([]goToolUpdate, 0, len(requests))
+	failures := make([]error, 0)
+
+	for i, update := range results {
+		if errs[i] != nil {
+			failures = append(failures, errs[i])
+			continue
+		}
+
+		if update != nil {
+			updates = append(updates, *update)
+		}
+	}
+
+	return updates, failures
+}
+
+func checkGoTool(ctx context.Context, client *goProxyClient, request goToolRequest) (*goToolUpdate, error) {
+	tool := request.tool
+	newVersion := request.want
+
+	if newVersion == "latest" {
+		latest, err := client.Latest(ctx, tool.Module)
+		if errors.Is(err, errGoModuleUnverifiable) {
+			return &goToolUpdate{
+				Name:       tool.Name,
+				Package:    tool.Package,
+				Module:     tool.Module,
+				Current:    tool.Version,
+				New:        "@latest",
+				Unverified: true,
+			}, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		newVersion = latest
+	}
+
+	outdated, err := isGoVersionLess(tool.Version, newVersion)
+	if err != nil {
+		return nil, fmt.Errorf("%s のバージョン比較に失敗: %w", tool.Name, err)
+	}
+
+	if !outdated {
+		return nil, nil
+	}
+
+	return &goToolUpdate{
+		Name:    tool.Name,
+		Package: tool.Package,
+		Module:  tool.Module,
+		Current: tool.Version,
+		New:     newVersion,
+	}, nil
+}
+
+func goUpdatesToPackages(updates []goToolUpdate) []PackageInfo {
+	packages := make([]PackageInfo, 0, len(updates))
+	for _, update := range updates {
+		packages = append(packages, PackageInfo{
+			Name:           update.Name,
+			CurrentVersion: update.Current,
+			NewVersion:     update.New,
+			Source:         update.Module,
+		})
+	}
+
+	return packages
+}
+
+// countUnverifiedGoUpdates は最新バージョンを確認できなかった更新の件数を返します。
+func countUnverifiedGoUpdates(updates []goToolUpdate) int {
+	count := 0
+
+	for _, update := range updates {
+		if update.Unverified {
+			count++
+		}
+	}
+
+	return count
+}
+
+// discoverGoTools は gobin 配下のバイナリからビルド情報を読み取ります。
+// Go 製でないファイル、ローカルビルド（(devel)）、replace されたモジュールは対象外です。
+func discoverGoTools(gobin string) ([]goTool, error) {
+	entries, err := os.ReadDir(gobin)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("$GOBIN (%s) の読み取りに失敗: %w", gobin, err)
+	}
+
+	tools := make([]goTool, 0, len(entries))
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		info, err := readBuildInfoFunc(filepath.Join(gobin, entry.Name()))
+		if err != nil {
+			continue
+		}
+
+		if info.Main.Path == "" || info.Main.Replace != nil || !strings.HasPrefix(info.Main.Version, "v") {
+			continue
+		}
+
+		tools = append(tools, goTool{
+			Name:    strings.TrimSuffix(entry.Name(), ".exe"),
+			Package: info.Path,
+			Module:  info.Main.Path,
+			Version: info.Main.Version,
+		})
+	}
+
+	return tools, nil
+}
+
+// resolveGoBinDir は go env から go install のインストール先を返します。
+func resolveGoBinDir(ctx context.Context) (string, error) {
+	return goBinDir(goEnvFunc(ctx))
+}
+
+// goBinDir は go install のインストール先を返します。
+// GOBIN を優先し、未設定なら GOPATH の先頭要素の bin（GOPATH も未設定なら ~/go/bin）を使用します。
+func goBinDir(env goEnvironment) (string, error) {
+	if env.GOBIN != "" {
+		return env.GOBIN, nil
+	}
+
+	gopath := env.GOPATH
+	if gopath != "" {
+		gopath = filepath.SplitList(gopath)[0]
+	}
+
+	if gopath == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+
+		gopath = filepath.Join(home, "go")
+	}
+
+	return filepath.Join(gopath, "bin"), nil
+}
+
 // extractToolName はパッケージパスからツール名を抽出します
 // 例: "github.com/golangci/golangci-lint/cmd/golangci-lint@latest" -> "golangci-lint"
 func extractToolName(pkg string) string {
@@ -181,19 +489,9 @@ func DefaultGoTargets() []string {
 // ListInstalledGoTools は $GOPATH/bin または $GOBIN にインストールされたツールを一覧表示します。
 func ListInstalledGoTools() ([]string, error) {
 	// GOBIN を優先、なければ GOPATH/bin
-	gobin := os.Getenv("GOBIN")
-	if gobin == "" {
-		gopath := os.Getenv("GOPATH")
-		if gopath == "" {
-			home, err := os.UserHomeDir()
-			if err != nil {
-				return nil, err
-			}
-
-			gopath = home + "/go"
-		}
-
-		gobin = gopath + "/bin"
+	gobin, err := resolveGoBinDir(context.Background())
+	if err != nil {
+		return nil, err
 	}
 
 	entries, err := os.ReadDir(gobin)
@@ -235,3 +533,84 @@ func ParseGoVersionOutput(output string) (modulePath, version string) {
 
 	return
 }
+
+var _ PackageInstaller = (*GoUpdater)(nil)
+
+// PackageName は指定からバージョンを除いたパッケージパスを返します。
+// バイナリ名のみの指定（例: "gopls"）は、InstalledPackages で読み取ったツールのパッケージパスに解決します。
+func (g *GoUpdater) PackageName(spec string) string {
+	pkg, _ := splitGoTarget(spec)
+
+	if !strings.Contains(pkg, "/") {
+		if tool, ok := findGoTool(g.installed, pkg); ok {
+			return tool.Package
+		}
+	}
+
+	return pkg
+}
+
+// InstalledPackages は GOBIN 配下のバイナリのビルド情報からパッケージパスを返します。
+func (g *GoUpdater) InstalledPackages(ctx context.Context) ([]string, error) {
+	gobin, err := resolveGoBinDir(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tools, err := discoverGoTools(gobin)
+	if err != nil {
+		return nil, err
+	}
+
+	g.installed = tools
+
+	names := make([]string, 0, len(tools))
+	for _, tool := range tools {
+		names = append(names, tool.Package)
+	}
+
+	return names, nil
+}
+
+// InstallPackage は go install でツールをインストールします。バージョン未指定の場合は @latest を使用します。
+// バイナリ名のみの指定はインストール元を特定できないため、パッケージパスの指定を求めます。
+func (g *GoUpdater) InstallPackage(ctx context.Context, spec string) error {
+	pkg, version := splitGoTarget(spec)
+	if !strings.Contains(pkg, "/") {
+		return fmt.Errorf("%q はインストールされていません。インストールするにはパッケージパスを指定してください（例: golang.org/x/tools/gopls）", pkg)
+	}
+
+	return runPackageCommand(ctx, "go", "install", pkg+"@"+version)
+}
+
+// RemovePackage は GOBIN 配下から該当パッケージのバイナリを削除します。
+func (g *GoUpdater) RemovePackage(ctx context.Context, name string) error {
+	gobin, err := resolveGoBinDir(ctx)
+	if err != nil {
+		return err
+	}
+
+	tools, err := discoverGoTools(gobin)
+	if err != nil {
+		return err
+	}
+
+	tool, ok := findGoTool(tools, name)
+	if !ok {
+		return fmt.Errorf("%s のバイナリが %s に見つかりません", name, gobin)
+	}
+
+	// discoverGoTools は Windows の .exe 拡張子を除いた名前を返すため、両方を試す
+	for _, file := range []string{tool.Name, tool.Name + ".exe"} {
+		err := os.Remove(filepath.Join(gobin, file))
+		if err == nil {
+			return nil
+		}
+
+		if !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("%s の削除に失敗: %w", file, err)
+		}
+	}
+
+	return fmt.Errorf("%s のバイナリが %s に見つかりません", name, gobin)
+}
@@ -0,0 +1,393 @@
+package updater
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// defaultGoProxy は GOPROXY 未設定時に Go が使用する既定値です。
+const defaultGoProxy = "https://proxy.golang.org,direct"
+
+// goProxyHTTPClient はモジュールプロキシへの問い合わせに使用する HTTP クライアントです。
+var goProxyHTTPClient = &http.Client{Timeout: 30 * time.Second}
+
+// errGoModuleNotFound はプロキシにモジュールが存在しない（404/410）ことを表します。
+var errGoModuleNotFound = errors.New("モジュールが見つかりません")
+
+// errGoModuleUnverifiable はモジュールプロキシで最新バージョンを確認できないことを表します。
+// GOPRIVATE / GONOPROXY の対象、GOPROXY が direct / off のみ、プロキシに無く direct にフォールバックする場合が該当し、
+// 呼び出し側はバージョン不明のまま go install <module>@latest を実行します。
+var errGoModuleUnverifiable = errors.New("モジュールプロキシで最新バージョンを確認できません")
+
+// goProxyLatest はモジュールプロキシの `@latest` 応答です。
+type goProxyLatest struct {
+	Version string `json:"Version"`
+}
+
+// goEnvironment は `go env -json` で解決した Go の設定値です。
+// `go env -w` で書き込んだ値も反映されます。
+type goEnvironment struct {
+	GOPROXY   string `json:"GOPROXY"`
+	GONOPROXY string `json:"GONOPROXY"`
+	GOPRIVATE string `json:"GOPRIVATE"`
+	GOBIN     string `json:"GOBIN"`
+	GOPATH    string `json:"GOPATH"`
+}
+
+// goEnvFunc はテストで差し替え可能な Go の設定値の読み取り処理です。
+var goEnvFunc = readGoEnv
+
+// readGoEnv は `go env -json` で Go の設定値を読み取ります。
+// go コマンドを実行できない場合は環境変数の値を使用します。
+func readGoEnv(ctx context.Context) goEnvironment {
+	cmd := exec.CommandContext(ctx, "go", "env", "-json", "GOPROXY", "GONOPROXY", "GOPRIVATE", "GOBIN", "GOPATH")
+	cmd.Env = os.Environ()
+
+	var env goEnvironment
+
+	output, err := cmd.Output()
+	if err == nil && json.Unmarshal(output, &env) == nil {
+		return env
+	}
+
+	return goEnvironment{
+		GOPROXY:   os.Getenv("GOPROXY"),
+		GONOPROXY: os.Getenv("GONOPROXY"),
+		GOPRIVATE: os.Getenv("GOPRIVATE"),
+		GOBIN:     os.Getenv("GOBIN"),
+		GOPATH:    os.Getenv("GOPATH"),
+	}
+}
+
+// goProxyEntry は GOPROXY の 1 エントリです。
+type goProxyEntry struct {
+	// url は問い合わせ先のベース URL です。
+	url string
+	// fallbackOnError は直後の区切りが "|" の場合に true です。
+	// true ならあらゆるエラーで、false なら 404/410 の場合のみ次のプロキシを試します。
+	fallbackOnError bool
+}
+
+// goProxyClient はモジュールプロキシプロトコルで最新バージョンを問い合わせます。
+type goProxyClient struct {
+	// proxies は問い合わせ先の一覧（direct / off より前のもの）です。
+	proxies []goProxyEntry
+	// direct はプロキシで見つからない場合に go コマンドが direct（VCS から直接取得）にフォールバックすることを表します。
+	direct bool
+	// private は GONOPROXY / GOPRIVATE のパターン一覧です。一致するモジュールは問い合わせません。
+	private []string
+}
+
+// newGoProxyClient は設定値（空の場合は go env の GOPROXY）から goProxyClient を作成します。
+// GOPROXY が direct / off のみの場合、問い合わせ先のないクライアントを返します。
+func newGoProxyClient(configured string, env goEnvironment) *goProxyClient {
+	value := strings.TrimSpace(configured)
+	if value == "" {
+		value = strings.TrimSpace(env.GOPROXY)
+	}
+
+	if value == "" {
+		value = defaultGoProxy
+	}
+
+	client := &goProxyClient{private: splitGoPatternList(env.GONOPROXY)}
+	client.proxies, client.direct = parseGoProxyList(value)
+
+	if len(client.private) == 0 {
+		client.private = splitGoPatternList(env.GOPRIVATE)
+	}
+
+	return client
+}
+
+// parseGoProxyList は GOPROXY の値から問い合わせ可能なエントリを取り出します。
+// "," と "|" の区切りを受け付け、各エントリには直後の区切りを記録します。
+// direct / off 以降は go コマンドがプロキシを使わないため読み取らず、direct の有無を返します。
+func parseGoProxyList(value string) ([]goProxyEntry, bool) {
+	proxies := make([]goProxyEntry, 0, 2)
+
+	for value != "" {
+		field := value
+		separator := byte(0)
+
+		if idx := strings.IndexAny(value, ",|"); idx != -1 {
+			field, separator, value = value[:idx], value[idx], value[idx+1:]
+		} else {
+			value = ""
+		}
+
+		switch field = strings.TrimSpace(field); field {
+		case "":
+			continue
+		case "direct":
+			return proxies, true
+		case "off":
+			return proxies, false
+		}
+
+		proxies = append(proxies, goProxyEntry{
+			url:             strings.TrimRight(field, "/"),
+			fallbackOnError: separator == '|',
+		})
+	}
+
+	return proxies, false
+}
+
+func splitGoPatternList(value string) []string {
+	patterns := make([]string, 0, 2)
+
+	for _, pattern := range strings.Split(value, ",") {
+		if trimmed := strings.TrimSpace(pattern); trimmed != "" {
+			patterns = append(patterns, trimmed)
+		}
+	}
+
+	return patterns
+}
+
+// isPrivate はモジュールパスが GONOPROXY / GOPRIVATE のパターンに一致するかを判定します。
+// パターンはパス要素の先頭部分に対して glob で照合します（go コマンドと同じ規則）。
+func (c *goProxyClient) isPrivate(modulePath string) bool {
+	elements := strings.Split(modulePath, "/")
+
+	for _, pattern := range c.private {
+		count := strings.Count(pattern, "/") + 1
+		if count > len(elements) {
+			continue
+		}
+
+		if matched, err := path.Match(pattern, strings.Join(elements[:count], "/")); err == nil && matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Latest はモジュールの最新バージョンを返します。
+// go コマンドと同様に、"," の後ろのプロキシは 404/410 の場合のみ、"|" の後ろのプロキシは
+// あらゆるエラーの場合に試します。いずれにも存在しなければ errGoModuleNotFound を返します。
+// プロキシで確認できないモジュール（GOPRIVATE 対象、direct / off のみ、direct へのフォールバック）は
+// errGoModuleUnverifiable を返します。
+func (c *goProxyClient) Latest(ctx context.Context, modulePath string) (string, error) {
+	if len(c.proxies) == 0 || c.isPrivate(modulePath) {
+		return "", fmt.Errorf("%s: %w", modulePath, errGoModuleUnverifiable)
+	}
+
+	escaped, err := escapeGoModulePath(modulePath)
+	if err != nil {
+		return "", err
+	}
+
+	var lastErr error
+
+	for _, proxy := range c.proxies {
+		body, err := fetchGoProxy(ctx, proxy.url+"/"+escaped+"/@latest")
+		if err != nil {
+			if errors.Is(err, errGoModuleNotFound) || proxy.fallbackOnError {
+				lastErr = err
+				continue
+			}
+
+			return "", err
+		}
+
+		var latest goProxyLatest
+		if err := json.Unmarshal(body, &latest); err != nil {
+			return "", fmt.Errorf("%s の @latest 応答の解析に失敗: %w", modulePath, err)
+		}
+
+		if latest.Version == "" {
+			return "", fmt.Errorf("%s の @latest 応答にバージョンがありません", modulePath)
+		}
+
+		return latest.Version, nil
+	}
+
+	if c.direct {
+		return "", fmt.Errorf("%s: %w", modulePath, errGoModuleUnverifiable)
+	}
+
+	return "", fmt.Errorf("%s: %w", modulePath, lastErr)
+}
+
+// fetchGoProxy は http(s):// または file:// の URL から内容を取得します。
+func fetchGoProxy(ctx context.Context, rawURL string) ([]byte, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("GOPROXY の URL が不正です: %w", err)
+	}
+
+	if parsed.Scheme == "file" {
+		filePath, err := goProxyFilePath(parsed, runtime.GOOS)
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := os.ReadFile(filePath)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, errGoModuleNotFound
+		}
+
+		return data, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := goProxyHTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("モジュールプロキシへの問い合わせに失敗: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return nil, errGoModuleNotFound
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("モジュールプロキシが %s を返しました: %s", resp.Status, rawURL)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+// goProxyFilePath は file:// URL をローカルパスに変換します。
+// Windows では file:///C:/goproxy をドライブパス、file://host/share を UNC パスとして扱います。
+func goProxyFilePath(fileURL *url.URL, goos string) (string, error) {
+	host := fileURL.Host
+	if host == "localhost" {
+		host = ""
+	}
+
+	p := fileURL.Path
+
+	if goos != windowsOS {
+		if host != "" {
+			return "", fmt.Errorf("file URL のホスト指定には対応していません: %s", fileURL)
+		}
+
+		return p, nil
+	}
+
+	if host != "" {
+		return `\\` + host + strings.ReplaceAll(p, "/", `\`), nil
+	}
+
+	// "/C:/goproxy" の先頭スラッシュを取り除く
+	if len(p) >= 3 && p[0] == '/' && p[2] == ':' {
+		p = p[1:]
+	}
+
+	return strings.ReplaceAll(p, "/", `\`), nil
+}
+
+// escapeGoModulePath はモジュールプロキシ用にパスをエスケープします。
+// 大文字は "!" + 小文字に変換されます（例: github.com/BurntSushi -> github.com/!burnt!sushi）。
+func escapeGoModulePath(modulePath string) (string, error) {
+	if modulePath == "" || strings.Contains(modulePath, "!") {
+		return "", fmt.Errorf("不正なモジュールパスです: %q", modulePath)
+	}
+
+	var builder strings.Builder
+
+	for _, r := range modulePath {
+		if unicode.IsUpper(r) {
+			builder.WriteByte('!')
+			builder.WriteRune(unicode.ToLower(r))
+
+			continue
+		}
+
+		builder.WriteRune(r)
+	}
+
+	return builder.String(), nil
+}
+
+// isGoVersionLess は Go モジュールのバージョン left が right より古いかを判定します。
+// プレリリース（擬似バージョンを含む）は同じ major.minor.patch のリリースより古いとみなし、
+// "+incompatible" などのビルドメタデータは無視します。
+func isGoVersionLess(left, right string) (bool, error) {
+	leftCore, leftPre := splitGoVersion(left)
+	rightCore, rightPre := splitGoVersion(right)
+
+	if leftCore != rightCore {
+		return isSemverLess(leftCore, rightCore)
+	}
+
+	if _, err := parseSemver(leftCore); err != nil {
+		return false, err
+	}
+
+	switch {
+	case leftPre == rightPre:
+		return false, nil
+	case leftPre == "":
+		return false, nil
+	case rightPre == "":
+		return true, nil
+	default:
+		return comparePrerelease(leftPre, rightPre) < 0, nil
+	}
+}
+
+func splitGoVersion(version string) (core, prerelease string) {
+	version = strings.TrimSpace(version)
+	if idx := strings.IndexByte(version, '+'); idx != -1 {
+		version = version[:idx]
+	}
+
+	if idx := strings.IndexByte(version, '-'); idx != -1 {
+		return version[:idx], version[idx+1:]
+	}
+
+	return version, ""
+}
+
+// comparePrerelease は semver のプレリリース識別子を比較します（数値は数値として比較）。
+func comparePrerelease(left, right string) int {
+	leftParts := strings.Split(left, ".")
+	rightParts := strings.Split(right, ".")
+
+	for i := 0; i < len(leftParts) && i < len(rightParts); i++ {
+		leftNum, leftErr := strconv.Atoi(leftParts[i])
+		rightNum, rightErr := strconv.Atoi(rightParts[i])
+
+		switch {
+		case leftErr == nil && rightErr == nil:
+			if leftNum != rightNum {
+				if leftNum < rightNum {
+					return -1
+				}
+
+				return 1
+			}
+		case leftErr == nil:
+			return -1
+		case rightErr == nil:
+			return 1
+		default:
+			if c := strings.Compare(leftParts[i], rightParts[i]); c != 0 {
+				return c
+			}
+		}
+	}
+
+	return len(leftParts) - len(rightParts)
+}
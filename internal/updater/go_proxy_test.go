@@ -0,0 +1,204 @@
+package updater
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEscapeGoModulePath(t *testing.T) {
+	got, err := escapeGoModulePath("github.com/BurntSushi/toml")
+	require.NoError(t, err)
+	assert.Equal(t, "github.com/!burnt!sushi/toml", got)
+
+	_, err = escapeGoModulePath("")
+	assert.Error(t, err)
+}
+
+func TestParseGoProxyList(t *testing.T) {
+	proxies, direct := parseGoProxyList("https://proxy.golang.org,direct")
+	assert.Equal(t, []goProxyEntry{{url: "https://proxy.golang.org"}}, proxies)
+	assert.True(t, direct)
+
+	proxies, direct = parseGoProxyList(" https://a.example/ | file:///srv/goproxy ,off,https://b.example")
+	assert.Equal(t, []goProxyEntry{
+		{url: "https://a.example", fallbackOnError: true},
+		{url: "file:///srv/goproxy"},
+	}, proxies)
+	assert.False(t, direct, "off 以降は読み取らない")
+
+	proxies, direct = parseGoProxyList("direct")
+	assert.Empty(t, proxies)
+	assert.True(t, direct)
+}
+
+func TestGoProxyClientLatest_Fallback(t *testing.T) {
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer broken.Close()
+
+	missing := httptest.NewServer(http.NotFoundHandler())
+	defer missing.Close()
+
+	good := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"Version":"v1.2.3"}`))
+	}))
+	defer good.Close()
+
+	tests := []struct {
+		name        string
+		goproxy     string
+		want        string
+		wantErr     error
+		errContains string
+	}{
+		{name: "カンマ区切りは 404 で次を試す", goproxy: missing.URL + "," + good.URL, want: "v1.2.3"},
+		{name: "プロキシに無く direct にフォールバック", goproxy: missing.URL + ",direct", wantErr: errGoModuleUnverifiable},
+		{name: "プロキシに無く direct もない", goproxy: missing.URL, wantErr: errGoModuleNotFound},
+		{name: "カンマ区切りは 500 で打ち切る", goproxy: broken.URL + "," + good.URL, errContains: "500"},
+		{name: "パイプ区切りは 500 でも次を試す", goproxy: broken.URL + "|" + good.URL, want: "v1.2.3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newGoProxyClient(tt.goproxy, goEnvironment{})
+
+			got, err := client.Latest(context.Background(), "example.com/tool")
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			if tt.errContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGoProxyFilePath(t *testing.T) {
+	tests := []struct {
+		rawURL  string
+		goos    string
+		want    string
+		wantErr bool
+	}{
+		{rawURL: "file:///srv/goproxy/x/@latest", goos: "linux", want: "/srv/goproxy/x/@latest"},
+		{rawURL: "file://localhost/srv/goproxy", goos: "darwin", want: "/srv/goproxy"},
+		{rawURL: "file://server/srv/goproxy", goos: "linux", wantErr: true},
+		{rawURL: "file:///C:/goproxy/x/@latest", goos: windowsOS, want: `C:\goproxy\x\@latest`},
+		{rawURL: "file://server/share/goproxy", goos: windowsOS, want: `\\server\share\goproxy`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.goos+" "+tt.rawURL, func(t *testing.T) {
+			parsed, err := url.Parse(tt.rawURL)
+			require.NoError(t, err)
+
+			got, err := goProxyFilePath(parsed, tt.goos)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGoProxyClientIsPrivate(t *testing.T) {
+	client := &goProxyClient{private: []string{"*.corp.example", "github.com/acme"}}
+
+	assert.True(t, client.isPrivate("git.corp.example/tools/x"))
+	assert.True(t, client.isPrivate("github.com/acme/tool"))
+	assert.False(t, client.isPrivate("github.com/acme2/tool"))
+	assert.False(t, client.isPrivate("golang.org/x/tools"))
+}
+
+func TestNewGoProxyClient(t *testing.T) {
+	t.Run("GOPROXY=off は問い合わせ先なし", func(t *testing.T) {
+		client := newGoProxyClient("", goEnvironment{GOPROXY: "off"})
+		assert.Empty(t, client.proxies)
+
+		_, err := client.Latest(context.Background(), "golang.org/x/tools/gopls")
+		assert.ErrorIs(t, err, errGoModuleUnverifiable)
+	})
+
+	t.Run("GOPRIVATE を GONOPROXY 未設定時に使用", func(t *testing.T) {
+		client := newGoProxyClient("", goEnvironment{GOPRIVATE: "example.com/private"})
+		assert.Equal(t, []goProxyEntry{{url: "https://proxy.golang.org"}}, client.proxies)
+		assert.True(t, client.direct)
+		assert.True(t, client.isPrivate("example.com/private/tool"))
+
+		_, err := client.Latest(context.Background(), "example.com/private/tool")
+		assert.ErrorIs(t, err, errGoModuleUnverifiable)
+	})
+
+	t.Run("設定値は GOPROXY より優先", func(t *testing.T) {
+		client := newGoProxyClient("https://corp.example", goEnvironment{GOPROXY: "off"})
+		assert.Equal(t, []goProxyEntry{{url: "https://corp.example"}}, client.proxies)
+	})
+}
+
+func TestReadGoEnv_UsesGoEnvFile(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go コマンドが見つかりません")
+	}
+
+	gobin := filepath.Join(t.TempDir(), "bin")
+	envFile := filepath.Join(t.TempDir(), "go.env")
+	require.NoError(t, os.WriteFile(envFile, []byte("GOBIN="+gobin+"\nGOPRIVATE=example.com/private\n"), 0o644))
+
+	t.Setenv("GOENV", envFile)
+	t.Setenv("GOBIN", "")
+	t.Setenv("GOPRIVATE", "")
+	t.Setenv("GONOPROXY", "")
+
+	env := readGoEnv(context.Background())
+	assert.Equal(t, gobin, env.GOBIN, "go env -w で書き込んだ値を使用する")
+	assert.Equal(t, "example.com/private", env.GOPRIVATE)
+}
+
+func TestIsGoVersionLess(t *testing.T) {
+	tests := []struct {
+		left, right string
+		want        bool
+	}{
+		{"v1.2.3", "v1.2.4", true},
+		{"v1.2.3", "v1.2.3", false},
+		{"v1.10.0", "v1.9.0", false},
+		{"v1.3.0-rc.1", "v1.3.0", true},
+		{"v1.3.0", "v1.3.0-rc.1", false},
+		{"v1.3.0-rc.2", "v1.3.0-rc.10", true},
+		{"v0.0.0-20240101000000-abcdef123456", "v0.0.0-20250101000000-123456abcdef", true},
+		{"v2.0.0+incompatible", "v2.0.1+incompatible", true},
+		{"v1.2.4", "v1.2.3", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.left+"<"+tt.right, func(t *testing.T) {
+			got, err := isGoVersionLess(tt.left, tt.right)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := isGoVersionLess("latest", "v1.0.0")
+	assert.Error(t, err)
+}
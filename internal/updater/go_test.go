@@ -2,10 +2,17 @@ package updater
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"runtime"
+	"runtime/debug"
 	"sort"
 	"strings"
+	"sync/atomic"
 	"testing"
 
 	"github.com/scottlz0310/devsync/internal/config"
@@ -99,77 +106,286 @@ func TestGoUpdater_Configure(t *testing.T) {
 	}
 }
 
+// setupFakeGoTools は GOBIN にダミーのバイナリを作成し、ビルド情報と go env の読み取りを差し替えます。
+// latest はモジュールパスごとの @latest 応答で、httptest のモジュールプロキシを GOPROXY に設定します。
+func setupFakeGoTools(t *testing.T, tools map[string]*debug.BuildInfo, latest map[string]string) *atomic.Int32 {
+	t.Helper()
+
+	gobin := t.TempDir()
+	t.Setenv("GOBIN", gobin)
+	t.Setenv("GONOPROXY", "")
+	t.Setenv("GOPRIVATE", "")
+
+	for name := range tools {
+		require.NoError(t, os.WriteFile(filepath.Join(gobin, name), []byte("binary"), 0o755))
+	}
+
+	require.NoError(t, os.WriteFile(filepath.Join(gobin, "script.sh"), []byte("#!/bin/sh"), 0o755))
+
+	original := readBuildInfoFunc
+	readBuildInfoFunc = func(path string) (*debug.BuildInfo, error) {
+		info, ok := tools[filepath.Base(path)]
+		if !ok {
+			return nil, errors.New("not a Go binary")
+		}
+
+		return info, nil
+	}
+
+	originalGoEnv := goEnvFunc
+	goEnvFunc = func(context.Context) goEnvironment {
+		return goEnvironment{
+			GOPROXY:   os.Getenv("GOPROXY"),
+			GONOPROXY: os.Getenv("GONOPROXY"),
+			GOPRIVATE: os.Getenv("GOPRIVATE"),
+			GOBIN:     os.Getenv("GOBIN"),
+		}
+	}
+
+	t.Cleanup(func() {
+		readBuildInfoFunc = original
+		goEnvFunc = originalGoEnv
+	})
+
+	var requests atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+
+		modulePath, ok := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/"), "/@latest")
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		version, ok := latest[modulePath]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		fmt.Fprintf(w, `{"Version":%q,"Time":"2026-01-01T00:00:00Z"}`, version)
+	}))
+	t.Cleanup(server.Close)
+
+	t.Setenv("GOPROXY", server.URL)
+
+	return &requests
+}
+
+func fakeGoBuildInfo(pkg, module, version string) *debug.BuildInfo {
+	return &debug.BuildInfo{
+		Path: pkg,
+		Main: debug.Module{Path: module, Version: version},
+	}
+}
+
 func TestGoUpdater_Check(t *testing.T) {
-	t.Run("更新対象なし", func(t *testing.T) {
-		g := &GoUpdater{}
+	tools := map[string]*debug.BuildInfo{
+		"gopls":         fakeGoBuildInfo("golang.org/x/tools/gopls", "golang.org/x/tools/gopls", "v0.16.0"),
+		"golangci-lint": fakeGoBuildInfo("github.com/golangci/golangci-lint/v2/cmd/golangci-lint", "github.com/golangci/golangci-lint/v2", "v2.1.0"),
+		"toml":          fakeGoBuildInfo("github.com/BurntSushi/toml/cmd/tomlv", "github.com/BurntSushi/toml", "v1.4.0"),
+		"local":         fakeGoBuildInfo("example.com/local", "example.com/local", "(devel)"),
+	}
+	latest := map[string]string{
+		"golang.org/x/tools/gopls":             "v0.17.1",
+		"github.com/golangci/golangci-lint/v2": "v2.1.0",
+		"github.com/!burnt!sushi/toml":         "v1.5.0",
+	}
 
-		got, err := g.Check(context.Background())
+	t.Run("targets なしはインストール済みツールを自動で対象にし古いものだけ報告", func(t *testing.T) {
+		requests := setupFakeGoTools(t, tools, latest)
+
+		got, err := (&GoUpdater{}).Check(context.Background())
+		require.NoError(t, err)
+
+		assert.Equal(t, 2, got.AvailableUpdates)
+		assert.Contains(t, got.Message, "2 件")
+		assert.ElementsMatch(t, []PackageInfo{
+			{Name: "gopls", CurrentVersion: "v0.16.0", NewVersion: "v0.17.1", Source: "golang.org/x/tools/gopls"},
+			{Name: "toml", CurrentVersion: "v1.4.0", NewVersion: "v1.5.0", Source: "github.com/BurntSushi/toml"},
+		}, got.Packages)
+		assert.Equal(t, int32(3), requests.Load(), "(devel) やGo製でないファイルは問い合わせない")
+	})
+
+	t.Run("すべて最新", func(t *testing.T) {
+		setupFakeGoTools(t, map[string]*debug.BuildInfo{"golangci-lint": tools["golangci-lint"]}, latest)
+
+		got, err := (&GoUpdater{}).Check(context.Background())
 		require.NoError(t, err)
-		require.NotNil(t, got)
 
 		assert.Equal(t, 0, got.AvailableUpdates)
-		assert.Contains(t, got.Message, "設定されていません")
 		assert.Empty(t, got.Packages)
+		assert.Contains(t, got.Message, "最新")
 	})
 
-	t.Run("更新対象あり", func(t *testing.T) {
-		g := &GoUpdater{
-			targets: []string{
-				"golang.org/x/tools/gopls@latest",
-				"github.com/fatih/gomodifytags",
-			},
-		}
+	t.Run("targets 指定時は対象のみ、未インストールは @latest でインストール対象", func(t *testing.T) {
+		setupFakeGoTools(t, tools, latest)
+
+		g := &GoUpdater{targets: []string{"golang.org/x/tools/gopls@latest", "github.com/go-delve/delve/cmd/dlv"}}
 
 		got, err := g.Check(context.Background())
 		require.NoError(t, err)
-		require.NotNil(t, got)
 
-		assert.Equal(t, 2, got.AvailableUpdates)
-		assert.Contains(t, got.Message, "2 件")
-		require.Len(t, got.Packages, 2)
+		assert.Equal(t, []PackageInfo{
+			{Name: "gopls", CurrentVersion: "v0.16.0", NewVersion: "v0.17.1", Source: "golang.org/x/tools/gopls"},
+			{Name: "dlv", NewVersion: "@latest"},
+		}, got.Packages)
+	})
+
+	t.Run("targets のバージョン固定はプロキシに問い合わせない", func(t *testing.T) {
+		requests := setupFakeGoTools(t, tools, latest)
 
-		assert.Equal(t, "gopls", got.Packages[0].Name)
-		assert.Equal(t, "@latest", got.Packages[0].NewVersion)
-		assert.Equal(t, "gomodifytags", got.Packages[1].Name)
-		assert.Equal(t, "@latest", got.Packages[1].NewVersion)
+		g := &GoUpdater{targets: []string{"gopls@v0.16.0", "golang.org/x/tools/gopls@v0.16.2"}}
+
+		got, err := g.Check(context.Background())
+		require.NoError(t, err)
+
+		assert.Equal(t, []PackageInfo{{Name: "gopls", CurrentVersion: "v0.16.0", NewVersion: "v0.16.2", Source: "golang.org/x/tools/gopls"}}, got.Packages)
+		assert.Equal(t, int32(0), requests.Load())
+	})
+
+	t.Run("一部のモジュールが見つからない場合は件数をメッセージに含める", func(t *testing.T) {
+		setupFakeGoTools(t, tools, map[string]string{"golang.org/x/tools/gopls": "v0.17.1"})
+
+		got, err := (&GoUpdater{}).Check(context.Background())
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, got.AvailableUpdates)
+		assert.Contains(t, got.Message, "2 件はバージョンを確認できませんでした")
+	})
+
+	t.Run("すべての問い合わせに失敗してもエラーにしない", func(t *testing.T) {
+		setupFakeGoTools(t, tools, map[string]string{})
+
+		got, err := (&GoUpdater{}).Check(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 0, got.AvailableUpdates)
+		assert.Contains(t, got.Message, "3 件はバージョンを確認できませんでした")
+	})
+
+	t.Run("プロキシで確認できないモジュールはバージョン不明として @latest を対象にする", func(t *testing.T) {
+		requests := setupFakeGoTools(t, tools, latest)
+		t.Setenv("GOPRIVATE", "github.com/BurntSushi")
+
+		got, err := (&GoUpdater{}).Check(context.Background())
+		require.NoError(t, err)
+
+		assert.ElementsMatch(t, []PackageInfo{
+			{Name: "gopls", CurrentVersion: "v0.16.0", NewVersion: "v0.17.1", Source: "golang.org/x/tools/gopls"},
+			{Name: "toml", CurrentVersion: "v1.4.0", NewVersion: "@latest", Source: "github.com/BurntSushi/toml"},
+		}, got.Packages)
+		assert.Contains(t, got.Message, "1 件は最新バージョン不明")
+		assert.Equal(t, int32(2), requests.Load(), "GOPRIVATE 対象は問い合わせない")
+	})
+
+	t.Run("GOPROXY=direct はすべて @latest を対象にする", func(t *testing.T) {
+		requests := setupFakeGoTools(t, tools, latest)
+		t.Setenv("GOPROXY", "direct")
+
+		got, err := (&GoUpdater{}).Check(context.Background())
+		require.NoError(t, err)
+
+		assert.Equal(t, 3, got.AvailableUpdates)
+		assert.Contains(t, got.Message, "3 件は最新バージョン不明")
+		assert.Equal(t, int32(0), requests.Load())
+	})
+
+	t.Run("proxy 設定は GOPROXY より優先", func(t *testing.T) {
+		setupFakeGoTools(t, tools, latest)
+		t.Setenv("GOPROXY", "off")
+
+		proxyDir := t.TempDir()
+		latestFile := filepath.Join(proxyDir, "golang.org", "x", "tools", "gopls", "@latest")
+		require.NoError(t, os.MkdirAll(filepath.Dir(latestFile), 0o755))
+		require.NoError(t, os.WriteFile(latestFile, []byte(`{"Version":"v0.18.0"}`), 0o644))
+
+		g := &GoUpdater{targets: []string{"gopls"}}
+		require.NoError(t, g.Configure(config.ManagerConfig{"proxy": "file://" + filepath.ToSlash(proxyDir)}))
+
+		got, err := g.Check(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, []PackageInfo{{Name: "gopls", CurrentVersion: "v0.16.0", NewVersion: "v0.18.0", Source: "golang.org/x/tools/gopls"}}, got.Packages)
+	})
+
+	t.Run("GOBIN が存在しない場合は対象なし", func(t *testing.T) {
+		t.Setenv("GOBIN", filepath.Join(t.TempDir(), "missing"))
+
+		got, err := (&GoUpdater{}).Check(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 0, got.AvailableUpdates)
 	})
 }
 
 func TestGoUpdater_Update_DryRun(t *testing.T) {
+	tools := map[string]*debug.BuildInfo{
+		"gopls": fakeGoBuildInfo("golang.org/x/tools/gopls", "golang.org/x/tools/gopls", "v0.16.0"),
+	}
+
 	t.Run("更新対象なし", func(t *testing.T) {
-		g := &GoUpdater{}
+		setupFakeGoTools(t, tools, map[string]string{"golang.org/x/tools/gopls": "v0.16.0"})
 
-		got, err := g.Update(context.Background(), UpdateOptions{DryRun: true})
+		got, err := (&GoUpdater{}).Update(context.Background(), UpdateOptions{DryRun: true})
 		require.NoError(t, err)
 		require.NotNil(t, got)
 
-		assert.Contains(t, got.Message, "設定されていません")
+		assert.Contains(t, got.Message, "最新")
 		assert.Empty(t, got.Packages)
 		assert.Equal(t, 0, got.UpdatedCount)
 	})
 
 	t.Run("更新対象あり", func(t *testing.T) {
-		g := &GoUpdater{
-			targets: []string{
-				"golang.org/x/tools/gopls@latest",
-				"github.com/fatih/gomodifytags",
-			},
-		}
+		setupFakeGoTools(t, tools, map[string]string{"golang.org/x/tools/gopls": "v0.17.1"})
+
+		g := &GoUpdater{targets: []string{"golang.org/x/tools/gopls@latest", "github.com/fatih/gomodifytags"}}
 
 		got, err := g.Update(context.Background(), UpdateOptions{DryRun: true})
 		require.NoError(t, err)
 		require.NotNil(t, got)
 
 		assert.Contains(t, got.Message, "DryRun")
-		require.Len(t, got.Packages, 2)
-
-		assert.Equal(t, "gopls", got.Packages[0].Name)
-		assert.Equal(t, "@latest", got.Packages[0].NewVersion)
-		assert.Equal(t, "gomodifytags", got.Packages[1].Name)
-		assert.Equal(t, "@latest", got.Packages[1].NewVersion)
+		assert.Equal(t, []PackageInfo{
+			{Name: "gopls", CurrentVersion: "v0.16.0", NewVersion: "v0.17.1", Source: "golang.org/x/tools/gopls"},
+			{Name: "gomodifytags", NewVersion: "@latest"},
+		}, got.Packages)
 	})
 }
 
+func TestGoUpdater_Update_InstallsResolvedVersion(t *testing.T) {
+	if runtime.GOOS == windowsOS {
+		t.Skip("fake go コマンドは POSIX シェル前提")
+	}
+
+	setupFakeGoTools(t, map[string]*debug.BuildInfo{
+		"gopls": fakeGoBuildInfo("golang.org/x/tools/gopls", "golang.org/x/tools/gopls", "v0.16.0"),
+		"tool":  fakeGoBuildInfo("git.corp.example/tools/cmd/tool", "git.corp.example/tools", "v1.0.0"),
+	}, map[string]string{"golang.org/x/tools/gopls": "v0.17.1"})
+	t.Setenv("GOPRIVATE", "git.corp.example")
+
+	binDir := t.TempDir()
+	logFile := filepath.Join(binDir, "go.log")
+	script := "#!/bin/sh\necho \"$@\" >> " + quotePosixShellArg(logFile) + "\n"
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, "go"), []byte(script), 0o755))
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	got, err := (&GoUpdater{}).Update(context.Background(), UpdateOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, got.UpdatedCount)
+	assert.Empty(t, got.Errors)
+	assert.ElementsMatch(t, []PackageInfo{
+		{Name: "gopls", CurrentVersion: "v0.16.0", NewVersion: "v0.17.1", Source: "golang.org/x/tools/gopls"},
+		{Name: "tool", CurrentVersion: "v1.0.0", NewVersion: "@latest", Source: "git.corp.example/tools"},
+	}, got.Packages)
+
+	logged, err := os.ReadFile(logFile)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{
+		"install golang.org/x/tools/gopls@v0.17.1",
+		"install git.corp.example/tools/cmd/tool@latest",
+	}, strings.Split(strings.TrimSpace(string(logged)), "\n"))
+}
+
 func TestListInstalledGoTools(t *testing.T) {
 	t.Run("GOBIN配下のファイルを列挙する", func(t *testing.T) {
 		dir := t.TempDir()
@@ -0,0 +1,284 @@
+package updater
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/scottlz0310/devsync/internal/config"
+)
+
+// HelmPluginUpdater は helm プラグインの実装です。
+// git で導入されたプラグインについて、helm plugin update が取り込むリモートのブランチの先頭コミットと
+// ローカルのコミットを比較し、更新後は plugin.yaml を読み直して実際のバージョンを報告します。
+//
+//	sys:
+//	  managers:
+//	    helm:
+//	      exclude: ["diff"]  # 更新しないプラグイン
+type HelmPluginUpdater struct {
+	exclude []string
+}
+
+// helmPlugin は HELM_PLUGINS 配下のプラグインです。
+type helmPlugin struct {
+	name    string
+	version string
+	dir     string
+}
+
+// helmPluginUpdate は更新があるプラグインです。
+type helmPluginUpdate struct {
+	PackageInfo
+	dir string
+}
+
+// 起動時にレジストリに登録
+func init() {
+	Register(&HelmPluginUpdater{})
+}
+
+func (h *HelmPluginUpdater) Name() string {
+	return "helm"
+}
+
+func (h *HelmPluginUpdater) DisplayName() string {
+	return "helm (Helm プラグイン)"
+}
+
+func (h *HelmPluginUpdater) IsAvailable() bool {
+	_, err := exec.LookPath("helm")
+	return err == nil
+}
+
+func (h *HelmPluginUpdater) Configure(cfg config.ManagerConfig) error {
+	if cfg == nil {
+		return nil
+	}
+
+	if exclude, ok := toStringList(cfg["exclude"]); ok {
+		h.exclude = h.exclude[:0]
+
+		for _, name := range exclude {
+			h.exclude = append(h.exclude, strings.ToLower(name))
+		}
+	}
+
+	return nil
+}
+
+func (h *HelmPluginUpdater) Check(ctx context.Context) (*CheckResult, error) {
+	updates, err := h.planUpdates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	packages := make([]PackageInfo, 0, len(updates))
+	for _, update := range updates {
+		packages = append(packages, update.PackageInfo)
+	}
+
+	message := fmt.Sprintf("%d 件のプラグインが更新可能です", len(packages))
+	if len(packages) == 0 {
+		message = "すべてのプラグインは最新です"
+	}
+
+	return &CheckResult{
+		AvailableUpdates: len(packages),
+		Packages:         packages,
+		Message:          message,
+	}, nil
+}
+
+func (h *HelmPluginUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateResult, error) {
+	result := &UpdateResult{}
+
+	updates, err := h.planUpdates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(updates) == 0 {
+		result.Message = "すべてのプラグインは最新です"
+		return result, nil
+	}
+
+	if opts.DryRun {
+		for _, update := range updates {
+			result.Packages = append(result.Packages, update.PackageInfo)
+		}
+
+		result.Message = fmt.Sprintf("%d 件のプラグインを更新予定（DryRunモード）", len(updates))
+
+		return result, nil
+	}
+
+	for _, update := range updates {
+		if err := runPackageCommand(ctx, "helm", "plugin", "update", update.Name); err != nil {
+			result.FailedCount++
+			result.Errors = append(result.Errors, err)
+
+			continue
+		}
+
+		// 取り込んだコミットの plugin.yaml のバージョンを報告する
+		pkg := update.PackageInfo
+		if data, err := os.ReadFile(filepath.Join(update.dir, "plugin.yaml")); err == nil {
+			if version := parseHelmPluginYAML(string(data)).version; version != "" {
+				pkg.NewVersion = version
+			}
+		}
+
+		result.UpdatedCount++
+		result.Packages = append(result.Packages, pkg)
+	}
+
+	if result.FailedCount > 0 {
+		result.Message = fmt.Sprintf("%d 件更新、%d 件失敗", result.UpdatedCount, result.FailedCount)
+	} else {
+		result.Message = fmt.Sprintf("%d 件のプラグインを更新しました", result.UpdatedCount)
+	}
+
+	return result, nil
+}
+
+// planUpdates は git で導入されたプラグインのうち、追跡しているブランチのリモートの先頭がローカルと異なるものを返します。
+// helm plugin update はチェックアウト中のブランチを git pull するため、タグではなくブランチの先頭と比較します。
+// アーカイブから導入したプラグインと、バージョン指定（タグのチェックアウト）で導入したプラグインは
+// helm plugin update で更新されないため対象外です。
+// 更新前の NewVersion は plugin.yaml を取得できないため "<ブランチ>@<コミット>" で表します。
+func (h *HelmPluginUpdater) planUpdates(ctx context.Context) ([]helmPluginUpdate, error) {
+	output, err := runCommandOutputWithLocaleC(ctx, "helm", []string{"env", "HELM_PLUGINS"}, "helm env の実行に失敗: %w")
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make([]helmPluginUpdate, 0)
+
+	for _, plugin := range discoverHelmPlugins(strings.TrimSpace(string(output))) {
+		if containsString(h.exclude, strings.ToLower(plugin.name)) {
+			continue
+		}
+
+		if _, err := os.Stat(filepath.Join(plugin.dir, ".git")); err != nil {
+			continue
+		}
+
+		branch, local, remote, err := helmPluginRevisions(ctx, plugin.dir)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", plugin.name, err)
+		}
+
+		if branch == "" || remote == "" || remote == local {
+			continue
+		}
+
+		updates = append(updates, helmPluginUpdate{
+			PackageInfo: PackageInfo{Name: plugin.name, CurrentVersion: plugin.version, NewVersion: branch + "@" + shortCommit(remote)},
+			dir:         plugin.dir,
+		})
+	}
+
+	return updates, nil
+}
+
+// helmPluginRevisions はチェックアウト中のブランチと、ローカル・リモート（origin）の先頭コミットを返します。
+// ブランチをチェックアウトしていない（detached HEAD）場合は branch が空です。
+func helmPluginRevisions(ctx context.Context, dir string) (branch, local, remote string, err error) {
+	// symbolic-ref は detached HEAD の場合に終了コード 1 で失敗する
+	ref, refErr := runCommandOutputWithLocaleC(ctx, "git", []string{"-C", dir, "symbolic-ref", "--quiet", "--short", "HEAD"},
+		"git symbolic-ref の実行に失敗: %w")
+	if refErr != nil {
+		return "", "", "", nil
+	}
+
+	branch = strings.TrimSpace(string(ref))
+
+	head, err := runCommandOutputWithLocaleC(ctx, "git", []string{"-C", dir, "rev-parse", "HEAD"}, "git rev-parse の実行に失敗: %w")
+	if err != nil {
+		return "", "", "", err
+	}
+
+	heads, err := runCommandOutputWithLocaleC(ctx, "git", []string{"-C", dir, "ls-remote", "--heads", "origin", "refs/heads/" + branch},
+		"git ls-remote の実行に失敗: %w")
+	if err != nil {
+		return "", "", "", err
+	}
+
+	if fields := strings.Fields(string(heads)); len(fields) > 0 {
+		remote = fields[0]
+	}
+
+	return branch, strings.TrimSpace(string(head)), remote, nil
+}
+
+// shortCommit は表示用にコミットハッシュを 7 桁に短縮します。
+func shortCommit(commit string) string {
+	if len(commit) > 7 {
+		return commit[:7]
+	}
+
+	return commit
+}
+
+// discoverHelmPlugins は HELM_PLUGINS（複数の場合はパス区切り）配下の plugin.yaml を読み取ります。
+func discoverHelmPlugins(pluginsPath string) []helmPlugin {
+	var plugins []helmPlugin
+
+	for _, root := range filepath.SplitList(pluginsPath) {
+		entries, err := os.ReadDir(root)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			dir := filepath.Join(root, entry.Name())
+
+			data, err := os.ReadFile(filepath.Join(dir, "plugin.yaml"))
+			if err != nil {
+				continue
+			}
+
+			plugin := parseHelmPluginYAML(string(data))
+			if plugin.name == "" {
+				continue
+			}
+
+			plugin.dir = dir
+			plugins = append(plugins, plugin)
+		}
+	}
+
+	sort.Slice(plugins, func(i, j int) bool {
+		return plugins[i].name < plugins[j].name
+	})
+
+	return plugins
+}
+
+// parseHelmPluginYAML は plugin.yaml のトップレベルの name / version を取り出します。
+func parseHelmPluginYAML(content string) helmPlugin {
+	var plugin helmPlugin
+
+	for _, line := range strings.Split(content, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok || strings.HasPrefix(key, " ") || strings.HasPrefix(key, "\t") {
+			continue
+		}
+
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+
+		switch strings.TrimSpace(key) {
+		case "name":
+			plugin.name = value
+		case "version":
+			plugin.version = value
+		}
+	}
+
+	return plugin
+}
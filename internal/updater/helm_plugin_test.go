@@ -0,0 +1,117 @@
+package updater
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/scottlz0310/devsync/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseHelmPluginYAML(t *testing.T) {
+	content := `name: "diff"
+version: "3.9.4"
+usage: "Preview helm upgrade changes as a diff"
+hooks:
+  install: "$HELM_PLUGIN_DIR/install-binary.sh"
+`
+
+	assert.Equal(t, helmPlugin{name: "diff", version: "3.9.4"}, parseHelmPluginYAML(content))
+}
+
+func TestHelmPluginUpdater_CheckAndUpdate(t *testing.T) {
+	pluginsDir, logPath := writeFakeHelmCommands(t)
+
+	writeHelmPluginForTest(t, pluginsDir, "helm-diff", "diff", "3.9.4", true)
+	writeHelmPluginForTest(t, pluginsDir, "helm-secrets", "secrets", "4.5.0", true)
+	writeHelmPluginForTest(t, pluginsDir, "helm-archive", "archive", "0.1.0", false)
+	// 既定のブランチがタグより古いプラグイン（リモートのブランチの先頭と一致していれば最新）
+	writeHelmPluginForTest(t, pluginsDir, "helm-unittest", "unittest", "0.5.1", true)
+	// バージョン指定で導入したプラグイン（detached HEAD）は helm plugin update で更新されない
+	writeHelmPluginForTest(t, pluginsDir, "helm-pinned", "pinned", "1.0.0", true)
+
+	h := &HelmPluginUpdater{}
+	require.NoError(t, h.Configure(config.ManagerConfig{"exclude": []interface{}{"secrets"}}))
+
+	checkResult, err := h.Check(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []PackageInfo{{Name: "diff", CurrentVersion: "3.9.4", NewVersion: "master@4567def"}}, checkResult.Packages,
+		"除外したプラグインと git 管理外のプラグインは対象外")
+
+	result, err := h.Update(context.Background(), UpdateOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.UpdatedCount)
+	assert.Equal(t, []PackageInfo{{Name: "diff", CurrentVersion: "3.9.4", NewVersion: "3.10.0"}}, result.Packages,
+		"更新後の plugin.yaml のバージョンを報告する")
+
+	data, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	assert.Equal(t, "helm plugin update diff\n", string(data))
+}
+
+func writeHelmPluginForTest(t *testing.T, pluginsDir, dirName, name, version string, git bool) {
+	t.Helper()
+
+	dir := filepath.Join(pluginsDir, dirName)
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "plugin.yaml"),
+		[]byte("name: \""+name+"\"\nversion: \""+version+"\"\n"), 0o644))
+
+	if git {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, ".git"), 0o755))
+	}
+}
+
+// writeFakeHelmCommands は fake の helm / git を PATH に配置し、HELM_PLUGINS と実行ログのパスを返します。
+func writeFakeHelmCommands(t *testing.T) (string, string) {
+	t.Helper()
+
+	if runtime.GOOS == windowsOS {
+		t.Skip("fake helm は POSIX シェル前提")
+	}
+
+	pluginsDir := t.TempDir()
+	logPath := filepath.Join(t.TempDir(), "commands.log")
+	t.Setenv("DEVSYNC_TEST_HELM_PLUGINS", pluginsDir)
+	t.Setenv("DEVSYNC_TEST_HELM_LOG", logPath)
+
+	helm := `#!/bin/sh
+if [ "$1" = "env" ]; then
+  echo "${DEVSYNC_TEST_HELM_PLUGINS}"
+  exit 0
+fi
+echo "helm $*" >> "${DEVSYNC_TEST_HELM_LOG}"
+if [ "$1 $2 $3" = "plugin update diff" ]; then
+  printf 'name: "diff"\nversion: "3.10.0"\n' > "${DEVSYNC_TEST_HELM_PLUGINS}/helm-diff/plugin.yaml"
+fi
+`
+	// ローカルの HEAD は 0123abc...、リモートのブランチの先頭は helm-diff のみ異なる
+	git := `#!/bin/sh
+case "$3" in
+  symbolic-ref)
+    case "$2" in
+      */helm-pinned) exit 1 ;;
+      *) echo "master" ;;
+    esac
+    ;;
+  rev-parse) echo "0123abc0123abc0123abc0123abc0123abc0123a" ;;
+  ls-remote)
+    case "$2" in
+      */helm-diff) printf '4567def4567def4567def4567def4567def4567d\trefs/heads/master\n' ;;
+      *) printf '0123abc0123abc0123abc0123abc0123abc0123a\trefs/heads/master\n' ;;
+    esac
+    ;;
+esac
+`
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "helm"), []byte(helm), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "git"), []byte(git), 0o755))
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	return pluginsDir, logPath
+}
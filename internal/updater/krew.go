@@ -0,0 +1,248 @@
+package updater
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/scottlz0310/devsync/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+// KrewUpdater は kubectl krew (kubectl プラグイン) の実装です。
+// Check はローカルのプラグインインデックスと比較するだけで、インデックスの更新（kubectl krew update）は
+// Update の実行時のみ行います。
+//
+//	sys:
+//	  managers:
+//	    krew:
+//	      exclude: ["ctx"]  # 更新しないプラグイン
+type KrewUpdater struct {
+	exclude []string
+}
+
+// 起動時にレジストリに登録
+func init() {
+	Register(&KrewUpdater{})
+}
+
+func (k *KrewUpdater) Name() string {
+	return "krew"
+}
+
+func (k *KrewUpdater) DisplayName() string {
+	return "krew (kubectl プラグイン)"
+}
+
+// IsAvailable は kubectl と krew 本体（kubectl-krew）が PATH にあるかを判定します。
+func (k *KrewUpdater) IsAvailable() bool {
+	if _, err := exec.LookPath("kubectl"); err != nil {
+		return false
+	}
+
+	_, err := exec.LookPath("kubectl-krew")
+
+	return err == nil
+}
+
+func (k *KrewUpdater) Configure(cfg config.ManagerConfig) error {
+	if cfg == nil {
+		return nil
+	}
+
+	if exclude, ok := toStringList(cfg["exclude"]); ok {
+		k.exclude = k.exclude[:0]
+
+		for _, name := range exclude {
+			k.exclude = append(k.exclude, strings.ToLower(name))
+		}
+	}
+
+	return nil
+}
+
+func (k *KrewUpdater) Check(ctx context.Context) (*CheckResult, error) {
+	packages, err := k.planUpdates(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	message := fmt.Sprintf("%d 件のプラグインが更新可能です", len(packages))
+	if len(packages) == 0 {
+		message = "すべてのプラグインは最新です"
+	}
+
+	return &CheckResult{
+		AvailableUpdates: len(packages),
+		Packages:         packages,
+		Message:          message,
+	}, nil
+}
+
+func (k *KrewUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateResult, error) {
+	result := &UpdateResult{}
+
+	// DryRun ではローカルの状態を変更しないよう、インデックスを更新せずに比較する
+	packages, err := k.planUpdates(ctx, !opts.DryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(packages) == 0 {
+		result.Message = "すべてのプラグインは最新です"
+		return result, nil
+	}
+
+	if opts.DryRun {
+		result.Packages = packages
+		result.Message = fmt.Sprintf("%d 件のプラグインを更新予定（DryRunモード）", len(packages))
+
+		return result, nil
+	}
+
+	// インデックスは planUpdates で更新済み
+	args := []string{"krew", "upgrade", "--no-update-index"}
+	for _, pkg := range packages {
+		args = append(args, pkg.Name)
+	}
+
+	if err := runPackageCommand(ctx, "kubectl", args...); err != nil {
+		result.Errors = append(result.Errors, err)
+		return result, err
+	}
+
+	result.UpdatedCount = len(packages)
+	result.Packages = packages
+	result.Message = fmt.Sprintf("%d 件のプラグインを更新しました", result.UpdatedCount)
+
+	return result, nil
+}
+
+// planUpdates はインストール済みのバージョン（レシート）とプラグインインデックスのバージョンを比較します。
+// refreshIndex が true の場合は、先にインデックスを更新します（更新しないと新しいバージョンを検出できない）。
+func (k *KrewUpdater) planUpdates(ctx context.Context, refreshIndex bool) ([]PackageInfo, error) {
+	if refreshIndex {
+		if _, err := runCommandOutputWithLocaleC(ctx, "kubectl", []string{"krew", "update"}, "kubectl krew update の実行に失敗: %w"); err != nil {
+			return nil, err
+		}
+	}
+
+	output, err := runCommandOutputWithLocaleC(ctx, "kubectl", []string{"krew", "list"}, "kubectl krew list の実行に失敗: %w")
+	if err != nil {
+		return nil, err
+	}
+
+	receiptsDir := krewReceiptsDir()
+	packages := make([]PackageInfo, 0)
+
+	for _, plugin := range parseKrewList(string(output)) {
+		if containsString(k.exclude, strings.ToLower(plugin.Name)) {
+			continue
+		}
+
+		if version := readKrewReceiptVersion(receiptsDir, plugin.Name); version != "" {
+			plugin.CurrentVersion = version
+		}
+
+		if plugin.CurrentVersion == "" {
+			continue
+		}
+
+		info, err := runCommandOutputWithLocaleC(ctx, "kubectl", []string{"krew", "info", plugin.Name}, "kubectl krew info の実行に失敗: %w")
+		if err != nil {
+			return nil, err
+		}
+
+		latest := parseKrewInfoVersion(string(info))
+		if latest == "" || latest == plugin.CurrentVersion {
+			continue
+		}
+
+		if less, err := isSemverLess(plugin.CurrentVersion, latest); err == nil && !less {
+			continue
+		}
+
+		plugin.NewVersion = latest
+		packages = append(packages, plugin)
+	}
+
+	return packages, nil
+}
+
+// parseKrewList は `kubectl krew list` の出力を解析します。
+// krew は標準出力が端末の場合のみ PLUGIN / VERSION の表を出力し、パイプではプラグイン名のみを出力するため、
+// バージョン列がない場合はバージョンを空にします（インストール済みのバージョンはレシートから読み取ります）。
+func parseKrewList(output string) []PackageInfo {
+	plugins := make([]PackageInfo, 0)
+
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || fields[0] == "PLUGIN" {
+			continue
+		}
+
+		plugin := PackageInfo{Name: fields[0]}
+		if len(fields) > 1 {
+			plugin.CurrentVersion = fields[1]
+		}
+
+		plugins = append(plugins, plugin)
+	}
+
+	return plugins
+}
+
+// parseKrewInfoVersion は `kubectl krew info` の "VERSION: v0.4.4" 行からバージョンを取り出します。
+func parseKrewInfoVersion(output string) string {
+	for _, line := range strings.Split(output, "\n") {
+		if version, ok := strings.CutPrefix(strings.TrimSpace(line), "VERSION:"); ok {
+			return strings.TrimSpace(version)
+		}
+	}
+
+	return ""
+}
+
+// krewReceiptsDir はインストール済みプラグインのレシートのディレクトリ（$KREW_ROOT/receipts、既定は ~/.krew/receipts）を返します。
+func krewReceiptsDir() string {
+	root := strings.TrimSpace(os.Getenv("KREW_ROOT"))
+	if root == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+
+		root = filepath.Join(home, ".krew")
+	}
+
+	return filepath.Join(root, "receipts")
+}
+
+// readKrewReceiptVersion はレシート（<name>.yaml）の spec.version を返します。
+// カスタムインデックスのプラグイン（"index/name"）もレシートは名前のみのファイルです。読み取れない場合は空を返します。
+func readKrewReceiptVersion(receiptsDir, plugin string) string {
+	if receiptsDir == "" {
+		return ""
+	}
+
+	data, err := os.ReadFile(filepath.Join(receiptsDir, path.Base(plugin)+".yaml"))
+	if err != nil {
+		return ""
+	}
+
+	var receipt struct {
+		Spec struct {
+			Version string `yaml:"version"`
+		} `yaml:"spec"`
+	}
+
+	if err := yaml.Unmarshal(data, &receipt); err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(receipt.Spec.Version)
+}
@@ -0,0 +1,115 @@
+package updater
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/scottlz0310/devsync/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseKrewList(t *testing.T) {
+	testCases := []struct {
+		name   string
+		output string
+		want   []PackageInfo
+	}{
+		{
+			name:   "バージョン列あり",
+			output: "PLUGIN  VERSION\nctx     v0.9.5\nkrew    v0.4.4\n",
+			want:   []PackageInfo{{Name: "ctx", CurrentVersion: "v0.9.5"}, {Name: "krew", CurrentVersion: "v0.4.4"}},
+		},
+		{
+			name:   "パイプ出力は名前のみ",
+			output: "ctx\nns\n",
+			want:   []PackageInfo{{Name: "ctx"}, {Name: "ns"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, parseKrewList(tc.output))
+		})
+	}
+}
+
+func TestReadKrewReceiptVersion(t *testing.T) {
+	dir := t.TempDir()
+	writeKrewReceipt(t, dir, "ctx", "v0.9.4")
+
+	assert.Equal(t, "v0.9.4", readKrewReceiptVersion(dir, "ctx"))
+	assert.Equal(t, "v0.9.4", readKrewReceiptVersion(dir, "my-index/ctx"), "カスタムインデックスも名前のみのレシート")
+	assert.Empty(t, readKrewReceiptVersion(dir, "ns"))
+	assert.Empty(t, readKrewReceiptVersion("", "ctx"))
+}
+
+func TestKrewUpdater_CheckAndUpdate(t *testing.T) {
+	logPath := writeFakeKubectlCommand(t)
+
+	k := &KrewUpdater{}
+	require.NoError(t, k.Configure(config.ManagerConfig{"exclude": []interface{}{"ns"}}))
+
+	checkResult, err := k.Check(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []PackageInfo{{Name: "ctx", CurrentVersion: "v0.9.4", NewVersion: "v0.9.5"}}, checkResult.Packages)
+
+	result, err := k.Update(context.Background(), UpdateOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.UpdatedCount)
+
+	data, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	assert.Equal(t, "kubectl krew update\nkubectl krew upgrade --no-update-index ctx\n", string(data),
+		"インデックスは Update の実行時のみ更新し、除外したプラグインは更新しない")
+}
+
+func writeFakeKubectlCommand(t *testing.T) string {
+	t.Helper()
+
+	if runtime.GOOS == windowsOS {
+		t.Skip("fake kubectl は POSIX シェル前提")
+	}
+
+	logPath := filepath.Join(t.TempDir(), "commands.log")
+	t.Setenv("DEVSYNC_TEST_KUBECTL_LOG", logPath)
+
+	script := `#!/bin/sh
+case "$2 $3" in
+  "update ") echo "kubectl $*" >> "${DEVSYNC_TEST_KUBECTL_LOG}"; echo "Updated the local copy of plugin index." 1>&2 ;;
+  "list ") printf 'ctx\nkrew\nns\n' ;;
+  "info ctx") printf 'NAME: ctx\nINDEX: default\nVERSION: v0.9.5\n' ;;
+  "info krew") printf 'NAME: krew\nINDEX: default\nVERSION: v0.4.4\n' ;;
+  "info ns") printf 'NAME: ns\nINDEX: default\nVERSION: v0.9.5\n' ;;
+  *) echo "kubectl $*" >> "${DEVSYNC_TEST_KUBECTL_LOG}" ;;
+esac
+`
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "kubectl"), []byte(script), 0o755))
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	// パイプでは krew list がバージョンを出力しないため、バージョンはレシートから読む
+	krewRoot := t.TempDir()
+	t.Setenv("KREW_ROOT", krewRoot)
+
+	receiptsDir := filepath.Join(krewRoot, "receipts")
+	writeKrewReceipt(t, receiptsDir, "ctx", "v0.9.4")
+	writeKrewReceipt(t, receiptsDir, "krew", "v0.4.4")
+	writeKrewReceipt(t, receiptsDir, "ns", "v0.9.4")
+
+	return logPath
+}
+
+func writeKrewReceipt(t *testing.T, dir, name, version string) {
+	t.Helper()
+
+	receipt := "apiVersion: krew.googlecontainertools.github.com/v1alpha2\nkind: Plugin\nmetadata:\n  name: " + name +
+		"\nspec:\n  version: " + version + "\n  homepage: https://example.com\nstatus:\n  source:\n    name: default\n"
+
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name+".yaml"), []byte(receipt), 0o644))
+}
@@ -0,0 +1,144 @@
+package updater
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/scottlz0310/devsync/internal/config"
+)
+
+// MiseUpdater は mise（旧 rtx、複数言語のランタイムバージョン管理）の実装です。
+type MiseUpdater struct {
+	settings runtimeUpdateSettings
+}
+
+var _ runtimeManager = (*MiseUpdater)(nil)
+
+// miseListEntry は "mise ls --json" の要素です。
+type miseListEntry struct {
+	Version          string `json:"version"`
+	RequestedVersion string `json:"requested_version"`
+	Installed        bool   `json:"installed"`
+}
+
+// 起動時にレジストリに登録
+func init() {
+	Register(&MiseUpdater{settings: defaultRuntimeUpdateSettings()})
+}
+
+func (m *MiseUpdater) Name() string {
+	return "mise"
+}
+
+func (m *MiseUpdater) DisplayName() string {
+	return "mise (ランタイムバージョン管理)"
+}
+
+func (m *MiseUpdater) IsAvailable() bool {
+	_, err := exec.LookPath("mise")
+	return err == nil
+}
+
+func (m *MiseUpdater) Configure(cfg config.ManagerConfig) error {
+	if cfg == nil {
+		return nil
+	}
+
+	m.settings.configure(cfg)
+
+	return nil
+}
+
+func (m *MiseUpdater) Check(ctx context.Context) (*CheckResult, error) {
+	return checkRuntimeUpdates(ctx, m, m.settings)
+}
+
+func (m *MiseUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateResult, error) {
+	return updateRuntimeVersions(ctx, opts, m.Name(), m, m.settings)
+}
+
+// selfUpdate は mise 本体（self-update）とプラグインを更新します。
+// パッケージマネージャで導入した mise は self-update に失敗するため、その場合は self_update: false を指定してください。
+func (m *MiseUpdater) selfUpdate(ctx context.Context) error {
+	if err := runSystemCommand(ctx, false, "mise", "self-update", "--yes"); err != nil {
+		return fmt.Errorf("mise self-update に失敗: %w", err)
+	}
+
+	if err := runSystemCommand(ctx, false, "mise", "plugins", "update"); err != nil {
+		return fmt.Errorf("mise plugins update に失敗: %w", err)
+	}
+
+	return nil
+}
+
+func (m *MiseUpdater) installedVersions(ctx context.Context) (map[string][]string, error) {
+	entries, err := m.list(ctx, "--installed")
+	if err != nil {
+		return nil, err
+	}
+
+	installed := make(map[string][]string, len(entries))
+
+	for tool, toolEntries := range entries {
+		for _, entry := range toolEntries {
+			if entry.Installed {
+				installed[tool] = append(installed[tool], entry.Version)
+			}
+		}
+	}
+
+	return installed, nil
+}
+
+func (m *MiseUpdater) availableVersions(ctx context.Context, tool string) ([]string, error) {
+	output, err := runCommandOutputWithLocaleC(ctx, "mise", []string{"ls-remote", tool}, "mise ls-remote の実行に失敗: %w")
+	if err != nil {
+		return nil, err
+	}
+
+	return runtimeVersionTokens(string(output)), nil
+}
+
+// globalVersion はグローバル設定でバージョンを完全指定している場合のみ、そのバージョンを返します。
+// "3.12" のような系列指定は新しいパッチリリースのインストールで自動的に切り替わるため、書き換えません。
+func (m *MiseUpdater) globalVersion(ctx context.Context, tool string) (string, error) {
+	entries, err := m.list(ctx, "--global")
+	if err != nil {
+		return "", err
+	}
+
+	for _, entry := range entries[tool] {
+		if entry.RequestedVersion == entry.Version {
+			return entry.Version, nil
+		}
+	}
+
+	return "", nil
+}
+
+func (m *MiseUpdater) installVersion(ctx context.Context, tool, version string) error {
+	return runSystemCommand(ctx, false, "mise", "install", tool+"@"+version)
+}
+
+func (m *MiseUpdater) setGlobalVersion(ctx context.Context, tool, version string) error {
+	return runSystemCommand(ctx, false, "mise", "use", "--global", tool+"@"+version)
+}
+
+func (m *MiseUpdater) list(ctx context.Context, filter string) (map[string][]miseListEntry, error) {
+	args := []string{"ls", filter, "--json"}
+
+	output, err := runCommandOutputWithLocaleC(ctx, "mise", args, "mise "+strings.Join(args, " ")+" の実行に失敗: %w")
+	if err != nil {
+		return nil, err
+	}
+
+	var entries map[string][]miseListEntry
+	if err := json.Unmarshal(output, &entries); err != nil {
+		return nil, fmt.Errorf("mise ls の出力解析に失敗: %w", err)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,74 @@
+package updater
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiseUpdater_CheckAndUpdate(t *testing.T) {
+	logPath := writeFakeMiseCommand(t)
+
+	m := &MiseUpdater{settings: defaultRuntimeUpdateSettings()}
+
+	checkResult, err := m.Check(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []PackageInfo{
+		{Name: "node 20.15", CurrentVersion: "20.15.0", NewVersion: "20.15.1"},
+		{Name: "python 3.12", CurrentVersion: "3.12.3", NewVersion: "3.12.5"},
+	}, checkResult.Packages)
+
+	m.settings.install = true
+	m.settings.setGlobal = true
+
+	result, err := m.Update(context.Background(), UpdateOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.UpdatedCount)
+
+	data, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+
+	log := string(data)
+	assert.Contains(t, log, "mise self-update --yes")
+	assert.Contains(t, log, "mise plugins update")
+	assert.Contains(t, log, "mise install node@20.15.1")
+	assert.Contains(t, log, "mise install python@3.12.5")
+	assert.Contains(t, log, "mise use --global node@20.15.1", "完全指定のグローバルは書き換える")
+	assert.NotContains(t, log, "mise use --global python", "系列指定（3.12）のグローバルは書き換えない")
+}
+
+func writeFakeMiseCommand(t *testing.T) string {
+	t.Helper()
+
+	if runtime.GOOS == windowsOS {
+		t.Skip("fake mise は POSIX シェル前提")
+	}
+
+	logPath := filepath.Join(t.TempDir(), "commands.log")
+	t.Setenv("DEVSYNC_TEST_MISE_LOG", logPath)
+
+	script := `#!/bin/sh
+case "$1 $2" in
+  "ls --installed")
+    echo '{"node":[{"version":"20.15.0","requested_version":"20.15.0","installed":true}],"python":[{"version":"3.12.3","requested_version":"3.12","installed":true},{"version":"3.11.9","installed":true}]}'
+    ;;
+  "ls --global")
+    echo '{"node":[{"version":"20.15.0","requested_version":"20.15.0","installed":true}],"python":[{"version":"3.12.3","requested_version":"3.12","installed":true}]}'
+    ;;
+  "ls-remote node") printf '20.14.0\n20.15.0\n20.15.1\n22.4.0\n' ;;
+  "ls-remote python") printf '3.11.9\n3.12.3\n3.12.5\n' ;;
+  *) echo "mise $*" >> "${DEVSYNC_TEST_MISE_LOG}" ;;
+esac
+`
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "mise"), []byte(script), 0o755))
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	return logPath
+}
@@ -0,0 +1,369 @@
+package updater
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/scottlz0310/devsync/internal/config"
+)
+
+// Nix の更新モード
+const (
+	nixModeProfile     = "profile"
+	nixModeHomeManager = "home-manager"
+)
+
+// nixExperimentalFeatures は nix-command / flakes を未有効化の環境でも利用するための指定です。
+const nixExperimentalFeatures = "nix-command flakes"
+
+var (
+	// nixANSIPattern は diff-closures の色付け（端末出力時）を除去するためのパターンです。
+	nixANSIPattern = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+	// nixDiffClosuresPattern は "name: 1.0 → 1.1, +12.3 KiB" 形式の行にマッチします。
+	nixDiffClosuresPattern = regexp.MustCompile(`^(\S+): (.+)$`)
+)
+
+// NixUpdater は nix profile / Home Manager (flake) の実装です。
+//
+//	sys:
+//	  managers:
+//	    nix:
+//	      mode: "home-manager"        # profile（既定）/ home-manager
+//	      flake: "~/dotfiles"          # nix flake update の対象（home-manager モードでは必須）
+//	      home_manager_attr: "me@host" # homeConfigurations の属性名（既定: $USER）
+type NixUpdater struct {
+	mode            string
+	flake           string
+	homeManagerAttr string
+}
+
+// nixProfileElement は "nix profile list --json" の要素です。
+type nixProfileElement struct {
+	Name        string   `json:"-"`
+	AttrPath    string   `json:"attrPath"`
+	OriginalURL string   `json:"originalUrl"`
+	StorePaths  []string `json:"storePaths"`
+}
+
+// 起動時にレジストリに登録
+func init() {
+	Register(&NixUpdater{mode: nixModeProfile})
+}
+
+func (n *NixUpdater) Name() string {
+	return "nix"
+}
+
+func (n *NixUpdater) DisplayName() string {
+	return "Nix (nix profile / Home Manager)"
+}
+
+func (n *NixUpdater) IsAvailable() bool {
+	_, err := exec.LookPath("nix")
+	return err == nil
+}
+
+func (n *NixUpdater) Configure(cfg config.ManagerConfig) error {
+	if cfg == nil {
+		return nil
+	}
+
+	if mode := stringConfigValue(cfg, "mode"); mode != "" {
+		if mode != nixModeProfile && mode != nixModeHomeManager {
+			return fmt.Errorf("mode の値が不正です: %s（%s / %s のいずれかを指定してください）", mode, nixModeProfile, nixModeHomeManager)
+		}
+
+		n.mode = mode
+	}
+
+	if flake := stringConfigValue(cfg, "flake"); flake != "" {
+		expanded, err := expandHomePath(flake)
+		if err != nil {
+			return err
+		}
+
+		n.flake = expanded
+	}
+
+	n.homeManagerAttr = stringConfigValue(cfg, "home_manager_attr")
+
+	if n.mode == nixModeHomeManager && n.flake == "" {
+		return fmt.Errorf("home-manager モードでは flake の指定が必要です")
+	}
+
+	return nil
+}
+
+// Check は更新後の closure をビルドし（切り替えは行わない）、現在の closure との差分を返します。
+// home-manager モードでは flake.lock を書き換えずに入力を最新化した activationPackage を、
+// profile モードではプロファイルの各要素を最新の入力でビルドして比較します。
+func (n *NixUpdater) Check(ctx context.Context) (*CheckResult, error) {
+	var (
+		packages []PackageInfo
+		err      error
+	)
+
+	if n.mode == nixModeHomeManager {
+		packages, err = n.checkHomeManager(ctx)
+	} else {
+		packages, err = n.checkProfile(ctx)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &CheckResult{
+		AvailableUpdates: len(packages),
+		Packages:         packages,
+	}, nil
+}
+
+func (n *NixUpdater) checkHomeManager(ctx context.Context) ([]PackageInfo, error) {
+	current, err := homeManagerGenerationPath()
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := n.build(ctx, n.homeManagerInstallable(), "--recreate-lock-file", "--no-write-lock-file")
+	if err != nil {
+		return nil, err
+	}
+
+	if current == target {
+		return nil, nil
+	}
+
+	return n.diffClosures(ctx, current, target)
+}
+
+func (n *NixUpdater) checkProfile(ctx context.Context) ([]PackageInfo, error) {
+	elements, err := n.profileElements(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var packages []PackageInfo
+
+	for _, element := range elements {
+		// ストアパスを直接インストールした要素は更新元がないため対象外
+		if element.OriginalURL == "" || element.AttrPath == "" || len(element.StorePaths) == 0 {
+			continue
+		}
+
+		target, err := n.build(ctx, element.OriginalURL+"#"+element.AttrPath, "--refresh")
+		if err != nil {
+			return nil, err
+		}
+
+		if target == element.StorePaths[0] {
+			continue
+		}
+
+		diff, err := n.diffClosures(ctx, element.StorePaths[0], target)
+		if err != nil {
+			return nil, err
+		}
+
+		packages = append(packages, diff...)
+	}
+
+	return packages, nil
+}
+
+func (n *NixUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateResult, error) {
+	result := &UpdateResult{}
+
+	checkResult, err := n.Check(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if checkResult.AvailableUpdates == 0 {
+		result.Message = "すべてのパッケージは最新です"
+		return result, nil
+	}
+
+	result.Packages = checkResult.Packages
+
+	if opts.DryRun {
+		result.Message = fmt.Sprintf("%d 件のパッケージが更新可能です（DryRunモード）", checkResult.AvailableUpdates)
+		return result, nil
+	}
+
+	if n.flake != "" {
+		if err := runSystemCommand(ctx, false, "nix", nixArgs("flake", "update", "--flake", n.flake)...); err != nil {
+			result.Errors = append(result.Errors, err)
+			return result, fmt.Errorf("nix flake update に失敗: %w", err)
+		}
+	}
+
+	if n.mode == nixModeHomeManager {
+		if err := runSystemCommand(ctx, false, "home-manager", "switch", "--flake", n.flake+"#"+n.attr()); err != nil {
+			result.Errors = append(result.Errors, err)
+			return result, fmt.Errorf("home-manager switch に失敗: %w", err)
+		}
+	} else if err := runSystemCommand(ctx, false, "nix", nixArgs("profile", "upgrade", "--all")...); err != nil {
+		result.Errors = append(result.Errors, err)
+		return result, fmt.Errorf("nix profile upgrade に失敗: %w", err)
+	}
+
+	result.UpdatedCount = checkResult.AvailableUpdates
+	result.Message = fmt.Sprintf("%d 件のパッケージを更新しました", result.UpdatedCount)
+
+	return result, nil
+}
+
+func (n *NixUpdater) attr() string {
+	if n.homeManagerAttr != "" {
+		return n.homeManagerAttr
+	}
+
+	return os.Getenv("USER")
+}
+
+func (n *NixUpdater) homeManagerInstallable() string {
+	return fmt.Sprintf("%s#homeConfigurations.%q.activationPackage", n.flake, n.attr())
+}
+
+// build は installable をビルドし（結果のリンクは作らない）、出力のストアパスを返します。
+func (n *NixUpdater) build(ctx context.Context, installable string, extraArgs ...string) (string, error) {
+	args := append([]string{"build", "--no-link", "--print-out-paths"}, extraArgs...)
+	args = append(args, installable)
+
+	output, err := runCommandOutputWithLocaleC(ctx, "nix", nixArgs(args...), "nix build "+installable+" に失敗: %w")
+	if err != nil {
+		return "", err
+	}
+
+	lines := strings.Fields(string(output))
+	if len(lines) == 0 {
+		return "", fmt.Errorf("nix build %s の出力にストアパスがありません", installable)
+	}
+
+	return lines[0], nil
+}
+
+func (n *NixUpdater) diffClosures(ctx context.Context, before, after string) ([]PackageInfo, error) {
+	output, err := runCommandOutputWithLocaleC(ctx, "nix", nixArgs("store", "diff-closures", before, after), "nix store diff-closures に失敗: %w")
+	if err != nil {
+		return nil, err
+	}
+
+	return parseNixDiffClosures(string(output)), nil
+}
+
+// profileElements は "nix profile list --json" の要素を名前順で返します。
+// 新しい形式（version 3 以降）は名前をキーとするオブジェクト、古い形式は配列です。
+func (n *NixUpdater) profileElements(ctx context.Context) ([]nixProfileElement, error) {
+	output, err := runCommandOutputWithLocaleC(ctx, "nix", nixArgs("profile", "list", "--json"), "nix profile list に失敗: %w")
+	if err != nil {
+		return nil, err
+	}
+
+	var profile struct {
+		Elements json.RawMessage `json:"elements"`
+	}
+
+	if err := json.Unmarshal(output, &profile); err != nil {
+		return nil, fmt.Errorf("nix profile list の解析に失敗: %w", err)
+	}
+
+	named := map[string]nixProfileElement{}
+	if err := json.Unmarshal(profile.Elements, &named); err == nil {
+		elements := make([]nixProfileElement, 0, len(named))
+		for _, name := range sortedManagerNames(named) {
+			element := named[name]
+			element.Name = name
+			elements = append(elements, element)
+		}
+
+		return elements, nil
+	}
+
+	var elements []nixProfileElement
+	if err := json.Unmarshal(profile.Elements, &elements); err != nil {
+		return nil, fmt.Errorf("nix profile list の解析に失敗: %w", err)
+	}
+
+	return elements, nil
+}
+
+// parseNixDiffClosures は "nix store diff-closures" の出力をパースします
+// 形式: "name: 1.0 → 1.1, +12.3 KiB"（追加は "∅ → 1.0"、削除は "1.0 → ∅"、再ビルドのみはサイズ差分のみ）
+func parseNixDiffClosures(output string) []PackageInfo {
+	lines := strings.Split(nixANSIPattern.ReplaceAllString(output, ""), "\n")
+	packages := make([]PackageInfo, 0, len(lines))
+
+	for _, line := range lines {
+		match := nixDiffClosuresPattern.FindStringSubmatch(strings.TrimSpace(line))
+		if match == nil {
+			continue
+		}
+
+		pkg := PackageInfo{Name: match[1]}
+
+		change := match[2]
+		if before, after, ok := strings.Cut(change, "→"); ok {
+			// バージョン差分の後ろにサイズ差分（", +12.3 KiB"）が続く
+			if index := strings.LastIndex(after, ", "); index >= 0 {
+				after = after[:index]
+			}
+
+			pkg.CurrentVersion = nixVersionValue(before)
+			pkg.NewVersion = nixVersionValue(after)
+		}
+
+		packages = append(packages, pkg)
+	}
+
+	return packages
+}
+
+func nixVersionValue(value string) string {
+	value = strings.TrimSpace(value)
+	if value == "∅" {
+		return ""
+	}
+
+	return value
+}
+
+// nixArgs は nix コマンドの引数に実験的機能の有効化を付加します。
+func nixArgs(args ...string) []string {
+	return append([]string{"--extra-experimental-features", nixExperimentalFeatures}, args...)
+}
+
+// homeManagerGenerationPath は現在の Home Manager 世代のストアパスを返します。
+func homeManagerGenerationPath() (string, error) {
+	candidates := make([]string, 0, 3)
+
+	stateHome := os.Getenv("XDG_STATE_HOME")
+	if stateHome == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			stateHome = filepath.Join(home, ".local", "state")
+		}
+	}
+
+	if stateHome != "" {
+		candidates = append(candidates, filepath.Join(stateHome, "nix", "profiles", "home-manager"))
+	}
+
+	if user := os.Getenv("USER"); user != "" {
+		candidates = append(candidates, filepath.Join("/nix/var/nix/profiles/per-user", user, "home-manager"))
+	}
+
+	for _, candidate := range candidates {
+		if resolved, err := filepath.EvalSymlinks(candidate); err == nil {
+			return resolved, nil
+		}
+	}
+
+	return "", fmt.Errorf("現在の Home Manager 世代が見つかりません（home-manager switch を一度実行してください）")
+}
@@ -0,0 +1,232 @@
+package updater
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/scottlz0310/devsync/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNixUpdater_Configure(t *testing.T) {
+	testCases := []struct {
+		name        string
+		cfg         config.ManagerConfig
+		wantMode    string
+		errContains string
+	}{
+		{name: "既定はprofile", cfg: config.ManagerConfig{}, wantMode: nixModeProfile},
+		{name: "home-managerとflake", cfg: config.ManagerConfig{"mode": "home-manager", "flake": "/etc/dotfiles"}, wantMode: nixModeHomeManager},
+		{name: "home-managerでflake未指定", cfg: config.ManagerConfig{"mode": "home-manager"}, errContains: "flake の指定が必要です"},
+		{name: "不正なmode", cfg: config.ManagerConfig{"mode": "nixos"}, errContains: "mode の値が不正です"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			n := &NixUpdater{mode: nixModeProfile}
+
+			err := n.Configure(tc.cfg)
+			if tc.errContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.errContains)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantMode, n.mode)
+		})
+	}
+}
+
+func TestParseNixDiffClosures(t *testing.T) {
+	output := "\x1b[1mfirefox\x1b[0m: 125.0.3 → 126.0, +1.2 MiB\n" +
+		"glibc: +3.2 KiB\n" +
+		"htop: ∅ → 3.3.0, +512.0 KiB\n" +
+		"neofetch: 7.1.0 → ∅, -300.0 KiB\n"
+
+	assert.Equal(t, []PackageInfo{
+		{Name: "firefox", CurrentVersion: "125.0.3", NewVersion: "126.0"},
+		{Name: "glibc"},
+		{Name: "htop", NewVersion: "3.3.0"},
+		{Name: "neofetch", CurrentVersion: "7.1.0"},
+	}, parseNixDiffClosures(output))
+}
+
+func TestNixUpdater_CheckProfile(t *testing.T) {
+	writeFakeNixCommands(t)
+	t.Setenv("DEVSYNC_TEST_NIX_MODE", "")
+
+	got, err := (&NixUpdater{mode: nixModeProfile}).Check(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, got.AvailableUpdates, "最新の要素とストアパス直指定の要素は対象外")
+	assert.Equal(t, []PackageInfo{{Name: "ripgrep", CurrentVersion: "14.0.3", NewVersion: "14.1.0"}}, got.Packages)
+}
+
+func TestNixUpdater_CheckHomeManager(t *testing.T) {
+	writeFakeNixCommands(t)
+	t.Setenv("DEVSYNC_TEST_NIX_MODE", "")
+
+	stateHome := t.TempDir()
+	generation := filepath.Join(t.TempDir(), "home-manager-generation")
+	require.NoError(t, os.Mkdir(generation, 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(stateHome, "nix", "profiles"), 0o755))
+	require.NoError(t, os.Symlink(generation, filepath.Join(stateHome, "nix", "profiles", "home-manager")))
+	t.Setenv("XDG_STATE_HOME", stateHome)
+
+	n := &NixUpdater{mode: nixModeHomeManager, flake: "/home/me/dotfiles", homeManagerAttr: "me@host"}
+
+	got, err := n.Check(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, got.AvailableUpdates)
+
+	log := readNixLog(t)
+	assert.Contains(t, log, `build --no-link --print-out-paths --recreate-lock-file --no-write-lock-file /home/me/dotfiles#homeConfigurations."me@host".activationPackage`)
+	assert.Contains(t, log, "store diff-closures "+generation+" /nix/store/hm-new")
+	assert.NotContains(t, log, "flake update", "Check では flake.lock を更新しない")
+	assert.NotContains(t, log, "home-manager switch", "Check では切り替えない")
+
+	result, err := n.Update(context.Background(), UpdateOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.UpdatedCount)
+
+	log = readNixLog(t)
+	assert.Contains(t, log, "flake update --flake /home/me/dotfiles")
+	assert.Contains(t, log, "home-manager switch --flake /home/me/dotfiles#me@host")
+}
+
+func TestNixUpdater_Update(t *testing.T) {
+	t.Run("DryRunは切り替えない", func(t *testing.T) {
+		writeFakeNixCommands(t)
+		t.Setenv("DEVSYNC_TEST_NIX_MODE", "")
+
+		got, err := (&NixUpdater{mode: nixModeProfile, flake: "/home/me/tools"}).Update(context.Background(), UpdateOptions{DryRun: true})
+		require.NoError(t, err)
+		assert.Equal(t, "1 件のパッケージが更新可能です（DryRunモード）", got.Message)
+		assert.Len(t, got.Packages, 1)
+		assert.NotContains(t, readNixLog(t), "profile upgrade")
+	})
+
+	t.Run("flake updateとprofile upgrade", func(t *testing.T) {
+		writeFakeNixCommands(t)
+		t.Setenv("DEVSYNC_TEST_NIX_MODE", "")
+
+		got, err := (&NixUpdater{mode: nixModeProfile, flake: "/home/me/tools"}).Update(context.Background(), UpdateOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, 1, got.UpdatedCount)
+		assert.Equal(t, []PackageInfo{{Name: "ripgrep", CurrentVersion: "14.0.3", NewVersion: "14.1.0"}}, got.Packages)
+
+		log := readNixLog(t)
+		assert.Contains(t, log, "flake update --flake /home/me/tools")
+		assert.Contains(t, log, "profile upgrade --all")
+	})
+
+	t.Run("profile upgrade失敗", func(t *testing.T) {
+		writeFakeNixCommands(t)
+		t.Setenv("DEVSYNC_TEST_NIX_MODE", "upgrade_error")
+
+		_, err := (&NixUpdater{mode: nixModeProfile}).Update(context.Background(), UpdateOptions{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "nix profile upgrade に失敗")
+	})
+
+	t.Run("ビルド失敗", func(t *testing.T) {
+		writeFakeNixCommands(t)
+		t.Setenv("DEVSYNC_TEST_NIX_MODE", "build_error")
+
+		_, err := (&NixUpdater{mode: nixModeProfile}).Update(context.Background(), UpdateOptions{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "hash mismatch")
+	})
+}
+
+// writeFakeNixCommands は fake の nix / home-manager を PATH に配置します。
+// 実行した引数は DEVSYNC_TEST_NIX_LOG に追記されます。
+func writeFakeNixCommands(t *testing.T) {
+	t.Helper()
+
+	if runtime.GOOS == windowsOS {
+		t.Skip("fake nix は POSIX シェル前提")
+	}
+
+	dir := t.TempDir()
+	t.Setenv("DEVSYNC_TEST_NIX_LOG", filepath.Join(t.TempDir(), "nix.log"))
+
+	nix := `#!/bin/sh
+mode="${DEVSYNC_TEST_NIX_MODE}"
+# --extra-experimental-features の指定を読み飛ばす
+if [ "$1" = "--extra-experimental-features" ]; then
+  shift 2
+fi
+echo "$*" >> "${DEVSYNC_TEST_NIX_LOG}"
+case "$1 $2" in
+  "profile list")
+    cat <<'JSON'
+{"version":3,"elements":{
+  "ripgrep":{"active":true,"attrPath":"legacyPackages.x86_64-linux.ripgrep","originalUrl":"flake:nixpkgs","storePaths":["/nix/store/old-ripgrep-14.0.3"]},
+  "jq":{"active":true,"attrPath":"legacyPackages.x86_64-linux.jq","originalUrl":"flake:nixpkgs","storePaths":["/nix/store/jq-1.7.1"]},
+  "local":{"active":true,"attrPath":"","originalUrl":"","storePaths":["/nix/store/local-1.0"]}
+}}
+JSON
+    exit 0
+    ;;
+  "profile upgrade")
+    if [ "${mode}" = "upgrade_error" ]; then
+      echo "error: profile is locked" 1>&2
+      exit 1
+    fi
+    exit 0
+    ;;
+  "flake update")
+    exit 0
+    ;;
+  "store diff-closures")
+    case "$3" in
+      /nix/store/old-ripgrep*) echo "ripgrep: 14.0.3 → 14.1.0, +20.1 KiB" ;;
+      *)
+        echo "git: 2.44.0 → 2.45.1, +1.0 MiB"
+        echo "home-manager-files: +4.0 KiB"
+        ;;
+    esac
+    exit 0
+    ;;
+esac
+if [ "$1" = "build" ]; then
+  if [ "${mode}" = "build_error" ]; then
+    echo "error: hash mismatch in fixed-output derivation" 1>&2
+    exit 1
+  fi
+  for last in "$@"; do :; done
+  case "${last}" in
+    *ripgrep) echo "/nix/store/new-ripgrep-14.1.0" ;;
+    *jq) echo "/nix/store/jq-1.7.1" ;;
+    *activationPackage) echo "/nix/store/hm-new" ;;
+  esac
+  exit 0
+fi
+echo "invalid args: $*" 1>&2
+exit 1
+`
+
+	homeManager := `#!/bin/sh
+echo "home-manager $*" >> "${DEVSYNC_TEST_NIX_LOG}"
+exit 0
+`
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "nix"), []byte(nix), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "home-manager"), []byte(homeManager), 0o755))
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func readNixLog(t *testing.T) string {
+	t.Helper()
+
+	data, err := os.ReadFile(os.Getenv("DEVSYNC_TEST_NIX_LOG"))
+	require.NoError(t, err)
+
+	return string(data)
+}
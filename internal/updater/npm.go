@@ -12,7 +12,7 @@ import (
 
 // NpmUpdater は npm グローバルパッケージマネージャの実装です。
 type NpmUpdater struct {
-	// 将来的な拡張のための設定フィールド
+	policy versionPolicy
 }
 
 // 起動時にレジストリに登録
@@ -33,8 +33,23 @@ func (n *NpmUpdater) IsAvailable() bool {
 	return err == nil
 }
 
+// SupportsVersionPolicy は allow / allow_packages に対応していることを返します。
+func (n *NpmUpdater) SupportsVersionPolicy() bool {
+	return true
+}
+
 func (n *NpmUpdater) Configure(cfg config.ManagerConfig) error {
-	// 現時点では設定項目なし
+	if cfg == nil {
+		return nil
+	}
+
+	policy, err := parseVersionPolicy(n.Name(), cfg)
+	if err != nil {
+		return err
+	}
+
+	n.policy = policy
+
 	return nil
 }
 
@@ -72,27 +87,44 @@ func (n *NpmUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateRes
 		return result, nil
 	}
 
+	packages, held := n.policy.split(checkResult.Packages, opts)
+	result.Held = held
+
+	if len(packages) == 0 {
+		result.Message = allHeldMessage(held)
+		return result, nil
+	}
+
 	if opts.DryRun {
-		result.Message = fmt.Sprintf("%d 件のパッケージが更新可能です（DryRunモード）", checkResult.AvailableUpdates)
-		result.Packages = checkResult.Packages
+		result.Message = fmt.Sprintf("%d 件のパッケージが更新可能です（DryRunモード）", len(packages)) + heldMessageSuffix(held)
+		result.Packages = packages
 
 		return result, nil
 	}
 
+	// 保留する更新がある場合は、許可範囲内のパッケージだけをバージョン指定でインストールする
+	args := []string{"update", "-g"}
+	if len(held) > 0 {
+		args = []string{"install", "-g"}
+		for _, pkg := range packages {
+			args = append(args, pkg.Name+"@"+pkg.NewVersion)
+		}
+	}
+
 	// 実際の更新を実行
-	cmd := exec.CommandContext(ctx, "npm", "update", "-g")
+	cmd := exec.CommandContext(ctx, "npm", args...)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	cmd.Stdin = os.Stdin
 
 	if err := cmd.Run(); err != nil {
 		result.Errors = append(result.Errors, err)
-		return result, fmt.Errorf("npm update -g に失敗: %w", err)
+		return result, fmt.Errorf("npm %s %s に失敗: %w", args[0], args[1], err)
 	}
 
-	result.UpdatedCount = checkResult.AvailableUpdates
-	result.Packages = checkResult.Packages
-	result.Message = fmt.Sprintf("%d 件のパッケージを更新しました", result.UpdatedCount)
+	result.UpdatedCount = len(packages)
+	result.Packages = packages
+	result.Message = fmt.Sprintf("%d 件のパッケージを更新しました", result.UpdatedCount) + heldMessageSuffix(held)
 
 	return result, nil
 }
@@ -129,3 +161,57 @@ func (n *NpmUpdater) parseOutdatedJSON(output []byte) []PackageInfo {
 
 	return packages
 }
+
+// npmBundledPackages は Node.js に同梱され、宣言一覧の差分から除外するパッケージです。
+var npmBundledPackages = map[string]bool{
+	"npm":      true,
+	"corepack": true,
+}
+
+var _ PackageInstaller = (*NpmUpdater)(nil)
+
+func (n *NpmUpdater) PackageName(spec string) string {
+	return stripPackageVersion(spec)
+}
+
+// InstalledPackages は "npm ls -g --depth=0 --json" からグローバルパッケージ名を取得します。
+func (n *NpmUpdater) InstalledPackages(ctx context.Context) ([]string, error) {
+	output, err := runPackageListCommand(ctx, "npm", "ls", "-g", "--depth=0", "--json")
+	if err != nil {
+		return nil, err
+	}
+
+	return n.parseListJSON(output)
+}
+
+func (n *NpmUpdater) InstallPackage(ctx context.Context, spec string) error {
+	return runPackageCommand(ctx, "npm", "install", "-g", spec)
+}
+
+func (n *NpmUpdater) RemovePackage(ctx context.Context, name string) error {
+	return runPackageCommand(ctx, "npm", "uninstall", "-g", name)
+}
+
+// parseListJSON は "npm ls -g --depth=0 --json" の出力をパースします
+// JSON 形式: { "dependencies": { "package-name": { "version": "1.0.0" }, ... } }
+func (n *NpmUpdater) parseListJSON(output []byte) ([]string, error) {
+	var list struct {
+		Dependencies map[string]json.RawMessage `json:"dependencies"`
+	}
+
+	if err := json.Unmarshal(output, &list); err != nil {
+		return nil, fmt.Errorf("npm ls の出力の解析に失敗: %w", err)
+	}
+
+	names := make([]string, 0, len(list.Dependencies))
+
+	for name := range list.Dependencies {
+		if npmBundledPackages[name] {
+			continue
+		}
+
+		names = append(names, name)
+	}
+
+	return names, nil
+}
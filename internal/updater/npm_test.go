@@ -7,7 +7,9 @@ import (
 	"runtime"
 	"testing"
 
+	"github.com/scottlz0310/devsync/internal/config"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNpmUpdater_parseOutdatedJSON(t *testing.T) {
@@ -208,6 +210,30 @@ func TestNpmUpdater_Update(t *testing.T) {
 	}
 }
 
+func TestNpmUpdater_PackageInstaller(t *testing.T) {
+	fakeDir := t.TempDir()
+	writeFakeNpmCommand(t, fakeDir)
+
+	t.Setenv("PATH", fakeDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("DEVSYNC_TEST_NPM_MODE", "")
+
+	n := &NpmUpdater{}
+
+	result, err := ApplyPackages(context.Background(), n, []string{"typescript@5", "eslint"}, ApplyOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"eslint"}, result.Installed)
+	assert.Empty(t, result.Drift.Unlisted)
+	assert.Empty(t, result.Errors)
+
+	t.Setenv("DEVSYNC_TEST_NPM_MODE", "install_error")
+
+	result, err = ApplyPackages(context.Background(), n, []string{"eslint"}, ApplyOptions{Prune: true})
+	assert.NoError(t, err)
+	assert.Empty(t, result.Installed)
+	assert.Equal(t, []string{"typescript"}, result.Removed)
+	assert.Len(t, result.Errors, 1)
+}
+
 // writeFakeNpmCommand はテスト用のフェイク npm コマンドを指定ディレクトリに作成します。
 // 環境変数 DEVSYNC_TEST_NPM_MODE によって動作を切り替えます。
 func writeFakeNpmCommand(t *testing.T, dir string) {
@@ -218,6 +244,9 @@ func writeFakeNpmCommand(t *testing.T, dir string) {
 set mode=%DEVSYNC_TEST_NPM_MODE%
 if "%1"=="outdated" goto docheck
 if "%1"=="update" goto doupdate
+if "%1"=="ls" goto dols
+if "%1"=="install" goto doinstall
+if "%1"=="uninstall" exit /b 0
 echo invalid args 1>&2
 exit /b 1
 :docheck
@@ -237,6 +266,15 @@ if "%mode%"=="update_error" (
   exit /b 1
 )
 exit /b 0
+:dols
+echo {"dependencies":{"npm":{"version":"10.0.0"},"typescript":{"version":"5.0.0"}}}
+exit /b 0
+:doinstall
+if "%mode%"=="install_error" (
+  echo npm install failed 1>&2
+  exit /b 1
+)
+exit /b 0
 `
 		path := filepath.Join(dir, "npm.cmd")
 		err := os.WriteFile(path, []byte(script), 0o755)
@@ -264,6 +302,23 @@ case "$1" in
     fi
     exit 0
     ;;
+  ls)
+    echo '{"dependencies":{"npm":{"version":"10.0.0"},"typescript":{"version":"5.0.0"}}}'
+    exit 0
+    ;;
+  install)
+    if [ "${mode}" = "install_error" ]; then
+      echo "npm install failed" 1>&2
+      exit 1
+    fi
+    if [ -n "${DEVSYNC_TEST_NPM_LOG}" ]; then
+      echo "$*" >> "${DEVSYNC_TEST_NPM_LOG}"
+    fi
+    exit 0
+    ;;
+  uninstall)
+    exit 0
+    ;;
   *)
     echo "invalid args" 1>&2
     exit 1
@@ -277,3 +332,41 @@ esac
 		assert.NoError(t, err)
 	}
 }
+
+func TestNpmUpdater_UpdateWithVersionPolicy(t *testing.T) {
+	if runtime.GOOS == windowsOS {
+		t.Skip("fake npm の引数記録は POSIX シェルのみ")
+	}
+
+	fakeDir := t.TempDir()
+	writeFakeNpmCommand(t, fakeDir)
+
+	logPath := filepath.Join(t.TempDir(), "npm.log")
+
+	t.Setenv("PATH", fakeDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("DEVSYNC_TEST_NPM_MODE", "updates")
+	t.Setenv("DEVSYNC_TEST_NPM_LOG", logPath)
+
+	n := &NpmUpdater{}
+	require.NoError(t, n.Configure(config.ManagerConfig{
+		"allow":          "patch",
+		"allow_packages": map[string]interface{}{"eslint": "minor"},
+	}))
+
+	got, err := n.Update(context.Background(), UpdateOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, got.UpdatedCount)
+	assert.Equal(t, "eslint", got.Packages[0].Name)
+	require.Len(t, got.Held, 1)
+	assert.Equal(t, "typescript", got.Held[0].Name, "パッチ更新のみ許可のため 5.0.0 → 5.3.0 は保留")
+	assert.Contains(t, got.Message, "1 件は許可範囲を超えるため保留")
+
+	logged, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	assert.Equal(t, "install -g eslint@8.56.0\n", string(logged), "許可範囲内のパッケージのみをバージョン指定でインストールする")
+
+	got, err = n.Update(context.Background(), UpdateOptions{DryRun: true, AllowMajor: true})
+	require.NoError(t, err)
+	assert.Len(t, got.Packages, 2, "--allow-major ではすべての更新を対象にする")
+	assert.Empty(t, got.Held)
+}
@@ -20,7 +20,10 @@ var semverPattern = regexp.MustCompile(`v?(\d+\.\d+\.\d+)`)
 const windowsOS = "windows"
 
 // NvmUpdater は nvm (Node.js バージョン管理) の実装です。
-type NvmUpdater struct{}
+type NvmUpdater struct {
+	settings nvmSettings
+	policy   versionPolicy
+}
 
 // 起動時にレジストリへ登録します。
 func init() {
@@ -46,20 +49,60 @@ func (n *NvmUpdater) IsAvailable() bool {
 	return err == nil
 }
 
+// SupportsVersionPolicy は allow / allow_packages に対応していることを返します。
+func (n *NvmUpdater) SupportsVersionPolicy() bool {
+	return true
+}
+
 func (n *NvmUpdater) Configure(cfg config.ManagerConfig) error {
-	// 現時点では設定項目なし
+	if cfg == nil {
+		return nil
+	}
+
+	settings, err := parseNvmSettings(cfg)
+	if err != nil {
+		return err
+	}
+
+	policy, err := parseVersionPolicy(n.Name(), cfg)
+	if err != nil {
+		return err
+	}
+
+	n.settings = settings
+	n.policy = policy
+
 	return nil
 }
 
 func (n *NvmUpdater) Check(ctx context.Context) (*CheckResult, error) {
+	result, _, err := n.check(ctx)
+	return result, err
+}
+
+// check は更新の有無と、追跡系列で導入すべきバージョン（更新がない場合も含む）を返します。
+func (n *NvmUpdater) check(ctx context.Context) (*CheckResult, string, error) {
 	currentVersion, err := n.currentVersion(ctx)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
-	latestVersion, err := n.latestVersion(ctx)
+	latestVersion, err := n.targetVersion(ctx)
 	if err != nil {
-		return nil, err
+		return nil, "", err
+	}
+
+	// 系列を固定している場合は default エイリアスではなく、追跡系列のインストール済みバージョンと比較する
+	// （set_default なしで別のメジャーを使っていても、追跡系列が最新なら更新不要）
+	if !n.settings.track.isLatest() {
+		installed, err := n.trackedInstalledVersion(ctx, latestVersion)
+		if err != nil {
+			return nil, "", err
+		}
+
+		if installed != "" {
+			currentVersion = installed
+		}
 	}
 
 	if currentVersion == "" {
@@ -72,19 +115,24 @@ func (n *NvmUpdater) Check(ctx context.Context) (*CheckResult, error) {
 				},
 			},
 			Message: "現在の Node.js バージョンを検出できなかったため、最新バージョンの導入を提案します",
-		}, nil
+		}, latestVersion, nil
 	}
 
 	needsUpdate, cmpErr := isSemverLess(currentVersion, latestVersion)
 	if cmpErr != nil {
-		return nil, fmt.Errorf("nvm バージョン比較に失敗: %w", cmpErr)
+		return nil, "", fmt.Errorf("nvm バージョン比較に失敗: %w", cmpErr)
+	}
+
+	// 追跡系列が未インストールの場合は、追跡対象外のメジャー（新しすぎる場合も含む）から追跡系列へ切り替える
+	if !n.settings.track.isLatest() && !sameSemverMajor(currentVersion, latestVersion) {
+		needsUpdate = true
 	}
 
 	if !needsUpdate {
 		return &CheckResult{
 			AvailableUpdates: 0,
 			Packages:         []PackageInfo{},
-		}, nil
+		}, latestVersion, nil
 	}
 
 	return &CheckResult{
@@ -96,19 +144,35 @@ func (n *NvmUpdater) Check(ctx context.Context) (*CheckResult, error) {
 				NewVersion:     latestVersion,
 			},
 		},
-	}, nil
+	}, latestVersion, nil
 }
 
 func (n *NvmUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateResult, error) {
-	checkResult, err := n.Check(ctx)
+	result := &UpdateResult{}
+
+	// 新しいリリースの LTS 名などを解決できるよう、先に nvm 本体を更新する
+	if !opts.DryRun && n.settings.selfUpdate {
+		if err := n.selfUpdate(ctx); err != nil {
+			result.Errors = append(result.Errors, fmt.Errorf("nvm 本体の更新に失敗: %w", err))
+		}
+	}
+
+	checkResult, trackedVersion, err := n.check(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	result := &UpdateResult{}
-
 	if checkResult.AvailableUpdates == 0 {
 		result.Message = "nvm 管理下の Node.js は最新です"
+		n.cleanupSupersededVersions(ctx, opts, trackedVersion, result)
+
+		return result, nil
+	}
+
+	if _, held := n.policy.split(checkResult.Packages, opts); len(held) > 0 {
+		result.Held = held
+		result.Message = fmt.Sprintf("Node.js %s → %s は許可範囲を超えるため保留しました", held[0].CurrentVersion, held[0].NewVersion)
+		n.cleanupSupersededVersions(ctx, opts, trackedVersion, result)
 
 		return result, nil
 	}
@@ -116,13 +180,15 @@ func (n *NvmUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateRes
 	if opts.DryRun {
 		result.Message = fmt.Sprintf("%d 件の Node.js バージョン更新が可能です（DryRunモード）", checkResult.AvailableUpdates)
 		result.Packages = checkResult.Packages
+		n.cleanupSupersededVersions(ctx, opts, trackedVersion, result)
 
 		return result, nil
 	}
 
+	currentVersion := checkResult.Packages[0].CurrentVersion
 	targetVersion := checkResult.Packages[0].NewVersion
 
-	cmd, err := n.buildCommand(ctx, "install", targetVersion)
+	cmd, err := n.buildCommand(ctx, n.installArgs(targetVersion, currentVersion)...)
 	if err != nil {
 		result.Errors = append(result.Errors, err)
 
@@ -143,9 +209,29 @@ func (n *NvmUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateRes
 	result.Packages = checkResult.Packages
 	result.Message = fmt.Sprintf("Node.js %s をインストールしました", targetVersion)
 
+	if n.settings.setDefault && runtime.GOOS != windowsOS {
+		if _, err := n.runCommandOutput(ctx, "alias", "default", targetVersion); err != nil {
+			result.Errors = append(result.Errors, fmt.Errorf("nvm alias default %s に失敗: %w", targetVersion, err))
+		}
+	}
+
+	n.cleanupSupersededVersions(ctx, opts, trackedVersion, result)
+
 	return result, nil
 }
 
+// installArgs は nvm install の引数を組み立てます。
+// reinstall_packages が有効な場合は、現在のバージョンのグローバル npm パッケージを移行します（Windows 版 nvm は非対応）。
+func (n *NvmUpdater) installArgs(targetVersion, currentVersion string) []string {
+	args := []string{"install", targetVersion}
+
+	if n.settings.reinstallPackages && currentVersion != "" && runtime.GOOS != windowsOS {
+		args = append(args, "--reinstall-packages-from="+currentVersion)
+	}
+
+	return args
+}
+
 func (n *NvmUpdater) currentVersion(ctx context.Context) (string, error) {
 	output, err := n.runCommandOutput(ctx, "current")
 	if err != nil {
@@ -0,0 +1,558 @@
+package updater
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/scottlz0310/devsync/internal/config"
+)
+
+var (
+	nodeLTSPattern      = regexp.MustCompile(`\((?:Latest )?LTS: ([A-Za-z]+)\)`)
+	nvmCodenamePattern  = regexp.MustCompile(`^[a-z]+$`)
+	nvmMajorOnlyPattern = regexp.MustCompile(`^v?(\d+)$`)
+)
+
+// nvmSettings は nvm の設定です。
+//
+//	sys:
+//	  managers:
+//	    nvm:
+//	      track: "20"                 # latest（既定）/ lts / LTS コードネーム（例: iron）/ メジャーバージョン
+//	      reinstall_packages: true    # グローバル npm パッケージを新しいバージョンへ移行（既定: false）
+//	      set_default: true           # インストールしたバージョンを default エイリアスに設定（既定: false）
+//	      uninstall_after_days: 30    # 追跡系列の新しいバージョン導入から N 日経過した旧バージョンを削除（既定: 0 = 削除しない）
+//	      self_update: true           # git で導入した nvm 本体を最新タグに更新（既定: false）
+type nvmSettings struct {
+	track              nvmTrack
+	reinstallPackages  bool
+	setDefault         bool
+	uninstallAfterDays int
+	selfUpdate         bool
+}
+
+// nvmTrack は追跡する Node.js のリリース系列です。ゼロ値は従来どおり最新バージョンを追跡します。
+type nvmTrack struct {
+	lts      bool
+	codename string
+	major    int
+}
+
+func parseNvmSettings(cfg config.ManagerConfig) (nvmSettings, error) {
+	var settings nvmSettings
+
+	track, err := parseNvmTrack(stringConfigValue(cfg, "track"))
+	if err != nil {
+		return nvmSettings{}, err
+	}
+
+	settings.track = track
+
+	if value, ok := cfg["reinstall_packages"].(bool); ok {
+		settings.reinstallPackages = value
+	}
+
+	if value, ok := cfg["set_default"].(bool); ok {
+		settings.setDefault = value
+	}
+
+	if value, ok := cfg["self_update"].(bool); ok {
+		settings.selfUpdate = value
+	}
+
+	if value, ok := intConfigValue(cfg, "uninstall_after_days"); ok {
+		if value < 0 {
+			return nvmSettings{}, fmt.Errorf("sys.managers.nvm.uninstall_after_days は 0 以上を指定してください: %d", value)
+		}
+
+		settings.uninstallAfterDays = value
+	}
+
+	return settings, nil
+}
+
+// intConfigValue は YAML / JSON 由来の数値（int / float64 など）を int として取り出します。
+func intConfigValue(cfg config.ManagerConfig, key string) (int, bool) {
+	switch value := cfg[key].(type) {
+	case int:
+		return value, true
+	case int64:
+		return int(value), true
+	case uint64:
+		return int(value), true
+	case float64:
+		return int(value), true
+	case string:
+		number, err := strconv.Atoi(strings.TrimSpace(value))
+		return number, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// parseNvmTrack は track の値（latest / lts / lts/* / lts/<codename> / <codename> / <major>）を解釈します。
+func parseNvmTrack(value string) (nvmTrack, error) {
+	normalized := strings.ToLower(strings.TrimSpace(value))
+
+	switch normalized {
+	case "", "latest", "node":
+		return nvmTrack{}, nil
+	case "lts", "lts/*":
+		return nvmTrack{lts: true}, nil
+	}
+
+	if match := nvmMajorOnlyPattern.FindStringSubmatch(normalized); match != nil {
+		major, err := strconv.Atoi(match[1])
+		if err == nil && major > 0 {
+			return nvmTrack{major: major}, nil
+		}
+	}
+
+	codename := strings.TrimPrefix(normalized, "lts/")
+	if nvmCodenamePattern.MatchString(codename) {
+		return nvmTrack{lts: true, codename: codename}, nil
+	}
+
+	return nvmTrack{}, fmt.Errorf("sys.managers.nvm.track の値が不正です: %q（latest / lts / LTS コードネーム / メジャーバージョンを指定）", value)
+}
+
+func (t nvmTrack) isLatest() bool {
+	return !t.lts && t.major == 0
+}
+
+func (t nvmTrack) String() string {
+	switch {
+	case t.codename != "":
+		return "lts/" + t.codename
+	case t.lts:
+		return "lts/*"
+	case t.major > 0:
+		return fmt.Sprintf("v%d", t.major)
+	default:
+		return "latest"
+	}
+}
+
+func (t nvmTrack) matches(release nodeRelease) bool {
+	switch {
+	case t.codename != "":
+		return release.lts == t.codename
+	case t.lts:
+		return release.lts != ""
+	case t.major > 0:
+		parts, err := parseSemver(release.version)
+		return err == nil && parts[0] == t.major
+	default:
+		return true
+	}
+}
+
+// nodeRelease は nvm ls-remote の 1 行分です（lts は LTS のコードネームを小文字で保持し、LTS でなければ空）。
+type nodeRelease struct {
+	version string
+	lts     string
+}
+
+// targetVersion は track に従って導入すべき Node.js のバージョンを返します。
+func (n *NvmUpdater) targetVersion(ctx context.Context) (string, error) {
+	track := n.settings.track
+	if track.isLatest() {
+		return n.latestVersion(ctx)
+	}
+
+	args := []string{"ls-remote", "--no-colors"}
+
+	if runtime.GOOS == windowsOS {
+		// Windows 版 nvm の一覧には LTS のコードネームが含まれない
+		if track.lts {
+			return "", fmt.Errorf("Windows 版 nvm では %s の追跡に対応していません（メジャーバージョンを指定してください）", track)
+		}
+
+		args = []string{"list", "available"}
+	}
+
+	output, err := n.runCommandOutput(ctx, args...)
+	if err != nil {
+		return "", fmt.Errorf("nvm %s の実行に失敗: %w", strings.Join(args, " "), err)
+	}
+
+	version := selectNodeRelease(parseNodeReleases(output), track)
+	if version == "" {
+		return "", fmt.Errorf("%s に一致する Node.js バージョンが見つかりません", track)
+	}
+
+	return version, nil
+}
+
+// parseNodeReleases は nvm ls-remote / nvm list available の出力からバージョンと LTS 名を取り出します。
+func parseNodeReleases(output string) []nodeRelease {
+	var releases []nodeRelease
+
+	for _, line := range strings.Split(output, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.Contains(strings.ToLower(trimmed), "iojs") {
+			continue
+		}
+
+		lts := ""
+		if match := nodeLTSPattern.FindStringSubmatch(trimmed); match != nil {
+			lts = strings.ToLower(match[1])
+		}
+
+		for _, match := range semverPattern.FindAllStringSubmatch(trimmed, -1) {
+			releases = append(releases, nodeRelease{version: match[1], lts: lts})
+		}
+	}
+
+	return releases
+}
+
+// selectNodeRelease は track に一致するリリースのうち最新のバージョンを返します。
+func selectNodeRelease(releases []nodeRelease, track nvmTrack) string {
+	latest := ""
+
+	for _, release := range releases {
+		if !track.matches(release) {
+			continue
+		}
+
+		if latest == "" {
+			latest = release.version
+			continue
+		}
+
+		if less, err := isSemverLess(latest, release.version); err == nil && less {
+			latest = release.version
+		}
+	}
+
+	return latest
+}
+
+func sameSemverMajor(left, right string) bool {
+	leftParts, leftErr := parseSemver(left)
+	rightParts, rightErr := parseSemver(right)
+
+	return leftErr == nil && rightErr == nil && leftParts[0] == rightParts[0]
+}
+
+// trackedInstalledVersion は target と同じメジャー（追跡系列）のインストール済みバージョンのうち最新のものを返します。
+// 追跡系列がインストールされていない場合は空を返します。
+func (n *NvmUpdater) trackedInstalledVersion(ctx context.Context, target string) (string, error) {
+	versions, err := n.installedNodeVersions(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	newest := ""
+
+	for _, version := range versions {
+		if !sameSemverMajor(version, target) {
+			continue
+		}
+
+		if newest == "" {
+			newest = version
+			continue
+		}
+
+		if less, _ := isSemverLess(newest, version); less {
+			newest = version
+		}
+	}
+
+	return newest, nil
+}
+
+// installedNodeVersions はインストール済みの Node.js のバージョン（v 接頭辞なし）を返します。
+// Windows 版 nvm は nvm list の出力から、それ以外は $NVM_DIR/versions/node のディレクトリから読み取ります。
+func (n *NvmUpdater) installedNodeVersions(ctx context.Context) ([]string, error) {
+	if runtime.GOOS == windowsOS {
+		output, err := n.runCommandOutput(ctx, "list")
+		if err != nil {
+			return nil, fmt.Errorf("nvm list の実行に失敗: %w", err)
+		}
+
+		return parseNodeVersionList(output), nil
+	}
+
+	_, nvmScript, err := n.resolveUnixNvmRuntime()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(filepath.Join(filepath.Dir(nvmScript), "versions", "node"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("nvm のインストール済みバージョンの取得に失敗: %w", err)
+	}
+
+	names := make([]string, 0, len(entries))
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+
+	return parseNodeVersionList(strings.Join(names, "\n")), nil
+}
+
+// parseNodeVersionList は出力中のバージョン（例: "v20.18.0"、Windows 版 nvm の "* 20.18.0 (Currently using ...)"）を取り出します。
+func parseNodeVersionList(output string) []string {
+	var versions []string
+
+	for _, field := range strings.Fields(output) {
+		version := strings.TrimPrefix(field, "v")
+		if _, err := parseSemver(version); err == nil {
+			versions = append(versions, version)
+		}
+	}
+
+	return versions
+}
+
+// selfUpdate は git で導入された nvm（$NVM_DIR/.git）を最新のリリースタグに切り替えます。
+// インストーラやパッケージマネージャで導入した場合と Windows 版 nvm は対象外です。
+func (n *NvmUpdater) selfUpdate(ctx context.Context) error {
+	if runtime.GOOS == windowsOS {
+		return nil
+	}
+
+	_, nvmScript, err := n.resolveUnixNvmRuntime()
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(nvmScript)
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err != nil {
+		return nil
+	}
+
+	if err := runSystemCommand(ctx, false, "git", "-C", dir, "fetch", "--tags", "--quiet", "origin"); err != nil {
+		return err
+	}
+
+	revision, err := runCommandOutputWithLocaleC(ctx, "git", []string{"-C", dir, "rev-list", "--tags", "--max-count=1"}, "git rev-list の実行に失敗: %w")
+	if err != nil {
+		return err
+	}
+
+	tag, err := runCommandOutputWithLocaleC(ctx, "git",
+		[]string{"-C", dir, "describe", "--abbrev=0", "--tags", "--match", "v[0-9]*", strings.TrimSpace(string(revision))},
+		"git describe の実行に失敗: %w")
+	if err != nil {
+		return err
+	}
+
+	return runSystemCommand(ctx, false, "git", "-C", dir, "checkout", "--quiet", strings.TrimSpace(string(tag)))
+}
+
+// cleanupSupersededVersions は uninstall_after_days に従って追跡系列（trackedVersion と同じメジャー）の旧バージョンを削除し、
+// 結果を result に追記します。使用中のバージョンと $NVM_DIR/alias/* が指すバージョンは残します。
+// 削除の失敗は Node.js の更新自体を失敗扱いにせず、Errors に記録します。
+func (n *NvmUpdater) cleanupSupersededVersions(ctx context.Context, opts UpdateOptions, trackedVersion string, result *UpdateResult) {
+	if n.settings.uninstallAfterDays <= 0 || runtime.GOOS == windowsOS {
+		return
+	}
+
+	trackedParts, err := parseSemver(trackedVersion)
+	if err != nil {
+		return
+	}
+
+	_, nvmScript, err := n.resolveUnixNvmRuntime()
+	if err != nil {
+		result.Errors = append(result.Errors, err)
+		return
+	}
+
+	currentVersion, err := n.currentVersion(ctx)
+	if err != nil {
+		result.Errors = append(result.Errors, err)
+		return
+	}
+
+	nvmDir := filepath.Dir(nvmScript)
+	keep := append(nvmAliasTargets(filepath.Join(nvmDir, "alias")), currentVersion)
+
+	superseded, err := supersededNodeVersions(filepath.Join(nvmDir, "versions", "node"), trackedParts[0],
+		n.settings.uninstallAfterDays, time.Now(), keep)
+	if err != nil {
+		result.Errors = append(result.Errors, err)
+		return
+	}
+
+	if len(superseded) == 0 {
+		return
+	}
+
+	if opts.DryRun {
+		result.Message += fmt.Sprintf("、旧バージョン %s を削除予定", strings.Join(superseded, ", "))
+		return
+	}
+
+	removed := 0
+
+	for _, version := range superseded {
+		if _, err := n.runCommandOutput(ctx, "uninstall", version); err != nil {
+			result.Errors = append(result.Errors, fmt.Errorf("nvm uninstall %s に失敗: %w", version, err))
+			continue
+		}
+
+		removed++
+	}
+
+	if removed > 0 {
+		result.Message += fmt.Sprintf("、旧バージョン %d 件を削除しました", removed)
+	}
+}
+
+// nvmAliasTargets は aliasDir（$NVM_DIR/alias）以下のエイリアスが指すバージョン指定（v 接頭辞なし）を返します。
+// lts/* などのサブディレクトリも含みます。読み取れないファイルは無視します。
+func nvmAliasTargets(aliasDir string) []string {
+	var targets []string
+
+	//nolint:errcheck // コールバックは常に nil を返し、読み取れないエイリアスは無視する
+	filepath.WalkDir(aliasDir, func(path string, entry os.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+
+		if target := strings.TrimPrefix(strings.TrimSpace(string(data)), "v"); target != "" {
+			targets = append(targets, target)
+		}
+
+		return nil
+	})
+
+	return targets
+}
+
+// supersededNodeVersions は versionsDir（$NVM_DIR/versions/node）のメジャー major のうち、新しいバージョンが
+// days 日以上前にインストールされている旧バージョンを古い順に返します。
+// keep はバージョン指定（例: 20.15.0、20.15）で、一致するバージョン（部分指定の場合は一致するうち最新のもの）を除外します。
+func supersededNodeVersions(versionsDir string, major, days int, now time.Time, keep []string) ([]string, error) {
+	entries, err := os.ReadDir(versionsDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("nvm のインストール済みバージョンの取得に失敗: %w", err)
+	}
+
+	type installed struct {
+		version string
+		modTime time.Time
+	}
+
+	var versions []installed
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		version := strings.TrimPrefix(entry.Name(), "v")
+
+		parts, err := parseSemver(version)
+		if err != nil || parts[0] != major {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		versions = append(versions, installed{version: version, modTime: info.ModTime()})
+	}
+
+	if len(versions) < 2 {
+		return nil, nil
+	}
+
+	names := make([]string, 0, len(versions))
+	for _, candidate := range versions {
+		names = append(names, candidate.version)
+	}
+
+	newest := versions[0]
+	for _, candidate := range versions[1:] {
+		if less, _ := isSemverLess(newest.version, candidate.version); less {
+			newest = candidate
+		}
+	}
+
+	// 新しいバージョンのディレクトリの更新時刻を「置き換えられた日時」とみなす
+	if now.Sub(newest.modTime) < time.Duration(days)*24*time.Hour {
+		return nil, nil
+	}
+
+	pinned := resolveNodeVersionSpecs(keep, names)
+
+	var superseded []string
+
+	for _, candidate := range versions {
+		if candidate.version == newest.version || containsString(pinned, candidate.version) {
+			continue
+		}
+
+		superseded = append(superseded, candidate.version)
+	}
+
+	sort.Slice(superseded, func(i, j int) bool {
+		less, _ := isSemverLess(superseded[i], superseded[j])
+		return less
+	})
+
+	return superseded, nil
+}
+
+// resolveNodeVersionSpecs は nvm と同様に、バージョン指定を versions のうち一致する最新のバージョンに解決します。
+// "20.15" のような部分指定はその系列の最新を指します。node / lts/* などの名前は解決しません。
+func resolveNodeVersionSpecs(specs, versions []string) []string {
+	resolved := make([]string, 0, len(specs))
+
+	for _, spec := range specs {
+		match := ""
+
+		for _, version := range versions {
+			if version != spec && !strings.HasPrefix(version, spec+".") {
+				continue
+			}
+
+			if match == "" {
+				match = version
+				continue
+			}
+
+			if less, _ := isSemverLess(match, version); less {
+				match = version
+			}
+		}
+
+		if match != "" {
+			resolved = append(resolved, match)
+		}
+	}
+
+	return resolved
+}
@@ -0,0 +1,301 @@
+package updater
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/scottlz0310/devsync/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const nvmLsRemoteOutput = `
+        v18.20.4   (LTS: Hydrogen)
+        v20.17.0   (LTS: Iron)
+        v20.18.0   (Latest LTS: Iron)
+        v22.11.0   (Latest LTS: Jod)
+        v23.1.0
+`
+
+func TestParseNvmTrack(t *testing.T) {
+	testCases := []struct {
+		name      string
+		value     string
+		want      nvmTrack
+		wantStr   string
+		expectErr bool
+	}{
+		{name: "未指定は最新", value: "", want: nvmTrack{}, wantStr: "latest"},
+		{name: "latest", value: "latest", want: nvmTrack{}, wantStr: "latest"},
+		{name: "lts/*", value: "lts/*", want: nvmTrack{lts: true}, wantStr: "lts/*"},
+		{name: "lts", value: "LTS", want: nvmTrack{lts: true}, wantStr: "lts/*"},
+		{name: "lts/コードネーム", value: "lts/iron", want: nvmTrack{lts: true, codename: "iron"}, wantStr: "lts/iron"},
+		{name: "コードネームのみ", value: "Iron", want: nvmTrack{lts: true, codename: "iron"}, wantStr: "lts/iron"},
+		{name: "メジャーバージョン", value: "20", want: nvmTrack{major: 20}, wantStr: "v20"},
+		{name: "v付きメジャーバージョン", value: "v22", want: nvmTrack{major: 22}, wantStr: "v22"},
+		{name: "不正な値はエラー", value: "20.1", expectErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parseNvmTrack(tc.value)
+			if tc.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+			assert.Equal(t, tc.wantStr, got.String())
+		})
+	}
+}
+
+func TestSelectNodeRelease(t *testing.T) {
+	releases := parseNodeReleases(nvmLsRemoteOutput)
+
+	testCases := []struct {
+		name  string
+		track nvmTrack
+		want  string
+	}{
+		{name: "最新", track: nvmTrack{}, want: "23.1.0"},
+		{name: "LTS のみ", track: nvmTrack{lts: true}, want: "22.11.0"},
+		{name: "コードネーム指定", track: nvmTrack{lts: true, codename: "iron"}, want: "20.18.0"},
+		{name: "メジャー指定", track: nvmTrack{major: 18}, want: "18.20.4"},
+		{name: "一致なし", track: nvmTrack{major: 16}, want: ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, selectNodeRelease(releases, tc.track))
+		})
+	}
+}
+
+func TestParseNvmSettings(t *testing.T) {
+	settings, err := parseNvmSettings(config.ManagerConfig{
+		"track":                "20",
+		"reinstall_packages":   true,
+		"set_default":          true,
+		"uninstall_after_days": 30,
+		"self_update":          true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, nvmSettings{
+		track:              nvmTrack{major: 20},
+		reinstallPackages:  true,
+		setDefault:         true,
+		uninstallAfterDays: 30,
+		selfUpdate:         true,
+	}, settings)
+
+	_, err = parseNvmSettings(config.ManagerConfig{"uninstall_after_days": -1})
+	assert.Error(t, err)
+
+	assert.Error(t, (&NvmUpdater{}).Configure(config.ManagerConfig{"track": "not/valid"}))
+}
+
+func TestSupersededNodeVersions(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	installed := map[string]time.Time{
+		"v20.10.0": now.AddDate(0, -6, 0),
+		"v20.12.1": now.AddDate(0, -5, 0),
+		"v20.12.2": now.AddDate(0, -4, 0),
+		"v20.14.0": now.AddDate(0, -3, 0),
+		"v20.15.0": now.AddDate(0, -2, 0),
+		"v20.18.0": now.AddDate(0, 0, -40),
+		"v22.10.0": now.AddDate(0, 0, -20),
+		"v22.11.0": now.AddDate(0, 0, -5),
+		"v18.20.4": now.AddDate(-1, 0, 0),
+		"v18.20.5": now.AddDate(0, -6, 0),
+	}
+
+	for name, modTime := range installed {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(path, 0o755))
+		require.NoError(t, os.Chtimes(path, modTime, modTime))
+	}
+
+	got, err := supersededNodeVersions(dir, 20, 30, now, []string{"20.15.0", "20.12", "14.0.0", "lts/iron"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"20.10.0", "20.12.1", "20.14.0"}, got,
+		"使用中のバージョンとエイリアスが指すバージョン（部分指定はその系列の最新）は残し、追跡系列以外は削除しない")
+
+	got, err = supersededNodeVersions(dir, 22, 30, now, nil)
+	require.NoError(t, err)
+	assert.Empty(t, got, "置き換えから日数が経っていない系列は残す")
+
+	got, err = supersededNodeVersions(filepath.Join(dir, "missing"), 20, 30, now, nil)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func TestNvmAliasTargets(t *testing.T) {
+	dir := t.TempDir()
+
+	aliases := map[string]string{
+		"default":  "lts/iron\n",
+		"myproj":   "v18.20.4\n",
+		"lts/iron": "v20.18.0\n",
+		"lts/*":    "lts/jod\n",
+	}
+
+	for name, target := range aliases {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(target), 0o644))
+	}
+
+	assert.ElementsMatch(t, []string{"lts/iron", "18.20.4", "20.18.0", "lts/jod"}, nvmAliasTargets(dir))
+	assert.Empty(t, nvmAliasTargets(filepath.Join(dir, "missing")))
+}
+
+func TestNvmUpdater_TrackLTSCodename(t *testing.T) {
+	nvmDir, logPath := writeFakeNvmReleaseCommands(t)
+
+	// 旧バージョンは 40 日前に新しいバージョンへ置き換えられた状態にする
+	old := time.Now().AddDate(0, 0, -40)
+	for _, name := range []string{"v18.20.4", "v18.20.5", "v20.10.0", "v20.12.2", "v20.15.0"} {
+		path := filepath.Join(nvmDir, "versions", "node", name)
+		require.NoError(t, os.MkdirAll(path, 0o755))
+		require.NoError(t, os.Chtimes(path, old, old))
+	}
+
+	// 別のプロジェクト用のエイリアスが指すバージョンは削除しない
+	require.NoError(t, os.MkdirAll(filepath.Join(nvmDir, "alias"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(nvmDir, "alias", "myproj"), []byte("v20.12.2\n"), 0o644))
+
+	n := &NvmUpdater{}
+	require.NoError(t, n.Configure(config.ManagerConfig{
+		"track":                "lts/iron",
+		"reinstall_packages":   true,
+		"set_default":          true,
+		"uninstall_after_days": 30,
+		"self_update":          true,
+	}))
+
+	checkResult, err := n.Check(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []PackageInfo{{Name: "node", CurrentVersion: "20.15.0", NewVersion: "20.18.0"}}, checkResult.Packages,
+		"default が別のメジャーでも、追跡系列のインストール済みバージョンと比較する")
+
+	result, err := n.Update(context.Background(), UpdateOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, result.Errors)
+	assert.Equal(t, 1, result.UpdatedCount)
+	assert.Contains(t, result.Message, "旧バージョン 1 件を削除しました")
+
+	data, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+
+	log := string(data)
+	assert.Contains(t, log, "git -C "+nvmDir+" fetch --tags --quiet origin")
+	assert.Contains(t, log, "git -C "+nvmDir+" checkout --quiet v0.40.1")
+	assert.Contains(t, log, "nvm install 20.18.0 --reinstall-packages-from=20.15.0")
+	assert.Contains(t, log, "nvm alias default 20.18.0")
+	assert.Contains(t, log, "nvm uninstall 20.10.0")
+	assert.NotContains(t, log, "nvm uninstall 20.15.0", "同じメジャーの最新は残す")
+	assert.NotContains(t, log, "nvm uninstall 20.12.2", "エイリアスが指すバージョンは残す")
+	assert.NotContains(t, log, "nvm uninstall 18.20.4", "追跡系列以外のメジャーは削除しない")
+}
+
+func TestNvmUpdater_TrackComparesInstalledVersions(t *testing.T) {
+	testCases := []struct {
+		name      string
+		installed []string
+		want      []PackageInfo
+	}{
+		{
+			name:      "追跡系列が最新なら default が別のメジャーでも更新しない",
+			installed: []string{"v20.18.0", "v22.11.0"},
+			want:      []PackageInfo{},
+		},
+		{
+			name:      "追跡系列が未インストールなら現在のバージョンから切り替える",
+			installed: []string{"v22.11.0"},
+			want:      []PackageInfo{{Name: "node", CurrentVersion: "22.11.0", NewVersion: "20.18.0"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			nvmDir, _ := writeFakeNvmReleaseCommands(t)
+
+			for _, name := range tc.installed {
+				require.NoError(t, os.MkdirAll(filepath.Join(nvmDir, "versions", "node", name), 0o755))
+			}
+
+			n := &NvmUpdater{}
+			require.NoError(t, n.Configure(config.ManagerConfig{"track": "20"}))
+
+			checkResult, err := n.Check(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, checkResult.Packages)
+		})
+	}
+}
+
+func TestParseNodeVersionList(t *testing.T) {
+	assert.Equal(t, []string{"20.18.0", "18.20.4"}, parseNodeVersionList("  * 20.18.0 (Currently using 64-bit executable)\n    18.20.4\n"))
+	assert.Equal(t, []string{"22.11.0"}, parseNodeVersionList("v22.11.0\nsystem\n"))
+}
+
+// writeFakeNvmReleaseCommands は ls-remote の LTS 表記・実行ログに対応した fake の nvm.sh と git を配置し、
+// NVM_DIR と実行ログのパスを返します。
+func writeFakeNvmReleaseCommands(t *testing.T) (string, string) {
+	t.Helper()
+
+	if runtime.GOOS == windowsOS {
+		t.Skip("fake nvm.sh は POSIX シェル前提")
+	}
+
+	nvmDir := t.TempDir()
+	logPath := filepath.Join(t.TempDir(), "commands.log")
+
+	t.Setenv("NVM_DIR", nvmDir)
+	t.Setenv("DEVSYNC_TEST_NVM_LOG", logPath)
+	t.Setenv("DEVSYNC_TEST_NVM_STATE", filepath.Join(t.TempDir(), "default"))
+
+	require.NoError(t, os.MkdirAll(filepath.Join(nvmDir, ".git"), 0o755))
+
+	script := `nvm() {
+  case "$1" in
+    current)
+      if [ -f "${DEVSYNC_TEST_NVM_STATE}" ]; then cat "${DEVSYNC_TEST_NVM_STATE}"; else echo "v22.11.0"; fi
+      ;;
+    ls-remote)
+      cat <<'EOF'
+` + nvmLsRemoteOutput + `EOF
+      ;;
+    alias)
+      echo "nvm $*" >> "${DEVSYNC_TEST_NVM_LOG}"
+      echo "v$3" > "${DEVSYNC_TEST_NVM_STATE}"
+      ;;
+    *)
+      echo "nvm $*" >> "${DEVSYNC_TEST_NVM_LOG}"
+      ;;
+  esac
+}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(nvmDir, "nvm.sh"), []byte(script), 0o644))
+
+	git := `#!/bin/sh
+case "$3" in
+  rev-list) echo "0123abc" ;;
+  describe) echo "v0.40.1" ;;
+  *) echo "git $*" >> "${DEVSYNC_TEST_NVM_LOG}" ;;
+esac
+`
+	binDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, "git"), []byte(git), 0o755))
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	return nvmDir, logPath
+}
@@ -607,3 +607,30 @@ nvm() {
 		}
 	}
 }
+
+func TestNvmUpdater_UpdateWithVersionPolicy(t *testing.T) {
+	fakeDir := t.TempDir()
+	writeFakeNvmCommand(t, fakeDir)
+
+	t.Setenv("PATH", fakeDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	if runtime.GOOS != "windows" {
+		t.Setenv("NVM_DIR", fakeDir)
+	}
+
+	t.Setenv("DEVSYNC_TEST_NVM_MODE", "check_update")
+
+	n := &NvmUpdater{}
+	assert.NoError(t, n.Configure(config.ManagerConfig{"allow": "minor"}))
+
+	got, err := n.Update(context.Background(), UpdateOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, got.UpdatedCount)
+	assert.Equal(t, []PackageInfo{{Name: "node", CurrentVersion: "20.10.0", NewVersion: "22.11.0"}}, got.Held)
+	assert.Contains(t, got.Message, "保留しました")
+
+	got, err = n.Update(context.Background(), UpdateOptions{AllowMajor: true})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, got.UpdatedCount)
+	assert.Empty(t, got.Held)
+}
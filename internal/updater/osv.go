@@ -0,0 +1,365 @@
+package updater
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/scottlz0310/devsync/internal/env"
+)
+
+// Vulnerability は保留中の更新で修正される既知の脆弱性です。
+type Vulnerability struct {
+	ID      string   // OSV の ID（例: GHSA-xxxx-xxxx-xxxx、DSA-5678-1）
+	Aliases []string // CVE などの別名
+	Summary string   // 概要（OSV データにある場合のみ）
+}
+
+// osvManagerEcosystems はマネージャごとに照合する OSV のエコシステムです。
+// apt は Debian / Ubuntu のうちホストのディストリビューションのダンプのみを、ソースパッケージ名（PackageInfo.Source）で照合します。
+var osvManagerEcosystems = map[string][]string{
+	"npm":   {osvEcosystemNpm},
+	"pnpm":  {osvEcosystemNpm},
+	"pip":   {osvEcosystemPyPI},
+	"pipx":  {osvEcosystemPyPI},
+	"uv":    {osvEcosystemPyPI},
+	"cargo": {osvEcosystemCrates},
+	"go":    {osvEcosystemGo},
+	"gem":   {osvEcosystemRubyGems},
+	"apt":   {osvEcosystemDebian, osvEcosystemUbuntu},
+}
+
+// osvEntry は OSV スキーマの脆弱性 1 件です（使用するフィールドのみ）。
+type osvEntry struct {
+	ID        string        `json:"id"`
+	Summary   string        `json:"summary"`
+	Aliases   []string      `json:"aliases"`
+	Withdrawn string        `json:"withdrawn"`
+	Affected  []osvAffected `json:"affected"`
+}
+
+type osvAffected struct {
+	Package struct {
+		Ecosystem string `json:"ecosystem"`
+		Name      string `json:"name"`
+	} `json:"package"`
+	Ranges   []osvRange `json:"ranges"`
+	Versions []string   `json:"versions"`
+}
+
+type osvRange struct {
+	Type   string     `json:"type"`
+	Events []osvEvent `json:"events"`
+}
+
+type osvEvent struct {
+	Introduced   string `json:"introduced"`
+	Fixed        string `json:"fixed"`
+	LastAffected string `json:"last_affected"`
+}
+
+func (e osvEvent) version() string {
+	switch {
+	case e.Introduced != "":
+		return e.Introduced
+	case e.Fixed != "":
+		return e.Fixed
+	default:
+		return e.LastAffected
+	}
+}
+
+// osvRecord は 1 つのパッケージに対する脆弱性の影響範囲です。
+type osvRecord struct {
+	vulnerability Vulnerability
+	affected      osvAffected
+}
+
+// VulnerabilityDatabase はローカルにダウンロードした OSV のダンプをパッケージごとに索引付けしたものです。
+type VulnerabilityDatabase struct {
+	records map[string][]osvRecord
+	// aptRelease はホストのディストリビューションとリリース（例: Debian:12、Ubuntu:22.04）です。
+	aptRelease string
+}
+
+// osvDetectDistroFunc はテストで差し替え可能なディストリビューションの判定処理です。
+var osvDetectDistroFunc = env.DetectLinuxDistro
+
+// LoadVulnerabilityDatabase は dir 配下の OSV のダンプを読み込みます。
+// ダンプはエコシステムごとの all.zip（例: dir/npm/all.zip、dir/PyPI.zip）でも、展開した JSON でも構いません。
+// managers を指定した場合は、それらが照合するエコシステムのみを索引付けします。
+func LoadVulnerabilityDatabase(dir string, managers []string) (*VulnerabilityDatabase, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("OSV データのディレクトリを参照できません: %w", err)
+	}
+
+	if !info.IsDir() {
+		return nil, fmt.Errorf("OSV データのパスがディレクトリではありません: %s", dir)
+	}
+
+	wanted := osvWantedEcosystems(managers)
+	db := &VulnerabilityDatabase{records: make(map[string][]osvRecord)}
+
+	// Debian / Ubuntu はリリースごとに修正バージョンが異なるため、ホストのリリースのデータのみを読み込む
+	if wanted[osvEcosystemDebian] || wanted[osvEcosystemUbuntu] {
+		db.aptRelease = osvHostAptRelease()
+		wanted[osvEcosystemDebian] = osvBaseEcosystem(db.aptRelease) == osvEcosystemDebian
+		wanted[osvEcosystemUbuntu] = osvBaseEcosystem(db.aptRelease) == osvEcosystemUbuntu
+	}
+
+	err = filepath.WalkDir(dir, func(path string, entry fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+
+		// エコシステム名のディレクトリ・zip のうち対象外のものは読み込まない
+		if filepath.Dir(path) == dir && osvSkipEcosystemEntry(entry.Name(), wanted) {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if entry.IsDir() {
+			return nil
+		}
+
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".json":
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+
+			return db.add(path, data, wanted)
+		case ".zip":
+			return db.addZip(path, wanted)
+		default:
+			return nil
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("OSV データの読み込みに失敗: %w", err)
+	}
+
+	return db, nil
+}
+
+// osvWantedEcosystems は managers が照合するエコシステムの集合を返します（managers が空の場合はすべて）。
+func osvWantedEcosystems(managers []string) map[string]bool {
+	wanted := make(map[string]bool)
+
+	if len(managers) == 0 {
+		for _, ecosystems := range osvManagerEcosystems {
+			for _, ecosystem := range ecosystems {
+				wanted[ecosystem] = true
+			}
+		}
+
+		return wanted
+	}
+
+	for _, manager := range managers {
+		for _, ecosystem := range osvManagerEcosystems[manager] {
+			wanted[ecosystem] = true
+		}
+	}
+
+	return wanted
+}
+
+// osvSkipEcosystemEntry は name（例: PyPI、PyPI.zip）が対応エコシステムの名前で、かつ対象外かを判定します。
+func osvSkipEcosystemEntry(name string, wanted map[string]bool) bool {
+	name = strings.TrimSuffix(name, filepath.Ext(name))
+
+	for _, ecosystems := range osvManagerEcosystems {
+		for _, ecosystem := range ecosystems {
+			if strings.EqualFold(name, ecosystem) {
+				return !wanted[ecosystem]
+			}
+		}
+	}
+
+	return false
+}
+
+func (db *VulnerabilityDatabase) addZip(path string, wanted map[string]bool) error {
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		return err
+	}
+	defer archive.Close()
+
+	for _, file := range archive.File {
+		if !strings.EqualFold(filepath.Ext(file.Name), ".json") {
+			continue
+		}
+
+		reader, err := file.Open()
+		if err != nil {
+			return err
+		}
+
+		data, err := io.ReadAll(reader)
+		reader.Close()
+
+		if err != nil {
+			return err
+		}
+
+		if err := db.add(path+"!"+file.Name, data, wanted); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (db *VulnerabilityDatabase) add(source string, data []byte, wanted map[string]bool) error {
+	var entry osvEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return fmt.Errorf("%s の解析に失敗: %w", source, err)
+	}
+
+	// OSV 形式でないファイルや取り下げられた脆弱性は対象外
+	if entry.ID == "" || entry.Withdrawn != "" {
+		return nil
+	}
+
+	vulnerability := Vulnerability{ID: entry.ID, Aliases: entry.Aliases, Summary: entry.Summary}
+
+	for _, affected := range entry.Affected {
+		ecosystem := osvBaseEcosystem(affected.Package.Ecosystem)
+		if !wanted[ecosystem] || affected.Package.Name == "" {
+			continue
+		}
+
+		if (ecosystem == osvEcosystemDebian || ecosystem == osvEcosystemUbuntu) && !osvReleaseMatches(affected.Package.Ecosystem, db.aptRelease) {
+			continue
+		}
+
+		key := osvPackageKey(ecosystem, affected.Package.Name)
+		db.records[key] = append(db.records[key], osvRecord{vulnerability: vulnerability, affected: affected})
+	}
+
+	return nil
+}
+
+// osvBaseEcosystem はリリース付きのエコシステム名（例: Debian:12、Ubuntu:22.04:LTS）からエコシステムを取り出します。
+func osvBaseEcosystem(ecosystem string) string {
+	base, _, _ := strings.Cut(ecosystem, ":")
+	return base
+}
+
+// osvHostAptRelease は /etc/os-release の ID / VERSION_ID から OSV のエコシステム名（例: Debian:12、Ubuntu:22.04）を返します。
+// Debian / Ubuntu 以外（派生ディストリビューションを含む）やリリースを判定できない場合は空を返し、apt の照合を行いません。
+func osvHostAptRelease() string {
+	distro, ok := osvDetectDistroFunc()
+	if !ok || distro.VersionID == "" {
+		return ""
+	}
+
+	switch distro.ID {
+	case "debian":
+		return osvEcosystemDebian + ":" + distro.VersionID
+	case "ubuntu":
+		return osvEcosystemUbuntu + ":" + distro.VersionID
+	default:
+		return ""
+	}
+}
+
+// osvReleaseMatches は OSV のエコシステム名（例: Debian:12、Ubuntu:22.04:LTS）が release のものかを判定します。
+// Ubuntu Pro（Ubuntu:Pro:...）は ESM の契約が必要なため対象外です。
+func osvReleaseMatches(ecosystem, release string) bool {
+	if release == "" {
+		return false
+	}
+
+	return strings.TrimSuffix(ecosystem, ":LTS") == release
+}
+
+func osvPackageKey(ecosystem, name string) string {
+	if ecosystem == osvEcosystemPyPI {
+		name = pythonPackageName(name)
+	}
+
+	return ecosystem + "/" + strings.ToLower(strings.TrimSpace(name))
+}
+
+// FixedVulnerabilities は manager のパッケージ pkg を現在のバージョンから新しいバージョンへ更新することで
+// 修正される脆弱性を返します（現在のバージョンが影響を受け、新しいバージョンは影響を受けないもの）。
+func (db *VulnerabilityDatabase) FixedVulnerabilities(manager string, pkg PackageInfo) []Vulnerability {
+	if db == nil || pkg.CurrentVersion == "" || pkg.NewVersion == "" {
+		return nil
+	}
+
+	name := pkg.Name
+	if pkg.Source != "" {
+		name = pkg.Source
+	}
+
+	seen := make(map[string]bool)
+
+	var fixed []Vulnerability
+
+	for _, ecosystem := range osvManagerEcosystems[manager] {
+		for _, record := range db.records[osvPackageKey(ecosystem, name)] {
+			if seen[record.vulnerability.ID] {
+				continue
+			}
+
+			current, err := osvVersionAffected(ecosystem, record.affected, pkg.CurrentVersion)
+			if err != nil || !current {
+				continue
+			}
+
+			next, err := osvVersionAffected(ecosystem, record.affected, pkg.NewVersion)
+			if err != nil || next {
+				continue
+			}
+
+			seen[record.vulnerability.ID] = true
+			fixed = append(fixed, record.vulnerability)
+		}
+	}
+
+	sort.Slice(fixed, func(i, j int) bool {
+		return fixed[i].ID < fixed[j].ID
+	})
+
+	return fixed
+}
+
+// Enrich は manager の確認結果の各パッケージに、更新で修正される脆弱性を設定します。
+// 戻り値は脆弱性が見つかったパッケージ数です。OSV のエコシステムに対応しないマネージャでは何もしません。
+func (db *VulnerabilityDatabase) Enrich(manager string, result *CheckResult) int {
+	if db == nil || result == nil {
+		return 0
+	}
+
+	count := 0
+
+	for i := range result.Packages {
+		result.Packages[i].Vulnerabilities = db.FixedVulnerabilities(manager, result.Packages[i])
+		if len(result.Packages[i].Vulnerabilities) > 0 {
+			count++
+		}
+	}
+
+	return count
+}
+
+// SupportsVulnerabilityLookup は manager のパッケージを OSV のデータと照合できるかを返します。
+func SupportsVulnerabilityLookup(manager string) bool {
+	_, ok := osvManagerEcosystems[manager]
+	return ok
+}
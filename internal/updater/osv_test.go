@@ -0,0 +1,283 @@
+package updater
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/scottlz0310/devsync/internal/env"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeOSVDump はエコシステムごとのディレクトリ・zip を含む OSV のダンプを作成します。
+func writeOSVDump(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	writeFile := func(path, content string) {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+
+	writeFile(filepath.Join(dir, "PyPI", "PYSEC-2024-1.json"), `{
+  "id": "PYSEC-2024-1",
+  "aliases": ["CVE-2024-35195"],
+  "summary": "Requests session verify bypass",
+  "affected": [{
+    "package": {"ecosystem": "PyPI", "name": "Requests"},
+    "ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "0"}, {"fixed": "2.32.0"}]}]
+  }]
+}`)
+	writeFile(filepath.Join(dir, "Debian", "DSA-5764-1.json"), `{
+  "id": "DSA-5764-1",
+  "affected": [{
+    "package": {"ecosystem": "Debian:12", "name": "openssl"},
+    "ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "0"}, {"fixed": "3.0.14-1~deb12u2"}]}]
+  }, {
+    "package": {"ecosystem": "Debian:11", "name": "openssl"},
+    "ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "0"}, {"fixed": "1.1.1w-0+deb11u2"}]}]
+  }]
+}`)
+	writeFile(filepath.Join(dir, "Ubuntu", "USN-6986-1.json"), `{
+  "id": "USN-6986-1",
+  "affected": [{
+    "package": {"ecosystem": "Ubuntu:22.04:LTS", "name": "openssl"},
+    "ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "0"}, {"fixed": "3.0.2-0ubuntu1.18"}]}]
+  }, {
+    "package": {"ecosystem": "Ubuntu:Pro:18.04:LTS", "name": "openssl"},
+    "ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "0"}, {"fixed": "1.1.1-1ubuntu2.1~18.04.23+esm5"}]}]
+  }]
+}`)
+	writeFile(filepath.Join(dir, "Go", "GO-2024-0001.json"), `{
+  "id": "GO-2024-0001",
+  "affected": [{
+    "package": {"ecosystem": "Go", "name": "golang.org/x/tools/gopls"},
+    "ranges": [{"type": "SEMVER", "events": [{"introduced": "0.16.0"}, {"fixed": "0.16.2"}]}]
+  }]
+}`)
+	writeFile(filepath.Join(dir, "README.txt"), "not an osv file")
+
+	archive, err := os.Create(filepath.Join(dir, "npm.zip"))
+	require.NoError(t, err)
+
+	writer := zip.NewWriter(archive)
+
+	for name, content := range map[string]string{
+		"GHSA-35jh-r3h4-6jhm.json": `{
+  "id": "GHSA-35jh-r3h4-6jhm",
+  "aliases": ["CVE-2021-23337"],
+  "summary": "Command Injection in lodash",
+  "affected": [{
+    "package": {"ecosystem": "npm", "name": "lodash"},
+    "ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "4.17.21"}]}]
+  }]
+}`,
+		"GHSA-withdrawn.json": `{
+  "id": "GHSA-withdrawn",
+  "withdrawn": "2024-01-01T00:00:00Z",
+  "affected": [{
+    "package": {"ecosystem": "npm", "name": "lodash"},
+    "versions": ["4.17.20"]
+  }]
+}`,
+		"GHSA-versions.json": `{
+  "id": "GHSA-versions",
+  "affected": [{
+    "package": {"ecosystem": "npm", "name": "lodash"},
+    "versions": ["4.17.20"]
+  }]
+}`,
+	} {
+		w, err := writer.Create(name)
+		require.NoError(t, err)
+
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, writer.Close())
+	require.NoError(t, archive.Close())
+
+	return dir
+}
+
+// stubOSVDistro はホストのディストリビューションの判定を差し替えます。
+func stubOSVDistro(t *testing.T, id, versionID string) {
+	t.Helper()
+
+	original := osvDetectDistroFunc
+	osvDetectDistroFunc = func() (env.LinuxDistro, bool) {
+		return env.LinuxDistro{ID: id, VersionID: versionID}, id != ""
+	}
+
+	t.Cleanup(func() { osvDetectDistroFunc = original })
+}
+
+func TestVulnerabilityDatabase_FixedVulnerabilities(t *testing.T) {
+	stubOSVDistro(t, "debian", "12")
+
+	db, err := LoadVulnerabilityDatabase(writeOSVDump(t), nil)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name    string
+		manager string
+		pkg     PackageInfo
+		wantIDs []string
+	}{
+		{
+			name:    "zip内のnpmの脆弱性（取り下げ済みは除外）",
+			manager: "npm",
+			pkg:     PackageInfo{Name: "lodash", CurrentVersion: "4.17.20", NewVersion: "4.17.21"},
+			wantIDs: []string{"GHSA-35jh-r3h4-6jhm", "GHSA-versions"},
+		},
+		{
+			name:    "pnpmもnpmのデータと照合",
+			manager: "pnpm",
+			pkg:     PackageInfo{Name: "lodash", CurrentVersion: "4.17.19", NewVersion: "4.17.21"},
+			wantIDs: []string{"GHSA-35jh-r3h4-6jhm"},
+		},
+		{
+			name:    "更新後も影響を受ける場合は対象外",
+			manager: "npm",
+			pkg:     PackageInfo{Name: "lodash", CurrentVersion: "4.17.19", NewVersion: "4.17.20"},
+		},
+		{
+			name:    "PyPIは名前を正規化して照合",
+			manager: "pipx",
+			pkg:     PackageInfo{Name: "requests", CurrentVersion: "2.31.0", NewVersion: "2.32.3"},
+			wantIDs: []string{"PYSEC-2024-1"},
+		},
+		{
+			name:    "Goはモジュールパスで照合",
+			manager: "go",
+			pkg:     PackageInfo{Name: "gopls", Source: "golang.org/x/tools/gopls", CurrentVersion: "v0.16.0", NewVersion: "v0.17.1"},
+			wantIDs: []string{"GO-2024-0001"},
+		},
+		{
+			name:    "aptはリリース付きのエコシステムと照合",
+			manager: "apt",
+			pkg:     PackageInfo{Name: "openssl", CurrentVersion: "3.0.13-1~deb12u1", NewVersion: "3.0.14-1~deb12u2"},
+			wantIDs: []string{"DSA-5764-1"},
+		},
+		{
+			name:    "aptはソースパッケージ名で照合",
+			manager: "apt",
+			pkg:     PackageInfo{Name: "libssl3", Source: "openssl", CurrentVersion: "3.0.13-1~deb12u1", NewVersion: "3.0.14-1~deb12u2"},
+			wantIDs: []string{"DSA-5764-1"},
+		},
+		{
+			name:    "aptは他のリリース・ディストリビューションの範囲と照合しない",
+			manager: "apt",
+			pkg:     PackageInfo{Name: "openssl", CurrentVersion: "1.1.1w-0+deb11u1", NewVersion: "3.0.2-0ubuntu1.18"},
+		},
+		{
+			name:    "対応しないマネージャ",
+			manager: "brew",
+			pkg:     PackageInfo{Name: "lodash", CurrentVersion: "4.17.20", NewVersion: "4.17.21"},
+		},
+		{
+			name:    "新しいバージョンが不明",
+			manager: "go",
+			pkg:     PackageInfo{Name: "gopls", Source: "golang.org/x/tools/gopls", NewVersion: "@latest"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var ids []string
+			for _, vulnerability := range db.FixedVulnerabilities(tc.manager, tc.pkg) {
+				ids = append(ids, vulnerability.ID)
+			}
+
+			assert.Equal(t, tc.wantIDs, ids)
+		})
+	}
+}
+
+func TestVulnerabilityDatabase_Enrich(t *testing.T) {
+	db, err := LoadVulnerabilityDatabase(writeOSVDump(t), []string{"npm"})
+	require.NoError(t, err)
+
+	result := &CheckResult{
+		AvailableUpdates: 2,
+		Packages: []PackageInfo{
+			{Name: "lodash", CurrentVersion: "4.17.19", NewVersion: "4.17.21"},
+			{Name: "typescript", CurrentVersion: "5.4.0", NewVersion: "5.6.3"},
+		},
+	}
+
+	assert.Equal(t, 1, db.Enrich("npm", result))
+	assert.Equal(t, []Vulnerability{{
+		ID:      "GHSA-35jh-r3h4-6jhm",
+		Aliases: []string{"CVE-2021-23337"},
+		Summary: "Command Injection in lodash",
+	}}, result.Packages[0].Vulnerabilities)
+	assert.Empty(t, result.Packages[1].Vulnerabilities)
+
+	assert.Empty(t, db.FixedVulnerabilities("pipx", PackageInfo{Name: "requests", CurrentVersion: "2.31.0", NewVersion: "2.32.3"}),
+		"対象外のエコシステムは読み込まない")
+
+	var nilDB *VulnerabilityDatabase
+	assert.Equal(t, 0, nilDB.Enrich("npm", result))
+}
+
+func TestVulnerabilityDatabase_AptRelease(t *testing.T) {
+	testCases := []struct {
+		name      string
+		id        string
+		versionID string
+		wantIDs   []string
+	}{
+		{name: "Ubuntu 22.04 は LTS のデータと照合", id: "ubuntu", versionID: "22.04", wantIDs: []string{"USN-6986-1"}},
+		{name: "Debian 12 は Ubuntu のデータと照合しない", id: "debian", versionID: "12"},
+		{name: "派生ディストリビューションは照合しない", id: "linuxmint", versionID: "21.3"},
+		{name: "os-release がない", id: ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			stubOSVDistro(t, tc.id, tc.versionID)
+
+			db, err := LoadVulnerabilityDatabase(writeOSVDump(t), []string{"apt"})
+			require.NoError(t, err)
+
+			var ids []string
+			for _, vulnerability := range db.FixedVulnerabilities("apt", PackageInfo{
+				Name: "libssl3", Source: "openssl", CurrentVersion: "3.0.2-0ubuntu1.17", NewVersion: "3.0.2-0ubuntu1.18",
+			}) {
+				ids = append(ids, vulnerability.ID)
+			}
+
+			assert.Equal(t, tc.wantIDs, ids)
+		})
+	}
+}
+
+func TestOSVReleaseMatches(t *testing.T) {
+	assert.True(t, osvReleaseMatches("Debian:12", "Debian:12"))
+	assert.True(t, osvReleaseMatches("Ubuntu:22.04:LTS", "Ubuntu:22.04"))
+	assert.False(t, osvReleaseMatches("Ubuntu:Pro:22.04:LTS", "Ubuntu:22.04"))
+	assert.False(t, osvReleaseMatches("Debian:11", "Debian:12"))
+	assert.False(t, osvReleaseMatches("Debian:12", ""))
+}
+
+func TestLoadVulnerabilityDatabase_Errors(t *testing.T) {
+	_, err := LoadVulnerabilityDatabase(filepath.Join(t.TempDir(), "missing"), nil)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.json"), []byte("{"), 0o644))
+
+	_, err = LoadVulnerabilityDatabase(dir, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "broken.json の解析に失敗")
+}
+
+func TestSupportsVulnerabilityLookup(t *testing.T) {
+	assert.True(t, SupportsVulnerabilityLookup("cargo"))
+	assert.False(t, SupportsVulnerabilityLookup("snap"))
+}
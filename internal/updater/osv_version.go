@@ -0,0 +1,359 @@
+package updater
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// OSV のエコシステム名
+const (
+	osvEcosystemNpm      = "npm"
+	osvEcosystemPyPI     = "PyPI"
+	osvEcosystemCrates   = "crates.io"
+	osvEcosystemGo       = "Go"
+	osvEcosystemRubyGems = "RubyGems"
+	osvEcosystemDebian   = "Debian"
+	osvEcosystemUbuntu   = "Ubuntu"
+)
+
+// compareOSVVersion はエコシステムの規則でバージョンを比較し、left が古ければ負、新しければ正を返します。
+func compareOSVVersion(ecosystem, left, right string) (int, error) {
+	switch ecosystem {
+	case osvEcosystemNpm, osvEcosystemCrates, osvEcosystemGo:
+		return compareSemverVersion(left, right)
+	case osvEcosystemDebian, osvEcosystemUbuntu:
+		return compareDebianVersion(left, right), nil
+	default:
+		return compareGenericVersion(left, right), nil
+	}
+}
+
+// compareSemverVersion はプレリリースを考慮して semver を比較します（"v" 接頭辞は無視）。
+func compareSemverVersion(left, right string) (int, error) {
+	left = strings.TrimPrefix(strings.TrimSpace(left), "v")
+	right = strings.TrimPrefix(strings.TrimSpace(right), "v")
+
+	less, err := isGoVersionLess(left, right)
+	if err != nil {
+		return 0, err
+	}
+
+	if less {
+		return -1, nil
+	}
+
+	greater, err := isGoVersionLess(right, left)
+	if err != nil {
+		return 0, err
+	}
+
+	if greater {
+		return 1, nil
+	}
+
+	return 0, nil
+}
+
+// compareDebianVersion は dpkg の規則（[epoch:]upstream[-revision]、"~" は空文字より前）でバージョンを比較します。
+func compareDebianVersion(left, right string) int {
+	leftEpoch, leftUpstream, leftRevision := splitDebianVersion(left)
+	rightEpoch, rightUpstream, rightRevision := splitDebianVersion(right)
+
+	if leftEpoch != rightEpoch {
+		if leftEpoch < rightEpoch {
+			return -1
+		}
+
+		return 1
+	}
+
+	if c := compareDebianPart(leftUpstream, rightUpstream); c != 0 {
+		return c
+	}
+
+	return compareDebianPart(leftRevision, rightRevision)
+}
+
+func splitDebianVersion(version string) (epoch int, upstream, revision string) {
+	version = strings.TrimSpace(version)
+
+	if before, after, ok := strings.Cut(version, ":"); ok {
+		if n, err := strconv.Atoi(before); err == nil {
+			epoch, version = n, after
+		}
+	}
+
+	if idx := strings.LastIndex(version, "-"); idx != -1 {
+		return epoch, version[:idx], version[idx+1:]
+	}
+
+	return epoch, version, ""
+}
+
+// compareDebianPart は dpkg の verrevcmp に相当する比較です。
+func compareDebianPart(left, right string) int {
+	for left != "" || right != "" {
+		// 数字以外の部分を 1 文字ずつ比較する
+		for (left != "" && !isASCIIDigit(left[0])) || (right != "" && !isASCIIDigit(right[0])) {
+			l, r := debianCharOrder(left), debianCharOrder(right)
+			if l != r {
+				return l - r
+			}
+
+			left, right = left[1:], right[1:]
+		}
+
+		var leftDigits, rightDigits string
+
+		leftDigits, left = splitLeadingDigits(left)
+		rightDigits, right = splitLeadingDigits(right)
+
+		if c := compareDigitStrings(leftDigits, rightDigits); c != 0 {
+			return c
+		}
+	}
+
+	return 0
+}
+
+// debianCharOrder は dpkg の文字の並び順です（"~" < 終端・数字 < 英字 < 記号）。
+func debianCharOrder(s string) int {
+	if s == "" {
+		return 0
+	}
+
+	c := s[0]
+
+	switch {
+	case c == '~':
+		return -1
+	case isASCIIDigit(c):
+		return 0
+	case (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z'):
+		return int(c)
+	default:
+		return int(c) + 256
+	}
+}
+
+// compareGenericVersion は数字と英字の並びに分けて比較します（PyPI / RubyGems など）。
+// "1.0rc1" や "1.0.pre" のように英字で始まる続きはプレリリースとみなし、続きのない版より古く扱います。
+func compareGenericVersion(left, right string) int {
+	leftTokens := genericVersionTokens(left)
+	rightTokens := genericVersionTokens(right)
+
+	for i := 0; i < len(leftTokens) || i < len(rightTokens); i++ {
+		switch {
+		case i >= len(leftTokens):
+			if c := trailingTokenOrder(rightTokens[i]); c != 0 {
+				return -c
+			}
+
+			continue
+		case i >= len(rightTokens):
+			if c := trailingTokenOrder(leftTokens[i]); c != 0 {
+				return c
+			}
+
+			continue
+		}
+
+		l, r := leftTokens[i], rightTokens[i]
+		lNum, rNum := isASCIIDigit(l[0]), isASCIIDigit(r[0])
+
+		switch {
+		case lNum && rNum:
+			if c := compareDigitStrings(l, r); c != 0 {
+				return c
+			}
+		case lNum:
+			return 1
+		case rNum:
+			return -1
+		default:
+			if c := genericTokenRank(l) - genericTokenRank(r); c != 0 {
+				return c
+			}
+
+			if c := strings.Compare(l, r); c != 0 {
+				return c
+			}
+		}
+	}
+
+	return 0
+}
+
+// genericTokenRank はプレリリースを表す英字の並び順です（dev < alpha < beta < rc）。
+func genericTokenRank(token string) int {
+	switch token {
+	case "dev":
+		return 0
+	case "a", "alpha":
+		return 1
+	case "b", "beta":
+		return 2
+	case "c", "rc", "pre", "preview":
+		return 3
+	default:
+		return 4
+	}
+}
+
+// trailingTokenOrder は片方にだけ続くトークンの扱いです（"1.0" と "1.0.0" は同じ、数字・post は新しい、それ以外はプレリリース）。
+func trailingTokenOrder(token string) int {
+	if isASCIIDigit(token[0]) {
+		return compareDigitStrings(token, "")
+	}
+
+	if token == "post" {
+		return 1
+	}
+
+	return -1
+}
+
+func genericVersionTokens(version string) []string {
+	version = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(version), "v"))
+
+	var tokens []string
+
+	for version != "" {
+		c := version[0]
+
+		switch {
+		case isASCIIDigit(c):
+			var digits string
+
+			digits, version = splitLeadingDigits(version)
+			tokens = append(tokens, digits)
+		case c >= 'a' && c <= 'z':
+			end := 1
+			for end < len(version) && version[end] >= 'a' && version[end] <= 'z' {
+				end++
+			}
+
+			tokens = append(tokens, version[:end])
+			version = version[end:]
+		default:
+			version = version[1:]
+		}
+	}
+
+	return tokens
+}
+
+func splitLeadingDigits(s string) (digits, rest string) {
+	end := 0
+	for end < len(s) && isASCIIDigit(s[end]) {
+		end++
+	}
+
+	return s[:end], s[end:]
+}
+
+// compareDigitStrings は任意の長さの数字列を数値として比較します（空文字は 0）。
+func compareDigitStrings(left, right string) int {
+	left = strings.TrimLeft(left, "0")
+	right = strings.TrimLeft(right, "0")
+
+	if len(left) != len(right) {
+		if len(left) < len(right) {
+			return -1
+		}
+
+		return 1
+	}
+
+	return strings.Compare(left, right)
+}
+
+func isASCIIDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// osvVersionAffected は OSV の affected 要素（versions / ranges）が version を含むかを判定します。
+// GIT の範囲はコミットハッシュのため対象外です。
+func osvVersionAffected(ecosystem string, affected osvAffected, version string) (bool, error) {
+	for _, v := range affected.Versions {
+		if c, err := compareOSVVersion(ecosystem, v, version); err == nil && c == 0 {
+			return true, nil
+		}
+	}
+
+	for _, r := range affected.Ranges {
+		if r.Type == "GIT" {
+			continue
+		}
+
+		inRange, err := osvRangeContains(ecosystem, r.Events, version)
+		if err != nil {
+			return false, err
+		}
+
+		if inRange {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// osvRangeContains は introduced / fixed / last_affected のイベント列が version を含むかを評価します。
+// OSV スキーマの評価手順に従い、イベントをバージョン順に並べてから先頭から適用します。
+func osvRangeContains(ecosystem string, events []osvEvent, version string) (bool, error) {
+	sorted := append([]osvEvent(nil), events...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		left, right := sorted[i].version(), sorted[j].version()
+		if left == "0" || right == "0" {
+			return left == "0" && right != "0"
+		}
+
+		c, err := compareOSVVersion(ecosystem, left, right)
+
+		return err == nil && c < 0
+	})
+
+	affected := false
+
+	for _, event := range sorted {
+		switch {
+		case event.Introduced != "":
+			if event.Introduced == "0" {
+				affected = true
+				continue
+			}
+
+			c, err := compareOSVVersion(ecosystem, version, event.Introduced)
+			if err != nil {
+				return false, fmt.Errorf("introduced %q との比較に失敗: %w", event.Introduced, err)
+			}
+
+			if c >= 0 {
+				affected = true
+			}
+		case event.Fixed != "":
+			c, err := compareOSVVersion(ecosystem, version, event.Fixed)
+			if err != nil {
+				return false, fmt.Errorf("fixed %q との比較に失敗: %w", event.Fixed, err)
+			}
+
+			if c >= 0 {
+				affected = false
+			}
+		case event.LastAffected != "":
+			c, err := compareOSVVersion(ecosystem, version, event.LastAffected)
+			if err != nil {
+				return false, fmt.Errorf("last_affected %q との比較に失敗: %w", event.LastAffected, err)
+			}
+
+			if c > 0 {
+				affected = false
+			}
+		}
+	}
+
+	return affected, nil
+}
@@ -0,0 +1,78 @@
+package updater
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareOSVVersion(t *testing.T) {
+	testCases := []struct {
+		name      string
+		ecosystem string
+		left      string
+		right     string
+		want      int
+	}{
+		{name: "semver", ecosystem: osvEcosystemNpm, left: "4.17.20", right: "4.17.21", want: -1},
+		{name: "semverのプレリリース", ecosystem: osvEcosystemCrates, left: "1.0.0-rc.1", right: "1.0.0", want: -1},
+		{name: "Goのv接頭辞", ecosystem: osvEcosystemGo, left: "v0.17.1", right: "0.17.1", want: 0},
+		{name: "Debianのepoch", ecosystem: osvEcosystemDebian, left: "1:2.0-1", right: "2:1.0-1", want: -1},
+		{name: "Debianのチルダ", ecosystem: osvEcosystemDebian, left: "3.0.15-1~deb12u1", right: "3.0.15-1", want: -1},
+		{name: "Debianのリビジョン", ecosystem: osvEcosystemUbuntu, left: "3.0.2-0ubuntu1.18", right: "3.0.2-0ubuntu1.17", want: 1},
+		{name: "PyPIのプレリリース", ecosystem: osvEcosystemPyPI, left: "2.0rc1", right: "2.0", want: -1},
+		{name: "PyPIのdevはalphaより前", ecosystem: osvEcosystemPyPI, left: "2.0.dev1", right: "2.0a1", want: -1},
+		{name: "PyPIのpost", ecosystem: osvEcosystemPyPI, left: "2.0.post1", right: "2.0", want: 1},
+		{name: "末尾の0は同じ", ecosystem: osvEcosystemPyPI, left: "2.0", right: "2.0.0", want: 0},
+		{name: "RubyGemsのpre", ecosystem: osvEcosystemRubyGems, left: "7.1.0.pre", right: "7.1.0", want: -1},
+		{name: "数値の桁数", ecosystem: osvEcosystemRubyGems, left: "1.10.0", right: "1.9.3", want: 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := compareOSVVersion(tc.ecosystem, tc.left, tc.right)
+			require.NoError(t, err)
+
+			switch {
+			case tc.want < 0:
+				assert.Negative(t, got)
+			case tc.want > 0:
+				assert.Positive(t, got)
+			default:
+				assert.Zero(t, got)
+			}
+		})
+	}
+}
+
+func TestOSVRangeContains(t *testing.T) {
+	events := []osvEvent{
+		{Fixed: "1.2.3"},
+		{Introduced: "0"},
+		{Introduced: "2.0.0"},
+		{LastAffected: "2.1.0"},
+	}
+
+	testCases := []struct {
+		version string
+		want    bool
+	}{
+		{version: "1.0.0", want: true},
+		{version: "1.2.3", want: false},
+		{version: "1.9.0", want: false},
+		{version: "2.1.0", want: true},
+		{version: "2.1.1", want: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.version, func(t *testing.T) {
+			got, err := osvRangeContains(osvEcosystemNpm, events, tc.version)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got, "イベントは並べ替えてから評価する")
+		})
+	}
+
+	_, err := osvRangeContains(osvEcosystemNpm, []osvEvent{{Introduced: "1.0.0"}}, "latest")
+	assert.Error(t, err, "バージョンとして解釈できない場合はエラー")
+}
@@ -0,0 +1,272 @@
+package updater
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/scottlz0310/devsync/internal/config"
+)
+
+// PackageInstaller は sys.managers.<name>.packages による宣言的なパッケージ管理に対応するマネージャが実装します。
+// Updater がインストール済みパッケージの更新のみを行うのに対し、
+// 宣言された一覧との差分（未インストール・一覧外）を検出してインストール・削除できます。
+type PackageInstaller interface {
+	Updater
+
+	// PackageName は packages の指定（バージョン指定などを含む）から、
+	// インストール済み一覧と照合するためのパッケージ名を返します。
+	PackageName(spec string) string
+
+	// InstalledPackages はインストール済みのパッケージ名を返します。
+	InstalledPackages(ctx context.Context) ([]string, error)
+
+	// InstallPackage は packages の指定どおりにパッケージをインストールします。
+	InstallPackage(ctx context.Context, spec string) error
+
+	// RemovePackage はパッケージを削除します。name は InstalledPackages が返す名前です。
+	RemovePackage(ctx context.Context, name string) error
+}
+
+// PackageDrift は宣言されたパッケージ一覧とインストール状態の差分です。
+type PackageDrift struct {
+	// Missing は宣言されているが未インストールのパッケージです（packages の指定のまま）。
+	Missing []string
+	// Unlisted はインストール済みだが宣言されていないパッケージ名です。
+	Unlisted []string
+}
+
+// HasDrift は差分があるかを返します。
+func (d *PackageDrift) HasDrift() bool {
+	return d != nil && (len(d.Missing) > 0 || len(d.Unlisted) > 0)
+}
+
+// ApplyOptions は宣言的パッケージ一覧の適用オプションです。
+type ApplyOptions struct {
+	// DryRun が true の場合、インストール・削除は行わず計画のみ返します。
+	DryRun bool
+	// Prune が true の場合、宣言されていないパッケージを削除します。
+	Prune bool
+}
+
+// ApplyResult は宣言的パッケージ一覧の適用結果です。
+type ApplyResult struct {
+	// Drift は適用前の差分です。
+	Drift *PackageDrift
+	// Installed はインストールしたパッケージです（DryRun 時はインストール予定）。
+	Installed []string
+	// Removed は削除したパッケージです（DryRun 時は削除予定）。
+	Removed []string
+	// Errors はインストール・削除に失敗したパッケージのエラーです。
+	Errors []error
+}
+
+// DesiredPackages はマネージャ設定の packages を返します。
+// go マネージャは packages が未指定の場合、従来の targets を宣言一覧として扱います。
+func DesiredPackages(name string, cfg config.ManagerConfig) []string {
+	if cfg == nil {
+		return nil
+	}
+
+	packages, ok := toStringList(cfg["packages"])
+	if !ok && name == "go" {
+		packages, _ = toStringList(cfg["targets"])
+	}
+
+	return packages
+}
+
+// RemoveUnlistedEnabled はマネージャ設定の remove_unlisted が有効かを返します。
+func RemoveUnlistedEnabled(cfg config.ManagerConfig) bool {
+	if cfg == nil {
+		return false
+	}
+
+	enabled, ok := cfg["remove_unlisted"].(bool)
+
+	return ok && enabled
+}
+
+func toStringList(value interface{}) ([]string, bool) {
+	var items []string
+
+	switch v := value.(type) {
+	case []interface{}:
+		items = make([]string, 0, len(v))
+
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				items = append(items, s)
+			}
+		}
+	case []string:
+		items = append([]string(nil), v...)
+	default:
+		return nil, false
+	}
+
+	result := make([]string, 0, len(items))
+
+	for _, item := range items {
+		if trimmed := strings.TrimSpace(item); trimmed != "" {
+			result = append(result, trimmed)
+		}
+	}
+
+	return result, true
+}
+
+// CheckDrift は宣言されたパッケージ一覧とインストール済みパッケージの差分を返します。
+func CheckDrift(ctx context.Context, installer PackageInstaller, desired []string) (*PackageDrift, error) {
+	installed, err := installer.InstalledPackages(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return computeDrift(installer.PackageName, desired, installed), nil
+}
+
+func computeDrift(nameOf func(string) string, desired, installed []string) *PackageDrift {
+	drift := &PackageDrift{}
+
+	installedSet := make(map[string]bool, len(installed))
+	for _, name := range installed {
+		installedSet[name] = true
+	}
+
+	desiredSet := make(map[string]bool, len(desired))
+
+	for _, spec := range desired {
+		name := nameOf(spec)
+		if desiredSet[name] {
+			continue
+		}
+
+		desiredSet[name] = true
+
+		if !installedSet[name] {
+			drift.Missing = append(drift.Missing, spec)
+		}
+	}
+
+	for _, name := range installed {
+		if !desiredSet[name] {
+			drift.Unlisted = append(drift.Unlisted, name)
+		}
+	}
+
+	sort.Strings(drift.Unlisted)
+
+	return drift
+}
+
+// ApplyPackages は未インストールのパッケージをインストールし、Prune 指定時は一覧外のパッケージを削除します。
+// 個々のパッケージの失敗は ApplyResult.Errors に記録し、残りのパッケージの処理を継続します。
+func ApplyPackages(ctx context.Context, installer PackageInstaller, desired []string, opts ApplyOptions) (*ApplyResult, error) {
+	drift, err := CheckDrift(ctx, installer, desired)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ApplyResult{Drift: drift}
+
+	for _, spec := range drift.Missing {
+		if ctx.Err() != nil {
+			result.Errors = append(result.Errors, ctx.Err())
+			return result, nil
+		}
+
+		if !opts.DryRun {
+			if err := installer.InstallPackage(ctx, spec); err != nil {
+				result.Errors = append(result.Errors, fmt.Errorf("%s のインストールに失敗: %w", spec, err))
+				continue
+			}
+		}
+
+		result.Installed = append(result.Installed, spec)
+	}
+
+	if !opts.Prune {
+		return result, nil
+	}
+
+	for _, name := range drift.Unlisted {
+		if ctx.Err() != nil {
+			result.Errors = append(result.Errors, ctx.Err())
+			return result, nil
+		}
+
+		if !opts.DryRun {
+			if err := installer.RemovePackage(ctx, name); err != nil {
+				result.Errors = append(result.Errors, fmt.Errorf("%s の削除に失敗: %w", name, err))
+				continue
+			}
+		}
+
+		result.Removed = append(result.Removed, name)
+	}
+
+	return result, nil
+}
+
+// runPackageCommand はインストール・削除コマンドを実行し、出力をそのまま端末に流します。
+func runPackageCommand(ctx context.Context, command string, args ...string) error {
+	cmd := exec.CommandContext(ctx, command, args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s %s に失敗: %w", command, strings.Join(args, " "), err)
+	}
+
+	return nil
+}
+
+// runPackageListCommand はインストール済み一覧を取得するコマンドを実行し、標準出力を返します。
+func runPackageListCommand(ctx context.Context, command string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, command, args...)
+
+	var stderr bytes.Buffer
+
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf(
+			"%s %s の実行に失敗: %w",
+			command,
+			strings.Join(args, " "),
+			buildCommandOutputErr(err, combineCommandOutputs(output, stderr.Bytes())),
+		)
+	}
+
+	return output, nil
+}
+
+// stripPackageVersion は "name@version" 形式からバージョン指定を除去します。
+// スコープ付き npm パッケージ（@scope/name@version）の先頭の "@" は保持します。
+func stripPackageVersion(spec string) string {
+	spec = strings.TrimSpace(spec)
+	if idx := strings.LastIndex(spec, "@"); idx > 0 {
+		return spec[:idx]
+	}
+
+	return spec
+}
+
+// pythonPackageName は pip 形式の指定（extras やバージョン指定子を含む）からパッケージ名を取り出し、
+// PEP 503 に従って正規化します（例: "Black[jupyter]>=24" -> "black"）。
+func pythonPackageName(spec string) string {
+	spec = strings.TrimSpace(spec)
+	if idx := strings.IndexAny(spec, "[=<>!~;@ "); idx != -1 {
+		spec = spec[:idx]
+	}
+
+	spec = strings.ToLower(spec)
+
+	return strings.NewReplacer("_", "-", ".", "-").Replace(spec)
+}
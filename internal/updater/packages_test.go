@@ -0,0 +1,240 @@
+package updater
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"testing"
+
+	"github.com/scottlz0310/devsync/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeInstaller はテスト用の PackageInstaller です。
+type fakeInstaller struct {
+	mockUpdater
+	installed  []string
+	listErr    error
+	installErr map[string]error
+	calls      []string
+}
+
+func (f *fakeInstaller) PackageName(spec string) string {
+	return stripPackageVersion(spec)
+}
+
+func (f *fakeInstaller) InstalledPackages(context.Context) ([]string, error) {
+	return f.installed, f.listErr
+}
+
+func (f *fakeInstaller) InstallPackage(_ context.Context, spec string) error {
+	f.calls = append(f.calls, "install "+spec)
+
+	return f.installErr[spec]
+}
+
+func (f *fakeInstaller) RemovePackage(_ context.Context, name string) error {
+	f.calls = append(f.calls, "remove "+name)
+
+	return nil
+}
+
+func TestDesiredPackages(t *testing.T) {
+	tests := []struct {
+		name     string
+		manager  string
+		cfg      config.ManagerConfig
+		expected []string
+	}{
+		{
+			name:     "nil設定",
+			manager:  "npm",
+			cfg:      nil,
+			expected: nil,
+		},
+		{
+			name:     "YAML由来の[]interface{}（空白要素は除外）",
+			manager:  "npm",
+			cfg:      config.ManagerConfig{"packages": []interface{}{"typescript", " ", "@biomejs/biome@1.9.0", 1}},
+			expected: []string{"typescript", "@biomejs/biome@1.9.0"},
+		},
+		{
+			name:     "goはpackages未指定ならtargetsを使用",
+			manager:  "go",
+			cfg:      config.ManagerConfig{"targets": []string{"golang.org/x/tools/gopls@latest"}},
+			expected: []string{"golang.org/x/tools/gopls@latest"},
+		},
+		{
+			name:     "go以外はtargetsを使用しない",
+			manager:  "npm",
+			cfg:      config.ManagerConfig{"targets": []string{"typescript"}},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, DesiredPackages(tt.manager, tt.cfg))
+		})
+	}
+}
+
+func TestRemoveUnlistedEnabled(t *testing.T) {
+	assert.False(t, RemoveUnlistedEnabled(nil))
+	assert.False(t, RemoveUnlistedEnabled(config.ManagerConfig{"remove_unlisted": "yes"}))
+	assert.True(t, RemoveUnlistedEnabled(config.ManagerConfig{"remove_unlisted": true}))
+}
+
+func TestComputeDrift(t *testing.T) {
+	drift := computeDrift(
+		stripPackageVersion,
+		[]string{"typescript@5", "@biomejs/biome", "eslint", "typescript"},
+		[]string{"zx", "typescript", "@biomejs/biome", "prettier"},
+	)
+
+	assert.Equal(t, []string{"eslint"}, drift.Missing)
+	assert.Equal(t, []string{"prettier", "zx"}, drift.Unlisted)
+	assert.True(t, drift.HasDrift())
+	assert.False(t, (&PackageDrift{}).HasDrift())
+	assert.False(t, (*PackageDrift)(nil).HasDrift())
+}
+
+func TestApplyPackages(t *testing.T) {
+	tests := []struct {
+		name          string
+		opts          ApplyOptions
+		expectedCalls []string
+		installed     []string
+		removed       []string
+		errCount      int
+	}{
+		{
+			name:          "不足分のみインストール",
+			opts:          ApplyOptions{},
+			expectedCalls: []string{"install eslint@9", "install broken"},
+			installed:     []string{"eslint@9"},
+			errCount:      1,
+		},
+		{
+			name:          "Prune指定で一覧外を削除",
+			opts:          ApplyOptions{Prune: true},
+			expectedCalls: []string{"install eslint@9", "install broken", "remove zx"},
+			installed:     []string{"eslint@9"},
+			removed:       []string{"zx"},
+			errCount:      1,
+		},
+		{
+			name:      "DryRunでは実行しない",
+			opts:      ApplyOptions{DryRun: true, Prune: true},
+			installed: []string{"eslint@9", "broken"},
+			removed:   []string{"zx"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			installer := &fakeInstaller{
+				installed:  []string{"typescript", "zx"},
+				installErr: map[string]error{"broken": errors.New("not found")},
+			}
+
+			result, err := ApplyPackages(context.Background(), installer, []string{"typescript", "eslint@9", "broken"}, tt.opts)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedCalls, installer.calls)
+			assert.Equal(t, tt.installed, result.Installed)
+			assert.Equal(t, tt.removed, result.Removed)
+			assert.Len(t, result.Errors, tt.errCount)
+		})
+	}
+
+	t.Run("一覧取得失敗はエラー", func(t *testing.T) {
+		installer := &fakeInstaller{listErr: errors.New("list failed")}
+
+		_, err := ApplyPackages(context.Background(), installer, []string{"typescript"}, ApplyOptions{})
+		assert.Error(t, err)
+		assert.Empty(t, installer.calls)
+	})
+}
+
+func TestStripPackageVersion(t *testing.T) {
+	tests := map[string]string{
+		"typescript":           "typescript",
+		"typescript@5.3.0":     "typescript",
+		"@biomejs/biome":       "@biomejs/biome",
+		"@biomejs/biome@1.9.0": "@biomejs/biome",
+		"ripgrep@14":           "ripgrep",
+	}
+
+	for input, expected := range tests {
+		assert.Equal(t, expected, stripPackageVersion(input), input)
+	}
+}
+
+func TestPythonPackageName(t *testing.T) {
+	tests := map[string]string{
+		"black":                "black",
+		"Black[jupyter]>=24.1": "black",
+		"ruff==0.6.0":          "ruff",
+		"pre_commit":           "pre-commit",
+		"zope.interface~=6.0":  "zope-interface",
+	}
+
+	for input, expected := range tests {
+		assert.Equal(t, expected, pythonPackageName(input), input)
+	}
+}
+
+func TestNpmUpdater_parseListJSON(t *testing.T) {
+	n := &NpmUpdater{}
+
+	names, err := n.parseListJSON([]byte(`{"dependencies":{"npm":{"version":"10.0.0"},"corepack":{"version":"0.29.0"},"typescript":{"version":"5.3.0"}}}`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"typescript"}, names)
+
+	_, err = n.parseListJSON([]byte("not json"))
+	assert.Error(t, err)
+}
+
+func TestPnpmUpdater_parseListJSON(t *testing.T) {
+	p := &PnpmUpdater{}
+
+	names, err := p.parseListJSON([]byte(`[{"path":"/global/5","dependencies":{"zx":{"version":"8.0.0"}}}]`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"zx"}, names)
+
+	names, err = p.parseListJSON([]byte(""))
+	require.NoError(t, err)
+	assert.Empty(t, names)
+}
+
+func TestGoUpdater_InstalledAndRemovePackages(t *testing.T) {
+	setupFakeGoTools(t, map[string]*debug.BuildInfo{
+		"gopls": fakeGoBuildInfo("golang.org/x/tools/gopls", "golang.org/x/tools/gopls", "v0.16.0"),
+	}, nil)
+
+	g := &GoUpdater{}
+
+	installed, err := g.InstalledPackages(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"golang.org/x/tools/gopls"}, installed)
+	assert.Equal(t, "golang.org/x/tools/gopls", g.PackageName("golang.org/x/tools/gopls@v0.16.0"))
+	assert.Equal(t, "golang.org/x/tools/gopls", g.PackageName("gopls"), "バイナリ名はインストール済みのパッケージパスに解決する")
+	assert.Equal(t, "staticcheck", g.PackageName("staticcheck@latest"))
+
+	drift, err := CheckDrift(context.Background(), g, []string{"gopls", "staticcheck"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"staticcheck"}, drift.Missing)
+	assert.Empty(t, drift.Unlisted)
+
+	err = g.InstallPackage(context.Background(), "staticcheck")
+	require.ErrorContains(t, err, "パッケージパスを指定してください")
+
+	require.NoError(t, g.RemovePackage(context.Background(), "golang.org/x/tools/gopls"))
+	_, err = os.Stat(filepath.Join(os.Getenv("GOBIN"), "gopls"))
+	assert.True(t, os.IsNotExist(err))
+
+	assert.Error(t, g.RemovePackage(context.Background(), "github.com/example/missing"))
+}
@@ -0,0 +1,292 @@
+package updater
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/scottlz0310/devsync/internal/config"
+)
+
+// pacmanCheckupdatesNoUpdatesExitCode は checkupdates が更新なしの場合に返す終了コードです。
+const pacmanCheckupdatesNoUpdatesExitCode = 2
+
+// pacmanSupportedAURHelpers は aur_helper に指定できる AUR ヘルパーです。
+var pacmanSupportedAURHelpers = []string{"paru", "yay"}
+
+// PacmanUpdater は pacman パッケージマネージャ (Arch Linux) の実装です。
+// aur_helper に paru / yay を指定すると AUR パッケージも含めて更新します。
+type PacmanUpdater struct {
+	useSudo   bool
+	aurHelper string
+}
+
+// 起動時にレジストリに登録
+func init() {
+	Register(&PacmanUpdater{useSudo: true})
+}
+
+func (p *PacmanUpdater) Name() string {
+	return "pacman"
+}
+
+func (p *PacmanUpdater) DisplayName() string {
+	return "pacman (Arch Linux)"
+}
+
+func (p *PacmanUpdater) IsAvailable() bool {
+	_, err := exec.LookPath("pacman")
+	return err == nil
+}
+
+func (p *PacmanUpdater) Configure(cfg config.ManagerConfig) error {
+	if cfg == nil {
+		return nil
+	}
+
+	p.useSudo = configureUseSudo(cfg, p.useSudo)
+
+	helper, ok := cfg["aur_helper"].(string)
+	if !ok {
+		return nil
+	}
+
+	helper = strings.TrimSpace(helper)
+	if helper != "" && !containsString(pacmanSupportedAURHelpers, helper) {
+		return fmt.Errorf("aur_helper の値が不正です: %s（%s のいずれかを指定してください）", helper, strings.Join(pacmanSupportedAURHelpers, " / "))
+	}
+
+	p.aurHelper = helper
+
+	return nil
+}
+
+// Check は更新可能なパッケージを確認します。
+// pacman-contrib の checkupdates があれば一時データベースで同期し、システムのデータベースを変更せずに確認します。
+// ない場合は最後に同期したデータベースを参照する pacman -Qu で確認します（同期していない更新は検出できません）。
+func (p *PacmanUpdater) Check(ctx context.Context) (*CheckResult, error) {
+	packages, err := p.checkRepositoryUpdates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.aurHelper != "" {
+		aurPackages, err := p.runQueryUpgrades(ctx, p.aurHelper, "-Qua")
+		if err != nil {
+			return nil, fmt.Errorf("%s -Qua の実行に失敗: %w", p.aurHelper, err)
+		}
+
+		packages = append(packages, aurPackages...)
+	}
+
+	return &CheckResult{
+		AvailableUpdates: len(packages),
+		Packages:         packages,
+	}, nil
+}
+
+func (p *PacmanUpdater) checkRepositoryUpdates(ctx context.Context) ([]PackageInfo, error) {
+	if !hasPacmanCheckupdates() {
+		packages, err := p.runQueryUpgrades(ctx, "pacman", "-Qu")
+		if err != nil {
+			return nil, fmt.Errorf("pacman -Qu の実行に失敗: %w", err)
+		}
+
+		return packages, nil
+	}
+
+	cmd := exec.CommandContext(ctx, "checkupdates")
+	cmd.Env = append(os.Environ(), "LANG=C", "LC_ALL=C")
+
+	var stderr bytes.Buffer
+
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil && exitCodeOf(err) != pacmanCheckupdatesNoUpdatesExitCode {
+		return nil, fmt.Errorf("checkupdates の実行に失敗: %w", buildCommandOutputErr(err, combineCommandOutputs(output, stderr.Bytes())))
+	}
+
+	return parsePacmanUpgrades(string(output)), nil
+}
+
+// runQueryUpgrades は pacman -Qu 形式のコマンドを実行します。
+// 更新がない場合は出力なしで終了コード 1 を返すため、これを成功として扱います。
+func (p *PacmanUpdater) runQueryUpgrades(ctx context.Context, command string, args ...string) ([]PackageInfo, error) {
+	cmd := exec.CommandContext(ctx, command, args...)
+	cmd.Env = append(os.Environ(), "LANG=C", "LC_ALL=C")
+
+	var stderr bytes.Buffer
+
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil && (exitCodeOf(err) != 1 || len(bytes.TrimSpace(output)) > 0 || len(bytes.TrimSpace(stderr.Bytes())) > 0) {
+		return nil, buildCommandOutputErr(err, combineCommandOutputs(output, stderr.Bytes()))
+	}
+
+	return parsePacmanUpgrades(string(output)), nil
+}
+
+func (p *PacmanUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateResult, error) {
+	// AUR ヘルパーは root での実行を拒否し、必要に応じて自身で sudo を呼び出すため sudo を付けない
+	command, args := systemCommandLine(p.useSudo, "pacman", "-Syu", "--noconfirm")
+	if p.aurHelper != "" {
+		command, args = p.aurHelper, []string{"-Syu", "--noconfirm"}
+	}
+
+	// checkupdates がない場合の pacman -Qu は同期前のデータベースを参照するため、更新を見落とす。
+	// -Sy だけの同期は部分更新の原因になるため、件数で判定せずに常に -Syu を実行する
+	if !opts.DryRun && !hasPacmanCheckupdates() {
+		return p.syncAndUpgrade(ctx, command, args)
+	}
+
+	checkResult, err := p.Check(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return runCountBasedUpdate(
+		ctx,
+		opts,
+		checkResult,
+		"すべてのパッケージは最新です",
+		func(count int) string {
+			return fmt.Sprintf("%d 件のパッケージが更新可能です（DryRunモード）", count)
+		},
+		command,
+		args,
+		p.updateCommandName()+" -Syu に失敗: %w",
+		func(count int) string {
+			return fmt.Sprintf("%d 件のパッケージを更新しました", count)
+		},
+	)
+}
+
+// syncAndUpgrade は -Syu を実行し、前後の pacman -Q の差分から更新したパッケージを求めます。
+func (p *PacmanUpdater) syncAndUpgrade(ctx context.Context, command string, args []string) (*UpdateResult, error) {
+	result := &UpdateResult{}
+
+	before, err := installedPacmanPackages(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.CommandContext(ctx, command, args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Stdin = os.Stdin
+
+	if err := cmd.Run(); err != nil {
+		result.Errors = append(result.Errors, err)
+		return result, fmt.Errorf(p.updateCommandName()+" -Syu に失敗: %w", err)
+	}
+
+	after, err := installedPacmanPackages(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Packages = diffPacmanPackages(before, after)
+	result.UpdatedCount = len(result.Packages)
+
+	result.Message = "すべてのパッケージは最新です"
+	if result.UpdatedCount > 0 {
+		result.Message = fmt.Sprintf("%d 件のパッケージを更新しました", result.UpdatedCount)
+	}
+
+	return result, nil
+}
+
+// installedPacmanPackages は pacman -Q の出力からインストール済みのパッケージとバージョンを返します。
+func installedPacmanPackages(ctx context.Context) (map[string]string, error) {
+	output, err := runCommandOutputWithLocaleC(ctx, "pacman", []string{"-Q"}, "pacman -Q の実行に失敗: %w")
+	if err != nil {
+		return nil, err
+	}
+
+	packages := make(map[string]string)
+
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 {
+			packages[fields[0]] = fields[1]
+		}
+	}
+
+	return packages, nil
+}
+
+// diffPacmanPackages はバージョンが変わったパッケージを名前順で返します（新規インストールと削除は含めません）。
+func diffPacmanPackages(before, after map[string]string) []PackageInfo {
+	packages := make([]PackageInfo, 0)
+
+	for name, oldVersion := range before {
+		newVersion, ok := after[name]
+		if !ok || newVersion == oldVersion {
+			continue
+		}
+
+		packages = append(packages, PackageInfo{Name: name, CurrentVersion: oldVersion, NewVersion: newVersion})
+	}
+
+	sort.Slice(packages, func(i, j int) bool {
+		return packages[i].Name < packages[j].Name
+	})
+
+	return packages
+}
+
+func hasPacmanCheckupdates() bool {
+	_, err := exec.LookPath("checkupdates")
+	return err == nil
+}
+
+func (p *PacmanUpdater) updateCommandName() string {
+	if p.aurHelper != "" {
+		return p.aurHelper
+	}
+
+	return "pacman"
+}
+
+// parsePacmanUpgrades は "pacman -Qu" / "checkupdates" の出力をパースします
+// 形式: "name old-version -> new-version"（pacman -Qu では末尾に " [ignored]" が付く場合があります）
+func parsePacmanUpgrades(output string) []PackageInfo {
+	lines := strings.Split(output, "\n")
+	packages := make([]PackageInfo, 0, len(lines))
+
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) < 4 || fields[2] != "->" {
+			continue
+		}
+
+		// IgnorePkg に指定されたパッケージは -Syu で更新されないため対象外
+		if len(fields) > 4 && fields[4] == "[ignored]" {
+			continue
+		}
+
+		packages = append(packages, PackageInfo{
+			Name:           fields[0],
+			CurrentVersion: fields[1],
+			NewVersion:     fields[3],
+		})
+	}
+
+	return packages
+}
+
+func containsString(items []string, target string) bool {
+	for _, item := range items {
+		if item == target {
+			return true
+		}
+	}
+
+	return false
+}
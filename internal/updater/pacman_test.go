@@ -0,0 +1,263 @@
+package updater
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/scottlz0310/devsync/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPacmanUpdater_Configure(t *testing.T) {
+	testCases := []struct {
+		name        string
+		cfg         config.ManagerConfig
+		wantSudo    bool
+		wantHelper  string
+		errContains string
+	}{
+		{name: "既定値", cfg: config.ManagerConfig{}, wantSudo: true},
+		{name: "use_sudo=false", cfg: config.ManagerConfig{"use_sudo": false}, wantSudo: false},
+		{name: "paruを指定", cfg: config.ManagerConfig{"aur_helper": "paru"}, wantSudo: true, wantHelper: "paru"},
+		{name: "未対応のAURヘルパー", cfg: config.ManagerConfig{"aur_helper": "pikaur"}, errContains: "aur_helper の値が不正です"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &PacmanUpdater{useSudo: true}
+
+			err := p.Configure(tc.cfg)
+			if tc.errContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.errContains)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantSudo, p.useSudo)
+			assert.Equal(t, tc.wantHelper, p.aurHelper)
+		})
+	}
+}
+
+func TestParsePacmanUpgrades(t *testing.T) {
+	output := `linux 6.9.1.arch1-1 -> 6.9.2.arch1-1
+git 2.45.0-1 -> 2.45.1-1
+firefox 126.0-1 -> 126.0.1-1 [ignored]
+:: Synchronizing package databases...
+`
+
+	assert.Equal(t, []PackageInfo{
+		{Name: "linux", CurrentVersion: "6.9.1.arch1-1", NewVersion: "6.9.2.arch1-1"},
+		{Name: "git", CurrentVersion: "2.45.0-1", NewVersion: "2.45.1-1"},
+	}, parsePacmanUpgrades(output))
+}
+
+func TestPacmanUpdater_Check(t *testing.T) {
+	testCases := []struct {
+		name         string
+		mode         string
+		checkupdates bool
+		aurHelper    string
+		wantUpdates  int
+		errContains  string
+	}{
+		{name: "pacman -Quで確認", mode: "updates", wantUpdates: 2},
+		{name: "pacman -Quで更新なし", mode: "none", wantUpdates: 0},
+		{name: "checkupdatesを優先", mode: "updates", checkupdates: true, wantUpdates: 1},
+		{name: "checkupdatesで更新なし", mode: "none", checkupdates: true, wantUpdates: 0},
+		{name: "AURヘルパーの更新を含む", mode: "updates", aurHelper: "paru", wantUpdates: 3},
+		{name: "pacman -Qu失敗", mode: "query_error", errContains: "pacman -Qu の実行に失敗"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			writeFakePacmanCommands(t, tc.checkupdates)
+			t.Setenv("DEVSYNC_TEST_PACMAN_MODE", tc.mode)
+
+			p := &PacmanUpdater{aurHelper: tc.aurHelper}
+
+			got, err := p.Check(context.Background())
+			if tc.errContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.errContains)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantUpdates, got.AvailableUpdates)
+		})
+	}
+}
+
+func TestPacmanUpdater_Update(t *testing.T) {
+	t.Run("pacman -Syuで更新", func(t *testing.T) {
+		logPath := writeFakePacmanCommands(t, false)
+		t.Setenv("DEVSYNC_TEST_PACMAN_MODE", "updates")
+
+		got, err := (&PacmanUpdater{}).Update(context.Background(), UpdateOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, 2, got.UpdatedCount)
+		assert.Equal(t, []PackageInfo{
+			{Name: "git", CurrentVersion: "2.45.0-1", NewVersion: "2.45.1-1"},
+			{Name: "linux", CurrentVersion: "6.9.1.arch1-1", NewVersion: "6.9.2.arch1-1"},
+		}, got.Packages)
+
+		data, err := os.ReadFile(logPath)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), "pacman -Syu --noconfirm")
+	})
+
+	t.Run("checkupdatesがなければ同期前のデータベースで更新なしでも-Syuを実行", func(t *testing.T) {
+		logPath := writeFakePacmanCommands(t, false)
+		t.Setenv("DEVSYNC_TEST_PACMAN_MODE", "none")
+
+		got, err := (&PacmanUpdater{}).Update(context.Background(), UpdateOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, 2, got.UpdatedCount)
+
+		data, err := os.ReadFile(logPath)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), "pacman -Syu --noconfirm")
+	})
+
+	t.Run("checkupdatesで更新なしなら実行しない", func(t *testing.T) {
+		logPath := writeFakePacmanCommands(t, true)
+		t.Setenv("DEVSYNC_TEST_PACMAN_MODE", "none")
+
+		got, err := (&PacmanUpdater{}).Update(context.Background(), UpdateOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, 0, got.UpdatedCount)
+		assert.Equal(t, "すべてのパッケージは最新です", got.Message)
+
+		assert.NoFileExists(t, logPath, "pacman を実行しない")
+	})
+
+	t.Run("DryRunは同期せずに確認結果を表示", func(t *testing.T) {
+		logPath := writeFakePacmanCommands(t, false)
+		t.Setenv("DEVSYNC_TEST_PACMAN_MODE", "updates")
+
+		got, err := (&PacmanUpdater{}).Update(context.Background(), UpdateOptions{DryRun: true})
+		require.NoError(t, err)
+		assert.Len(t, got.Packages, 2)
+
+		data, err := os.ReadFile(logPath)
+		require.NoError(t, err)
+		assert.NotContains(t, string(data), "-Syu")
+	})
+
+	t.Run("AURヘルパーで更新", func(t *testing.T) {
+		logPath := writeFakePacmanCommands(t, false)
+		t.Setenv("DEVSYNC_TEST_PACMAN_MODE", "updates")
+
+		got, err := (&PacmanUpdater{useSudo: true, aurHelper: "paru"}).Update(context.Background(), UpdateOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, 3, got.UpdatedCount)
+
+		data, err := os.ReadFile(logPath)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), "paru -Syu --noconfirm")
+	})
+
+	t.Run("更新失敗", func(t *testing.T) {
+		writeFakePacmanCommands(t, false)
+		t.Setenv("DEVSYNC_TEST_PACMAN_MODE", "upgrade_error")
+
+		_, err := (&PacmanUpdater{}).Update(context.Background(), UpdateOptions{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "pacman -Syu に失敗")
+	})
+}
+
+// writeFakePacmanCommands は fake の pacman / paru（必要に応じて checkupdates）を PATH に配置し、実行ログのパスを返します。
+func writeFakePacmanCommands(t *testing.T, withCheckupdates bool) string {
+	t.Helper()
+
+	if runtime.GOOS == windowsOS {
+		t.Skip("fake pacman は POSIX シェル前提")
+	}
+
+	dir := t.TempDir()
+	logPath := filepath.Join(t.TempDir(), "commands.log")
+	t.Setenv("DEVSYNC_TEST_PACMAN_LOG", logPath)
+
+	pacman := `#!/bin/sh
+mode="${DEVSYNC_TEST_PACMAN_MODE}"
+echo "$(basename "$0") $*" >> "${DEVSYNC_TEST_PACMAN_LOG}"
+case "$1" in
+  -Qu)
+    if [ "${mode}" = "query_error" ]; then
+      echo "error: failed to init transaction" 1>&2
+      exit 1
+    fi
+    if [ "${mode}" = "none" ]; then
+      exit 1
+    fi
+    echo "linux 6.9.1.arch1-1 -> 6.9.2.arch1-1"
+    echo "git 2.45.0-1 -> 2.45.1-1"
+    exit 0
+    ;;
+  -Qua)
+    if [ "${mode}" = "none" ]; then
+      exit 1
+    fi
+    echo "visual-studio-code-bin 1.89.0-1 -> 1.89.1-1"
+    exit 0
+    ;;
+  -Q)
+    upgraded="$(cat "${DEVSYNC_TEST_PACMAN_LOG}.upgraded" 2>/dev/null)"
+    if [ -n "${upgraded}" ]; then
+      echo "linux 6.9.2.arch1-1"
+      echo "git 2.45.1-1"
+    else
+      echo "linux 6.9.1.arch1-1"
+      echo "git 2.45.0-1"
+    fi
+    if [ "${upgraded}" = "paru" ]; then
+      echo "visual-studio-code-bin 1.89.1-1"
+    else
+      echo "visual-studio-code-bin 1.89.0-1"
+    fi
+    echo "bash 5.2.026-2"
+    exit 0
+    ;;
+  -Syu)
+    if [ "${mode}" = "upgrade_error" ]; then
+      echo "error: failed to commit transaction" 1>&2
+      exit 1
+    fi
+    basename "$0" > "${DEVSYNC_TEST_PACMAN_LOG}.upgraded"
+    exit 0
+    ;;
+esac
+echo "invalid args" 1>&2
+exit 1
+`
+
+	paru := pacman
+	checkupdates := `#!/bin/sh
+if [ "${DEVSYNC_TEST_PACMAN_MODE}" = "none" ]; then
+  exit 2
+fi
+echo "linux 6.9.1.arch1-1 -> 6.9.2.arch1-1"
+exit 0
+`
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "pacman"), []byte(pacman), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "paru"), []byte(paru), 0o755))
+
+	if withCheckupdates {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "checkupdates"), []byte(checkupdates), 0o755))
+	}
+
+	// 実環境の checkupdates を拾わないよう PATH は fake のディレクトリと基本コマンドのみにする
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+"/usr/bin"+string(os.PathListSeparator)+"/bin")
+
+	return logPath
+}
@@ -0,0 +1,203 @@
+package updater
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/scottlz0310/devsync/internal/config"
+)
+
+// PipUserUpdater は pip の user-site（pip install --user）パッケージの実装です。
+// 更新後に pip check を実行し、依存関係の不整合を UpdateResult.Errors に報告します。
+//
+//	sys:
+//	  managers:
+//	    pip:
+//	      exclude: ["numpy"]             # 更新しないパッケージ
+//	      break_system_packages: true    # PEP 668 の環境（Debian / Ubuntu など）で --break-system-packages を付ける
+type PipUserUpdater struct {
+	exclude             []string
+	breakSystemPackages bool
+}
+
+// pipOutdated は `pip list --outdated --format json` の要素です。
+type pipOutdated struct {
+	Name          string `json:"name"`
+	Version       string `json:"version"`
+	LatestVersion string `json:"latest_version"`
+}
+
+// 起動時にレジストリに登録
+func init() {
+	Register(&PipUserUpdater{})
+}
+
+func (p *PipUserUpdater) Name() string {
+	return "pip"
+}
+
+func (p *PipUserUpdater) DisplayName() string {
+	return "pip (Python user-site パッケージ)"
+}
+
+func (p *PipUserUpdater) IsAvailable() bool {
+	_, err := lookupFirstExecutable("python3", "python")
+	return err == nil
+}
+
+func (p *PipUserUpdater) Configure(cfg config.ManagerConfig) error {
+	if cfg == nil {
+		return nil
+	}
+
+	if exclude, ok := toStringList(cfg["exclude"]); ok {
+		p.exclude = p.exclude[:0]
+
+		for _, name := range exclude {
+			p.exclude = append(p.exclude, pythonPackageName(name))
+		}
+	}
+
+	if value, ok := cfg["break_system_packages"].(bool); ok {
+		p.breakSystemPackages = value
+	}
+
+	return nil
+}
+
+func (p *PipUserUpdater) Check(ctx context.Context) (*CheckResult, error) {
+	packages, err := p.planUpdates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	message := fmt.Sprintf("%d 件のパッケージが更新可能です", len(packages))
+	if len(packages) == 0 {
+		message = "すべてのパッケージは最新です"
+	}
+
+	return &CheckResult{
+		AvailableUpdates: len(packages),
+		Packages:         packages,
+		Message:          message,
+	}, nil
+}
+
+func (p *PipUserUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateResult, error) {
+	result := &UpdateResult{}
+
+	packages, err := p.planUpdates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(packages) == 0 {
+		result.Message = "すべてのパッケージは最新です"
+		return result, nil
+	}
+
+	if opts.DryRun {
+		result.Packages = packages
+		result.Message = fmt.Sprintf("%d 件のパッケージが更新可能です（DryRunモード）", len(packages))
+
+		return result, nil
+	}
+
+	python, err := lookupFirstExecutable("python3", "python")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pkg := range packages {
+		args := []string{"-m", "pip", "install", "--user", "--upgrade"}
+		if p.breakSystemPackages {
+			args = append(args, "--break-system-packages")
+		}
+
+		if err := runPackageCommand(ctx, python, append(args, pkg.Name)...); err != nil {
+			result.FailedCount++
+			result.Errors = append(result.Errors, err)
+
+			continue
+		}
+
+		result.UpdatedCount++
+		result.Packages = append(result.Packages, pkg)
+	}
+
+	// 個別に更新するとパッケージ間の依存関係の制約を壊すことがあるため、整合性を確認する
+	result.Errors = append(result.Errors, pipCheck(ctx, python)...)
+
+	if result.FailedCount > 0 {
+		result.Message = fmt.Sprintf("%d 件更新、%d 件失敗", result.UpdatedCount, result.FailedCount)
+	} else {
+		result.Message = fmt.Sprintf("%d 件のパッケージを更新しました", result.UpdatedCount)
+	}
+
+	return result, nil
+}
+
+// planUpdates は `pip list --user --outdated --format json` から更新可能なパッケージを返します（exclude を除く）。
+func (p *PipUserUpdater) planUpdates(ctx context.Context) ([]PackageInfo, error) {
+	python, err := lookupFirstExecutable("python3", "python")
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := runCommandOutputWithLocaleC(ctx, python,
+		[]string{"-m", "pip", "list", "--user", "--outdated", "--format", "json", "--disable-pip-version-check"},
+		"pip list --outdated の実行に失敗: %w")
+	if err != nil {
+		return nil, err
+	}
+
+	var outdated []pipOutdated
+	if err := json.Unmarshal([]byte(strings.TrimSpace(string(output))), &outdated); err != nil {
+		return nil, fmt.Errorf("pip list の出力解析に失敗: %w", err)
+	}
+
+	packages := make([]PackageInfo, 0, len(outdated))
+
+	for _, pkg := range outdated {
+		if containsString(p.exclude, pythonPackageName(pkg.Name)) {
+			continue
+		}
+
+		packages = append(packages, PackageInfo{Name: pkg.Name, CurrentVersion: pkg.Version, NewVersion: pkg.LatestVersion})
+	}
+
+	return packages, nil
+}
+
+// pipCheck は `pip check` を実行し、報告された不整合を 1 行ずつエラーとして返します。
+func pipCheck(ctx context.Context, python string) []error {
+	cmd := exec.CommandContext(ctx, python, "-m", "pip", "check", "--disable-pip-version-check")
+
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		return nil
+	}
+
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return []error{fmt.Errorf("pip check の実行に失敗: %w", err)}
+	}
+
+	var errs []error
+
+	for _, line := range strings.Split(string(output), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			errs = append(errs, fmt.Errorf("pip check: %s", line))
+		}
+	}
+
+	if len(errs) == 0 {
+		errs = append(errs, fmt.Errorf("pip check に失敗: %w", err))
+	}
+
+	return errs
+}
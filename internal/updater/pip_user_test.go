@@ -0,0 +1,72 @@
+package updater
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/scottlz0310/devsync/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPipUserUpdater_CheckAndUpdate(t *testing.T) {
+	logPath := writeFakePythonCommand(t)
+
+	p := &PipUserUpdater{}
+	require.NoError(t, p.Configure(config.ManagerConfig{
+		"exclude":               []interface{}{"NumPy"},
+		"break_system_packages": true,
+	}))
+
+	checkResult, err := p.Check(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []PackageInfo{
+		{Name: "requests", CurrentVersion: "2.31.0", NewVersion: "2.32.3"},
+		{Name: "urllib3", CurrentVersion: "1.26.18", NewVersion: "2.2.2"},
+	}, checkResult.Packages, "除外したパッケージは対象外")
+
+	result, err := p.Update(context.Background(), UpdateOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.UpdatedCount)
+	require.Len(t, result.Errors, 1, "pip check の不整合はエラーとして報告する")
+	assert.Contains(t, result.Errors[0].Error(), "botocore 1.34.0 has requirement urllib3<1.27")
+
+	data, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	assert.Equal(t, "pip install --user --upgrade --break-system-packages requests\n"+
+		"pip install --user --upgrade --break-system-packages urllib3\n", string(data))
+}
+
+func writeFakePythonCommand(t *testing.T) string {
+	t.Helper()
+
+	if runtime.GOOS == windowsOS {
+		t.Skip("fake python3 は POSIX シェル前提")
+	}
+
+	logPath := filepath.Join(t.TempDir(), "commands.log")
+	t.Setenv("DEVSYNC_TEST_PIP_LOG", logPath)
+
+	script := `#!/bin/sh
+shift 2
+case "$1" in
+  list)
+    echo '[{"name":"numpy","version":"1.26.2","latest_version":"2.0.0","latest_filetype":"wheel"},{"name":"requests","version":"2.31.0","latest_version":"2.32.3","latest_filetype":"wheel"},{"name":"urllib3","version":"1.26.18","latest_version":"2.2.2","latest_filetype":"wheel"}]'
+    ;;
+  check)
+    echo "botocore 1.34.0 has requirement urllib3<1.27,>=1.25.4, but you have urllib3 2.2.2."
+    exit 1
+    ;;
+  *) echo "pip $*" >> "${DEVSYNC_TEST_PIP_LOG}" ;;
+esac
+`
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "python3"), []byte(script), 0o755))
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	return logPath
+}
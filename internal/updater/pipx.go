@@ -11,8 +11,12 @@ import (
 )
 
 // PipxUpdater は pipx (Python CLI ツール) の実装です。
+// allow（許可するバージョンの範囲）を設定した場合は PyPI で最新バージョンを確認し、
+// 範囲内のパッケージのみを `pipx upgrade` で個別に更新します。
 type PipxUpdater struct {
-	// 将来的な拡張のための設定フィールド
+	policy versionPolicy
+	// pypiURL は PyPI のベース URL です（空の場合は既定値）。
+	pypiURL string
 }
 
 // 起動時にレジストリに登録
@@ -33,8 +37,23 @@ func (p *PipxUpdater) IsAvailable() bool {
 	return err == nil
 }
 
+// SupportsVersionPolicy は allow / allow_packages に対応していることを返します。
+func (p *PipxUpdater) SupportsVersionPolicy() bool {
+	return true
+}
+
 func (p *PipxUpdater) Configure(cfg config.ManagerConfig) error {
-	// 現時点では設定項目なし
+	if cfg == nil {
+		return nil
+	}
+
+	policy, err := parseVersionPolicy(p.Name(), cfg)
+	if err != nil {
+		return err
+	}
+
+	p.policy = policy
+
 	return nil
 }
 
@@ -73,6 +92,16 @@ func (p *PipxUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateRe
 		return result, nil
 	}
 
+	if p.policy.restricted() && !opts.AllowMajor {
+		return updateWithRegistryPolicy(ctx, opts, p.policy, checkResult.Packages,
+			func(ctx context.Context, name string) (string, error) {
+				return latestPyPIVersion(ctx, p.pypiURL, name)
+			},
+			func(ctx context.Context, pkg PackageInfo) error {
+				return runPackageCommand(ctx, "pipx", "upgrade", pkg.Name)
+			})
+	}
+
 	if opts.DryRun {
 		result.Message = fmt.Sprintf("%d 件のインストール済みパッケージについて更新を確認します（DryRunモード）", len(checkResult.Packages))
 		result.Packages = checkResult.Packages
@@ -135,3 +164,34 @@ func (p *PipxUpdater) parsePipxListJSON(output []byte) []PackageInfo {
 
 	return packages
 }
+
+var _ PackageInstaller = (*PipxUpdater)(nil)
+
+func (p *PipxUpdater) PackageName(spec string) string {
+	return pythonPackageName(spec)
+}
+
+// InstalledPackages は "pipx list --json" からインストール済みパッケージ名を取得します。
+func (p *PipxUpdater) InstalledPackages(ctx context.Context) ([]string, error) {
+	output, err := runPackageListCommand(ctx, "pipx", "list", "--json")
+	if err != nil {
+		return nil, err
+	}
+
+	packages := p.parsePipxListJSON(output)
+	names := make([]string, 0, len(packages))
+
+	for _, pkg := range packages {
+		names = append(names, pythonPackageName(pkg.Name))
+	}
+
+	return names, nil
+}
+
+func (p *PipxUpdater) InstallPackage(ctx context.Context, spec string) error {
+	return runPackageCommand(ctx, "pipx", "install", spec)
+}
+
+func (p *PipxUpdater) RemovePackage(ctx context.Context, name string) error {
+	return runPackageCommand(ctx, "pipx", "uninstall", name)
+}
@@ -2,12 +2,16 @@ package updater
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"runtime"
 	"testing"
 
+	"github.com/scottlz0310/devsync/internal/config"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestPipxUpdater_parsePipxListJSON(t *testing.T) {
@@ -217,6 +221,7 @@ func writeFakePipxCommand(t *testing.T, dir string) {
 set mode=%DEVSYNC_TEST_PIPX_MODE%
 if "%1"=="list" goto dolist
 if "%1"=="upgrade-all" goto doupgrade
+if "%1"=="upgrade" exit /b 0
 echo invalid args 1>&2
 exit /b 1
 :dolist
@@ -261,6 +266,12 @@ case "$1" in
     fi
     exit 0
     ;;
+  upgrade)
+    if [ -n "${DEVSYNC_TEST_PIPX_LOG}" ]; then
+      echo "$*" >> "${DEVSYNC_TEST_PIPX_LOG}"
+    fi
+    exit 0
+    ;;
   *)
     echo "invalid args" 1>&2
     exit 1
@@ -280,3 +291,46 @@ esac
 		}
 	}
 }
+
+func TestPipxUpdater_UpdateWithVersionPolicy(t *testing.T) {
+	if runtime.GOOS == windowsOS {
+		t.Skip("fake pipx の引数記録は POSIX シェルのみ")
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/pypi/black/json":
+			_, _ = w.Write([]byte(`{"info":{"version":"25.1.0"}}`))
+		case "/pypi/ruff/json":
+			_, _ = w.Write([]byte(`{"info":{"version":"0.2.2"}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	fakeDir := t.TempDir()
+	writeFakePipxCommand(t, fakeDir)
+
+	logPath := filepath.Join(t.TempDir(), "pipx.log")
+
+	t.Setenv("PATH", fakeDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("DEVSYNC_TEST_PIPX_MODE", "updates")
+	t.Setenv("DEVSYNC_TEST_PIPX_LOG", logPath)
+
+	p := &PipxUpdater{pypiURL: server.URL}
+	require.NoError(t, p.Configure(config.ManagerConfig{"allow": "minor"}))
+
+	got, err := p.Update(context.Background(), UpdateOptions{DryRun: true})
+	require.NoError(t, err)
+	assert.Equal(t, []PackageInfo{{Name: "ruff", CurrentVersion: "0.2.0", NewVersion: "0.2.2"}}, got.Packages)
+	assert.Equal(t, []PackageInfo{{Name: "black", CurrentVersion: "24.1.0", NewVersion: "25.1.0"}}, got.Held)
+
+	got, err = p.Update(context.Background(), UpdateOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, got.UpdatedCount)
+
+	logged, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	assert.Equal(t, "upgrade ruff\n", string(logged))
+}
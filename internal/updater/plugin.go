@@ -0,0 +1,419 @@
+package updater
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/scottlz0310/devsync/internal/config"
+)
+
+const (
+	// PluginPrefix は PATH から探索する外部プラグインの実行ファイル名の接頭辞です。
+	// 例: devsync-updater-company は "company" マネージャとして登録されます。
+	PluginPrefix = "devsync-updater-"
+	// PluginProtocolVersion は外部プラグインとの JSON プロトコルのバージョンです。
+	PluginProtocolVersion = 1
+
+	// pluginConfigKey は sys.managers.<name> でプラグインの実行ファイルを指定するキーです。
+	pluginConfigKey = "plugin"
+	// pluginProbeTimeout は name / is_available 問い合わせのタイムアウトです。
+	pluginProbeTimeout = 10 * time.Second
+)
+
+// プラグインプロトコルのコマンド
+const (
+	pluginCommandName        = "name"
+	pluginCommandIsAvailable = "is_available"
+	pluginCommandCheck       = "check"
+	pluginCommandUpdate      = "update"
+)
+
+// pluginRequest はプラグインの標準入力に渡す JSON です。
+type pluginRequest struct {
+	Protocol int                    `json:"protocol"`
+	Command  string                 `json:"command"`
+	Options  *pluginOptions         `json:"options,omitempty"`
+	Config   map[string]interface{} `json:"config,omitempty"`
+}
+
+type pluginOptions struct {
+	DryRun  bool `json:"dry_run"`
+	Verbose bool `json:"verbose"`
+}
+
+type pluginPackage struct {
+	Name           string `json:"name"`
+	CurrentVersion string `json:"current_version"`
+	NewVersion     string `json:"new_version"`
+	Security       bool   `json:"security,omitempty"`
+}
+
+// pluginResponse はプラグインが標準出力に返す JSON です。
+// コマンドごとに使用するフィールドが異なり、error が空でない場合は失敗として扱います。
+type pluginResponse struct {
+	Error            string          `json:"error"`
+	Name             string          `json:"name"`
+	DisplayName      string          `json:"display_name"`
+	Available        *bool           `json:"available"`
+	AvailableUpdates int             `json:"available_updates"`
+	UpdatedCount     int             `json:"updated_count"`
+	FailedCount      int             `json:"failed_count"`
+	Packages         []pluginPackage `json:"packages"`
+	Errors           []string        `json:"errors"`
+	Message          string          `json:"message"`
+}
+
+// PluginUpdater は外部実行ファイル（devsync-updater-<name>）を Updater として扱うアダプタです。
+// リクエストごとにプロセスを起動し、標準入力に JSON リクエストを渡して標準出力の JSON 応答を読み取ります。
+// プラグインの標準エラー出力は進捗表示としてそのまま端末に流します（update 時）。
+type PluginUpdater struct {
+	name string
+	path string
+
+	mu     sync.Mutex
+	config map[string]interface{}
+
+	infoOnce    sync.Once
+	displayName string
+
+	availableOnce sync.Once
+	available     bool
+}
+
+// NewPluginUpdater は実行ファイル path をプラグインとして扱う Updater を作成します。
+func NewPluginUpdater(name, path string) *PluginUpdater {
+	return &PluginUpdater{name: name, path: path}
+}
+
+func (p *PluginUpdater) Name() string {
+	return p.name
+}
+
+// Path はプラグインの実行ファイルのパスを返します。
+func (p *PluginUpdater) Path() string {
+	return p.path
+}
+
+// DisplayName はプラグインの name 応答の display_name を返します（取得できない場合は既定の表示名）。
+func (p *PluginUpdater) DisplayName() string {
+	p.infoOnce.Do(func() {
+		p.displayName = fmt.Sprintf("%s (プラグイン)", p.name)
+
+		ctx, cancel := context.WithTimeout(context.Background(), pluginProbeTimeout)
+		defer cancel()
+
+		resp, err := p.call(ctx, pluginCommandName, nil)
+		if err == nil && strings.TrimSpace(resp.DisplayName) != "" {
+			p.displayName = strings.TrimSpace(resp.DisplayName)
+		}
+	})
+
+	return p.displayName
+}
+
+// IsAvailable は実行ファイルが存在し、is_available 応答が false でない場合に true を返します。
+func (p *PluginUpdater) IsAvailable() bool {
+	p.availableOnce.Do(func() {
+		if !isExecutableFile(p.path) {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), pluginProbeTimeout)
+		defer cancel()
+
+		resp, err := p.call(ctx, pluginCommandIsAvailable, nil)
+		if err != nil {
+			return
+		}
+
+		p.available = resp.Available == nil || *resp.Available
+	})
+
+	return p.available
+}
+
+// Configure は plugin キーを除いたマネージャ設定を、リクエストの config としてプラグインに渡します。
+func (p *PluginUpdater) Configure(cfg config.ManagerConfig) error {
+	forwarded := make(map[string]interface{}, len(cfg))
+
+	for key, value := range cfg {
+		if key == pluginConfigKey {
+			continue
+		}
+
+		forwarded[key] = value
+	}
+
+	p.mu.Lock()
+	p.config = forwarded
+	p.mu.Unlock()
+
+	return nil
+}
+
+func (p *PluginUpdater) Check(ctx context.Context) (*CheckResult, error) {
+	resp, err := p.call(ctx, pluginCommandCheck, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	packages := pluginPackagesToInfo(resp.Packages)
+
+	available := resp.AvailableUpdates
+	if available == 0 {
+		available = len(packages)
+	}
+
+	return &CheckResult{
+		AvailableUpdates: available,
+		Packages:         packages,
+		Message:          resp.Message,
+	}, nil
+}
+
+func (p *PluginUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateResult, error) {
+	resp, err := p.call(ctx, pluginCommandUpdate, &pluginOptions{DryRun: opts.DryRun, Verbose: opts.Verbose})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &UpdateResult{
+		UpdatedCount: resp.UpdatedCount,
+		FailedCount:  resp.FailedCount,
+		Packages:     pluginPackagesToInfo(resp.Packages),
+		Message:      resp.Message,
+	}
+
+	for _, message := range resp.Errors {
+		result.Errors = append(result.Errors, fmt.Errorf("%s: %s", p.name, message))
+	}
+
+	return result, nil
+}
+
+// call はプラグインを起動して1件のリクエストを送り、応答を返します。
+func (p *PluginUpdater) call(ctx context.Context, command string, opts *pluginOptions) (*pluginResponse, error) {
+	p.mu.Lock()
+	request := pluginRequest{
+		Protocol: PluginProtocolVersion,
+		Command:  command,
+		Options:  opts,
+		Config:   p.config,
+	}
+	payload, err := json.Marshal(request)
+	p.mu.Unlock()
+
+	if err != nil {
+		return nil, fmt.Errorf("プラグイン %s へのリクエスト作成に失敗: %w", p.name, err)
+	}
+
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, p.path)
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	// update は時間がかかるため、プラグインの進捗（stderr）を端末にも表示する
+	if command == pluginCommandUpdate {
+		cmd.Stderr = io.MultiWriter(os.Stderr, &stderr)
+	}
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf(
+			"プラグイン %s の %s に失敗: %w",
+			p.name,
+			command,
+			buildCommandOutputErr(err, combineCommandOutputs(stdout.Bytes(), stderr.Bytes())),
+		)
+	}
+
+	var resp pluginResponse
+	if err := json.Unmarshal(bytes.TrimSpace(stdout.Bytes()), &resp); err != nil {
+		return nil, fmt.Errorf("プラグイン %s の %s 応答の解析に失敗: %w", p.name, command, err)
+	}
+
+	if resp.Error != "" {
+		return nil, fmt.Errorf("プラグイン %s の %s に失敗: %s", p.name, command, resp.Error)
+	}
+
+	return &resp, nil
+}
+
+func pluginPackagesToInfo(packages []pluginPackage) []PackageInfo {
+	if len(packages) == 0 {
+		return nil
+	}
+
+	result := make([]PackageInfo, 0, len(packages))
+	for _, pkg := range packages {
+		result = append(result, PackageInfo{
+			Name:           pkg.Name,
+			CurrentVersion: pkg.CurrentVersion,
+			NewVersion:     pkg.NewVersion,
+			Security:       pkg.Security,
+		})
+	}
+
+	return result
+}
+
+// DiscoverPlugins は pathEnv（PATH 形式）から devsync-updater-<name> 実行ファイルを探索し、
+// マネージャ名と実行ファイルのパスの対応を返します。同名の場合は PATH の先頭側を優先します。
+func DiscoverPlugins(pathEnv string) map[string]string {
+	plugins := make(map[string]string)
+
+	for _, dir := range filepath.SplitList(pathEnv) {
+		if dir == "" {
+			continue
+		}
+
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			name, ok := pluginNameFromFile(entry.Name())
+			if !ok || entry.IsDir() {
+				continue
+			}
+
+			if _, exists := plugins[name]; exists {
+				continue
+			}
+
+			path := filepath.Join(dir, entry.Name())
+			if isExecutableFile(path) {
+				plugins[name] = path
+			}
+		}
+	}
+
+	return plugins
+}
+
+// pluginNameFromFile は実行ファイル名からマネージャ名を取り出します。
+// Windows では .exe / .cmd / .bat 拡張子を除去します。
+func pluginNameFromFile(fileName string) (string, bool) {
+	name, ok := strings.CutPrefix(fileName, PluginPrefix)
+	if !ok {
+		return "", false
+	}
+
+	if runtime.GOOS == windowsOS {
+		ext := strings.ToLower(filepath.Ext(name))
+		if ext != ".exe" && ext != ".cmd" && ext != ".bat" {
+			return "", false
+		}
+
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+
+	if name == "" || strings.ContainsAny(name, " .") {
+		return "", false
+	}
+
+	return name, true
+}
+
+func isExecutableFile(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return false
+	}
+
+	if runtime.GOOS == windowsOS {
+		return true
+	}
+
+	return info.Mode()&0o111 != 0
+}
+
+// RegisterExternal は PATH 上のプラグイン、sys.managers.<name>.plugin で宣言されたプラグイン、
+// および update_command で定義されたカスタムマネージャをレジストリに登録します。
+// 組み込みマネージャと同名の定義は登録せず、警告としてエラーを返します（他の登録は継続します）。
+func RegisterExternal(cfg *config.SysConfig) error {
+	plugins := DiscoverPlugins(os.Getenv("PATH"))
+
+	var problems []string
+
+	if cfg != nil {
+		for name, managerCfg := range cfg.Managers {
+			path, ok := managerCfg[pluginConfigKey].(string)
+			if !ok || strings.TrimSpace(path) == "" {
+				continue
+			}
+
+			expanded, err := expandHomePath(strings.TrimSpace(path))
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %v", name, err))
+				continue
+			}
+
+			plugins[name] = expanded
+		}
+	}
+
+	for _, name := range sortedManagerNames(plugins) {
+		if existing, ok := Get(name); ok {
+			plugin, isPlugin := existing.(*PluginUpdater)
+			if !isPlugin {
+				problems = append(problems, fmt.Sprintf("%s: 組み込みマネージャと同名のため無視しました（%s）", name, plugins[name]))
+				continue
+			}
+
+			// 登録済みの同じプラグインは問い合わせ結果のキャッシュを保つため差し替えない
+			if plugin.Path() == plugins[name] {
+				continue
+			}
+		}
+
+		Register(NewPluginUpdater(name, plugins[name]))
+	}
+
+	problems = append(problems, registerCustomCommands(cfg, plugins)...)
+
+	if len(problems) > 0 {
+		return fmt.Errorf("外部マネージャの登録で問題がありました: %s", strings.Join(problems, "; "))
+	}
+
+	return nil
+}
+
+// sortedManagerNames はマップのキーを名前順に返します。
+func sortedManagerNames[V any](items map[string]V) []string {
+	names := make([]string, 0, len(items))
+	for name := range items {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// expandHomePath は先頭の ~ をホームディレクトリに展開します。
+func expandHomePath(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("ホームディレクトリの取得に失敗: %w", err)
+	}
+
+	return filepath.Join(home, strings.TrimPrefix(path, "~")), nil
+}
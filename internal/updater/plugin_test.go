@@ -0,0 +1,215 @@
+package updater
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/scottlz0310/devsync/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeFakePlugin はテスト用の外部プラグインを作成します。
+// 環境変数 DEVSYNC_TEST_PLUGIN_MODE で応答を切り替え、受け取ったリクエストを DEVSYNC_TEST_PLUGIN_LOG に書き出します。
+func writeFakePlugin(t *testing.T, dir, name string) string {
+	t.Helper()
+
+	if runtime.GOOS == windowsOS {
+		t.Skip("fake プラグインは POSIX シェル前提")
+	}
+
+	script := `#!/bin/sh
+request=$(cat)
+mode="${DEVSYNC_TEST_PLUGIN_MODE}"
+if [ -n "${DEVSYNC_TEST_PLUGIN_LOG}" ]; then
+  printf '%s' "${request}" > "${DEVSYNC_TEST_PLUGIN_LOG}"
+fi
+if [ "${mode}" = "crash" ]; then
+  echo "plugin crashed" 1>&2
+  exit 3
+fi
+if [ "${mode}" = "invalid" ]; then
+  echo "not json"
+  exit 0
+fi
+case "${request}" in
+  *'"command":"name"'*)
+    echo '{"name":"company","display_name":"Company CLI"}'
+    ;;
+  *'"command":"is_available"'*)
+    if [ "${mode}" = "unavailable" ]; then
+      echo '{"available":false}'
+    else
+      echo '{"available":true}'
+    fi
+    ;;
+  *'"command":"check"'*)
+    if [ "${mode}" = "error" ]; then
+      echo '{"error":"registry unreachable"}'
+      exit 0
+    fi
+    echo '{"packages":[{"name":"company-cli","current_version":"1.0.0","new_version":"1.2.0"}],"message":"1 update"}'
+    ;;
+  *'"command":"update"'*)
+    case "${request}" in
+      *'"dry_run":true'*)
+        echo '{"message":"dry run"}'
+        ;;
+      *)
+        echo "updating company-cli" 1>&2
+        echo '{"updated_count":1,"failed_count":1,"errors":["dotfiles: conflict"],"message":"done"}'
+        ;;
+    esac
+    ;;
+  *)
+    echo "unknown command" 1>&2
+    exit 1
+    ;;
+esac
+`
+
+	path := filepath.Join(dir, PluginPrefix+name)
+	require.NoError(t, os.WriteFile(path, []byte(script), 0o755))
+
+	return path
+}
+
+func TestPluginUpdater(t *testing.T) {
+	path := writeFakePlugin(t, t.TempDir(), "company")
+	logPath := filepath.Join(t.TempDir(), "request.json")
+
+	t.Setenv("DEVSYNC_TEST_PLUGIN_MODE", "")
+	t.Setenv("DEVSYNC_TEST_PLUGIN_LOG", logPath)
+
+	p := NewPluginUpdater("company", path)
+	require.NoError(t, p.Configure(config.ManagerConfig{"plugin": path, "channel": "stable"}))
+
+	assert.Equal(t, "company", p.Name())
+	assert.Equal(t, "Company CLI", p.DisplayName())
+	assert.True(t, p.IsAvailable())
+
+	checkResult, err := p.Check(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, checkResult.AvailableUpdates)
+	assert.Equal(t, []PackageInfo{{Name: "company-cli", CurrentVersion: "1.0.0", NewVersion: "1.2.0"}}, checkResult.Packages)
+	assert.Equal(t, "1 update", checkResult.Message)
+
+	var request pluginRequest
+
+	data, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &request))
+	assert.Equal(t, PluginProtocolVersion, request.Protocol)
+	assert.Equal(t, "check", request.Command)
+	assert.Equal(t, map[string]interface{}{"channel": "stable"}, request.Config, "plugin キーは転送しない")
+
+	dryRun, err := p.Update(context.Background(), UpdateOptions{DryRun: true})
+	require.NoError(t, err)
+	assert.Equal(t, "dry run", dryRun.Message)
+
+	updateResult, err := p.Update(context.Background(), UpdateOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, updateResult.UpdatedCount)
+	assert.Equal(t, 1, updateResult.FailedCount)
+	require.Len(t, updateResult.Errors, 1)
+	assert.Contains(t, updateResult.Errors[0].Error(), "dotfiles: conflict")
+}
+
+func TestPluginUpdater_Failures(t *testing.T) {
+	path := writeFakePlugin(t, t.TempDir(), "company")
+
+	testCases := []struct {
+		name        string
+		mode        string
+		errContains string
+	}{
+		{name: "error応答", mode: "error", errContains: "registry unreachable"},
+		{name: "異常終了", mode: "crash", errContains: "plugin crashed"},
+		{name: "不正なJSON", mode: "invalid", errContains: "応答の解析に失敗"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("DEVSYNC_TEST_PLUGIN_MODE", tc.mode)
+
+			_, err := NewPluginUpdater("company", path).Check(context.Background())
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.errContains)
+		})
+	}
+
+	t.Run("is_availableでfalse", func(t *testing.T) {
+		t.Setenv("DEVSYNC_TEST_PLUGIN_MODE", "unavailable")
+		assert.False(t, NewPluginUpdater("company", path).IsAvailable())
+	})
+
+	t.Run("実行ファイルが存在しない", func(t *testing.T) {
+		p := NewPluginUpdater("missing", filepath.Join(t.TempDir(), "missing"))
+		assert.False(t, p.IsAvailable())
+		assert.Equal(t, "missing (プラグイン)", p.DisplayName())
+	})
+}
+
+func TestDiscoverPlugins(t *testing.T) {
+	first := t.TempDir()
+	second := t.TempDir()
+
+	firstPath := writeFakePlugin(t, first, "company")
+	writeFakePlugin(t, second, "company")
+	dotfilesPath := writeFakePlugin(t, second, "dotfiles")
+
+	require.NoError(t, os.WriteFile(filepath.Join(second, PluginPrefix+"noexec"), []byte("#!/bin/sh"), 0o644))
+	require.NoError(t, os.Mkdir(filepath.Join(second, PluginPrefix+"dir"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(second, "other-tool"), []byte("#!/bin/sh"), 0o755))
+
+	plugins := DiscoverPlugins(first + string(os.PathListSeparator) + second)
+
+	assert.Equal(t, map[string]string{
+		"company":  firstPath,
+		"dotfiles": dotfilesPath,
+	}, plugins)
+}
+
+func TestRegisterExternal(t *testing.T) {
+	clearRegistry()
+	t.Cleanup(clearRegistry)
+
+	pathDir := t.TempDir()
+	writeFakePlugin(t, pathDir, "company")
+	declared := writeFakePlugin(t, t.TempDir(), "internal")
+
+	t.Setenv("PATH", pathDir)
+	Register(&mockUpdater{name: "apt", displayName: "APT", available: true})
+
+	cfg := &config.SysConfig{
+		Managers: map[string]config.ManagerConfig{
+			"internal": {"plugin": declared},
+			"apt":      {"plugin": declared},
+		},
+	}
+
+	err := RegisterExternal(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "apt: 組み込みマネージャと同名のため無視しました")
+
+	company, ok := Get("company")
+	require.True(t, ok)
+	assert.IsType(t, &PluginUpdater{}, company)
+
+	internal, ok := Get("internal")
+	require.True(t, ok)
+	assert.Equal(t, declared, internal.(*PluginUpdater).Path())
+
+	apt, ok := Get("apt")
+	require.True(t, ok)
+	assert.IsType(t, &mockUpdater{}, apt)
+
+	// 同じプラグインの再登録ではインスタンスを差し替えない
+	_ = RegisterExternal(cfg)
+	again, _ := Get("company")
+	assert.Same(t, company, again)
+}
@@ -325,3 +325,54 @@ func (p *PnpmUpdater) parseOutdatedMapJSON(output []byte) ([]PackageInfo, error)
 
 	return packages, nil
 }
+
+var _ PackageInstaller = (*PnpmUpdater)(nil)
+
+func (p *PnpmUpdater) PackageName(spec string) string {
+	return stripPackageVersion(spec)
+}
+
+// InstalledPackages は "pnpm ls -g --depth=0 --json" からグローバルパッケージ名を取得します。
+func (p *PnpmUpdater) InstalledPackages(ctx context.Context) ([]string, error) {
+	output, err := runPackageListCommand(ctx, "pnpm", "ls", "-g", "--depth=0", "--json")
+	if err != nil {
+		return nil, err
+	}
+
+	return p.parseListJSON(output)
+}
+
+func (p *PnpmUpdater) InstallPackage(ctx context.Context, spec string) error {
+	return runPackageCommand(ctx, "pnpm", "add", "-g", spec)
+}
+
+func (p *PnpmUpdater) RemovePackage(ctx context.Context, name string) error {
+	return runPackageCommand(ctx, "pnpm", "remove", "-g", name)
+}
+
+// parseListJSON は "pnpm ls -g --depth=0 --json" の出力をパースします
+// JSON 形式: [ { "dependencies": { "package-name": { "version": "1.0.0" } } } ]
+// グローバルパッケージが未インストールの場合は空配列または空出力になります。
+func (p *PnpmUpdater) parseListJSON(output []byte) ([]string, error) {
+	if len(bytes.TrimSpace(output)) == 0 {
+		return nil, nil
+	}
+
+	var importers []struct {
+		Dependencies map[string]json.RawMessage `json:"dependencies"`
+	}
+
+	if err := json.Unmarshal(output, &importers); err != nil {
+		return nil, fmt.Errorf("pnpm ls の出力の解析に失敗: %w", err)
+	}
+
+	names := make([]string, 0)
+
+	for _, importer := range importers {
+		for name := range importer.Dependencies {
+			names = append(names, name)
+		}
+	}
+
+	return names, nil
+}
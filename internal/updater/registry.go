@@ -0,0 +1,118 @@
+package updater
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	defaultPyPIURL = "https://pypi.org"
+
+	// registryUserAgent は crates.io の利用規約で必須の User-Agent です。
+	registryUserAgent = "devsync (https://github.com/scottlz0310/devsync)"
+)
+
+// registryHTTPClient はパッケージレジストリ・GitHub API への問い合わせに使用する HTTP クライアントです。
+var registryHTTPClient = &http.Client{Timeout: 30 * time.Second}
+
+// errRegistryPackageNotFound は問い合わせ先にパッケージが存在しない（404）ことを表します。
+var errRegistryPackageNotFound = errors.New("パッケージが見つかりません")
+
+// latestCrateVersion は crates.io の最新の安定版（max_stable_version）を返します。
+func latestCrateVersion(ctx context.Context, baseURL, name string) (string, error) {
+	if baseURL == "" {
+		baseURL = defaultCratesIOURL
+	}
+
+	var response struct {
+		Crate struct {
+			MaxStableVersion string `json:"max_stable_version"`
+			MaxVersion       string `json:"max_version"`
+		} `json:"crate"`
+	}
+
+	endpoint := strings.TrimRight(baseURL, "/") + "/api/v1/crates/" + url.PathEscape(name)
+	if err := fetchRegistryJSON(ctx, endpoint, nil, &response); err != nil {
+		return "", err
+	}
+
+	if response.Crate.MaxStableVersion != "" {
+		return response.Crate.MaxStableVersion, nil
+	}
+
+	if response.Crate.MaxVersion == "" {
+		return "", fmt.Errorf("crates.io の応答に %s のバージョンがありません", name)
+	}
+
+	return response.Crate.MaxVersion, nil
+}
+
+// latestPyPIVersion は PyPI の最新版（JSON API の info.version）を返します。
+func latestPyPIVersion(ctx context.Context, baseURL, name string) (string, error) {
+	if baseURL == "" {
+		baseURL = defaultPyPIURL
+	}
+
+	var response struct {
+		Info struct {
+			Version string `json:"version"`
+		} `json:"info"`
+	}
+
+	endpoint := strings.TrimRight(baseURL, "/") + "/pypi/" + url.PathEscape(pythonPackageName(name)) + "/json"
+	if err := fetchRegistryJSON(ctx, endpoint, nil, &response); err != nil {
+		return "", err
+	}
+
+	if response.Info.Version == "" {
+		return "", fmt.Errorf("PyPI の応答に %s のバージョンがありません", name)
+	}
+
+	return response.Info.Version, nil
+}
+
+// fetchRegistryJSON は endpoint を GET し、JSON の応答を out に読み込みます。
+func fetchRegistryJSON(ctx context.Context, endpoint string, headers map[string]string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, http.NoBody)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("User-Agent", registryUserAgent)
+
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := registryHTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("問い合わせに失敗: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return errRegistryPackageNotFound
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s が %s を返しました", req.URL.Host, resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("%s の応答の解析に失敗: %w", req.URL.Host, err)
+	}
+
+	return nil
+}
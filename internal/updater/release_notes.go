@@ -0,0 +1,374 @@
+package updater
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+)
+
+const (
+	defaultNpmRegistryURL = "https://registry.npmjs.org"
+	defaultGitHubAPIURL   = "https://api.github.com"
+	defaultCratesIOURL    = "https://crates.io"
+	defaultHomebrewAPIURL = "https://formulae.brew.sh"
+
+	// maxReleaseNotes は 1 パッケージあたりに返すリリースの上限（新しい順）です。
+	maxReleaseNotes = 10
+)
+
+// errReleaseNotesUnsupported はマネージャがリリースノートの取得に対応していないことを表します。
+var errReleaseNotesUnsupported = errors.New("リリースノートの取得に対応していません")
+
+// ReleaseNote は 1 つのリリースの変更内容です。
+type ReleaseNote struct {
+	Version   string // リリースのバージョン（タグ名）
+	Title     string // リリース名（タグ名と同じ場合は空）
+	Body      string // リリースノート本文（Markdown）
+	URL       string // リリースのページ
+	Published string // 公開日（YYYY-MM-DD、分かる場合のみ）
+}
+
+// ReleaseNotes は現在のバージョンより新しく、更新先のバージョン以下のリリースの一覧です。
+type ReleaseNotes struct {
+	// Repository はリリースの一覧ページ（GitHub のリポジトリが分かる場合のみ）です。
+	Repository string
+	// Notes は新しい順のリリースです。
+	Notes []ReleaseNote
+}
+
+// ReleaseNotesFetcher はパッケージの更新に含まれるリリースノートを取得します。
+// 問い合わせ先のベース URL はフィールドで差し替えられます（社内ミラーやテストのフィクスチャなど）。
+//
+//   - npm / pnpm: npm レジストリのメタデータからリポジトリを求め、GitHub Releases を参照します。
+//     GitHub のリリースがない場合はレジストリの公開日時からバージョンの一覧を返します。
+//   - go: モジュールパスが github.com/<owner>/<repo> の場合に GitHub Releases を参照します。
+//   - cargo: crates.io のメタデータからリポジトリを求め、GitHub Releases を参照します。
+//   - brew: Homebrew の formula 情報（ソース URL / homepage）からリポジトリを求め、GitHub Releases を参照します。
+type ReleaseNotesFetcher struct {
+	NpmRegistryURL string
+	GitHubAPIURL   string
+	CratesIOURL    string
+	HomebrewAPIURL string
+	// GitHubToken は GitHub API の認証トークンです（未認証の場合はレート制限が厳しくなります）。
+	GitHubToken string
+}
+
+// NewReleaseNotesFetcher は既定の問い合わせ先で ReleaseNotesFetcher を作成します。
+// GitHub のトークンは GITHUB_TOKEN / GH_TOKEN 環境変数から読み込みます。
+func NewReleaseNotesFetcher() *ReleaseNotesFetcher {
+	token := os.Getenv("GITHUB_TOKEN")
+	if token == "" {
+		token = os.Getenv("GH_TOKEN")
+	}
+
+	return &ReleaseNotesFetcher{
+		NpmRegistryURL: defaultNpmRegistryURL,
+		GitHubAPIURL:   defaultGitHubAPIURL,
+		CratesIOURL:    defaultCratesIOURL,
+		HomebrewAPIURL: defaultHomebrewAPIURL,
+		GitHubToken:    token,
+	}
+}
+
+// SupportsReleaseNotes は manager のパッケージのリリースノートを取得できるかを返します。
+func SupportsReleaseNotes(manager string) bool {
+	switch manager {
+	case "npm", "pnpm", "go", "cargo", "brew":
+		return true
+	default:
+		return false
+	}
+}
+
+// Fetch は manager のパッケージ pkg を現在のバージョンから新しいバージョンへ更新する際のリリースノートを返します。
+func (f *ReleaseNotesFetcher) Fetch(ctx context.Context, manager string, pkg PackageInfo) (*ReleaseNotes, error) {
+	if pkg.CurrentVersion == "" || pkg.NewVersion == "" {
+		return nil, fmt.Errorf("%s: バージョンが不明なためリリースノートを取得できません", pkg.Name)
+	}
+
+	var (
+		repository string
+		err        error
+	)
+
+	switch manager {
+	case "npm", "pnpm":
+		return f.npmReleaseNotes(ctx, pkg)
+	case "go":
+		module := pkg.Name
+		if pkg.Source != "" {
+			module = pkg.Source
+		}
+
+		repository, _ = parseGitHubRepository(module)
+	case "cargo":
+		repository, err = f.crateRepository(ctx, pkg.Name)
+	case "brew":
+		repository, err = f.homebrewRepository(ctx, pkg.Name)
+	default:
+		return nil, fmt.Errorf("%s: %w", manager, errReleaseNotesUnsupported)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", pkg.Name, err)
+	}
+
+	if repository == "" {
+		return &ReleaseNotes{}, nil
+	}
+
+	return f.githubReleaseNotes(ctx, repository, pkg)
+}
+
+// npmPackument は npm レジストリのパッケージメタデータです（使用するフィールドのみ）。
+type npmPackument struct {
+	Repository json.RawMessage   `json:"repository"`
+	Time       map[string]string `json:"time"`
+}
+
+func (f *ReleaseNotesFetcher) npmReleaseNotes(ctx context.Context, pkg PackageInfo) (*ReleaseNotes, error) {
+	// スコープ付きパッケージ（@scope/name）は "/" をエスケープする
+	endpoint := strings.TrimRight(f.NpmRegistryURL, "/") + "/" + strings.Replace(pkg.Name, "/", "%2F", 1)
+
+	var packument npmPackument
+	if err := fetchRegistryJSON(ctx, endpoint, nil, &packument); err != nil {
+		return nil, fmt.Errorf("%s: %w", pkg.Name, err)
+	}
+
+	if repository, ok := parseGitHubRepository(npmRepositoryURL(packument.Repository)); ok {
+		notes, err := f.githubReleaseNotes(ctx, repository, pkg)
+		if err == nil && len(notes.Notes) > 0 {
+			return notes, nil
+		}
+	}
+
+	return &ReleaseNotes{Notes: npmVersionNotes(packument.Time, pkg)}, nil
+}
+
+// npmRepositoryURL は repository フィールド（文字列または {type, url}）から URL を取り出します。
+func npmRepositoryURL(raw json.RawMessage) string {
+	var value string
+	if err := json.Unmarshal(raw, &value); err == nil {
+		return value
+	}
+
+	var object struct {
+		URL string `json:"url"`
+	}
+
+	if err := json.Unmarshal(raw, &object); err == nil {
+		return object.URL
+	}
+
+	return ""
+}
+
+// npmVersionNotes はレジストリの公開日時から、範囲内の安定版を本文なしのリリースとして返します。
+func npmVersionNotes(published map[string]string, pkg PackageInfo) []ReleaseNote {
+	notes := make([]ReleaseNote, 0)
+
+	for version, timestamp := range published {
+		// time には "created" / "modified" も含まれる。プレリリース（例: 2.0.0-beta.1）は除外する
+		if version == "created" || version == "modified" || strings.Contains(version, "-") || !releaseInRange(version, pkg) {
+			continue
+		}
+
+		notes = append(notes, ReleaseNote{Version: version, Published: releaseDate(timestamp)})
+	}
+
+	return sortReleaseNotes(notes)
+}
+
+func (f *ReleaseNotesFetcher) crateRepository(ctx context.Context, name string) (string, error) {
+	endpoint := strings.TrimRight(f.CratesIOURL, "/") + "/api/v1/crates/" + url.PathEscape(name)
+
+	var response struct {
+		Crate struct {
+			Repository string `json:"repository"`
+			Homepage   string `json:"homepage"`
+		} `json:"crate"`
+	}
+
+	if err := fetchRegistryJSON(ctx, endpoint, nil, &response); err != nil {
+		return "", err
+	}
+
+	return firstGitHubRepository(response.Crate.Repository, response.Crate.Homepage), nil
+}
+
+func (f *ReleaseNotesFetcher) homebrewRepository(ctx context.Context, name string) (string, error) {
+	endpoint := strings.TrimRight(f.HomebrewAPIURL, "/") + "/api/formula/" + url.PathEscape(name) + ".json"
+
+	var formula struct {
+		Homepage string `json:"homepage"`
+		URLs     struct {
+			Stable struct {
+				URL string `json:"url"`
+			} `json:"stable"`
+			Head struct {
+				URL string `json:"url"`
+			} `json:"head"`
+		} `json:"urls"`
+	}
+
+	if err := fetchRegistryJSON(ctx, endpoint, nil, &formula); err != nil {
+		return "", err
+	}
+
+	return firstGitHubRepository(formula.URLs.Stable.URL, formula.URLs.Head.URL, formula.Homepage), nil
+}
+
+// githubRelease は GitHub Releases API の応答です（使用するフィールドのみ）。
+type githubRelease struct {
+	TagName     string `json:"tag_name"`
+	Name        string `json:"name"`
+	Body        string `json:"body"`
+	HTMLURL     string `json:"html_url"`
+	Draft       bool   `json:"draft"`
+	Prerelease  bool   `json:"prerelease"`
+	PublishedAt string `json:"published_at"`
+}
+
+// githubReleaseNotes は repository（owner/repo）のリリースのうち、pkg の更新範囲に含まれるものを返します。
+func (f *ReleaseNotesFetcher) githubReleaseNotes(ctx context.Context, repository string, pkg PackageInfo) (*ReleaseNotes, error) {
+	endpoint := strings.TrimRight(f.GitHubAPIURL, "/") + "/repos/" + repository + "/releases?per_page=100"
+
+	headers := map[string]string{"Accept": "application/vnd.github+json"}
+	if f.GitHubToken != "" {
+		headers["Authorization"] = "Bearer " + f.GitHubToken
+	}
+
+	var releases []githubRelease
+	if err := fetchRegistryJSON(ctx, endpoint, headers, &releases); err != nil {
+		return nil, fmt.Errorf("%s の GitHub Releases: %w", repository, err)
+	}
+
+	notes := make([]ReleaseNote, 0)
+
+	for _, release := range releases {
+		if release.Draft || release.Prerelease || !releaseInRange(release.TagName, pkg) {
+			continue
+		}
+
+		note := ReleaseNote{
+			Version:   release.TagName,
+			Body:      strings.TrimSpace(release.Body),
+			URL:       release.HTMLURL,
+			Published: releaseDate(release.PublishedAt),
+		}
+
+		if title := strings.TrimSpace(release.Name); title != release.TagName {
+			note.Title = title
+		}
+
+		notes = append(notes, note)
+	}
+
+	return &ReleaseNotes{
+		Repository: "https://github.com/" + repository + "/releases",
+		Notes:      sortReleaseNotes(notes),
+	}, nil
+}
+
+// releaseInRange は tag のバージョンが pkg の現在のバージョンより新しく、新しいバージョン以下かを判定します。
+// モノレポのタグ（例: tools/v1.2.3）や接頭辞（例: release-1.2.3）は取り除いて比較します。
+func releaseInRange(tag string, pkg PackageInfo) bool {
+	version := releaseTagVersion(tag)
+	if version == "" {
+		return false
+	}
+
+	return compareGenericVersion(releaseTagVersion(pkg.CurrentVersion), version) < 0 &&
+		compareGenericVersion(version, releaseTagVersion(pkg.NewVersion)) <= 0
+}
+
+// releaseTagVersion はタグから最後の "/" までと、数字より前の接頭辞を取り除きます。
+func releaseTagVersion(tag string) string {
+	if idx := strings.LastIndex(tag, "/"); idx != -1 {
+		tag = tag[idx+1:]
+	}
+
+	if idx := strings.IndexAny(tag, "0123456789"); idx != -1 {
+		return tag[idx:]
+	}
+
+	return ""
+}
+
+// sortReleaseNotes は新しい順に並べ、maxReleaseNotes 件までに切り詰めます。
+func sortReleaseNotes(notes []ReleaseNote) []ReleaseNote {
+	sort.SliceStable(notes, func(i, j int) bool {
+		return compareGenericVersion(releaseTagVersion(notes[i].Version), releaseTagVersion(notes[j].Version)) > 0
+	})
+
+	if len(notes) > maxReleaseNotes {
+		notes = notes[:maxReleaseNotes]
+	}
+
+	return notes
+}
+
+// releaseDate は RFC 3339 の日時から日付部分を返します。
+func releaseDate(timestamp string) string {
+	if len(timestamp) >= len("2006-01-02") {
+		return timestamp[:len("2006-01-02")]
+	}
+
+	return timestamp
+}
+
+// firstGitHubRepository は候補の URL のうち最初に GitHub のリポジトリを指すものを owner/repo で返します。
+func firstGitHubRepository(candidates ...string) string {
+	for _, candidate := range candidates {
+		if repository, ok := parseGitHubRepository(candidate); ok {
+			return repository
+		}
+	}
+
+	return ""
+}
+
+// parseGitHubRepository は GitHub を指す URL やモジュールパスから owner/repo を取り出します。
+// 例: git+https://github.com/o/r.git、git@github.com:o/r.git、github:o/r、
+// github.com/o/r/v2、https://github.com/o/r/archive/refs/tags/v1.0.tar.gz
+func parseGitHubRepository(raw string) (string, bool) {
+	raw = strings.TrimSpace(raw)
+
+	var rest string
+
+	switch {
+	case strings.HasPrefix(raw, "github:"):
+		rest = strings.TrimPrefix(raw, "github:")
+	default:
+		idx := strings.Index(strings.ToLower(raw), "github.com")
+		if idx == -1 {
+			return "", false
+		}
+
+		rest = raw[idx+len("github.com"):]
+		if rest == "" || (rest[0] != '/' && rest[0] != ':') {
+			return "", false
+		}
+
+		rest = rest[1:]
+	}
+
+	parts := strings.SplitN(rest, "/", 3)
+	if len(parts) < 2 {
+		return "", false
+	}
+
+	owner := parts[0]
+	repo := strings.TrimSuffix(strings.SplitN(parts[1], "#", 2)[0], ".git")
+
+	if owner == "" || repo == "" {
+		return "", false
+	}
+
+	return owner + "/" + repo, true
+}
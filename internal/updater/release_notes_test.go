@@ -0,0 +1,223 @@
+package updater
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// releaseNotesFixtures は各問い合わせ先の応答を 1 つのサーバーで返すフィクスチャです。
+var releaseNotesFixtures = map[string]string{
+	// npm レジストリ
+	"/typescript": `{"repository":{"type":"git","url":"git+https://github.com/microsoft/TypeScript.git"},
+		"time":{"created":"2012-10-01T00:00:00Z","5.4.5":"2024-04-10T00:00:00Z","5.5.2":"2024-06-20T00:00:00Z"}}`,
+	"/@scope%2Fnoreleases": `{"repository":"github:example/noreleases",
+		"time":{"modified":"2024-07-01T00:00:00Z","1.0.0":"2024-01-01T00:00:00Z","1.1.0":"2024-03-01T12:00:00Z",
+		"2.0.0-beta.1":"2024-05-01T00:00:00Z","2.0.0":"2024-06-01T00:00:00Z","2.1.0":"2024-07-01T00:00:00Z"}}`,
+	// GitHub Releases
+	"/repos/microsoft/TypeScript/releases": `[
+		{"tag_name":"v5.6.0-beta","name":"TypeScript 5.6 Beta","prerelease":true},
+		{"tag_name":"v5.5.2","name":"TypeScript 5.5.2","body":"バグ修正","html_url":"https://github.com/microsoft/TypeScript/releases/tag/v5.5.2","published_at":"2024-06-20T00:00:00Z"},
+		{"tag_name":"v5.5.0","name":"v5.5.0","body":"新機能\n","html_url":"https://github.com/microsoft/TypeScript/releases/tag/v5.5.0"},
+		{"tag_name":"v5.4.5","name":"TypeScript 5.4.5"},
+		{"tag_name":"v5.5.1","draft":true}
+	]`,
+	"/repos/example/noreleases/releases": `[]`,
+	"/repos/golang/tools/releases": `[
+		{"tag_name":"gopls/v0.17.0","body":"gopls 0.17"},
+		{"tag_name":"gopls/v0.16.2","body":"gopls 0.16.2"},
+		{"tag_name":"gopls/v0.16.1","body":"gopls 0.16.1"}
+	]`,
+	"/repos/BurntSushi/ripgrep/releases": `[
+		{"tag_name":"14.1.1","body":"ripgrep 14.1.1"},
+		{"tag_name":"14.1.0","body":"ripgrep 14.1.0"}
+	]`,
+	"/repos/junegunn/fzf/releases": `[
+		{"tag_name":"v0.56.0","body":"fzf 0.56.0"},
+		{"tag_name":"v0.55.0","body":"fzf 0.55.0"}
+	]`,
+	// crates.io
+	"/api/v1/crates/ripgrep": `{"crate":{"repository":"https://github.com/BurntSushi/ripgrep","homepage":"https://github.com/BurntSushi/ripgrep"}}`,
+	"/api/v1/crates/private": `{"crate":{"repository":"https://gitlab.com/example/private"}}`,
+	// Homebrew
+	"/api/formula/fzf.json": `{"homepage":"https://github.com/junegunn/fzf","urls":{"stable":{"url":"https://github.com/junegunn/fzf/archive/refs/tags/v0.56.0.tar.gz"}}}`,
+}
+
+func newReleaseNotesTestFetcher(t *testing.T) (*ReleaseNotesFetcher, *[]string) {
+	t.Helper()
+
+	var authorizations []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if auth := r.Header.Get("Authorization"); auth != "" {
+			authorizations = append(authorizations, auth)
+		}
+
+		body, ok := releaseNotesFixtures[r.URL.EscapedPath()]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	fetcher := &ReleaseNotesFetcher{
+		NpmRegistryURL: server.URL,
+		GitHubAPIURL:   server.URL,
+		CratesIOURL:    server.URL,
+		HomebrewAPIURL: server.URL,
+	}
+
+	return fetcher, &authorizations
+}
+
+func releaseNoteVersions(notes *ReleaseNotes) []string {
+	versions := make([]string, 0, len(notes.Notes))
+	for _, note := range notes.Notes {
+		versions = append(versions, note.Version)
+	}
+
+	return versions
+}
+
+func TestReleaseNotesFetcher_Fetch(t *testing.T) {
+	tests := []struct {
+		name           string
+		manager        string
+		pkg            PackageInfo
+		wantVersions   []string
+		wantRepository string
+	}{
+		{
+			name:           "npm: リポジトリの GitHub Releases から範囲内のリリースを返す（draft / プレリリースは除外）",
+			manager:        "npm",
+			pkg:            PackageInfo{Name: "typescript", CurrentVersion: "5.4.5", NewVersion: "5.5.2"},
+			wantVersions:   []string{"v5.5.2", "v5.5.0"},
+			wantRepository: "https://github.com/microsoft/TypeScript/releases",
+		},
+		{
+			name:         "pnpm: GitHub のリリースがない場合はレジストリの公開日時から安定版の一覧を返す",
+			manager:      "pnpm",
+			pkg:          PackageInfo{Name: "@scope/noreleases", CurrentVersion: "1.0.0", NewVersion: "2.0.0"},
+			wantVersions: []string{"2.0.0", "1.1.0"},
+		},
+		{
+			name:           "go: モジュールパスからリポジトリを求め、モノレポのタグも比較する",
+			manager:        "go",
+			pkg:            PackageInfo{Name: "gopls", CurrentVersion: "v0.16.1", NewVersion: "v0.16.2", Source: "github.com/golang/tools/gopls"},
+			wantVersions:   []string{"gopls/v0.16.2"},
+			wantRepository: "https://github.com/golang/tools/releases",
+		},
+		{
+			name:         "go: GitHub 以外のモジュールはリリースノートなし",
+			manager:      "go",
+			pkg:          PackageInfo{Name: "gopls", CurrentVersion: "v0.16.1", NewVersion: "v0.16.2", Source: "golang.org/x/tools/gopls"},
+			wantVersions: []string{},
+		},
+		{
+			name:           "cargo: crates.io のリポジトリから GitHub Releases を参照する",
+			manager:        "cargo",
+			pkg:            PackageInfo{Name: "ripgrep", CurrentVersion: "14.0.3", NewVersion: "14.1.1"},
+			wantVersions:   []string{"14.1.1", "14.1.0"},
+			wantRepository: "https://github.com/BurntSushi/ripgrep/releases",
+		},
+		{
+			name:         "cargo: GitHub 以外のリポジトリはリリースノートなし",
+			manager:      "cargo",
+			pkg:          PackageInfo{Name: "private", CurrentVersion: "1.0.0", NewVersion: "1.1.0"},
+			wantVersions: []string{},
+		},
+		{
+			name:           "brew: formula のソース URL から GitHub Releases を参照する（リビジョン付きのバージョン）",
+			manager:        "brew",
+			pkg:            PackageInfo{Name: "fzf", CurrentVersion: "0.55.0_1", NewVersion: "0.56.0"},
+			wantVersions:   []string{"v0.56.0"},
+			wantRepository: "https://github.com/junegunn/fzf/releases",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fetcher, _ := newReleaseNotesTestFetcher(t)
+
+			notes, err := fetcher.Fetch(context.Background(), tt.manager, tt.pkg)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantVersions, releaseNoteVersions(notes))
+			assert.Equal(t, tt.wantRepository, notes.Repository)
+		})
+	}
+}
+
+func TestReleaseNotesFetcher_FetchContent(t *testing.T) {
+	fetcher, authorizations := newReleaseNotesTestFetcher(t)
+	fetcher.GitHubToken = "secret"
+
+	notes, err := fetcher.Fetch(context.Background(), "npm", PackageInfo{Name: "typescript", CurrentVersion: "5.4.5", NewVersion: "5.5.2"})
+	require.NoError(t, err)
+	require.Len(t, notes.Notes, 2)
+
+	assert.Equal(t, ReleaseNote{
+		Version:   "v5.5.2",
+		Title:     "TypeScript 5.5.2",
+		Body:      "バグ修正",
+		URL:       "https://github.com/microsoft/TypeScript/releases/tag/v5.5.2",
+		Published: "2024-06-20",
+	}, notes.Notes[0])
+	assert.Equal(t, "", notes.Notes[1].Title, "タグ名と同じリリース名は省略する")
+	assert.Equal(t, "新機能", notes.Notes[1].Body)
+	assert.Equal(t, []string{"Bearer secret"}, *authorizations, "GitHub API にのみトークンを送る")
+}
+
+func TestReleaseNotesFetcher_FetchErrors(t *testing.T) {
+	fetcher, _ := newReleaseNotesTestFetcher(t)
+	ctx := context.Background()
+
+	_, err := fetcher.Fetch(ctx, "apt", PackageInfo{Name: "curl", CurrentVersion: "1", NewVersion: "2"})
+	require.ErrorIs(t, err, errReleaseNotesUnsupported)
+
+	_, err = fetcher.Fetch(ctx, "brew", PackageInfo{Name: "missing", CurrentVersion: "1.0", NewVersion: "1.1"})
+	require.ErrorIs(t, err, errRegistryPackageNotFound)
+
+	_, err = fetcher.Fetch(ctx, "npm", PackageInfo{Name: "typescript", NewVersion: "5.5.2"})
+	require.Error(t, err, "現在のバージョンが不明な場合はエラー")
+}
+
+func TestParseGitHubRepository(t *testing.T) {
+	tests := []struct {
+		input  string
+		want   string
+		wantOK bool
+	}{
+		{input: "git+https://github.com/microsoft/TypeScript.git", want: "microsoft/TypeScript", wantOK: true},
+		{input: "git@github.com:owner/repo.git", want: "owner/repo", wantOK: true},
+		{input: "github:owner/repo", want: "owner/repo", wantOK: true},
+		{input: "github.com/owner/repo/v2", want: "owner/repo", wantOK: true},
+		{input: "https://github.com/owner/repo#readme", want: "owner/repo", wantOK: true},
+		{input: "https://github.com/owner/repo/archive/refs/tags/v1.0.tar.gz", want: "owner/repo", wantOK: true},
+		{input: "https://gitlab.com/owner/repo", wantOK: false},
+		{input: "https://github.com/owner", wantOK: false},
+		{input: "https://github.community/owner/repo", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, ok := parseGitHubRepository(tt.input)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSupportsReleaseNotes(t *testing.T) {
+	for _, manager := range []string{"npm", "pnpm", "go", "cargo", "brew"} {
+		assert.True(t, SupportsReleaseNotes(manager), manager)
+	}
+
+	assert.False(t, SupportsReleaseNotes("apt"))
+}
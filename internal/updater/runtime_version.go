@@ -0,0 +1,328 @@
+package updater
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/scottlz0310/devsync/internal/config"
+)
+
+// runtimeVersionPattern はランタイムのバージョン "X.Y.Z" と任意の接尾辞（例: Java の "-tem"）にマッチします。
+var runtimeVersionPattern = regexp.MustCompile(`^(\d+)\.(\d+)\.(\d+)(-[A-Za-z0-9.]+)?$`)
+
+// runtimeManager は言語ランタイムのバージョン管理ツール（pyenv / mise / sdkman など）ごとの操作です。
+type runtimeManager interface {
+	// selfUpdate はバージョン管理ツール本体とプラグイン（ビルド定義）を更新します。
+	selfUpdate(ctx context.Context) error
+	// installedVersions はランタイム名ごとのインストール済みバージョンを返します。
+	installedVersions(ctx context.Context) (map[string][]string, error)
+	// availableVersions はランタイムのインストール可能なバージョンを返します。
+	availableVersions(ctx context.Context, tool string) ([]string, error)
+	// globalVersion はグローバルに設定されているバージョンを返します（未設定の場合は空文字）。
+	globalVersion(ctx context.Context, tool string) (string, error)
+	installVersion(ctx context.Context, tool, version string) error
+	setGlobalVersion(ctx context.Context, tool, version string) error
+}
+
+// runtimeUpdateSettings はバージョン管理ツール共通の設定です。
+//
+//	sys:
+//	  managers:
+//	    pyenv:
+//	      self_update: true  # 本体・プラグインを更新（既定: true）
+//	      install: true      # 新しいパッチリリースをインストール（既定: false、報告のみ）
+//	      set_global: true   # グローバルだった系列を新しいバージョンに切り替え（既定: false）
+//	      tools: ["python"]  # 対象のランタイムを限定（mise / asdf / sdkman）
+type runtimeUpdateSettings struct {
+	selfUpdate bool
+	install    bool
+	setGlobal  bool
+	tools      []string
+}
+
+func defaultRuntimeUpdateSettings() runtimeUpdateSettings {
+	return runtimeUpdateSettings{selfUpdate: true}
+}
+
+func (s *runtimeUpdateSettings) configure(cfg config.ManagerConfig) {
+	if value, ok := cfg["self_update"].(bool); ok {
+		s.selfUpdate = value
+	}
+
+	if value, ok := cfg["install"].(bool); ok {
+		s.install = value
+	}
+
+	if value, ok := cfg["set_global"].(bool); ok {
+		s.setGlobal = value
+	}
+
+	if values, ok := cfg["tools"].([]interface{}); ok {
+		s.tools = s.tools[:0]
+
+		for _, value := range values {
+			if tool, ok := value.(string); ok && strings.TrimSpace(tool) != "" {
+				s.tools = append(s.tools, strings.TrimSpace(tool))
+			}
+		}
+	}
+}
+
+func (s *runtimeUpdateSettings) includes(tool string) bool {
+	return len(s.tools) == 0 || containsString(s.tools, tool)
+}
+
+// runtimeLine はパッチリリースを比較する単位（メジャー・マイナーと接尾辞）です。
+type runtimeLine struct {
+	major  int
+	minor  int
+	suffix string
+}
+
+func (l runtimeLine) String() string {
+	return fmt.Sprintf("%d.%d%s", l.major, l.minor, l.suffix)
+}
+
+// runtimeUpdate はランタイムの系列ごとの更新候補です。
+type runtimeUpdate struct {
+	tool    string
+	line    runtimeLine
+	current string
+	latest  string
+}
+
+func (u runtimeUpdate) packageInfo() PackageInfo {
+	return PackageInfo{
+		Name:           u.tool + " " + u.line.String(),
+		CurrentVersion: u.current,
+		NewVersion:     u.latest,
+	}
+}
+
+// parseRuntimeVersion は "X.Y.Z[-suffix]" を系列と semver に分解します。
+// rc / dev などのプレリリースや "system" は対象外として false を返します。
+func parseRuntimeVersion(version string) (runtimeLine, string, bool) {
+	match := runtimeVersionPattern.FindStringSubmatch(strings.TrimSpace(version))
+	if match == nil {
+		return runtimeLine{}, "", false
+	}
+
+	major, _ := strconv.Atoi(match[1])
+	minor, _ := strconv.Atoi(match[2])
+
+	return runtimeLine{major: major, minor: minor, suffix: match[4]}, match[1] + "." + match[2] + "." + match[3], true
+}
+
+// newestPerLine は系列ごとに最も新しいバージョンを返します。
+func newestPerLine(versions []string) map[runtimeLine]string {
+	newest := make(map[runtimeLine]string)
+
+	for _, version := range versions {
+		line, semver, ok := parseRuntimeVersion(version)
+		if !ok {
+			continue
+		}
+
+		current, exists := newest[line]
+		if !exists {
+			newest[line] = version
+			continue
+		}
+
+		_, currentSemver, _ := parseRuntimeVersion(current)
+		if less, err := isSemverLess(currentSemver, semver); err == nil && less {
+			newest[line] = version
+		}
+	}
+
+	return newest
+}
+
+// planRuntimeUpdates は各ランタイムの系列ごとに、インストール済みより新しいパッチリリースを求めます。
+func planRuntimeUpdates(ctx context.Context, manager runtimeManager, settings runtimeUpdateSettings) ([]runtimeUpdate, error) {
+	installed, err := manager.installedVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var updates []runtimeUpdate
+
+	for _, tool := range sortedManagerNames(installed) {
+		if !settings.includes(tool) {
+			continue
+		}
+
+		installedLines := newestPerLine(installed[tool])
+		if len(installedLines) == 0 {
+			continue
+		}
+
+		available, err := manager.availableVersions(ctx, tool)
+		if err != nil {
+			return nil, fmt.Errorf("%s のバージョン一覧の取得に失敗: %w", tool, err)
+		}
+
+		availableLines := newestPerLine(available)
+
+		for _, line := range sortedRuntimeLines(installedLines) {
+			current := installedLines[line]
+
+			latest, ok := availableLines[line]
+			if !ok {
+				continue
+			}
+
+			_, currentSemver, _ := parseRuntimeVersion(current)
+			_, latestSemver, _ := parseRuntimeVersion(latest)
+
+			if less, err := isSemverLess(currentSemver, latestSemver); err != nil || !less {
+				continue
+			}
+
+			updates = append(updates, runtimeUpdate{tool: tool, line: line, current: current, latest: latest})
+		}
+	}
+
+	return updates, nil
+}
+
+func sortedRuntimeLines(lines map[runtimeLine]string) []runtimeLine {
+	keys := make([]runtimeLine, 0, len(lines))
+	for line := range lines {
+		keys = append(keys, line)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].major != keys[j].major {
+			return keys[i].major < keys[j].major
+		}
+
+		if keys[i].minor != keys[j].minor {
+			return keys[i].minor < keys[j].minor
+		}
+
+		return keys[i].suffix < keys[j].suffix
+	})
+
+	return keys
+}
+
+// checkRuntimeUpdates はパッチリリースの更新候補を CheckResult として返します。
+func checkRuntimeUpdates(ctx context.Context, manager runtimeManager, settings runtimeUpdateSettings) (*CheckResult, error) {
+	updates, err := planRuntimeUpdates(ctx, manager, settings)
+	if err != nil {
+		return nil, err
+	}
+
+	packages := make([]PackageInfo, 0, len(updates))
+	for _, update := range updates {
+		packages = append(packages, update.packageInfo())
+	}
+
+	return &CheckResult{
+		AvailableUpdates: len(packages),
+		Packages:         packages,
+	}, nil
+}
+
+// updateRuntimeVersions は本体・プラグインを更新したうえで、新しいパッチリリースをインストールします。
+// install が無効な場合は更新候補の報告のみ行います。
+func updateRuntimeVersions(
+	ctx context.Context,
+	opts UpdateOptions,
+	name string,
+	manager runtimeManager,
+	settings runtimeUpdateSettings,
+) (*UpdateResult, error) {
+	result := &UpdateResult{}
+
+	// ビルド定義が更新されないと新しいバージョンが一覧に現れないため、先に本体・プラグインを更新する
+	if !opts.DryRun && settings.selfUpdate {
+		if err := manager.selfUpdate(ctx); err != nil {
+			result.Errors = append(result.Errors, fmt.Errorf("%s 本体・プラグインの更新に失敗: %w", name, err))
+		}
+	}
+
+	updates, err := planRuntimeUpdates(ctx, manager, settings)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(updates) == 0 {
+		result.Message = "すべてのランタイムは最新です"
+		return result, nil
+	}
+
+	for _, update := range updates {
+		result.Packages = append(result.Packages, update.packageInfo())
+	}
+
+	if opts.DryRun {
+		result.Message = fmt.Sprintf("%d 件のランタイムのパッチリリースが利用可能です（DryRunモード）", len(updates))
+		return result, nil
+	}
+
+	if !settings.install {
+		result.Message = fmt.Sprintf("%d 件のランタイムのパッチリリースが利用可能です（sys.managers.%s.install: true でインストールします）", len(updates), name)
+		return result, nil
+	}
+
+	for _, update := range updates {
+		installRuntimeUpdate(ctx, manager, settings, update, result)
+	}
+
+	if result.FailedCount > 0 {
+		result.Message = fmt.Sprintf("%d 件更新、%d 件失敗", result.UpdatedCount, result.FailedCount)
+	} else {
+		result.Message = fmt.Sprintf("%d 件のランタイムを更新しました", result.UpdatedCount)
+	}
+
+	return result, nil
+}
+
+func installRuntimeUpdate(ctx context.Context, manager runtimeManager, settings runtimeUpdateSettings, update runtimeUpdate, result *UpdateResult) {
+	// グローバル設定の判定はインストール前の状態で行う
+	global := ""
+	if settings.setGlobal {
+		global, _ = manager.globalVersion(ctx, update.tool)
+	}
+
+	if err := manager.installVersion(ctx, update.tool, update.latest); err != nil {
+		result.FailedCount++
+		result.Errors = append(result.Errors, fmt.Errorf("%s %s のインストールに失敗: %w", update.tool, update.latest, err))
+
+		return
+	}
+
+	result.UpdatedCount++
+
+	if global != update.current {
+		return
+	}
+
+	if err := manager.setGlobalVersion(ctx, update.tool, update.latest); err != nil {
+		result.Errors = append(result.Errors, fmt.Errorf("%s %s のグローバル設定に失敗: %w", update.tool, update.latest, err))
+	}
+}
+
+// runtimeVersionTokens は出力から "X.Y.Z[-suffix]" 形式のトークンを取り出します。
+// 表形式（sdk list java など）の区切り文字 "|" や選択中の印 "*" / ">" は除去します。
+func runtimeVersionTokens(output string) []string {
+	fields := strings.FieldsFunc(output, func(r rune) bool {
+		return r == ' ' || r == '\t' || r == '\n' || r == '\r' || r == '|' || r == '*' || r == '>'
+	})
+
+	versions := make([]string, 0, len(fields))
+
+	for _, field := range fields {
+		if _, _, ok := parseRuntimeVersion(field); ok {
+			versions = append(versions, field)
+		}
+	}
+
+	return versions
+}
@@ -0,0 +1,177 @@
+package updater
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/scottlz0310/devsync/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRuntimeManager はバージョン管理ツールの操作を記録するテスト用の runtimeManager です。
+type fakeRuntimeManager struct {
+	installed   map[string][]string
+	available   map[string][]string
+	global      map[string]string
+	selfErr     error
+	installErr  map[string]error
+	selfUpdated bool
+	installs    []string
+	globals     []string
+}
+
+func (f *fakeRuntimeManager) selfUpdate(context.Context) error {
+	f.selfUpdated = true
+	return f.selfErr
+}
+
+func (f *fakeRuntimeManager) installedVersions(context.Context) (map[string][]string, error) {
+	return f.installed, nil
+}
+
+func (f *fakeRuntimeManager) availableVersions(_ context.Context, tool string) ([]string, error) {
+	return f.available[tool], nil
+}
+
+func (f *fakeRuntimeManager) globalVersion(_ context.Context, tool string) (string, error) {
+	return f.global[tool], nil
+}
+
+func (f *fakeRuntimeManager) installVersion(_ context.Context, tool, version string) error {
+	if err := f.installErr[version]; err != nil {
+		return err
+	}
+
+	f.installs = append(f.installs, tool+"@"+version)
+
+	return nil
+}
+
+func (f *fakeRuntimeManager) setGlobalVersion(_ context.Context, tool, version string) error {
+	f.globals = append(f.globals, tool+"@"+version)
+	return nil
+}
+
+func newFakeRuntimeManager() *fakeRuntimeManager {
+	return &fakeRuntimeManager{
+		installed: map[string][]string{
+			"python": {"3.11.9", "3.12.1", "3.12.3", "system", "3.13.0rc1"},
+			"java":   {"21.0.3-tem"},
+		},
+		available: map[string][]string{
+			"python": {"3.11.9", "3.12.3", "3.12.5", "3.13.0", "3.13.0rc2"},
+			"java":   {"21.0.4-tem", "21.0.5-graal", "22.0.2-tem"},
+		},
+		global: map[string]string{"python": "3.12.3"},
+	}
+}
+
+func TestParseRuntimeVersion(t *testing.T) {
+	testCases := []struct {
+		version  string
+		wantLine string
+		wantOK   bool
+	}{
+		{version: "3.12.5", wantLine: "3.12", wantOK: true},
+		{version: "21.0.4-tem", wantLine: "21.0-tem", wantOK: true},
+		{version: "3.13.0rc1", wantOK: false},
+		{version: "system", wantOK: false},
+		{version: "3.12-dev", wantOK: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.version, func(t *testing.T) {
+			line, _, ok := parseRuntimeVersion(tc.version)
+			assert.Equal(t, tc.wantOK, ok)
+
+			if ok {
+				assert.Equal(t, tc.wantLine, line.String())
+			}
+		})
+	}
+}
+
+func TestCheckRuntimeUpdates(t *testing.T) {
+	manager := newFakeRuntimeManager()
+
+	result, err := checkRuntimeUpdates(context.Background(), manager, defaultRuntimeUpdateSettings())
+	require.NoError(t, err)
+	assert.Equal(t, []PackageInfo{
+		{Name: "java 21.0-tem", CurrentVersion: "21.0.3-tem", NewVersion: "21.0.4-tem"},
+		{Name: "python 3.12", CurrentVersion: "3.12.3", NewVersion: "3.12.5"},
+	}, result.Packages, "系列ごとに最新のインストール済みバージョンと比較し、別系列・プレリリースは対象外")
+
+	settings := defaultRuntimeUpdateSettings()
+	settings.configure(config.ManagerConfig{"tools": []interface{}{"java"}})
+
+	result, err = checkRuntimeUpdates(context.Background(), manager, settings)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.AvailableUpdates)
+}
+
+func TestUpdateRuntimeVersions(t *testing.T) {
+	t.Run("DryRunは本体更新もインストールもしない", func(t *testing.T) {
+		manager := newFakeRuntimeManager()
+
+		result, err := updateRuntimeVersions(context.Background(), UpdateOptions{DryRun: true}, "pyenv", manager, defaultRuntimeUpdateSettings())
+		require.NoError(t, err)
+		assert.Contains(t, result.Message, "DryRunモード")
+		assert.False(t, manager.selfUpdated)
+		assert.Empty(t, manager.installs)
+	})
+
+	t.Run("install未指定は報告のみ", func(t *testing.T) {
+		manager := newFakeRuntimeManager()
+
+		result, err := updateRuntimeVersions(context.Background(), UpdateOptions{}, "pyenv", manager, defaultRuntimeUpdateSettings())
+		require.NoError(t, err)
+		assert.True(t, manager.selfUpdated)
+		assert.Empty(t, manager.installs)
+		assert.Equal(t, 0, result.UpdatedCount)
+		assert.Len(t, result.Packages, 2)
+		assert.Contains(t, result.Message, "sys.managers.pyenv.install: true")
+	})
+
+	t.Run("インストールとグローバル切り替え", func(t *testing.T) {
+		manager := newFakeRuntimeManager()
+		manager.selfErr = errors.New("git pull failed")
+		manager.installErr = map[string]error{"21.0.4-tem": errors.New("download failed")}
+
+		settings := defaultRuntimeUpdateSettings()
+		settings.configure(config.ManagerConfig{"install": true, "set_global": true})
+
+		result, err := updateRuntimeVersions(context.Background(), UpdateOptions{}, "mise", manager, settings)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"python@3.12.5"}, manager.installs)
+		assert.Equal(t, []string{"python@3.12.5"}, manager.globals)
+		assert.Equal(t, 1, result.UpdatedCount)
+		assert.Equal(t, 1, result.FailedCount)
+		assert.Equal(t, "1 件更新、1 件失敗", result.Message)
+		require.Len(t, result.Errors, 2)
+		assert.Contains(t, result.Errors[0].Error(), "mise 本体・プラグインの更新に失敗")
+		assert.Contains(t, result.Errors[1].Error(), "java 21.0.4-tem のインストールに失敗")
+	})
+
+	t.Run("更新なし", func(t *testing.T) {
+		manager := &fakeRuntimeManager{installed: map[string][]string{"ruby": {"3.3.4"}}, available: map[string][]string{"ruby": {"3.3.4"}}}
+
+		result, err := updateRuntimeVersions(context.Background(), UpdateOptions{}, "rbenv", manager, defaultRuntimeUpdateSettings())
+		require.NoError(t, err)
+		assert.Equal(t, "すべてのランタイムは最新です", result.Message)
+	})
+}
+
+func TestRuntimeVersionTokens(t *testing.T) {
+	output := `================================================================================
+Available Java Versions for Linux 64bit
+================================================================================
+ Vendor        | Use | Version      | Dist    | Status     | Identifier
+--------------------------------------------------------------------------------
+ Temurin       |     | 22.0.2       | tem     |            | 22.0.2-tem
+               | >>> | 21.0.4       | tem     | installed  | 21.0.4-tem
+`
+
+	assert.Equal(t, []string{"22.0.2", "22.0.2-tem", "21.0.4", "21.0.4-tem"}, runtimeVersionTokens(output))
+}
@@ -0,0 +1,215 @@
+package updater
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/scottlz0310/devsync/internal/config"
+)
+
+// SdkmanUpdater は SDKMAN!（Java / Kotlin / Gradle などの SDK バージョン管理）の実装です。
+// sdk はシェル関数のため、sdkman-init.sh を読み込んだ bash 経由で実行します。
+type SdkmanUpdater struct {
+	settings runtimeUpdateSettings
+}
+
+var _ runtimeManager = (*SdkmanUpdater)(nil)
+
+// 起動時にレジストリに登録
+func init() {
+	Register(&SdkmanUpdater{settings: defaultRuntimeUpdateSettings()})
+}
+
+func (s *SdkmanUpdater) Name() string {
+	return "sdkman"
+}
+
+func (s *SdkmanUpdater) DisplayName() string {
+	return "SDKMAN! (SDK バージョン管理)"
+}
+
+func (s *SdkmanUpdater) IsAvailable() bool {
+	if runtime.GOOS == windowsOS {
+		return false
+	}
+
+	if _, err := exec.LookPath("bash"); err != nil {
+		return false
+	}
+
+	_, err := resolveSdkmanDir()
+
+	return err == nil
+}
+
+func (s *SdkmanUpdater) Configure(cfg config.ManagerConfig) error {
+	if cfg == nil {
+		return nil
+	}
+
+	s.settings.configure(cfg)
+
+	return nil
+}
+
+func (s *SdkmanUpdater) Check(ctx context.Context) (*CheckResult, error) {
+	return checkRuntimeUpdates(ctx, s, s.settings)
+}
+
+func (s *SdkmanUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateResult, error) {
+	return updateRuntimeVersions(ctx, opts, s.Name(), s, s.settings)
+}
+
+// selfUpdate は SDKMAN! 本体（sdk selfupdate）と候補一覧（sdk update）を更新します。
+func (s *SdkmanUpdater) selfUpdate(ctx context.Context) error {
+	if err := s.runSdk(ctx, "selfupdate"); err != nil {
+		return fmt.Errorf("sdk selfupdate に失敗: %w", err)
+	}
+
+	if err := s.runSdk(ctx, "update"); err != nil {
+		return fmt.Errorf("sdk update に失敗: %w", err)
+	}
+
+	return nil
+}
+
+// installedVersions は <SDKMAN_DIR>/candidates/<candidate>/<version> のディレクトリから取得します。
+func (s *SdkmanUpdater) installedVersions(context.Context) (map[string][]string, error) {
+	dir, err := resolveSdkmanDir()
+	if err != nil {
+		return nil, err
+	}
+
+	candidates, err := os.ReadDir(filepath.Join(dir, "candidates"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string][]string{}, nil
+		}
+
+		return nil, fmt.Errorf("SDKMAN! の候補ディレクトリの読み込みに失敗: %w", err)
+	}
+
+	installed := make(map[string][]string, len(candidates))
+
+	for _, candidate := range candidates {
+		if !candidate.IsDir() {
+			continue
+		}
+
+		versions, err := os.ReadDir(filepath.Join(dir, "candidates", candidate.Name()))
+		if err != nil {
+			continue
+		}
+
+		for _, version := range versions {
+			if version.Name() == "current" || !version.IsDir() {
+				continue
+			}
+
+			installed[candidate.Name()] = append(installed[candidate.Name()], version.Name())
+		}
+	}
+
+	return installed, nil
+}
+
+func (s *SdkmanUpdater) availableVersions(ctx context.Context, tool string) ([]string, error) {
+	cmd, err := s.buildCommand(ctx, "list", tool)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("sdk list %s の実行に失敗: %w", tool, buildCommandOutputErr(err, output))
+	}
+
+	return runtimeVersionTokens(string(output)), nil
+}
+
+// globalVersion は <SDKMAN_DIR>/candidates/<candidate>/current のリンク先（既定のバージョン）を返します。
+func (s *SdkmanUpdater) globalVersion(_ context.Context, tool string) (string, error) {
+	dir, err := resolveSdkmanDir()
+	if err != nil {
+		return "", err
+	}
+
+	target, err := os.Readlink(filepath.Join(dir, "candidates", tool, "current"))
+	if err != nil {
+		return "", nil
+	}
+
+	return filepath.Base(target), nil
+}
+
+func (s *SdkmanUpdater) installVersion(ctx context.Context, tool, version string) error {
+	return s.runSdk(ctx, "install", tool, version)
+}
+
+func (s *SdkmanUpdater) setGlobalVersion(ctx context.Context, tool, version string) error {
+	return s.runSdk(ctx, "default", tool, version)
+}
+
+func (s *SdkmanUpdater) runSdk(ctx context.Context, args ...string) error {
+	cmd, err := s.buildCommand(ctx, args...)
+	if err != nil {
+		return err
+	}
+
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Stdin = os.Stdin
+
+	return cmd.Run()
+}
+
+// buildCommand は sdkman-init.sh を読み込んで sdk を実行するコマンドを組み立てます。
+// 対話的な確認・ページャ・色付けは無効化します。
+func (s *SdkmanUpdater) buildCommand(ctx context.Context, args ...string) (*exec.Cmd, error) {
+	dir, err := resolveSdkmanDir()
+	if err != nil {
+		return nil, err
+	}
+
+	quotedArgs := make([]string, 0, len(args))
+	for _, arg := range args {
+		quotedArgs = append(quotedArgs, quotePosixShellArg(arg))
+	}
+
+	initScript := filepath.Join(dir, "bin", "sdkman-init.sh")
+	commandText := fmt.Sprintf(
+		". %s >/dev/null 2>&1 && sdkman_auto_answer=true sdkman_colour_enable=false sdk %s",
+		quotePosixShellArg(initScript),
+		strings.Join(quotedArgs, " "),
+	)
+
+	cmd := exec.CommandContext(ctx, "bash", "-c", commandText)
+	cmd.Env = append(os.Environ(), "LANG=C", "LC_ALL=C", "PAGER=cat", "SDKMAN_DIR="+dir)
+
+	return cmd, nil
+}
+
+func resolveSdkmanDir() (string, error) {
+	candidates := make([]string, 0, 2)
+
+	if dir := strings.TrimSpace(os.Getenv("SDKMAN_DIR")); dir != "" {
+		candidates = append(candidates, dir)
+	}
+
+	if home, err := os.UserHomeDir(); err == nil && strings.TrimSpace(home) != "" {
+		candidates = append(candidates, filepath.Join(home, ".sdkman"))
+	}
+
+	for _, candidate := range candidates {
+		if info, err := os.Stat(filepath.Join(candidate, "bin", "sdkman-init.sh")); err == nil && !info.IsDir() {
+			return candidate, nil
+		}
+	}
+
+	return "", fmt.Errorf("sdkman-init.sh が見つかりません（SDKMAN_DIR または ~/.sdkman）")
+}
@@ -0,0 +1,93 @@
+package updater
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/scottlz0310/devsync/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSdkmanUpdater_CheckAndUpdate(t *testing.T) {
+	logPath := writeFakeSdkman(t)
+
+	s := &SdkmanUpdater{settings: defaultRuntimeUpdateSettings()}
+	assert.True(t, s.IsAvailable())
+
+	checkResult, err := s.Check(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []PackageInfo{
+		{Name: "gradle 8.8", CurrentVersion: "8.8.0", NewVersion: "8.8.1"},
+		{Name: "java 21.0-tem", CurrentVersion: "21.0.3-tem", NewVersion: "21.0.4-tem"},
+	}, checkResult.Packages)
+
+	s.settings.install = true
+	s.settings.setGlobal = true
+
+	result, err := s.Update(context.Background(), UpdateOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.UpdatedCount)
+
+	data, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+
+	log := string(data)
+	assert.Contains(t, log, "sdk selfupdate auto=true")
+	assert.Contains(t, log, "sdk install java 21.0.4-tem auto=true")
+	assert.Contains(t, log, "sdk default java 21.0.4-tem", "current のリンク先だった系列は既定を切り替える")
+	assert.NotContains(t, log, "sdk default gradle")
+}
+
+func TestSdkmanUpdater_NotInstalled(t *testing.T) {
+	t.Setenv("SDKMAN_DIR", t.TempDir())
+	testutil.SetTestHome(t, t.TempDir())
+
+	assert.False(t, (&SdkmanUpdater{}).IsAvailable())
+}
+
+// writeFakeSdkman は sdk 関数を定義した fake の sdkman-init.sh と候補ディレクトリを作成し、実行ログのパスを返します。
+func writeFakeSdkman(t *testing.T) string {
+	t.Helper()
+
+	if runtime.GOOS == windowsOS {
+		t.Skip("fake sdkman は POSIX シェル前提")
+	}
+
+	dir := t.TempDir()
+	logPath := filepath.Join(t.TempDir(), "commands.log")
+	t.Setenv("SDKMAN_DIR", dir)
+	t.Setenv("DEVSYNC_TEST_SDKMAN_LOG", logPath)
+
+	for _, version := range []string{"java/21.0.3-tem", "java/17.0.11-tem", "gradle/8.8.0"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "candidates", version), 0o755))
+	}
+
+	require.NoError(t, os.Symlink(filepath.Join(dir, "candidates", "java", "21.0.3-tem"), filepath.Join(dir, "candidates", "java", "current")))
+
+	initScript := `sdk() {
+  case "$1 $2" in
+    "list java")
+      echo " Vendor   | Use | Version | Dist | Status    | Identifier"
+      echo " Temurin  |     | 21.0.4  | tem  |           | 21.0.4-tem"
+      echo "          | >>> | 21.0.3  | tem  | installed | 21.0.3-tem"
+      echo "          |     | 17.0.11 | tem  | installed | 17.0.11-tem"
+      ;;
+    "list gradle")
+      echo "     8.9       8.8.1      > * 8.8.0"
+      ;;
+    *)
+      echo "sdk $* auto=${sdkman_auto_answer}" >> "${DEVSYNC_TEST_SDKMAN_LOG}"
+      ;;
+  esac
+}
+`
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "bin"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bin", "sdkman-init.sh"), []byte(initScript), 0o644))
+
+	return logPath
+}
@@ -0,0 +1,41 @@
+package updater
+
+import "fmt"
+
+// SecurityOnlyUpdater はセキュリティ更新のみを適用できるマネージャが実装します。
+// sys update --security-only では、このインターフェースを実装するマネージャのみを実行します。
+type SecurityOnlyUpdater interface {
+	// SupportsSecurityOnly は UpdateOptions.SecurityOnly に対応している場合に true を返します。
+	SupportsSecurityOnly() bool
+}
+
+// securityPackages はセキュリティ更新のパッケージのみを返します。
+func securityPackages(packages []PackageInfo) []PackageInfo {
+	filtered := make([]PackageInfo, 0, len(packages))
+
+	for _, pkg := range packages {
+		if pkg.Security {
+			filtered = append(filtered, pkg)
+		}
+	}
+
+	return filtered
+}
+
+// securityUpdateMessages は更新結果のメッセージを返します（securityOnly の場合はセキュリティ更新向けの文言）。
+func securityUpdateMessages(securityOnly bool) (upToDate string, dryRun, updated func(count int) string) {
+	upToDate = "すべてのパッケージは最新です"
+	dryRunFormat := "%d 件のパッケージが更新可能です（DryRunモード）"
+	updatedFormat := "%d 件のパッケージを更新しました"
+
+	if securityOnly {
+		upToDate = "適用可能なセキュリティ更新はありません"
+		dryRunFormat = "%d 件のセキュリティ更新が適用可能です（DryRunモード）"
+		updatedFormat = "%d 件のセキュリティ更新を適用しました"
+	}
+
+	dryRun = func(count int) string { return fmt.Sprintf(dryRunFormat, count) }
+	updated = func(count int) string { return fmt.Sprintf(updatedFormat, count) }
+
+	return upToDate, dryRun, updated
+}
@@ -0,0 +1,55 @@
+package updater
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+
+	"github.com/scottlz0310/devsync/internal/config"
+)
+
+// configureUseSudo は use_sudo（旧キー sudo）の設定値を解決します。未設定の場合は current を返します。
+func configureUseSudo(cfg config.ManagerConfig, current bool) bool {
+	if useSudo, ok := cfg["use_sudo"].(bool); ok {
+		return useSudo
+	}
+
+	// 旧キー `sudo` との後方互換
+	if useSudo, ok := cfg["sudo"].(bool); ok {
+		return useSudo
+	}
+
+	return current
+}
+
+// systemCommandLine は useSudo に応じて sudo 経由の実行コマンドを組み立てます。
+func systemCommandLine(useSudo bool, command string, args ...string) (string, []string) {
+	if !useSudo {
+		return command, args
+	}
+
+	return "sudo", append([]string{command}, args...)
+}
+
+// runSystemCommand はシステムパッケージマネージャのコマンドを実行し、出力を端末に流します（必要に応じて sudo を使用）。
+func runSystemCommand(ctx context.Context, useSudo bool, command string, args ...string) error {
+	name, fullArgs := systemCommandLine(useSudo, command, args...)
+
+	cmd := exec.CommandContext(ctx, name, fullArgs...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Stdin = os.Stdin
+
+	return cmd.Run()
+}
+
+// exitCodeOf はコマンドの終了コードを返します（終了コードを取得できないエラーの場合は -1）。
+func exitCodeOf(err error) int {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+
+	return -1
+}
@@ -11,6 +11,7 @@ import (
 	"sync"
 
 	"github.com/scottlz0310/devsync/internal/config"
+	"github.com/scottlz0310/devsync/internal/secret"
 )
 
 // Updater はパッケージマネージャの共通インターフェースです。
@@ -53,6 +54,11 @@ type PackageInfo struct {
 	Name           string // パッケージ名
 	CurrentVersion string // 現在のバージョン
 	NewVersion     string // 新しいバージョン
+	Security       bool   // セキュリティ更新かどうか（判別できるマネージャのみ）
+	// Source は脆弱性データとの照合に使う名前です（Go のモジュールパスなど、Name と異なる場合のみ）
+	Source string
+	// Vulnerabilities はこの更新で修正される既知の脆弱性です（sys check で OSV データと照合した場合のみ）
+	Vulnerabilities []Vulnerability
 }
 
 // UpdateOptions は更新実行時のオプションです。
@@ -61,6 +67,10 @@ type UpdateOptions struct {
 	DryRun bool
 	// Verbose が true の場合、詳細なログを出力
 	Verbose bool
+	// SecurityOnly が true の場合、セキュリティ更新のみを適用（SecurityOnlyUpdater を実装するマネージャのみ）
+	SecurityOnly bool
+	// AllowMajor が true の場合、allow（許可するバージョンの範囲）の設定に関わらずすべての更新を適用
+	AllowMajor bool
 }
 
 // UpdateResult は更新実行の結果を保持します。
@@ -73,6 +83,8 @@ type UpdateResult struct {
 	Packages []PackageInfo
 	// Errors は発生したエラーのリスト
 	Errors []error
+	// Held は allow の範囲を超えるため保留した更新のリスト
+	Held []PackageInfo
 	// Message は追加情報（任意）
 	Message string
 }
@@ -84,6 +96,9 @@ type Registry struct {
 	updaters map[string]Updater
 }
 
+// expandSecretRefs はテストで差し替え可能なシークレット参照の展開処理です。
+var expandSecretRefs = secret.ExpandReferences
+
 // グローバルレジストリのインスタンス
 var globalRegistry = &Registry{
 	updaters: make(map[string]Updater),
@@ -137,7 +152,7 @@ func Available() []Updater {
 
 // GetEnabled は設定で有効化されているUpdaterを返します。
 // 設定の enable リストに含まれ、かつ利用可能なマネージャのみを返します。
-func GetEnabled(cfg *config.SysConfig) ([]Updater, error) {
+func GetEnabled(ctx context.Context, cfg *config.SysConfig) ([]Updater, error) {
 	if cfg == nil || len(cfg.Enable) == 0 {
 		return nil, nil
 	}
@@ -161,8 +176,14 @@ func GetEnabled(cfg *config.SysConfig) ([]Updater, error) {
 			continue
 		}
 		// マネージャ固有の設定を適用
+		// シークレット参照はここで初めて解決し、元の設定（保存対象）には書き戻さない
 		if managerCfg, ok := cfg.Managers[name]; ok {
-			if err := u.Configure(managerCfg); err != nil {
+			resolvedCfg, err := resolveManagerConfigSecrets(ctx, managerCfg)
+			if err != nil {
+				return nil, fmt.Errorf("%s のシークレット参照の解決に失敗: %w", name, err)
+			}
+
+			if err := u.Configure(resolvedCfg); err != nil {
 				return nil, fmt.Errorf("%s の設定適用に失敗: %w", name, err)
 			}
 		}
@@ -170,6 +191,9 @@ func GetEnabled(cfg *config.SysConfig) ([]Updater, error) {
 		result = append(result, u)
 	}
 
+	// conda と mamba は同じ環境を操作するため、両方が有効な場合は mamba にまとめる
+	result = mergeCondaUpdaters(result)
+
 	warnErr := buildEnableWarning(notFound, unavailable)
 	if warnErr != nil {
 		return result, warnErr
@@ -178,6 +202,101 @@ func GetEnabled(cfg *config.SysConfig) ([]Updater, error) {
 	return result, nil
 }
 
+// resolveManagerConfigSecrets はマネージャ設定内のシークレット参照を解決した複製を返します。
+// 参照を含まない場合は元の設定をそのまま返します。
+func resolveManagerConfigSecrets(ctx context.Context, cfg config.ManagerConfig) (config.ManagerConfig, error) {
+	if cfg == nil || !containsSecretRef(map[string]interface{}(cfg)) {
+		return cfg, nil
+	}
+
+	resolved, err := resolveSecretValue(ctx, map[string]interface{}(cfg))
+	if err != nil {
+		return nil, err
+	}
+
+	resolvedMap, ok := resolved.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("マネージャ設定の形式が不正です")
+	}
+
+	return config.ManagerConfig(resolvedMap), nil
+}
+
+func containsSecretRef(value interface{}) bool {
+	switch v := value.(type) {
+	case string:
+		return secret.ContainsReference(v)
+	case []string:
+		for _, item := range v {
+			if secret.ContainsReference(item) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if containsSecretRef(item) {
+				return true
+			}
+		}
+	case map[string]interface{}:
+		for _, item := range v {
+			if containsSecretRef(item) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func resolveSecretValue(ctx context.Context, value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		return expandSecretRefs(ctx, v)
+	case []string:
+		result := make([]string, 0, len(v))
+
+		for _, item := range v {
+			expanded, err := expandSecretRefs(ctx, item)
+			if err != nil {
+				return nil, err
+			}
+
+			result = append(result, expanded)
+		}
+
+		return result, nil
+	case []interface{}:
+		result := make([]interface{}, 0, len(v))
+
+		for _, item := range v {
+			expanded, err := resolveSecretValue(ctx, item)
+			if err != nil {
+				return nil, err
+			}
+
+			result = append(result, expanded)
+		}
+
+		return result, nil
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+
+		for key, item := range v {
+			expanded, err := resolveSecretValue(ctx, item)
+			if err != nil {
+				return nil, err
+			}
+
+			result[key] = expanded
+		}
+
+		return result, nil
+	default:
+		return value, nil
+	}
+}
+
 func buildEnableWarning(notFound, unavailable []string) error {
 	if len(notFound) == 0 && len(unavailable) == 0 {
 		return nil
@@ -15,6 +15,7 @@ type mockUpdater struct {
 	displayName string
 	available   bool
 	configErr   error
+	configured  config.ManagerConfig
 }
 
 func (m *mockUpdater) Name() string        { return m.name }
@@ -29,7 +30,9 @@ func (m *mockUpdater) Update(_ context.Context, _ UpdateOptions) (*UpdateResult,
 	return &UpdateResult{UpdatedCount: 0}, nil
 }
 
-func (m *mockUpdater) Configure(_ config.ManagerConfig) error {
+func (m *mockUpdater) Configure(cfg config.ManagerConfig) error {
+	m.configured = cfg
+
 	return m.configErr
 }
 
@@ -150,7 +153,7 @@ func TestGetEnabled(t *testing.T) {
 	t.Run("nilの設定", func(t *testing.T) {
 		clearRegistry()
 
-		result, err := GetEnabled(nil)
+		result, err := GetEnabled(context.Background(), nil)
 		assert.NoError(t, err)
 		assert.Nil(t, result)
 	})
@@ -162,7 +165,7 @@ func TestGetEnabled(t *testing.T) {
 			Enable: []string{},
 		}
 
-		result, err := GetEnabled(cfg)
+		result, err := GetEnabled(context.Background(), cfg)
 		assert.NoError(t, err)
 		assert.Nil(t, result)
 	})
@@ -181,7 +184,7 @@ func TestGetEnabled(t *testing.T) {
 			Managers: make(map[string]config.ManagerConfig),
 		}
 
-		result, err := GetEnabled(cfg)
+		result, err := GetEnabled(context.Background(), cfg)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "未インストールまたは利用不可のためスキップ")
 		assert.Contains(t, err.Error(), "brew")
@@ -200,7 +203,7 @@ func TestGetEnabled(t *testing.T) {
 			Managers: make(map[string]config.ManagerConfig),
 		}
 
-		result, err := GetEnabled(cfg)
+		result, err := GetEnabled(context.Background(), cfg)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "未知のマネージャが指定されています")
 		assert.Contains(t, err.Error(), "unknown1")
@@ -223,7 +226,7 @@ func TestGetEnabled(t *testing.T) {
 			Managers: make(map[string]config.ManagerConfig),
 		}
 
-		result, err := GetEnabled(cfg)
+		result, err := GetEnabled(context.Background(), cfg)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "未インストールまたは利用不可のためスキップ")
 		assert.Contains(t, err.Error(), "brew")
@@ -246,7 +249,7 @@ func TestGetEnabled(t *testing.T) {
 			},
 		}
 
-		result, err := GetEnabled(cfg)
+		result, err := GetEnabled(context.Background(), cfg)
 		require.NoError(t, err)
 		assert.Len(t, result, 1)
 	})
@@ -268,8 +271,77 @@ func TestGetEnabled(t *testing.T) {
 			},
 		}
 
-		_, err := GetEnabled(cfg)
+		_, err := GetEnabled(context.Background(), cfg)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "apt の設定適用に失敗")
 	})
+
+	t.Run("シークレット参照は解決して適用し元の設定は書き換えない", func(t *testing.T) {
+		clearRegistry()
+
+		original := expandSecretRefs
+		expandSecretRefs = func(_ context.Context, value string) (string, error) {
+			if value == "${secret:bitwarden/Registry/token}" {
+				return "resolved-token", nil
+			}
+
+			return value, nil
+		}
+
+		t.Cleanup(func() {
+			expandSecretRefs = original
+		})
+
+		mock := &mockUpdater{name: "npm", available: true}
+		Register(mock)
+
+		cfg := &config.SysConfig{
+			Enable: []string{"npm"},
+			Managers: map[string]config.ManagerConfig{
+				"npm": {
+					"token":   "${secret:bitwarden/Registry/token}",
+					"targets": []interface{}{"a", "${secret:bitwarden/Registry/token}"},
+					"nested":  map[string]interface{}{"auth": "${secret:bitwarden/Registry/token}"},
+					"flag":    true,
+				},
+			},
+		}
+
+		_, err := GetEnabled(context.Background(), cfg)
+		require.NoError(t, err)
+
+		assert.Equal(t, "resolved-token", mock.configured["token"])
+		assert.Equal(t, []interface{}{"a", "resolved-token"}, mock.configured["targets"])
+		assert.Equal(t, map[string]interface{}{"auth": "resolved-token"}, mock.configured["nested"])
+		assert.Equal(t, true, mock.configured["flag"])
+
+		assert.Equal(t, "${secret:bitwarden/Registry/token}", cfg.Managers["npm"]["token"])
+		assert.Equal(t, []interface{}{"a", "${secret:bitwarden/Registry/token}"}, cfg.Managers["npm"]["targets"])
+	})
+
+	t.Run("シークレット参照の解決失敗はエラー", func(t *testing.T) {
+		clearRegistry()
+
+		original := expandSecretRefs
+		expandSecretRefs = func(context.Context, string) (string, error) {
+			return "", assert.AnError
+		}
+
+		t.Cleanup(func() {
+			expandSecretRefs = original
+		})
+
+		Register(&mockUpdater{name: "npm", available: true})
+
+		cfg := &config.SysConfig{
+			Enable: []string{"npm"},
+			Managers: map[string]config.ManagerConfig{
+				"npm": {"token": "bw://Registry/token"},
+			},
+		}
+
+		_, err := GetEnabled(context.Background(), cfg)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "シークレット参照の解決に失敗")
+	})
 }
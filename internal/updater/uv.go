@@ -160,3 +160,34 @@ func parseToolLine(line string) (name, version string, ok bool) {
 
 	return name, version, true
 }
+
+var _ PackageInstaller = (*UVUpdater)(nil)
+
+func (u *UVUpdater) PackageName(spec string) string {
+	return pythonPackageName(spec)
+}
+
+// InstalledPackages は "uv tool list" からインストール済みツール名を取得します。
+func (u *UVUpdater) InstalledPackages(ctx context.Context) ([]string, error) {
+	output, err := runPackageListCommand(ctx, "uv", "tool", "list")
+	if err != nil {
+		return nil, err
+	}
+
+	packages := u.parseToolListOutput(string(output))
+	names := make([]string, 0, len(packages))
+
+	for _, pkg := range packages {
+		names = append(names, pythonPackageName(pkg.Name))
+	}
+
+	return names, nil
+}
+
+func (u *UVUpdater) InstallPackage(ctx context.Context, spec string) error {
+	return runPackageCommand(ctx, "uv", "tool", "install", spec)
+}
+
+func (u *UVUpdater) RemovePackage(ctx context.Context, name string) error {
+	return runPackageCommand(ctx, "uv", "tool", "uninstall", name)
+}
@@ -0,0 +1,312 @@
+package updater
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/scottlz0310/devsync/internal/config"
+)
+
+// 更新を許可するバージョンの範囲（VersionBump の戻り値にも使用）
+const (
+	BumpPatch = "patch"
+	BumpMinor = "minor"
+	BumpMajor = "major"
+)
+
+// VersionPolicyUpdater は allow / allow_packages（許可するバージョンの範囲）に対応するマネージャが実装します。
+// config validate では、このインターフェースを実装しないマネージャへの設定を警告します。
+type VersionPolicyUpdater interface {
+	// SupportsVersionPolicy は allow / allow_packages に対応している場合に true を返します。
+	SupportsVersionPolicy() bool
+}
+
+// versionPolicy は更新を許可するバージョンの範囲です。範囲を超える更新は保留されます。
+// ゼロ値は従来どおりすべての更新を許可します。
+//
+//	sys:
+//	  managers:
+//	    npm:
+//	      allow: minor            # patch / minor / major（既定: major = 制限なし）
+//	      allow_packages:
+//	        typescript: major     # パッケージごとの上書き
+type versionPolicy struct {
+	allow    string
+	packages map[string]string
+}
+
+// parseVersionPolicy は sys.managers.<manager> の allow / allow_packages を読み込みます。
+func parseVersionPolicy(manager string, cfg config.ManagerConfig) (versionPolicy, error) {
+	var policy versionPolicy
+
+	if value, ok := cfg["allow"]; ok {
+		level, err := parseAllowLevel(value)
+		if err != nil {
+			return versionPolicy{}, fmt.Errorf("sys.managers.%s.allow: %w", manager, err)
+		}
+
+		policy.allow = level
+	}
+
+	packages, ok := cfg["allow_packages"].(map[string]interface{})
+	if !ok {
+		return policy, nil
+	}
+
+	policy.packages = make(map[string]string, len(packages))
+
+	for name, value := range packages {
+		level, err := parseAllowLevel(value)
+		if err != nil {
+			return versionPolicy{}, fmt.Errorf("sys.managers.%s.allow_packages.%s: %w", manager, name, err)
+		}
+
+		policy.packages[strings.ToLower(strings.TrimSpace(name))] = level
+	}
+
+	return policy, nil
+}
+
+func parseAllowLevel(value interface{}) (string, error) {
+	level, _ := value.(string)
+	level = strings.ToLower(strings.TrimSpace(level))
+
+	switch level {
+	case BumpPatch, BumpMinor, BumpMajor:
+		return level, nil
+	default:
+		return "", fmt.Errorf("値が不正です: %v（patch / minor / major）", value)
+	}
+}
+
+// restricted は major 未満の範囲が 1 つでも設定されているかを返します。
+func (p versionPolicy) restricted() bool {
+	if p.allow != "" && p.allow != BumpMajor {
+		return true
+	}
+
+	for _, level := range p.packages {
+		if level != BumpMajor {
+			return true
+		}
+	}
+
+	return false
+}
+
+// level はパッケージに適用される許可範囲を返します（パッケージごとの設定が優先）。
+func (p versionPolicy) level(name string) string {
+	if level, ok := p.packages[strings.ToLower(name)]; ok {
+		return level
+	}
+
+	if p.allow == "" {
+		return BumpMajor
+	}
+
+	return p.allow
+}
+
+// split は packages を許可範囲内の更新と保留する更新に分けます。
+// opts.AllowMajor の場合と、バージョンを比較できない更新は保留しません。
+func (p versionPolicy) split(packages []PackageInfo, opts UpdateOptions) (allowed, held []PackageInfo) {
+	if opts.AllowMajor || !p.restricted() {
+		return packages, nil
+	}
+
+	for _, pkg := range packages {
+		bump := VersionBump(pkg.CurrentVersion, pkg.NewVersion)
+		if bump != "" && bumpRank(bump) > bumpRank(p.level(pkg.Name)) {
+			held = append(held, pkg)
+			continue
+		}
+
+		allowed = append(allowed, pkg)
+	}
+
+	return allowed, held
+}
+
+func bumpRank(level string) int {
+	switch level {
+	case BumpPatch:
+		return 1
+	case BumpMinor:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// VersionBump は current から next への更新の種類（major / minor / patch）を返します。
+// semver の慣習に従い、0.x 系のマイナー更新はメジャー更新として扱います。
+// バージョンを解釈できない場合や更新でない場合は空文字を返します。
+func VersionBump(current, next string) string {
+	currentParts, ok := versionCore(current)
+	if !ok {
+		return ""
+	}
+
+	nextParts, ok := versionCore(next)
+	if !ok {
+		return ""
+	}
+
+	if less, err := isSemverLess(formatVersionCore(currentParts), formatVersionCore(nextParts)); err != nil || !less {
+		return ""
+	}
+
+	switch {
+	case currentParts[0] != nextParts[0]:
+		return BumpMajor
+	case currentParts[0] == 0 && currentParts[1] != nextParts[1]:
+		return BumpMajor
+	case currentParts[1] != nextParts[1]:
+		return BumpMinor
+	default:
+		return BumpPatch
+	}
+}
+
+// versionCore はバージョン先頭の数字（最大 3 要素、不足分は 0）を取り出します。
+// 例: v20.11.1 -> [20 11 1]、24.1 -> [24 1 0]、0.55.0_1 -> [0 55 0]
+func versionCore(version string) ([3]int, bool) {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if !looksLikeVersionToken(version) || !isASCIIDigit(version[0]) {
+		return [3]int{}, false
+	}
+
+	var parts [3]int
+
+	for i := 0; i < len(parts); i++ {
+		digits, rest := splitLeadingDigits(version)
+		if digits == "" {
+			break
+		}
+
+		value, err := strconv.Atoi(digits)
+		if err != nil {
+			return [3]int{}, false
+		}
+
+		parts[i] = value
+
+		if !strings.HasPrefix(rest, ".") {
+			break
+		}
+
+		version = rest[1:]
+	}
+
+	return parts, true
+}
+
+func formatVersionCore(parts [3]int) string {
+	return fmt.Sprintf("%d.%d.%d", parts[0], parts[1], parts[2])
+}
+
+// heldMessageSuffix は保留した更新がある場合にメッセージへ付け加える文言です。
+func heldMessageSuffix(held []PackageInfo) string {
+	if len(held) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("（%d 件は許可範囲を超えるため保留）", len(held))
+}
+
+// allHeldMessage はすべての更新を保留した場合のメッセージです。
+func allHeldMessage(held []PackageInfo) string {
+	return fmt.Sprintf("%d 件の更新はすべて許可範囲を超えるため保留しました", len(held))
+}
+
+// updateWithRegistryPolicy は事前に新しいバージョンを知る手段がないマネージャ（cargo / pipx）向けに、
+// レジストリで最新バージョンを確認したうえで、許可範囲内のパッケージのみを update で個別に更新します。
+// git / ローカルパスからインストールしたパッケージは呼び出し側で installed から除外してください。
+// レジストリに存在しないパッケージは対象外です。
+func updateWithRegistryPolicy(
+	ctx context.Context,
+	opts UpdateOptions,
+	policy versionPolicy,
+	installed []PackageInfo,
+	latest func(ctx context.Context, name string) (string, error),
+	update func(ctx context.Context, pkg PackageInfo) error,
+) (*UpdateResult, error) {
+	result := &UpdateResult{}
+
+	outdated, errs := registryOutdatedPackages(ctx, installed, latest)
+	result.Errors = errs
+
+	packages, held := policy.split(outdated, opts)
+	result.Held = held
+
+	switch {
+	case len(packages) == 0 && len(held) > 0:
+		result.Message = allHeldMessage(held)
+		return result, nil
+	case len(packages) == 0:
+		result.Message = "すべてのパッケージは最新です"
+		return result, nil
+	case opts.DryRun:
+		result.Packages = packages
+		result.Message = fmt.Sprintf("%d 件のパッケージが更新可能です（DryRunモード）", len(packages)) + heldMessageSuffix(held)
+
+		return result, nil
+	}
+
+	for _, pkg := range packages {
+		if err := update(ctx, pkg); err != nil {
+			result.FailedCount++
+			result.Errors = append(result.Errors, err)
+
+			continue
+		}
+
+		result.UpdatedCount++
+		result.Packages = append(result.Packages, pkg)
+	}
+
+	if result.FailedCount > 0 {
+		result.Message = fmt.Sprintf("%d 件更新、%d 件失敗", result.UpdatedCount, result.FailedCount)
+		return result, fmt.Errorf("一部のパッケージ更新に失敗しました")
+	}
+
+	result.Message = fmt.Sprintf("%d 件のパッケージを更新しました", result.UpdatedCount) + heldMessageSuffix(held)
+
+	return result, nil
+}
+
+// registryOutdatedPackages はレジストリの最新バージョンが新しいパッケージを、NewVersion を設定して返します。
+func registryOutdatedPackages(
+	ctx context.Context,
+	installed []PackageInfo,
+	latest func(ctx context.Context, name string) (string, error),
+) ([]PackageInfo, []error) {
+	var (
+		outdated []PackageInfo
+		errs     []error
+	)
+
+	for _, pkg := range installed {
+		version, err := latest(ctx, pkg.Name)
+		if errors.Is(err, errRegistryPackageNotFound) {
+			continue
+		}
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s の最新バージョンの確認に失敗: %w", pkg.Name, err))
+			continue
+		}
+
+		if VersionBump(pkg.CurrentVersion, version) == "" {
+			continue
+		}
+
+		pkg.NewVersion = version
+		outdated = append(outdated, pkg)
+	}
+
+	return outdated, errs
+}
@@ -0,0 +1,99 @@
+package updater
+
+import (
+	"testing"
+
+	"github.com/scottlz0310/devsync/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersionBump(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		next    string
+		want    string
+	}{
+		{name: "パッチ更新", current: "1.2.3", next: "1.2.4", want: BumpPatch},
+		{name: "マイナー更新", current: "1.2.3", next: "1.3.0", want: BumpMinor},
+		{name: "メジャー更新", current: "1.2.3", next: "2.0.0", want: BumpMajor},
+		{name: "v 接頭辞", current: "v20.10.0", next: "v22.11.0", want: BumpMajor},
+		{name: "0.x 系のマイナー更新はメジャー扱い", current: "0.24.0", next: "0.25.0", want: BumpMajor},
+		{name: "0.x 系のパッチ更新", current: "0.2.0", next: "0.2.2", want: BumpPatch},
+		{name: "要素が 2 つのバージョン", current: "24.1", next: "24.2", want: BumpMinor},
+		{name: "リビジョン付き（brew）", current: "0.55.0_1", next: "0.56.0", want: BumpMajor},
+		{name: "プレリリースは先頭の数字で判定", current: "5.4.5", next: "5.5.0-beta", want: BumpMinor},
+		{name: "同じバージョン", current: "1.2.3", next: "1.2.3", want: ""},
+		{name: "ダウングレード", current: "2.0.0", next: "1.9.0", want: ""},
+		{name: "解釈できないバージョン", current: "sha256:0123", next: "sha256:4567", want: ""},
+		{name: "空のバージョン", current: "", next: "1.0.0", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, VersionBump(tt.current, tt.next))
+		})
+	}
+}
+
+func TestParseVersionPolicy(t *testing.T) {
+	policy, err := parseVersionPolicy("npm", config.ManagerConfig{
+		"allow":          "Minor",
+		"allow_packages": map[string]interface{}{"TypeScript": "major", "eslint": "patch"},
+	})
+	require.NoError(t, err)
+	assert.True(t, policy.restricted())
+	assert.Equal(t, BumpMinor, policy.level("other"))
+	assert.Equal(t, BumpMajor, policy.level("typescript"), "パッケージ名は大文字小文字を区別しない")
+	assert.Equal(t, BumpPatch, policy.level("eslint"))
+
+	policy, err = parseVersionPolicy("npm", config.ManagerConfig{"allow": "major"})
+	require.NoError(t, err)
+	assert.False(t, policy.restricted())
+
+	policy, err = parseVersionPolicy("npm", config.ManagerConfig{})
+	require.NoError(t, err)
+	assert.False(t, policy.restricted(), "未設定の場合は制限しない")
+	assert.Equal(t, BumpMajor, policy.level("typescript"))
+
+	_, err = parseVersionPolicy("npm", config.ManagerConfig{"allow": "breaking"})
+	require.ErrorContains(t, err, "sys.managers.npm.allow")
+
+	_, err = parseVersionPolicy("cargo", config.ManagerConfig{"allow_packages": map[string]interface{}{"ripgrep": 1}})
+	require.ErrorContains(t, err, "sys.managers.cargo.allow_packages.ripgrep")
+}
+
+func TestVersionPolicy_Split(t *testing.T) {
+	packages := []PackageInfo{
+		{Name: "typescript", CurrentVersion: "4.9.5", NewVersion: "5.5.2"},
+		{Name: "eslint", CurrentVersion: "8.40.0", NewVersion: "8.56.0"},
+		{Name: "prettier", CurrentVersion: "3.3.2", NewVersion: "3.3.3"},
+		{Name: "unknown", CurrentVersion: "", NewVersion: "1.0.0"},
+	}
+
+	policy := versionPolicy{allow: BumpPatch, packages: map[string]string{"eslint": BumpMinor}}
+
+	allowed, held := policy.split(packages, UpdateOptions{})
+	assert.Equal(t, []PackageInfo{packages[1], packages[2], packages[3]}, allowed, "バージョンを比較できない更新は保留しない")
+	assert.Equal(t, []PackageInfo{packages[0]}, held)
+
+	allowed, held = policy.split(packages, UpdateOptions{AllowMajor: true})
+	assert.Equal(t, packages, allowed)
+	assert.Empty(t, held)
+
+	allowed, held = versionPolicy{}.split(packages, UpdateOptions{})
+	assert.Equal(t, packages, allowed)
+	assert.Empty(t, held)
+}
+
+func TestVersionPolicyUpdater(t *testing.T) {
+	for _, u := range []Updater{&NpmUpdater{}, &NvmUpdater{}, &CargoUpdater{}, &PipxUpdater{}} {
+		p, ok := u.(VersionPolicyUpdater)
+		require.True(t, ok, u.Name())
+		assert.True(t, p.SupportsVersionPolicy(), u.Name())
+	}
+
+	_, ok := Updater(&AptUpdater{}).(VersionPolicyUpdater)
+	assert.False(t, ok, "allow に対応しないマネージャは実装しない")
+}
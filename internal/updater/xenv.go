@@ -0,0 +1,157 @@
+package updater
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/scottlz0310/devsync/internal/config"
+)
+
+// XenvUpdater は pyenv / rbenv / goenv など "<lang>env" 系のバージョン管理ツールの実装です。
+// 本体とプラグインは git で導入されている場合に git pull で更新します（Homebrew などで導入した場合は対象外）。
+type XenvUpdater struct {
+	command     string
+	tool        string
+	displayName string
+	listArgs    []string
+	settings    runtimeUpdateSettings
+}
+
+var _ runtimeManager = (*XenvUpdater)(nil)
+
+// 起動時にレジストリに登録
+func init() {
+	for _, x := range builtinXenvUpdaters() {
+		Register(x)
+	}
+}
+
+// builtinXenvUpdaters は組み込みの "<lang>env" 系 Updater を返します。
+func builtinXenvUpdaters() []*XenvUpdater {
+	return []*XenvUpdater{
+		NewXenvUpdater("pyenv", "python", "pyenv (Python バージョン管理)", "install", "--list"),
+		// rbenv の install --list は系列ごとの最新安定版のみ表示するため --list-all を使う
+		NewXenvUpdater("rbenv", "ruby", "rbenv (Ruby バージョン管理)", "install", "--list-all"),
+		NewXenvUpdater("goenv", "go", "goenv (Go バージョン管理)", "install", "--list"),
+	}
+}
+
+// NewXenvUpdater は command（例: pyenv）で tool（例: python）を管理する Updater を作成します。
+// listArgs はインストール可能なバージョン一覧を表示する引数です。
+func NewXenvUpdater(command, tool, displayName string, listArgs ...string) *XenvUpdater {
+	return &XenvUpdater{
+		command:     command,
+		tool:        tool,
+		displayName: displayName,
+		listArgs:    listArgs,
+		settings:    defaultRuntimeUpdateSettings(),
+	}
+}
+
+func (x *XenvUpdater) Name() string {
+	return x.command
+}
+
+func (x *XenvUpdater) DisplayName() string {
+	return x.displayName
+}
+
+func (x *XenvUpdater) IsAvailable() bool {
+	_, err := exec.LookPath(x.command)
+	return err == nil
+}
+
+func (x *XenvUpdater) Configure(cfg config.ManagerConfig) error {
+	if cfg == nil {
+		return nil
+	}
+
+	x.settings.configure(cfg)
+
+	return nil
+}
+
+func (x *XenvUpdater) Check(ctx context.Context) (*CheckResult, error) {
+	return checkRuntimeUpdates(ctx, x, x.settings)
+}
+
+func (x *XenvUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateResult, error) {
+	return updateRuntimeVersions(ctx, opts, x.command, x, x.settings)
+}
+
+// selfUpdate は <root> と <root>/plugins/* のうち git 管理のものを git pull で更新します。
+func (x *XenvUpdater) selfUpdate(ctx context.Context) error {
+	output, err := runCommandOutputWithLocaleC(ctx, x.command, []string{"root"}, x.command+" root の実行に失敗: %w")
+	if err != nil {
+		return err
+	}
+
+	root := strings.TrimSpace(string(output))
+	dirs := []string{root}
+
+	plugins, _ := filepath.Glob(filepath.Join(root, "plugins", "*"))
+	dirs = append(dirs, plugins...)
+
+	var failures []string
+
+	for _, dir := range dirs {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err != nil {
+			continue
+		}
+
+		if err := runSystemCommand(ctx, false, "git", "-C", dir, "pull", "--ff-only"); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", dir, err))
+		}
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("git pull に失敗: %s", strings.Join(failures, "; "))
+	}
+
+	return nil
+}
+
+func (x *XenvUpdater) installedVersions(ctx context.Context) (map[string][]string, error) {
+	output, err := runCommandOutputWithLocaleC(ctx, x.command, []string{"versions", "--bare"}, x.command+" versions の実行に失敗: %w")
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string][]string{x.tool: strings.Fields(string(output))}, nil
+}
+
+func (x *XenvUpdater) availableVersions(ctx context.Context, _ string) ([]string, error) {
+	output, err := runCommandOutputWithLocaleC(ctx, x.command, x.listArgs, x.command+" "+strings.Join(x.listArgs, " ")+" の実行に失敗: %w")
+	if err != nil {
+		return nil, err
+	}
+
+	return runtimeVersionTokens(string(output)), nil
+}
+
+// globalVersion は "<command> global" の先頭のバージョンを返します（複数指定時は先頭が優先）。
+func (x *XenvUpdater) globalVersion(ctx context.Context, _ string) (string, error) {
+	output, err := runCommandOutputWithLocaleC(ctx, x.command, []string{"global"}, x.command+" global の実行に失敗: %w")
+	if err != nil {
+		return "", err
+	}
+
+	fields := strings.Fields(string(output))
+	if len(fields) == 0 {
+		return "", nil
+	}
+
+	return fields[0], nil
+}
+
+func (x *XenvUpdater) installVersion(ctx context.Context, _, version string) error {
+	return runSystemCommand(ctx, false, x.command, "install", "--skip-existing", version)
+}
+
+func (x *XenvUpdater) setGlobalVersion(ctx context.Context, _, version string) error {
+	return runSystemCommand(ctx, false, x.command, "global", version)
+}
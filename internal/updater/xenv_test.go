@@ -0,0 +1,105 @@
+package updater
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/scottlz0310/devsync/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuiltinXenvUpdaters(t *testing.T) {
+	updaters := builtinXenvUpdaters()
+	require.Len(t, updaters, 3)
+
+	got := make(map[string][]string, len(updaters))
+	for _, x := range updaters {
+		got[x.Name()+"/"+x.tool] = x.listArgs
+	}
+
+	assert.Equal(t, map[string][]string{
+		"pyenv/python": {"install", "--list"},
+		"rbenv/ruby":   {"install", "--list-all"},
+		"goenv/go":     {"install", "--list"},
+	}, got)
+}
+
+func TestXenvUpdater_CheckAndUpdate(t *testing.T) {
+	logPath := writeFakePyenvCommand(t)
+
+	p := NewXenvUpdater("pyenv", "python", "pyenv", "install", "--list")
+
+	checkResult, err := p.Check(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []PackageInfo{{Name: "python 3.12", CurrentVersion: "3.12.3", NewVersion: "3.12.5"}}, checkResult.Packages)
+
+	require.NoError(t, p.Configure(config.ManagerConfig{"install": true, "set_global": true}))
+
+	result, err := p.Update(context.Background(), UpdateOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.UpdatedCount)
+	assert.Empty(t, result.Errors)
+
+	data, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+
+	log := string(data)
+	assert.Contains(t, log, "git -C "+filepath.Join(os.Getenv("DEVSYNC_TEST_PYENV_ROOT"), "plugins", "pyenv-virtualenv")+" pull --ff-only")
+	assert.NotContains(t, log, "plugins/local-plugin", "git 管理外のプラグインは更新しない")
+	assert.Contains(t, log, "pyenv install --skip-existing 3.12.5")
+	assert.Contains(t, log, "pyenv global 3.12.5")
+}
+
+// writeFakePyenvCommand は fake の pyenv / git を PATH に配置し、実行ログのパスを返します。
+func writeFakePyenvCommand(t *testing.T) string {
+	t.Helper()
+
+	if runtime.GOOS == windowsOS {
+		t.Skip("fake pyenv は POSIX シェル前提")
+	}
+
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, ".git"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "plugins", "pyenv-virtualenv", ".git"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "plugins", "local-plugin"), 0o755))
+
+	logPath := filepath.Join(t.TempDir(), "commands.log")
+	t.Setenv("DEVSYNC_TEST_PYENV_ROOT", root)
+	t.Setenv("DEVSYNC_TEST_PYENV_LOG", logPath)
+
+	pyenv := `#!/bin/sh
+case "$1" in
+  root) echo "${DEVSYNC_TEST_PYENV_ROOT}" ;;
+  versions) printf 'system\n3.11.9\n3.12.3\n3.12.3/envs/tools\n' ;;
+  global)
+    if [ -n "$2" ]; then
+      echo "pyenv global $2" >> "${DEVSYNC_TEST_PYENV_LOG}"
+    else
+      printf '3.12.3\n3.11.9\n'
+    fi
+    ;;
+  install)
+    if [ "$2" = "--list" ]; then
+      printf 'Available versions:\n  3.11.9\n  3.12.3\n  3.12.5\n  3.13.0rc1\n  pypy3.10-7.3.16\n'
+    else
+      echo "pyenv $*" >> "${DEVSYNC_TEST_PYENV_LOG}"
+    fi
+    ;;
+  *) echo "invalid args" 1>&2; exit 1 ;;
+esac
+`
+	git := `#!/bin/sh
+echo "git $*" >> "${DEVSYNC_TEST_PYENV_LOG}"
+`
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "pyenv"), []byte(pyenv), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "git"), []byte(git), 0o755))
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	return logPath
+}
@@ -0,0 +1,172 @@
+package updater
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/scottlz0310/devsync/internal/config"
+	"github.com/scottlz0310/devsync/internal/env"
+)
+
+// zypper の更新方法（sys.managers.zypper.mode）
+const (
+	zypperModeAuto   = "auto"   // /etc/os-release から判定（既定）
+	zypperModeUpdate = "update" // zypper update（Leap / SLES）
+	zypperModeDup    = "dup"    // zypper dup（Tumbleweed / Slowroll などのローリングリリース）
+)
+
+// zypperRollingReleaseIDs は zypper dup で更新するディストリビューションの os-release の ID です。
+var zypperRollingReleaseIDs = []string{"opensuse-tumbleweed", "opensuse-slowroll", "opensuse-microos"}
+
+// zypperDetectDistroFunc はテストで差し替え可能なディストリビューションの判定処理です。
+var zypperDetectDistroFunc = env.DetectLinuxDistro
+
+// ZypperUpdater は zypper パッケージマネージャ (openSUSE/SLES) の実装です。
+// ローリングリリース（Tumbleweed など）は zypper update ではディストリビューションの更新に追従できないため、
+// list-updates --dup / dup で確認・更新します。
+//
+//	sys:
+//	  managers:
+//	    zypper:
+//	      mode: dup   # auto（既定、/etc/os-release の ID から判定）/ update / dup
+type ZypperUpdater struct {
+	useSudo bool
+	mode    string
+}
+
+// 起動時にレジストリに登録
+func init() {
+	Register(&ZypperUpdater{useSudo: true})
+}
+
+func (z *ZypperUpdater) Name() string {
+	return "zypper"
+}
+
+func (z *ZypperUpdater) DisplayName() string {
+	return "zypper (openSUSE/SLES)"
+}
+
+func (z *ZypperUpdater) IsAvailable() bool {
+	_, err := exec.LookPath("zypper")
+	return err == nil
+}
+
+func (z *ZypperUpdater) Configure(cfg config.ManagerConfig) error {
+	if cfg == nil {
+		return nil
+	}
+
+	z.useSudo = configureUseSudo(cfg, z.useSudo)
+
+	if mode := stringConfigValue(cfg, "mode"); mode != "" {
+		if mode != zypperModeAuto && mode != zypperModeUpdate && mode != zypperModeDup {
+			return fmt.Errorf("sys.managers.zypper.mode の値が不正です: %q（%s / %s / %s）", mode, zypperModeAuto, zypperModeUpdate, zypperModeDup)
+		}
+
+		z.mode = mode
+	}
+
+	return nil
+}
+
+// useDup は zypper dup で更新するかを返します。mode が auto（未指定）の場合は os-release の ID で判定します。
+func (z *ZypperUpdater) useDup() bool {
+	switch z.mode {
+	case zypperModeDup:
+		return true
+	case zypperModeUpdate:
+		return false
+	}
+
+	distro, ok := zypperDetectDistroFunc()
+
+	return ok && containsString(zypperRollingReleaseIDs, distro.ID)
+}
+
+// Check はリポジトリを更新せず（--no-refresh）、キャッシュ済みのメタデータから更新可能なパッケージを確認します。
+// リポジトリの更新は root 権限が必要なため Update で行います。
+func (z *ZypperUpdater) Check(ctx context.Context) (*CheckResult, error) {
+	args := []string{"--non-interactive", "--no-refresh", "list-updates"}
+	if z.useDup() {
+		args = append(args, "--dup")
+	}
+
+	output, err := runCommandOutputWithLocaleC(ctx, "zypper", args, "zypper list-updates の実行に失敗: %w")
+	if err != nil {
+		return nil, err
+	}
+
+	packages := z.parseListUpdates(string(output))
+
+	return &CheckResult{
+		AvailableUpdates: len(packages),
+		Packages:         packages,
+	}, nil
+}
+
+func (z *ZypperUpdater) Update(ctx context.Context, opts UpdateOptions) (*UpdateResult, error) {
+	// DryRun 以外はリポジトリのメタデータを最新化してから更新対象を確認する
+	if !opts.DryRun {
+		if err := runSystemCommand(ctx, z.useSudo, "zypper", "--non-interactive", "refresh"); err != nil {
+			return nil, fmt.Errorf("zypper refresh に失敗: %w", err)
+		}
+	}
+
+	checkResult, err := z.Check(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	subcommand := "update"
+	if z.useDup() {
+		subcommand = "dup"
+	}
+
+	command, args := systemCommandLine(z.useSudo, "zypper", "--non-interactive", subcommand)
+
+	return runCountBasedUpdate(
+		ctx,
+		opts,
+		checkResult,
+		"すべてのパッケージは最新です",
+		func(count int) string {
+			return fmt.Sprintf("%d 件のパッケージが更新可能です（DryRunモード）", count)
+		},
+		command,
+		args,
+		"zypper "+subcommand+" に失敗: %w",
+		func(count int) string {
+			return fmt.Sprintf("%d 件のパッケージを更新しました", count)
+		},
+	)
+}
+
+// parseListUpdates は "zypper list-updates" の表形式の出力をパースします
+// 形式: "v | Repository | Name | Current Version | Available Version | Arch"
+func (z *ZypperUpdater) parseListUpdates(output string) []PackageInfo {
+	lines := strings.Split(output, "\n")
+	packages := make([]PackageInfo, 0, len(lines))
+
+	for _, line := range lines {
+		columns := strings.Split(line, "|")
+		if len(columns) < 6 {
+			continue
+		}
+
+		// 先頭列 "v" が更新可能なパッケージの行（ヘッダー "S" と区切り線は除外）
+		if strings.TrimSpace(columns[0]) != "v" {
+			continue
+		}
+
+		packages = append(packages, PackageInfo{
+			Name:           strings.TrimSpace(columns[2]),
+			CurrentVersion: strings.TrimSpace(columns[3]),
+			NewVersion:     strings.TrimSpace(columns[4]),
+		})
+	}
+
+	return packages
+}
@@ -0,0 +1,179 @@
+package updater
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/scottlz0310/devsync/internal/config"
+	"github.com/scottlz0310/devsync/internal/env"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestZypperUpdater_parseListUpdates(t *testing.T) {
+	output := `Loading repository data...
+Reading installed packages...
+S | Repository            | Name    | Current Version | Available Version | Arch
+--+-----------------------+---------+-----------------+-------------------+-------
+v | openSUSE-Tumbleweed   | curl    | 8.7.1-1.1       | 8.8.0-1.1         | x86_64
+v | openSUSE-Tumbleweed   | libcurl4| 8.7.1-1.1       | 8.8.0-1.1         | x86_64
+`
+
+	z := &ZypperUpdater{}
+	assert.Equal(t, []PackageInfo{
+		{Name: "curl", CurrentVersion: "8.7.1-1.1", NewVersion: "8.8.0-1.1"},
+		{Name: "libcurl4", CurrentVersion: "8.7.1-1.1", NewVersion: "8.8.0-1.1"},
+	}, z.parseListUpdates(output))
+}
+
+func TestZypperUpdater_CheckAndUpdate(t *testing.T) {
+	testCases := []struct {
+		name        string
+		mode        string
+		opts        UpdateOptions
+		wantUpdated int
+		errContains string
+		msgContains string
+	}{
+		{name: "DryRunは更新せず計画表示", mode: "updates", opts: UpdateOptions{DryRun: true}, msgContains: "DryRunモード"},
+		{name: "更新なし", mode: "none", msgContains: "すべてのパッケージは最新です"},
+		{name: "更新成功", mode: "updates", wantUpdated: 1, msgContains: "1 件のパッケージを更新しました"},
+		{name: "古いメタデータは refresh 後に更新", mode: "stale", wantUpdated: 1, msgContains: "1 件のパッケージを更新しました"},
+		{name: "DryRunは refresh しない", mode: "stale", opts: UpdateOptions{DryRun: true}, msgContains: "すべてのパッケージは最新です"},
+		{name: "refresh失敗", mode: "refresh_error", errContains: "zypper refresh に失敗"},
+		{name: "list-updates失敗", mode: "list_error", errContains: "zypper list-updates の実行に失敗"},
+		{name: "update失敗", mode: "update_error", errContains: "zypper update に失敗"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			writeFakeZypperCommand(t)
+			stubZypperDistro(t, "opensuse-leap")
+			t.Setenv("DEVSYNC_TEST_ZYPPER_MODE", tc.mode)
+
+			got, err := (&ZypperUpdater{}).Update(context.Background(), tc.opts)
+			if tc.errContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.errContains)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantUpdated, got.UpdatedCount)
+			assert.Contains(t, got.Message, tc.msgContains)
+		})
+	}
+}
+
+func TestZypperUpdater_Dup(t *testing.T) {
+	testCases := []struct {
+		name    string
+		distro  string
+		mode    string
+		wantDup bool
+	}{
+		{name: "Tumbleweed は dup", distro: "opensuse-tumbleweed", wantDup: true},
+		{name: "Leap は update", distro: "opensuse-leap", wantDup: false},
+		{name: "mode: dup で明示", distro: "opensuse-leap", mode: "dup", wantDup: true},
+		{name: "mode: update で明示", distro: "opensuse-tumbleweed", mode: "update", wantDup: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			logPath := writeFakeZypperCommand(t)
+			stubZypperDistro(t, tc.distro)
+			t.Setenv("DEVSYNC_TEST_ZYPPER_MODE", "updates")
+
+			z := &ZypperUpdater{}
+			require.NoError(t, z.Configure(config.ManagerConfig{"use_sudo": false, "mode": tc.mode}))
+
+			result, err := z.Update(context.Background(), UpdateOptions{})
+			require.NoError(t, err)
+			assert.Equal(t, 1, result.UpdatedCount)
+
+			data, err := os.ReadFile(logPath)
+			require.NoError(t, err)
+
+			if tc.wantDup {
+				assert.Equal(t, "--non-interactive refresh\n--non-interactive --no-refresh list-updates --dup\n--non-interactive dup\n", string(data))
+			} else {
+				assert.Equal(t, "--non-interactive refresh\n--non-interactive --no-refresh list-updates\n--non-interactive update\n", string(data))
+			}
+		})
+	}
+
+	assert.Error(t, (&ZypperUpdater{}).Configure(config.ManagerConfig{"mode": "upgrade"}))
+}
+
+func stubZypperDistro(t *testing.T, id string) {
+	t.Helper()
+
+	original := zypperDetectDistroFunc
+	zypperDetectDistroFunc = func() (env.LinuxDistro, bool) {
+		return env.LinuxDistro{ID: id}, true
+	}
+
+	t.Cleanup(func() { zypperDetectDistroFunc = original })
+}
+
+// writeFakeZypperCommand は fake zypper を PATH に配置し、実行時の引数を記録するログのパスを返します。
+func writeFakeZypperCommand(t *testing.T) string {
+	t.Helper()
+
+	if runtime.GOOS == windowsOS {
+		t.Skip("fake zypper は POSIX シェル前提")
+	}
+
+	script := `#!/bin/sh
+mode="${DEVSYNC_TEST_ZYPPER_MODE}"
+echo "$*" >> "${DEVSYNC_TEST_ZYPPER_LOG}"
+case "$*" in
+  "--non-interactive refresh")
+    if [ "${mode}" = "refresh_error" ]; then
+      echo "Repository 'oss' is invalid." 1>&2
+      exit 4
+    fi
+    touch "${DEVSYNC_TEST_ZYPPER_REFRESHED}"
+    exit 0
+    ;;
+  *list-updates*)
+    if [ "${mode}" = "list_error" ]; then
+      echo "Repository 'oss' is invalid." 1>&2
+      exit 4
+    fi
+    echo "S | Repository | Name | Current Version | Available Version | Arch"
+    echo "--+------------+------+-----------------+-------------------+-----"
+    if [ "${mode}" = "stale" ] && [ ! -f "${DEVSYNC_TEST_ZYPPER_REFRESHED}" ]; then
+      exit 0
+    fi
+    if [ "${mode}" != "none" ]; then
+      echo "v | repo-oss   | curl | 8.7.1-1.1       | 8.8.0-1.1         | x86_64"
+    fi
+    exit 0
+    ;;
+  *update*|*dup*)
+    if [ "${mode}" = "update_error" ]; then
+      echo "zypper update failed" 1>&2
+      exit 1
+    fi
+    exit 0
+    ;;
+esac
+echo "invalid args" 1>&2
+exit 1
+`
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "zypper"), []byte(script), 0o755))
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("DEVSYNC_TEST_ZYPPER_REFRESHED", filepath.Join(t.TempDir(), "refreshed"))
+
+	logPath := filepath.Join(t.TempDir(), "commands.log")
+	t.Setenv("DEVSYNC_TEST_ZYPPER_LOG", logPath)
+
+	return logPath
+}